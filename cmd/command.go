@@ -20,15 +20,27 @@
 package answercmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/apache/incubator-answer/internal/base/backup"
 	"github.com/apache/incubator-answer/internal/base/conf"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/stackexchange"
 	"github.com/apache/incubator-answer/internal/cli"
 	"github.com/apache/incubator-answer/internal/install"
 	"github.com/apache/incubator-answer/internal/migrations"
+	configrepo "github.com/apache/incubator-answer/internal/repo/config"
+	"github.com/apache/incubator-answer/internal/repo/plugin_config"
+	"github.com/apache/incubator-answer/internal/repo/search_sync"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/config"
+	"github.com/apache/incubator-answer/internal/service/plugin_common"
+	"github.com/apache/incubator-answer/internal/service/search_reindex"
 	"github.com/apache/incubator-answer/plugin"
+	"github.com/segmentfault/pacman/contrib/cache/memory"
 	"github.com/segmentfault/pacman/log"
 	"github.com/spf13/cobra"
 )
@@ -38,6 +50,12 @@ var (
 	dataDirPath string
 	// dumpDataPath dump data path
 	dumpDataPath string
+	// restoreArchivePath the archive created by dump command
+	restoreArchivePath string
+	// migrateFromConfig the config file of source database
+	migrateFromConfig string
+	// migrateToConfig the config file of target database
+	migrateToConfig string
 	// place to build new answer
 	buildDir string
 	// plugins needed to build in answer application
@@ -53,6 +71,16 @@ var (
 	i18nSourcePath string
 	// i18nTargetPath i18n to path
 	i18nTargetPath string
+	// importSource the name of the imported data source, the import of the same source could be resumed
+	importSource string
+	// importEmailDomain the domain of the placeholder email of imported users
+	importEmailDomain string
+	// reindexDryRun only report the drift of search index, nothing is sent to the search plugin
+	reindexDryRun bool
+	// reindexRestart reindex from the beginning instead of the checkpoint
+	reindexRestart bool
+	// reindexBatchSize the count of contents in a batch
+	reindexBatchSize int
 )
 
 func init() {
@@ -62,6 +90,12 @@ func init() {
 
 	dumpCmd.Flags().StringVarP(&dumpDataPath, "path", "p", "./", "dump data path, eg: -p ./dump/data/")
 
+	restoreCmd.Flags().StringVarP(&restoreArchivePath, "file", "f", "", "dump archive file, eg: -f ./answer_dump_2024-01-01_000000.tar.gz")
+
+	migrateDBCmd.Flags().StringVarP(&migrateFromConfig, "from", "f", "", "config file of source database, eg: -f ./data/conf/config.yaml")
+
+	migrateDBCmd.Flags().StringVarP(&migrateToConfig, "to", "t", "", "config file of target database, eg: -t ./new/conf/config.yaml")
+
 	buildCmd.Flags().StringSliceVarP(&buildWithPlugins, "with", "w", []string{}, "plugins needed to build")
 
 	buildCmd.Flags().StringVarP(&buildOutput, "output", "o", "", "build output path")
@@ -76,7 +110,21 @@ func init() {
 
 	i18nCmd.Flags().StringVarP(&i18nTargetPath, "target", "t", "", "i18n target path, eg: -t ./i18n/target")
 
-	for _, cmd := range []*cobra.Command{initCmd, checkCmd, runCmd, dumpCmd, upgradeCmd, buildCmd, pluginCmd, configCmd, i18nCmd} {
+	importStackExchangeCmd.Flags().StringVarP(&importSource, "source", "s", "", "name of the data source, default is stackexchange:<dir name>, eg: -s stackexchange:superuser")
+
+	importStackExchangeCmd.Flags().StringVarP(&importEmailDomain, "email-domain", "e", "stackexchange.invalid", "domain of the placeholder email of imported users, eg: -e example.com")
+
+	importCmd.AddCommand(importStackExchangeCmd)
+
+	searchReindexCmd.Flags().BoolVarP(&reindexDryRun, "dry-run", "d", false, "only report the drift between the search index and database")
+
+	searchReindexCmd.Flags().BoolVarP(&reindexRestart, "restart", "r", false, "reindex from the beginning instead of the checkpoint of last unfinished reindexing")
+
+	searchReindexCmd.Flags().IntVarP(&reindexBatchSize, "batch-size", "b", schema.SearchReindexDefaultBatchSize, "the count of contents in a batch, eg: -b 500")
+
+	searchCmd.AddCommand(searchReindexCmd)
+
+	for _, cmd := range []*cobra.Command{initCmd, checkCmd, runCmd, dumpCmd, restoreCmd, migrateDBCmd, upgradeCmd, buildCmd, pluginCmd, configCmd, i18nCmd, importCmd, searchCmd} {
 		rootCmd.AddCommand(cmd)
 	}
 }
@@ -170,7 +218,7 @@ To run answer, use:
 				fmt.Println("read config failed: ", err.Error())
 				return
 			}
-			err = cli.DumpAllData(c.Data.Database, dumpDataPath)
+			err = backup.DumpAllData(c.Data.Database, getUploadPath(c), dumpDataPath)
 			if err != nil {
 				fmt.Println("dump failed: ", err.Error())
 				return
@@ -179,6 +227,65 @@ To run answer, use:
 		},
 	}
 
+	// restoreCmd represents the restore command
+	restoreCmd = &cobra.Command{
+		Use:   "restore [archive]",
+		Short: "restore data from the dump archive",
+		Long:  `Restore data from the archive created by dump command, the target database must be empty`,
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) > 0 {
+				restoreArchivePath = args[0]
+			}
+			if len(restoreArchivePath) == 0 {
+				fmt.Println("please specify the dump archive, eg: answer restore ./answer_dump.tar.gz")
+				return
+			}
+			fmt.Println("Answer is restoring data")
+			cli.FormatAllPath(dataDirPath)
+			c, err := conf.ReadConfig(cli.GetConfigFilePath())
+			if err != nil {
+				fmt.Println("read config failed: ", err.Error())
+				return
+			}
+			err = backup.RestoreData(c.Data.Database, getUploadPath(c), restoreArchivePath)
+			if err != nil {
+				fmt.Println("restore failed: ", err.Error())
+				return
+			}
+			fmt.Println("Answer restored the data successfully.")
+		},
+	}
+
+	// migrateDBCmd represents the migrate-db command
+	migrateDBCmd = &cobra.Command{
+		Use:   "migrate-db",
+		Short: "migrate data to another database",
+		Long:  `Copy all data from the database in source config to the database in target config, eg: from sqlite3 to mysql`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if len(migrateFromConfig) == 0 || len(migrateToConfig) == 0 {
+				fmt.Println("please specify the config files, eg: answer migrate-db --from ./old.yaml --to ./new.yaml")
+				return
+			}
+			from, err := conf.ReadConfig(migrateFromConfig)
+			if err != nil {
+				fmt.Println("read source config failed: ", err.Error())
+				return
+			}
+			to, err := conf.ReadConfig(migrateToConfig)
+			if err != nil {
+				fmt.Println("read target config failed: ", err.Error())
+				return
+			}
+			fmt.Printf("Answer is migrating data from %s to %s\n", from.Data.Database.Driver, to.Data.Database.Driver)
+			if err = backup.MigrateDB(from.Data.Database, to.Data.Database); err != nil {
+				fmt.Println("migrate database failed: ", err.Error())
+				return
+			}
+			fmt.Println("Answer migrated the data successfully.")
+		},
+	}
+
 	// checkCmd represents the check command
 	checkCmd = &cobra.Command{
 		Use:   "check",
@@ -306,6 +413,86 @@ To run answer, use:
 			}
 		},
 	}
+
+	// importCmd represents the import command
+	importCmd = &cobra.Command{
+		Use:   "import",
+		Short: "import data from other platforms",
+		Long:  `Import data from other platforms, eg: answer import stackexchange ./dump/`,
+	}
+
+	// importStackExchangeCmd represents the import stackexchange command
+	importStackExchangeCmd = &cobra.Command{
+		Use:   "stackexchange <dir>",
+		Short: "import Stack Exchange data dump",
+		Long: `Import users, tags, questions, answers, comments and votes from the directory of Stack Exchange data dump,
+which contains Posts.xml, Users.xml, Comments.xml, Votes.xml, Tags.xml and PostHistory.xml.
+The imported records are remembered, run it again with the same source to resume an interrupted import.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			fmt.Println("Answer is importing Stack Exchange data dump")
+			cli.FormatAllPath(dataDirPath)
+			c, err := conf.ReadConfig(cli.GetConfigFilePath())
+			if err != nil {
+				fmt.Println("read config failed: ", err.Error())
+				return
+			}
+			report, err := stackexchange.Import(context.Background(), c.Data.Database, &stackexchange.Options{
+				Dir:         args[0],
+				Source:      importSource,
+				EmailDomain: importEmailDomain,
+			})
+			printImportReport(report)
+			if err != nil {
+				fmt.Println("import failed: ", err.Error())
+				return
+			}
+			fmt.Println("Answer imported the data successfully.")
+		},
+	}
+
+	// searchCmd represents the search command
+	searchCmd = &cobra.Command{
+		Use:   "search",
+		Short: "manage the index of search plugin",
+		Long:  `Manage the index of search plugin, eg: answer search reindex`,
+	}
+
+	// searchReindexCmd represents the search reindex command
+	searchReindexCmd = &cobra.Command{
+		Use:   "reindex",
+		Short: "rebuild the index of search plugin",
+		Long: `Send all questions and answers to the enabled search plugin in batches, the deleted ones are removed from the index.
+The checkpoint is saved after each batch, run it again to continue the interrupted reindexing.
+With --dry-run, nothing is sent and the drift between the index and database is reported,
+if the search plugin supports reading its index.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			fmt.Println("Answer is rebuilding the search index")
+			cli.FormatAllPath(dataDirPath)
+			c, err := conf.ReadConfig(cli.GetConfigFilePath())
+			if err != nil {
+				fmt.Println("read config failed: ", err.Error())
+				return
+			}
+			searchReindexService, cleanup, err := newSearchReindexService(c)
+			if err != nil {
+				fmt.Println("init failed: ", err.Error())
+				return
+			}
+			defer cleanup()
+			resp, err := searchReindexService.Reindex(context.Background(), &schema.SearchReindexReq{
+				DryRun:    reindexDryRun,
+				Restart:   reindexRestart,
+				BatchSize: reindexBatchSize,
+			}, printSearchReindexProgress)
+			printSearchIndexDrift(resp)
+			if err != nil {
+				fmt.Println("reindex failed: ", err.Error())
+				return
+			}
+			fmt.Println("Answer rebuilt the search index successfully.")
+		},
+	}
 )
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -316,3 +503,66 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// getUploadPath get the upload path from config, use the default upload path if not set
+func getUploadPath(c *conf.AllConfig) string {
+	if c.ServiceConfig != nil && len(c.ServiceConfig.UploadPath) > 0 {
+		return c.ServiceConfig.UploadPath
+	}
+	return cli.UploadFilePath
+}
+
+// printImportReport print the count of imported objects and the skipped records
+func printImportReport(report *stackexchange.Report) {
+	if report == nil {
+		return
+	}
+	for _, record := range report.Skipped {
+		fmt.Printf("skipped %s %s: %s\n", record.SourceType, record.SourceID, record.Reason)
+	}
+	for _, sourceType := range []string{stackexchange.SourceTypeUser, stackexchange.SourceTypeTag,
+		stackexchange.SourceTypeQuestion, stackexchange.SourceTypeAnswer, stackexchange.SourceTypeComment} {
+		fmt.Printf("%s: %d imported, %d imported before\n",
+			sourceType, report.Imported[sourceType], report.Resumed[sourceType])
+	}
+	fmt.Printf("skipped records: %d\n", len(report.Skipped))
+}
+
+// newSearchReindexService init the search plugin with its config in database and create the search reindex service
+func newSearchReindexService(c *conf.AllConfig) (*search_reindex.SearchReindexService, func(), error) {
+	db, err := data.NewDB(false, c.Data.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	dataData, cleanup, err := data.NewData(db, memory.NewCache())
+	if err != nil {
+		return nil, nil, err
+	}
+	configService := config.NewConfigService(configrepo.NewConfigRepo(dataData))
+	_ = plugin_common.NewPluginCommonService(plugin_config.NewPluginConfigRepo(dataData),
+		plugin_config.NewPluginUserConfigRepo(dataData), configService, dataData, nil)
+	return search_reindex.NewSearchReindexService(search_sync.NewSearchReindexRepo(dataData)), cleanup, nil
+}
+
+// printSearchReindexProgress print the progress of search reindexing after each batch
+func printSearchReindexProgress(progress *schema.SearchReindexResp) {
+	fmt.Printf("[reindex] %s %d/%d processed, %d updated, %d deleted, %d failed, checkpoint %s %s\n",
+		progress.PluginSlugName, progress.Processed, progress.Total, progress.Updated, progress.Deleted,
+		progress.Failed, progress.ObjectType, progress.LastObjectID)
+}
+
+// printSearchIndexDrift print the drift between the search index and database found by dry-run
+func printSearchIndexDrift(resp *schema.SearchReindexResp) {
+	if resp == nil || resp.Drift == nil {
+		return
+	}
+	if !resp.Drift.Supported {
+		fmt.Printf("search plugin %s does not support reading its index, the drift is unknown\n", resp.PluginSlugName)
+		return
+	}
+	fmt.Printf("index drift: %d missing, %d stale, %d orphaned\n",
+		resp.Drift.Missing, resp.Drift.Stale, resp.Drift.Orphaned)
+	if len(resp.Drift.Samples) > 0 {
+		fmt.Printf("drifted objects, eg: %s\n", strings.Join(resp.Drift.Samples, ", "))
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/apache/incubator-answer/internal/base/cron"
 	"github.com/apache/incubator-answer/internal/cli"
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/notification_digest"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman"
 	"github.com/segmentfault/pacman/contrib/log/zap"
@@ -88,7 +89,10 @@ func runApp() {
 	}
 }
 
-func newApplication(serverConf *conf.Server, server *gin.Engine, manager *cron.ScheduledTaskManager) *pacman.Application {
+// newApplication the services which only run scheduled jobs are required here,
+// so that they are created and register their jobs before the cron manager runs.
+func newApplication(serverConf *conf.Server, server *gin.Engine, manager *cron.ScheduledTaskManager,
+	_ *notification_digest.NotificationDigestService) *pacman.Application {
 	manager.Run()
 	return pacman.NewApp(
 		pacman.WithName(Name),
@@ -66,5 +66,6 @@ func initApplication(
 		translator.ProviderSet,
 		middleware.ProviderSetMiddleware,
 		newApplication,
+		wire.FieldsOf(new(*conf.Server), "HTTP"),
 	))
 }
@@ -39,42 +39,59 @@ import (
 	"github.com/apache/incubator-answer/internal/repo/activity"
 	"github.com/apache/incubator-answer/internal/repo/activity_common"
 	"github.com/apache/incubator-answer/internal/repo/answer"
+	"github.com/apache/incubator-answer/internal/repo/api_key"
+	"github.com/apache/incubator-answer/internal/repo/audit_log"
 	"github.com/apache/incubator-answer/internal/repo/auth"
 	"github.com/apache/incubator-answer/internal/repo/badge"
 	"github.com/apache/incubator-answer/internal/repo/badge_award"
 	"github.com/apache/incubator-answer/internal/repo/badge_group"
+	"github.com/apache/incubator-answer/internal/repo/bounty"
 	"github.com/apache/incubator-answer/internal/repo/captcha"
 	"github.com/apache/incubator-answer/internal/repo/collection"
 	"github.com/apache/incubator-answer/internal/repo/comment"
 	"github.com/apache/incubator-answer/internal/repo/config"
+	"github.com/apache/incubator-answer/internal/repo/cron_job"
+	"github.com/apache/incubator-answer/internal/repo/draft"
 	"github.com/apache/incubator-answer/internal/repo/export"
+	"github.com/apache/incubator-answer/internal/repo/feed"
+	"github.com/apache/incubator-answer/internal/repo/import_record"
 	"github.com/apache/incubator-answer/internal/repo/limit"
 	"github.com/apache/incubator-answer/internal/repo/meta"
 	notification2 "github.com/apache/incubator-answer/internal/repo/notification"
+	"github.com/apache/incubator-answer/internal/repo/notification_digest"
 	"github.com/apache/incubator-answer/internal/repo/plugin_config"
 	"github.com/apache/incubator-answer/internal/repo/question"
+	"github.com/apache/incubator-answer/internal/repo/question_merge"
+	"github.com/apache/incubator-answer/internal/repo/queue"
 	"github.com/apache/incubator-answer/internal/repo/rank"
 	"github.com/apache/incubator-answer/internal/repo/reason"
 	"github.com/apache/incubator-answer/internal/repo/report"
 	"github.com/apache/incubator-answer/internal/repo/review"
 	"github.com/apache/incubator-answer/internal/repo/revision"
 	"github.com/apache/incubator-answer/internal/repo/role"
+	"github.com/apache/incubator-answer/internal/repo/saved_search"
 	"github.com/apache/incubator-answer/internal/repo/search_common"
+	"github.com/apache/incubator-answer/internal/repo/search_sync"
 	"github.com/apache/incubator-answer/internal/repo/site_info"
 	"github.com/apache/incubator-answer/internal/repo/tag"
 	"github.com/apache/incubator-answer/internal/repo/tag_common"
+	"github.com/apache/incubator-answer/internal/repo/two_factor"
 	"github.com/apache/incubator-answer/internal/repo/unique"
 	"github.com/apache/incubator-answer/internal/repo/user"
 	"github.com/apache/incubator-answer/internal/repo/user_external_login"
 	"github.com/apache/incubator-answer/internal/repo/user_notification_config"
+	"github.com/apache/incubator-answer/internal/repo/webhook"
 	"github.com/apache/incubator-answer/internal/router"
 	"github.com/apache/incubator-answer/internal/service/action"
 	activity2 "github.com/apache/incubator-answer/internal/service/activity"
 	activity_common2 "github.com/apache/incubator-answer/internal/service/activity_common"
 	"github.com/apache/incubator-answer/internal/service/activity_queue"
 	"github.com/apache/incubator-answer/internal/service/answer_common"
+	api_key2 "github.com/apache/incubator-answer/internal/service/api_key"
+	audit_log2 "github.com/apache/incubator-answer/internal/service/audit_log"
 	auth2 "github.com/apache/incubator-answer/internal/service/auth"
 	badge2 "github.com/apache/incubator-answer/internal/service/badge"
+	bounty2 "github.com/apache/incubator-answer/internal/service/bounty"
 	collection2 "github.com/apache/incubator-answer/internal/service/collection"
 	"github.com/apache/incubator-answer/internal/service/collection_common"
 	comment2 "github.com/apache/incubator-answer/internal/service/comment"
@@ -82,8 +99,10 @@ import (
 	config2 "github.com/apache/incubator-answer/internal/service/config"
 	"github.com/apache/incubator-answer/internal/service/content"
 	"github.com/apache/incubator-answer/internal/service/dashboard"
+	draft2 "github.com/apache/incubator-answer/internal/service/draft"
 	"github.com/apache/incubator-answer/internal/service/event_queue"
 	export2 "github.com/apache/incubator-answer/internal/service/export"
+	feed2 "github.com/apache/incubator-answer/internal/service/feed"
 	"github.com/apache/incubator-answer/internal/service/follow"
 	"github.com/apache/incubator-answer/internal/service/importer"
 	meta2 "github.com/apache/incubator-answer/internal/service/meta"
@@ -91,9 +110,12 @@ import (
 	"github.com/apache/incubator-answer/internal/service/notice_queue"
 	"github.com/apache/incubator-answer/internal/service/notification"
 	"github.com/apache/incubator-answer/internal/service/notification_common"
+	notification_digest2 "github.com/apache/incubator-answer/internal/service/notification_digest"
+	"github.com/apache/incubator-answer/internal/service/notification_push"
 	"github.com/apache/incubator-answer/internal/service/object_info"
 	"github.com/apache/incubator-answer/internal/service/plugin_common"
 	"github.com/apache/incubator-answer/internal/service/question_common"
+	question_merge2 "github.com/apache/incubator-answer/internal/service/question_merge"
 	rank2 "github.com/apache/incubator-answer/internal/service/rank"
 	reason2 "github.com/apache/incubator-answer/internal/service/reason"
 	report2 "github.com/apache/incubator-answer/internal/service/report"
@@ -101,17 +123,21 @@ import (
 	review2 "github.com/apache/incubator-answer/internal/service/review"
 	"github.com/apache/incubator-answer/internal/service/revision_common"
 	role2 "github.com/apache/incubator-answer/internal/service/role"
+	saved_search2 "github.com/apache/incubator-answer/internal/service/saved_search"
 	"github.com/apache/incubator-answer/internal/service/search_parser"
+	"github.com/apache/incubator-answer/internal/service/search_reindex"
 	"github.com/apache/incubator-answer/internal/service/service_config"
 	"github.com/apache/incubator-answer/internal/service/siteinfo"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
 	tag2 "github.com/apache/incubator-answer/internal/service/tag"
 	tag_common2 "github.com/apache/incubator-answer/internal/service/tag_common"
+	two_factor2 "github.com/apache/incubator-answer/internal/service/two_factor"
 	"github.com/apache/incubator-answer/internal/service/uploader"
 	"github.com/apache/incubator-answer/internal/service/user_admin"
 	"github.com/apache/incubator-answer/internal/service/user_common"
 	user_external_login2 "github.com/apache/incubator-answer/internal/service/user_external_login"
 	user_notification_config2 "github.com/apache/incubator-answer/internal/service/user_notification_config"
+	webhook2 "github.com/apache/incubator-answer/internal/service/webhook"
 	"github.com/segmentfault/pacman"
 	"github.com/segmentfault/pacman/log"
 )
@@ -154,13 +180,18 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	emailService := export2.NewEmailService(configService, emailRepo, siteInfoCommonService)
 	userRoleRelRepo := role.NewUserRoleRelRepo(dataData)
 	roleRepo := role.NewRoleRepo(dataData)
-	roleService := role2.NewRoleService(roleRepo)
+	powerRepo := role.NewPowerRepo(dataData)
+	rolePowerRelRepo := role.NewRolePowerRelRepo(dataData)
+	roleService := role2.NewRoleService(roleRepo, powerRepo, rolePowerRelRepo)
 	userRoleRelService := role2.NewUserRoleRelService(userRoleRelRepo, roleService)
-	userCommon := usercommon.NewUserCommon(userRepo, userRoleRelService, authService, siteInfoCommonService)
+	rolePowerRelService := role2.NewRolePowerRelService(rolePowerRelRepo, userRoleRelService)
+	userCommon := usercommon.NewUserCommon(userRepo, userRoleRelService, rolePowerRelService, authService, siteInfoCommonService)
 	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(dataData)
 	userNotificationConfigRepo := user_notification_config.NewUserNotificationConfigRepo(dataData)
 	userNotificationConfigService := user_notification_config2.NewUserNotificationConfigService(userRepo, userNotificationConfigRepo)
-	userExternalLoginService := user_external_login2.NewUserExternalLoginService(userRepo, userCommon, userExternalLoginRepo, emailService, siteInfoCommonService, userActiveActivityRepo, userNotificationConfigService)
+	twoFactorRepo := two_factor.NewTwoFactorRepo(dataData)
+	twoFactorService := two_factor2.NewTwoFactorService(twoFactorRepo, userRepo, siteInfoCommonService, userRoleRelService, rolePowerRelService)
+	userExternalLoginService := user_external_login2.NewUserExternalLoginService(userRepo, userCommon, userExternalLoginRepo, emailService, siteInfoCommonService, userActiveActivityRepo, userNotificationConfigService, twoFactorService)
 	questionRepo := question.NewQuestionRepo(dataData, uniqueIDRepo)
 	answerRepo := answer.NewAnswerRepo(dataData, uniqueIDRepo, userRankRepo, activityRepo)
 	voteRepo := activity_common.NewVoteRepo(dataData, activityRepo)
@@ -170,7 +201,8 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	tagRepo := tag.NewTagRepo(dataData, uniqueIDRepo)
 	revisionRepo := revision.NewRevisionRepo(dataData, uniqueIDRepo)
 	revisionService := revision_common.NewRevisionService(revisionRepo, userRepo)
-	activityQueueService := activity_queue.NewActivityQueueService()
+	messageRepo := queue.NewQueueMessageRepo(dataData)
+	activityQueueService := activity_queue.NewActivityQueueService(messageRepo, serviceConf)
 	tagCommonService := tag_common2.NewTagCommonService(tagCommonRepo, tagRelRepo, tagRepo, revisionService, siteInfoCommonService, activityQueueService)
 	collectionRepo := collection.NewCollectionRepo(dataData, uniqueIDRepo)
 	collectionCommon := collectioncommon.NewCollectionCommon(collectionRepo)
@@ -178,50 +210,56 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	metaRepo := meta.NewMetaRepo(dataData)
 	metaCommonService := metacommon.NewMetaCommonService(metaRepo)
 	questionCommon := questioncommon.NewQuestionCommon(questionRepo, answerRepo, voteRepo, followRepo, tagCommonService, userCommon, collectionCommon, answerCommon, metaCommonService, configService, activityQueueService, revisionRepo, siteInfoCommonService, dataData)
-	eventQueueService := event_queue.NewEventQueueService()
-	userService := content.NewUserService(userRepo, userActiveActivityRepo, activityRepo, emailService, authService, siteInfoCommonService, userRoleRelService, userCommon, userExternalLoginService, userNotificationConfigRepo, userNotificationConfigService, questionCommon, eventQueueService)
+	eventQueueService := event_queue.NewEventQueueService(messageRepo, serviceConf)
+	userService := content.NewUserService(userRepo, userActiveActivityRepo, activityRepo, emailService, authService, siteInfoCommonService, userRoleRelService, userCommon, userExternalLoginService, userNotificationConfigRepo, userNotificationConfigService, questionCommon, eventQueueService, twoFactorService, rolePowerRelService)
 	captchaRepo := captcha.NewCaptchaRepo(dataData)
 	captchaService := action.NewCaptchaService(captchaRepo)
 	userController := controller.NewUserController(authService, userService, captchaService, emailService, siteInfoCommonService, userNotificationConfigService)
 	commentRepo := comment.NewCommentRepo(dataData, uniqueIDRepo)
 	commentCommonRepo := comment.NewCommentCommonRepo(dataData, uniqueIDRepo)
 	objService := object_info.NewObjService(answerRepo, questionRepo, commentCommonRepo, tagCommonRepo, tagCommonService)
-	notificationQueueService := notice_queue.NewNotificationQueueService()
-	externalNotificationQueueService := notice_queue.NewNewQuestionNotificationQueueService()
+	notificationQueueService := notice_queue.NewNotificationQueueService(messageRepo, serviceConf)
+	externalNotificationQueueService := notice_queue.NewNewQuestionNotificationQueueService(messageRepo, serviceConf)
 	commentService := comment2.NewCommentService(commentRepo, commentCommonRepo, userCommon, objService, voteRepo, emailService, userRepo, notificationQueueService, externalNotificationQueueService, activityQueueService, eventQueueService)
-	rolePowerRelRepo := role.NewRolePowerRelRepo(dataData)
-	rolePowerRelService := role2.NewRolePowerRelService(rolePowerRelRepo, userRoleRelService)
 	rankService := rank2.NewRankService(userCommon, userRankRepo, objService, userRoleRelService, rolePowerRelService, configService)
 	limitRepo := limit.NewRateLimitRepo(dataData)
-	rateLimitMiddleware := middleware.NewRateLimitMiddleware(limitRepo)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(limitRepo, serviceConf)
 	commentController := controller.NewCommentController(commentService, rankService, captchaService, rateLimitMiddleware)
 	reportRepo := report.NewReportRepo(dataData, uniqueIDRepo)
-	tagService := tag2.NewTagService(tagRepo, tagCommonService, revisionService, followRepo, siteInfoCommonService, activityQueueService)
+	tagService := tag2.NewTagService(tagRepo, tagCommonService, revisionService, followRepo, siteInfoCommonService, activityQueueService, activityRepo, questionRepo)
 	answerActivityRepo := activity.NewAnswerActivityRepo(dataData, activityRepo, userRankRepo, notificationQueueService)
 	answerActivityService := activity2.NewAnswerActivityService(answerActivityRepo, configService)
-	externalNotificationService := notification.NewExternalNotificationService(dataData, userNotificationConfigRepo, followRepo, emailService, userRepo, externalNotificationQueueService, userExternalLoginRepo, siteInfoCommonService)
+	externalNotificationService := notification.NewExternalNotificationService(dataData, userNotificationConfigRepo, followRepo, emailService, userRepo, externalNotificationQueueService, userExternalLoginRepo, siteInfoCommonService, tagCommonService)
 	reviewRepo := review.NewReviewRepo(dataData)
 	reviewService := review2.NewReviewService(reviewRepo, objService, userCommon, userRepo, questionRepo, answerRepo, userRoleRelService, externalNotificationQueueService, tagCommonService, questionCommon, notificationQueueService, siteInfoCommonService)
-	questionService := content.NewQuestionService(activityRepo, questionRepo, answerRepo, tagCommonService, tagService, questionCommon, userCommon, userRepo, userRoleRelService, revisionService, metaCommonService, collectionCommon, answerActivityService, emailService, notificationQueueService, externalNotificationQueueService, activityQueueService, siteInfoCommonService, externalNotificationService, reviewService, configService, eventQueueService, reviewRepo)
-	answerService := content.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo, emailService, userRoleRelService, notificationQueueService, externalNotificationQueueService, activityQueueService, reviewService, eventQueueService)
+	bountyRepo := bounty.NewBountyRepo(dataData, userRankRepo)
+	jobRepo := cron_job.NewCronJobRepo(dataData)
+	scheduledTaskManager := cron.NewScheduledTaskManager(jobRepo)
+	bountyService := bounty2.NewBountyService(bountyRepo, questionRepo, answerRepo, activityRepo, userCommon, notificationQueueService, scheduledTaskManager)
+	draftRepo := draft.NewDraftRepo(dataData)
+	draftService := draft2.NewDraftService(draftRepo, questionRepo, answerRepo, scheduledTaskManager)
+	questionService := content.NewQuestionService(activityRepo, questionRepo, answerRepo, tagCommonService, tagService, questionCommon, userCommon, userRepo, userRoleRelService, revisionService, metaCommonService, collectionCommon, answerActivityService, emailService, notificationQueueService, externalNotificationQueueService, activityQueueService, siteInfoCommonService, externalNotificationService, reviewService, configService, eventQueueService, reviewRepo, rolePowerRelService, bountyService, draftService, scheduledTaskManager)
+	answerService := content.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo, emailService, userRoleRelService, notificationQueueService, externalNotificationQueueService, activityQueueService, reviewService, eventQueueService, rolePowerRelService, draftService)
 	reportHandle := report_handle.NewReportHandle(questionService, answerService, commentService)
 	reportService := report2.NewReportService(reportRepo, objService, userCommon, answerRepo, questionRepo, commentCommonRepo, reportHandle, configService, eventQueueService)
-	reportController := controller.NewReportController(reportService, rankService, captchaService)
+	auditLogRepo := audit_log.NewAuditLogRepo(dataData)
+	auditLogService := audit_log2.NewAuditLogService(auditLogRepo, userCommon)
+	reportController := controller.NewReportController(reportService, rankService, captchaService, auditLogService)
 	contentVoteRepo := activity.NewVoteRepo(dataData, activityRepo, userRankRepo, notificationQueueService)
 	voteService := content.NewVoteService(contentVoteRepo, configService, questionRepo, answerRepo, commentCommonRepo, objService, eventQueueService)
 	voteController := controller.NewVoteController(voteService, rankService, captchaService)
-	tagController := controller.NewTagController(tagService, tagCommonService, rankService)
+	tagController := controller.NewTagController(tagService, tagCommonService, rankService, auditLogService)
 	followFollowRepo := activity.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
 	followService := follow.NewFollowService(followFollowRepo, followRepo, tagCommonRepo)
 	followController := controller.NewFollowController(followService)
 	collectionGroupRepo := collection.NewCollectionGroupRepo(dataData)
 	collectionService := collection2.NewCollectionService(collectionRepo, collectionGroupRepo, questionCommon)
 	collectionController := controller.NewCollectionController(collectionService)
-	questionController := controller.NewQuestionController(questionService, answerService, rankService, siteInfoCommonService, captchaService, rateLimitMiddleware)
-	answerController := controller.NewAnswerController(answerService, rankService, captchaService, siteInfoCommonService, rateLimitMiddleware)
+	questionController := controller.NewQuestionController(questionService, answerService, rankService, siteInfoCommonService, captchaService, rateLimitMiddleware, auditLogService)
+	answerController := controller.NewAnswerController(answerService, rankService, captchaService, siteInfoCommonService, rateLimitMiddleware, auditLogService)
 	searchParser := search_parser.NewSearchParser(tagCommonService, userCommon)
 	searchRepo := search_common.NewSearchRepo(dataData, uniqueIDRepo, userCommon, tagCommonService)
-	searchService := content.NewSearchService(searchParser, searchRepo)
+	searchService := content.NewSearchService(searchParser, searchRepo, tagCommonService, userCommon)
 	searchController := controller.NewSearchController(searchService, captchaService)
 	reviewActivityRepo := activity.NewReviewActivityRepo(dataData, activityRepo, userRankRepo, configService)
 	contentRevisionService := content.NewRevisionService(revisionRepo, userCommon, questionCommon, answerService, objService, questionRepo, answerRepo, tagRepo, tagCommonService, notificationQueueService, activityQueueService, reportRepo, reviewService, reviewActivityRepo)
@@ -229,19 +267,19 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	rankController := controller.NewRankController(rankService)
 	userAdminRepo := user.NewUserAdminRepo(dataData, authRepo)
 	userAdminService := user_admin.NewUserAdminService(userAdminRepo, userRoleRelService, authService, userCommon, userActiveActivityRepo, siteInfoCommonService, emailService, questionRepo, answerRepo, commentCommonRepo, userExternalLoginRepo)
-	userAdminController := controller_admin.NewUserAdminController(userAdminService)
+	userAdminController := controller_admin.NewUserAdminController(userAdminService, twoFactorService, auditLogService)
 	reasonRepo := reason.NewReasonRepo(configService)
 	reasonService := reason2.NewReasonService(reasonRepo)
 	reasonController := controller.NewReasonController(reasonService)
 	themeController := controller_admin.NewThemeController()
 	siteInfoService := siteinfo.NewSiteInfoService(siteInfoRepo, siteInfoCommonService, emailService, tagCommonService, configService, questionCommon)
-	siteInfoController := controller_admin.NewSiteInfoController(siteInfoService)
+	siteInfoController := controller_admin.NewSiteInfoController(siteInfoService, auditLogService)
 	controllerSiteInfoController := controller.NewSiteInfoController(siteInfoCommonService)
 	notificationRepo := notification2.NewNotificationRepo(dataData)
 	notificationCommon := notificationcommon.NewNotificationCommon(dataData, notificationRepo, userCommon, activityRepo, followRepo, objService, notificationQueueService, userExternalLoginRepo, siteInfoCommonService)
 	badgeRepo := badge.NewBadgeRepo(dataData, uniqueIDRepo)
 	notificationService := notification.NewNotificationService(dataData, notificationRepo, notificationCommon, revisionService, userRepo, reportRepo, reviewService, badgeRepo)
-	notificationController := controller.NewNotificationController(notificationService, rankService)
+	notificationPushService := notification_push.NewNotificationPushService(dataData, notificationQueueService, eventQueueService)
 	dashboardService := dashboard.NewDashboardService(questionRepo, answerRepo, commentCommonRepo, voteRepo, userRepo, reportRepo, configService, siteInfoCommonService, serviceConf, reviewService, revisionRepo, dataData)
 	dashboardController := controller.NewDashboardController(dashboardService)
 	uploaderService := uploader.NewUploaderService(serviceConf, siteInfoCommonService)
@@ -254,12 +292,13 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	roleController := controller_admin.NewRoleController(roleService)
 	pluginConfigRepo := plugin_config.NewPluginConfigRepo(dataData)
 	pluginUserConfigRepo := plugin_config.NewPluginUserConfigRepo(dataData)
-	importerService := importer.NewImporterService(questionService, rankService, userCommon)
+	importRecordRepo := import_record.NewImportRecordRepo(dataData)
+	importerService := importer.NewImporterService(questionService, answerService, commentService, voteService, questionRepo, rankService, userCommon, importRecordRepo, siteInfoCommonService)
 	pluginCommonService := plugin_common.NewPluginCommonService(pluginConfigRepo, pluginUserConfigRepo, configService, dataData, importerService)
-	pluginController := controller_admin.NewPluginController(pluginCommonService)
+	pluginController := controller_admin.NewPluginController(pluginCommonService, auditLogService)
 	permissionController := controller.NewPermissionController(rankService)
 	userPluginController := controller.NewUserPluginController(pluginCommonService)
-	reviewController := controller.NewReviewController(reviewService, rankService, captchaService)
+	reviewController := controller.NewReviewController(reviewService, rankService, captchaService, auditLogService)
 	metaService := meta2.NewMetaService(metaCommonService, userCommon, answerRepo, questionRepo, eventQueueService)
 	metaController := controller.NewMetaController(metaService)
 	badgeGroupRepo := badge_group.NewBadgeGroupRepo(dataData, uniqueIDRepo)
@@ -270,25 +309,52 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	badgeService := badge2.NewBadgeService(badgeRepo, badgeGroupRepo, badgeAwardRepo, badgeEventService, siteInfoCommonService)
 	badgeController := controller.NewBadgeController(badgeService, badgeAwardService)
 	controller_adminBadgeController := controller_admin.NewBadgeController(badgeService)
-	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, userAdminController, reasonController, themeController, siteInfoController, controllerSiteInfoController, notificationController, dashboardController, uploadController, activityController, roleController, pluginController, permissionController, userPluginController, reviewController, metaController, badgeController, controller_adminBadgeController)
+	webhookRepo := webhook.NewWebhookRepo(dataData)
+	webhookDeliveryRepo := webhook.NewWebhookDeliveryRepo(dataData)
+	webhookService := webhook2.NewWebhookService(webhookRepo, webhookDeliveryRepo, eventQueueService, messageRepo, serviceConf)
+	webhookController := controller_admin.NewWebhookController(webhookService)
+	apiKeyRepo := api_key.NewAPIKeyRepo(dataData)
+	apiKeyService := api_key2.NewAPIKeyService(apiKeyRepo, userRepo, userRoleRelService, rolePowerRelService)
+	notificationController := controller.NewNotificationController(notificationService, notificationPushService, rankService, authService, apiKeyService)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+	controller_adminAPIKeyController := controller_admin.NewAPIKeyController(apiKeyService)
+	notificationDigestRepo := notification_digest.NewNotificationDigestRepo(dataData)
+	notificationDigestService := notification_digest2.NewNotificationDigestService(notificationDigestRepo, userNotificationConfigRepo, followRepo, questionRepo, notificationRepo, userRepo, emailService, siteInfoCommonService, scheduledTaskManager)
+	savedSearchRepo := saved_search.NewSavedSearchRepo(dataData)
+	savedSearchService := saved_search2.NewSavedSearchService(savedSearchRepo, searchService, userRepo, notificationQueueService, notificationCommon, emailService, siteInfoCommonService, scheduledTaskManager)
+	cronJobController := controller_admin.NewCronJobController(scheduledTaskManager)
+	twoFactorController := controller.NewTwoFactorController(twoFactorService)
+	auditLogController := controller_admin.NewAuditLogController(auditLogService)
+	questionMergeRepo := question_merge.NewQuestionMergeRepo(dataData)
+	questionMergeService := question_merge2.NewQuestionMergeService(questionMergeRepo, questionRepo, answerRepo, questionCommon, metaCommonService, configService, siteInfoCommonService, activityQueueService, notificationQueueService, answerActivityService)
+	questionMergeController := controller.NewQuestionMergeController(questionMergeService, auditLogService, rankService)
+	bountyController := controller.NewBountyController(bountyService, rankService)
+	draftController := controller.NewDraftController(draftService)
+	feedRepo := feed.NewFeedRepo(dataData)
+	feedService := feed2.NewFeedService(feedRepo, userRepo)
+	feedController := controller.NewFeedController(feedService)
+	searchReindexRepo := search_sync.NewSearchReindexRepo(dataData)
+	searchReindexService := search_reindex.NewSearchReindexService(searchReindexRepo)
+	searchReindexController := controller_admin.NewSearchReindexController(searchReindexService)
+	savedSearchController := controller.NewSavedSearchController(savedSearchService)
+	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, userAdminController, reasonController, themeController, siteInfoController, controllerSiteInfoController, notificationController, dashboardController, uploadController, activityController, roleController, pluginController, permissionController, userPluginController, reviewController, metaController, badgeController, controller_adminBadgeController, webhookController, apiKeyController, controller_adminAPIKeyController, cronJobController, twoFactorController, auditLogController, questionMergeController, bountyController, draftController, feedController, searchReindexController, savedSearchController)
 	swaggerRouter := router.NewSwaggerRouter(swaggerConf)
 	uiRouter := router.NewUIRouter(controllerSiteInfoController, siteInfoCommonService)
-	authUserMiddleware := middleware.NewAuthUserMiddleware(authService, siteInfoCommonService)
+	authUserMiddleware := middleware.NewAuthUserMiddleware(authService, apiKeyService, rolePowerRelService, userRoleRelService, siteInfoCommonService)
 	avatarMiddleware := middleware.NewAvatarMiddleware(serviceConf, uploaderService)
 	shortIDMiddleware := middleware.NewShortIDMiddleware(siteInfoCommonService)
-	templateRenderController := templaterender.NewTemplateRenderController(questionService, userService, tagService, answerService, commentService, siteInfoCommonService, questionRepo)
-	templateController := controller.NewTemplateController(templateRenderController, siteInfoCommonService, eventQueueService, userService, questionService)
+	templateRenderController := templaterender.NewTemplateRenderController(questionService, userService, tagService, answerService, commentService, siteInfoCommonService, questionRepo, searchService)
+	templateController := controller.NewTemplateController(templateRenderController, siteInfoCommonService, eventQueueService, userService, questionService, questionMergeService, feedService)
 	templateRouter := router.NewTemplateRouter(templateController, templateRenderController, siteInfoController, authUserMiddleware)
 	connectorController := controller.NewConnectorController(siteInfoCommonService, emailService, userExternalLoginService)
-	userCenterLoginService := user_external_login2.NewUserCenterLoginService(userRepo, userCommon, userExternalLoginRepo, userActiveActivityRepo, siteInfoCommonService)
+	userCenterLoginService := user_external_login2.NewUserCenterLoginService(userRepo, userCommon, userExternalLoginRepo, userActiveActivityRepo, siteInfoCommonService, twoFactorService)
 	userCenterController := controller.NewUserCenterController(userCenterLoginService, siteInfoCommonService)
 	captchaController := controller.NewCaptchaController()
 	embedController := controller.NewEmbedController()
 	renderController := controller.NewRenderController()
 	pluginAPIRouter := router.NewPluginAPIRouter(connectorController, userCenterController, captchaController, embedController, renderController)
-	ginEngine := server.NewHTTPServer(debug, staticRouter, answerAPIRouter, swaggerRouter, uiRouter, authUserMiddleware, avatarMiddleware, shortIDMiddleware, templateRouter, pluginAPIRouter, uiConf)
-	scheduledTaskManager := cron.NewScheduledTaskManager(siteInfoCommonService, questionService)
-	application := newApplication(serverConf, ginEngine, scheduledTaskManager)
+	ginEngine := server.NewHTTPServer(debug, staticRouter, answerAPIRouter, swaggerRouter, uiRouter, authUserMiddleware, avatarMiddleware, shortIDMiddleware, rateLimitMiddleware, templateRouter, pluginAPIRouter, serverConf.HTTP, uiConf)
+	application := newApplication(serverConf, ginEngine, scheduledTaskManager, notificationDigestService)
 	return application, func() {
 		cleanup2()
 		cleanup()
@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DumpAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	sourceConf := &data.Database{Driver: "sqlite3", Connection: filepath.Join(dir, "source.db")}
+	targetConf := &data.Database{Driver: "sqlite3", Connection: filepath.Join(dir, "target.db")}
+	sourceUpload := filepath.Join(dir, "source_uploads")
+	targetUpload := filepath.Join(dir, "target_uploads")
+
+	sourceUserCount := initSourceDB(t, sourceConf)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceUpload, "avatar"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceUpload, "avatar", "a.png"), []byte("avatar"), 0o644))
+
+	dumpPath := filepath.Join(dir, "dump")
+	require.NoError(t, DumpAllData(sourceConf, sourceUpload, dumpPath))
+	archives, err := filepath.Glob(filepath.Join(dumpPath, "answer_dump_*.tar.gz"))
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+
+	require.NoError(t, RestoreData(targetConf, targetUpload, archives[0]))
+
+	targetDB, err := data.NewDB(false, targetConf)
+	require.NoError(t, err)
+	defer targetDB.Close()
+	targetUserCount, err := targetDB.Count(&entity.User{})
+	require.NoError(t, err)
+	assert.Equal(t, sourceUserCount, targetUserCount)
+	version, err := migrations.GetCurrentDBVersion(targetDB)
+	require.NoError(t, err)
+	assert.Equal(t, migrations.ExpectedVersion(), version)
+
+	content, err := os.ReadFile(filepath.Join(targetUpload, "avatar", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "avatar", string(content))
+
+	// restore to a database that is not empty must fail
+	assert.Error(t, RestoreData(targetConf, targetUpload, archives[0]))
+}
+
+func Test_MigrateDB(t *testing.T) {
+	dir := t.TempDir()
+	sourceConf := &data.Database{Driver: "sqlite3", Connection: filepath.Join(dir, "source.db")}
+	targetConf := &data.Database{Driver: "sqlite3", Connection: filepath.Join(dir, "target.db")}
+	sourceUserCount := initSourceDB(t, sourceConf)
+
+	require.NoError(t, MigrateDB(sourceConf, targetConf))
+
+	targetDB, err := data.NewDB(false, targetConf)
+	require.NoError(t, err)
+	defer targetDB.Close()
+	targetUserCount, err := targetDB.Count(&entity.User{})
+	require.NoError(t, err)
+	assert.Equal(t, sourceUserCount, targetUserCount)
+	version, err := migrations.GetCurrentDBVersion(targetDB)
+	require.NoError(t, err)
+	assert.Equal(t, migrations.ExpectedVersion(), version)
+	// the full-text tables are not entities, they must be created in the target database too
+	for _, table := range []string{"question_fts", "answer_fts"} {
+		exist, err := targetDB.IsTableExist(table)
+		require.NoError(t, err)
+		assert.True(t, exist, table)
+	}
+
+	// migrate to a database that is not empty must fail
+	assert.Error(t, MigrateDB(sourceConf, targetConf))
+}
+
+func initSourceDB(t *testing.T, dataConf *data.Database) (userCount int64) {
+	db, err := data.NewDB(false, dataConf)
+	require.NoError(t, err)
+	defer db.Close()
+	err = migrations.NewMentor(context.TODO(), db, &migrations.InitNeedUserInputData{
+		Language:      "en_US",
+		SiteName:      "ANSWER",
+		SiteURL:       "http://127.0.0.1:8080/",
+		ContactEmail:  "answer@answer.com",
+		AdminName:     "admin",
+		AdminPassword: "admin",
+		AdminEmail:    "answer@answer.com",
+	}).InitDB()
+	require.NoError(t, err)
+	userCount, err = db.Count(&entity.User{})
+	require.NoError(t, err)
+	return userCount
+}
@@ -0,0 +1,236 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/migrations"
+	"xorm.io/xorm"
+)
+
+const (
+	// DumpFormatVersion the version of dump archive format
+	DumpFormatVersion = 1
+
+	dumpManifestName = "manifest.json"
+	dumpTableDir     = "tables"
+	dumpUploadDir    = "uploads"
+	dumpBatchSize    = 1000
+)
+
+// DumpManifest describes the content of dump archive, it is used to check the integrity when restoring
+type DumpManifest struct {
+	FormatVersion int               `json:"format_version"`
+	DBVersion     int64             `json:"db_version"`
+	Driver        string            `json:"driver"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Tables        []*DumpTableInfo  `json:"tables"`
+	Uploads       map[string]string `json:"uploads"`
+}
+
+// DumpTableInfo the row count and checksum of one table
+type DumpTableInfo struct {
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// DumpAllData dump all database data and uploaded files to a driver-neutral archive.
+// Each table is saved as a JSON lines file, so that it can be restored to any supported database.
+func DumpAllData(dataConf *data.Database, uploadPath, dumpDataPath string) error {
+	db, err := data.NewDB(false, dataConf)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		return err
+	}
+
+	dbVersion, err := migrations.GetCurrentDBVersion(db)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dumpDataPath, os.ModePerm); err != nil {
+		return err
+	}
+	name := filepath.Join(dumpDataPath, fmt.Sprintf("answer_dump_%s.tar.gz", time.Now().Format("2006-01-02_150405")))
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gw)
+
+	manifest := &DumpManifest{
+		FormatVersion: DumpFormatVersion,
+		DBVersion:     dbVersion,
+		Driver:        dataConf.Driver,
+		CreatedAt:     time.Now(),
+		Uploads:       make(map[string]string),
+	}
+	for _, bean := range migrations.GetTables() {
+		tableInfo, err := dumpTable(db, tw, bean)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[dump] table %s: %d rows\n", tableInfo.Name, tableInfo.Rows)
+		manifest.Tables = append(manifest.Tables, tableInfo)
+	}
+	if err = dumpUploads(tw, uploadPath, manifest); err != nil {
+		return err
+	}
+	fmt.Printf("[dump] uploads: %d files\n", len(manifest.Uploads))
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = writeTarFile(tw, dumpManifestName, content); err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	if err = gw.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("[dump] archive saved to %s\n", name)
+	return nil
+}
+
+// dumpTable write all rows of the table as JSON lines to a temp file, then add it to the archive
+func dumpTable(db *xorm.Engine, tw *tar.Writer, bean any) (tableInfo *DumpTableInfo, err error) {
+	tableName := db.TableName(bean)
+	tableInfo = &DumpTableInfo{Name: tableName}
+	tmp, err := os.CreateTemp("", "answer-dump-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	encoder := json.NewEncoder(io.MultiWriter(tmp, hash))
+	session := db.Table(bean).BufferSize(dumpBatchSize)
+	if pks := tablePrimaryKeys(db, bean); len(pks) > 0 {
+		session = session.Asc(pks...)
+	}
+	err = session.Iterate(newBean(bean), func(_ int, row any) error {
+		tableInfo.Rows++
+		return encoder.Encode(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dump table %s failed: %w", tableName, err)
+	}
+	tableInfo.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	stat, err := tmp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	header := &tar.Header{
+		Name:    filepath.ToSlash(filepath.Join(dumpTableDir, tableName+".jsonl")),
+		Mode:    0o644,
+		Size:    stat.Size(),
+		ModTime: time.Now(),
+	}
+	if err = tw.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(tw, tmp); err != nil {
+		return nil, err
+	}
+	return tableInfo, nil
+}
+
+// dumpUploads add all uploaded files to the archive
+func dumpUploads(tw *tar.Writer, uploadPath string, manifest *DumpManifest) error {
+	if len(uploadPath) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(uploadPath); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(uploadPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(uploadPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		manifest.Uploads[rel] = hex.EncodeToString(sum[:])
+		return writeTarFile(tw, dumpUploadDir+"/"+rel, content)
+	})
+}
+
+func writeTarFile(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// tablePrimaryKeys get the primary keys of table, the rows are dumped in the order of primary keys
+func tablePrimaryKeys(db *xorm.Engine, bean any) []string {
+	table, err := db.TableInfo(bean)
+	if err != nil {
+		return nil
+	}
+	return table.PrimaryKeys
+}
+
+// newBean create a new empty bean with the same type as the given table bean
+func newBean(bean any) any {
+	return reflect.New(reflect.Indirect(reflect.ValueOf(bean)).Type()).Interface()
+}
@@ -0,0 +1,177 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/migrations"
+	"xorm.io/xorm"
+)
+
+// MigrateDB copy all data from the source database to the target database, the driver of them can be different.
+// The source database must be upgraded to the current version, and the target database must be empty.
+func MigrateDB(fromConf, toConf *data.Database) error {
+	source, err := data.NewDB(false, fromConf)
+	if err != nil {
+		return fmt.Errorf("connect source database failed: %w", err)
+	}
+	defer source.Close()
+	if err = source.Ping(); err != nil {
+		return fmt.Errorf("connect source database failed: %w", err)
+	}
+	target, err := data.NewDB(false, toConf)
+	if err != nil {
+		return fmt.Errorf("connect target database failed: %w", err)
+	}
+	defer target.Close()
+	if err = target.Ping(); err != nil {
+		return fmt.Errorf("connect target database failed: %w", err)
+	}
+
+	version, err := migrations.GetCurrentDBVersion(source)
+	if err != nil {
+		return err
+	}
+	if expected := migrations.ExpectedVersion(); version != expected {
+		return fmt.Errorf("the source database version is %d, but this answer requires %d, "+
+			"please run upgrade command first", version, expected)
+	}
+	exist, err := target.IsTableExist(&entity.Version{})
+	if err != nil {
+		return err
+	}
+	if exist {
+		return fmt.Errorf("the target database already has answer tables, please migrate to an empty database")
+	}
+	if err = initTargetDB(toConf, target, version); err != nil {
+		return fmt.Errorf("create tables failed: %w", err)
+	}
+	fmt.Printf("[migrate-db] tables created in target database (%s)\n", toConf.Driver)
+
+	for _, bean := range migrations.GetTables() {
+		if err = copyTable(source, target, bean); err != nil {
+			return fmt.Errorf("copy table %s failed: %w", source.TableName(bean), err)
+		}
+	}
+	if err = resetSequences(target); err != nil {
+		return err
+	}
+	if err = checkRowCount(source, target); err != nil {
+		return err
+	}
+	targetVersion, err := migrations.GetCurrentDBVersion(target)
+	if err != nil {
+		return err
+	}
+	if targetVersion != version {
+		return fmt.Errorf("the target database version is %d, but the source database version is %d", targetVersion, version)
+	}
+	return nil
+}
+
+// initTargetDB initialize the target database by the migrations up to the version of the source database.
+// The migrations start from an installed database, so the tables of a new installation are created first.
+// The rows added by the migrations are removed, all rows are copied from the source database.
+func initTargetDB(toConf *data.Database, target *xorm.Engine, version int64) error {
+	ctx := context.Background()
+	if err := migrations.InitSchema(ctx, target); err != nil {
+		return err
+	}
+	if _, err := target.Context(ctx).Insert(&entity.Version{ID: 1, VersionNumber: version}); err != nil {
+		return err
+	}
+	if err := migrations.Migrate(false, toConf, &data.CacheConf{}, ""); err != nil {
+		return fmt.Errorf("migrate target database failed: %w", err)
+	}
+	for _, bean := range migrations.GetTables() {
+		if _, err := target.Context(ctx).Where("1 = 1").Delete(bean); err != nil {
+			return fmt.Errorf("clean table %s failed: %w", target.TableName(bean), err)
+		}
+	}
+	return nil
+}
+
+// copyTable copy all rows of the table in batches
+func copyTable(source, target *xorm.Engine, bean any) error {
+	tableName := source.TableName(bean)
+	total, err := source.Table(bean).Count()
+	if err != nil {
+		return err
+	}
+
+	var copied int64
+	batch := make([]any, 0, dumpBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := insertRows(target, bean, batch); err != nil {
+			return err
+		}
+		copied += int64(len(batch))
+		batch = make([]any, 0, dumpBatchSize)
+		fmt.Printf("\r[migrate-db] table %s: %d/%d rows", tableName, copied, total)
+		return nil
+	}
+
+	session := source.Table(bean).BufferSize(dumpBatchSize)
+	if pks := tablePrimaryKeys(source, bean); len(pks) > 0 {
+		session = session.Asc(pks...)
+	}
+	err = session.Iterate(newBean(bean), func(_ int, row any) error {
+		batch = append(batch, row)
+		if len(batch) >= dumpBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\r[migrate-db] table %s: %d/%d rows\n", tableName, copied, total)
+	return nil
+}
+
+// checkRowCount check the row count of all tables are the same in source and target database
+func checkRowCount(source, target *xorm.Engine) error {
+	for _, bean := range migrations.GetTables() {
+		sourceCount, err := source.Table(bean).Count()
+		if err != nil {
+			return err
+		}
+		targetCount, err := target.Table(bean).Count()
+		if err != nil {
+			return err
+		}
+		if sourceCount != targetCount {
+			return fmt.Errorf("row count of table %s mismatch, source: %d, target: %d",
+				source.TableName(bean), sourceCount, targetCount)
+		}
+	}
+	fmt.Println("[migrate-db] row count of all tables checked")
+	return nil
+}
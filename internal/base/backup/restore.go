@@ -0,0 +1,312 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/migrations"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+// RestoreData restore the data from the archive created by DumpAllData.
+// The target database must be empty, the archive can be restored to any supported database driver.
+func RestoreData(dataConf *data.Database, uploadPath, archivePath string) error {
+	tmpDir, err := os.MkdirTemp("", "answer-restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	fmt.Printf("[restore] extracting %s\n", archivePath)
+	if err = extractArchive(archivePath, tmpDir); err != nil {
+		return fmt.Errorf("extract archive failed: %w", err)
+	}
+	manifest, err := readDumpManifest(tmpDir)
+	if err != nil {
+		return err
+	}
+	if err = checkDumpIntegrity(tmpDir, manifest); err != nil {
+		return err
+	}
+	fmt.Printf("[restore] archive checked, db version %d, dumped from %s at %s\n",
+		manifest.DBVersion, manifest.Driver, manifest.CreatedAt.Format("2006-01-02 15:04:05"))
+
+	db, err := data.NewDB(false, dataConf)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		return err
+	}
+	exist, err := db.IsTableExist(&entity.Version{})
+	if err != nil {
+		return err
+	}
+	if exist {
+		return fmt.Errorf("the target database already has answer tables, please restore to an empty database")
+	}
+	if err = migrations.InitSchema(context.Background(), db); err != nil {
+		return fmt.Errorf("create tables failed: %w", err)
+	}
+
+	tableInfoMapping := make(map[string]*DumpTableInfo, len(manifest.Tables))
+	for _, t := range manifest.Tables {
+		tableInfoMapping[t.Name] = t
+	}
+	for _, bean := range migrations.GetTables() {
+		tableName := db.TableName(bean)
+		tableInfo, ok := tableInfoMapping[tableName]
+		if !ok {
+			fmt.Printf("[restore] table %s not found in archive, skip\n", tableName)
+			continue
+		}
+		if err = restoreTable(db, bean, filepath.Join(tmpDir, dumpTableDir, tableName+".jsonl"), tableInfo.Rows); err != nil {
+			return fmt.Errorf("restore table %s failed: %w", tableName, err)
+		}
+	}
+	if err = resetSequences(db); err != nil {
+		return err
+	}
+	if err = restoreUploads(filepath.Join(tmpDir, dumpUploadDir), uploadPath, manifest); err != nil {
+		return err
+	}
+	fmt.Printf("[restore] uploads: %d files\n", len(manifest.Uploads))
+	return nil
+}
+
+// extractArchive extract the tar.gz archive to the target directory
+func extractArchive(archivePath, targetDir string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		target := filepath.Join(targetDir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(targetDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid file path in archive: %s", header.Name)
+		}
+		if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, tr)
+		_ = out.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func readDumpManifest(dir string) (manifest *DumpManifest, err error) {
+	content, err := os.ReadFile(filepath.Join(dir, dumpManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest failed: %w", err)
+	}
+	manifest = &DumpManifest{}
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest failed: %w", err)
+	}
+	if manifest.FormatVersion != DumpFormatVersion {
+		return nil, fmt.Errorf("unsupported dump format version %d, expected %d",
+			manifest.FormatVersion, DumpFormatVersion)
+	}
+	if expected := migrations.ExpectedVersion(); manifest.DBVersion != expected {
+		return nil, fmt.Errorf("the archive db version is %d, but this answer requires %d, "+
+			"please restore it with the same answer version", manifest.DBVersion, expected)
+	}
+	return manifest, nil
+}
+
+// checkDumpIntegrity check the checksum of all table files and uploaded files in archive
+func checkDumpIntegrity(dir string, manifest *DumpManifest) error {
+	for _, t := range manifest.Tables {
+		sum, err := fileSHA256(filepath.Join(dir, dumpTableDir, t.Name+".jsonl"))
+		if err != nil {
+			return err
+		}
+		if sum != t.SHA256 {
+			return fmt.Errorf("checksum of table %s mismatch, the archive may be corrupted", t.Name)
+		}
+	}
+	for name, expected := range manifest.Uploads {
+		sum, err := fileSHA256(filepath.Join(dir, dumpUploadDir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		if sum != expected {
+			return fmt.Errorf("checksum of upload file %s mismatch, the archive may be corrupted", name)
+		}
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// restoreTable insert all rows from the JSON lines file into table in batches
+func restoreTable(db *xorm.Engine, bean any, path string, total int64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var restored int64
+	batch := make([]any, 0, dumpBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := insertRows(db, bean, batch); err != nil {
+			return err
+		}
+		restored += int64(len(batch))
+		batch = batch[:0]
+		fmt.Printf("\r[restore] table %s: %d/%d rows", db.TableName(bean), restored, total)
+		return nil
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			row := newBean(bean)
+			if e := json.Unmarshal(line, row); e != nil {
+				return e
+			}
+			batch = append(batch, row)
+			if len(batch) >= dumpBatchSize {
+				if e := flush(); e != nil {
+					return e
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = flush(); err != nil {
+		return err
+	}
+	if restored != total {
+		return fmt.Errorf("restored %d rows, but the archive has %d rows", restored, total)
+	}
+	fmt.Printf("\r[restore] table %s: %d/%d rows\n", db.TableName(bean), restored, total)
+	return nil
+}
+
+// insertRows insert the rows in one transaction, the original id, created_at and updated_at are kept
+func insertRows(db *xorm.Engine, bean any, rows []any) error {
+	_, err := db.Transaction(func(session *xorm.Session) (any, error) {
+		return session.NoAutoTime().Table(bean).Insert(rows...)
+	})
+	return err
+}
+
+// resetSequences the rows are inserted with explicit id, so the sequences in postgres must be updated
+func resetSequences(db *xorm.Engine) error {
+	if db.Dialect().URI().DBType != schemas.POSTGRES {
+		return nil
+	}
+	for _, bean := range migrations.GetTables() {
+		table, err := db.TableInfo(bean)
+		if err != nil {
+			return err
+		}
+		if len(table.AutoIncrement) == 0 {
+			continue
+		}
+		sql := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE(MAX("%s"), 0) + 1, false) FROM "%s"`,
+			table.Name, table.AutoIncrement, table.AutoIncrement, table.Name)
+		if _, err = db.Exec(sql); err != nil {
+			return fmt.Errorf("reset sequence of table %s failed: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// restoreUploads copy the uploaded files in archive to the upload path
+func restoreUploads(sourceDir, uploadPath string, manifest *DumpManifest) error {
+	for name := range manifest.Uploads {
+		target := filepath.Join(uploadPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(filepath.Join(sourceDir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(target, content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
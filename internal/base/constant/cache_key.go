@@ -50,6 +50,13 @@ const (
 	NewQuestionNotificationLimitMax            = 50
 	RateLimitCacheKeyPrefix                    = "answer:rate-limit:"
 	RateLimitCacheTime                         = 5 * time.Minute
+	RateLimitWindowCacheKeyPrefix              = "answer:rate-limit:window:"
 	RedDotCacheKey                             = "answer:red-dot:%s:%s"
 	RedDotCacheTime                            = 30 * 24 * time.Hour
+	TwoFactorChallengeCacheKey                 = "answer:two-factor:challenge:"
+	TwoFactorChallengeCacheTime                = 5 * time.Minute
+	TwoFactorChallengeAttemptsCacheKey         = "answer:two-factor:challenge-attempts:"
+	PushEventSeqCacheKey                       = "answer:push-event:seq"
+	PushEventCacheKeyPrefix                    = "answer:push-event:"
+	PushEventCacheTime                         = time.Minute
 )
@@ -43,4 +43,10 @@ const (
 
 	EmailTplKeyNewQuestionTitle = "email_tpl.new_question.title"
 	EmailTplKeyNewQuestionBody  = "email_tpl.new_question.body"
+
+	EmailTplKeyDigestTitle = "email_tpl.digest.title"
+	EmailTplKeyDigestBody  = "email_tpl.digest.body"
+
+	EmailTplKeySavedSearchAlertTitle = "email_tpl.saved_search_alert.title"
+	EmailTplKeySavedSearchAlertBody  = "email_tpl.saved_search_alert.body"
 )
@@ -73,3 +73,27 @@ const (
 	EventCommentVote   EventType = eventComment + "." + eventVote
 	EventCommentFlag   EventType = eventComment + "." + eventFlag
 )
+
+// EventTypeList all event types that can be subscribed, such as by webhooks
+var EventTypeList = []EventType{
+	EventUserUpdate,
+	EventUserShare,
+	EventQuestionCreate,
+	EventQuestionUpdate,
+	EventQuestionDelete,
+	EventQuestionVote,
+	EventQuestionAccept,
+	EventQuestionFlag,
+	EventQuestionReact,
+	EventAnswerCreate,
+	EventAnswerUpdate,
+	EventAnswerDelete,
+	EventAnswerVote,
+	EventAnswerFlag,
+	EventAnswerReact,
+	EventCommentCreate,
+	EventCommentUpdate,
+	EventCommentDelete,
+	EventCommentVote,
+	EventCommentFlag,
+}
@@ -54,10 +54,20 @@ const (
 	NotificationYourAnswerWasDeleted = "notification.action.your_answer_was_deleted"
 	// NotificationYourCommentWasDeleted your comment was deleted
 	NotificationYourCommentWasDeleted = "notification.action.your_comment_was_deleted"
+	// NotificationYourQuestionWasMerged your question was merged
+	NotificationYourQuestionWasMerged = "notification.action.your_question_was_merged"
+	// NotificationYourAnswerWasMoved your answer was moved
+	NotificationYourAnswerWasMoved = "notification.action.your_answer_was_moved"
+	// NotificationBountyAwarded bounty awarded
+	NotificationBountyAwarded = "notification.action.bounty_awarded"
+	// NotificationBountyExpired bounty expired
+	NotificationBountyExpired = "notification.action.bounty_expired"
 	// NotificationInvitedYouToAnswer invited you to answer
 	NotificationInvitedYouToAnswer = "notification.action.invited_you_to_answer"
 	// NotificationEarnedBadge earned badge
 	NotificationEarnedBadge = "notification.action.earned_badge"
+	// NotificationSavedSearchMatched new content matched the saved search
+	NotificationSavedSearchMatched = "notification.action.saved_search_matched"
 )
 
 type NotificationChannelKey string
@@ -67,6 +77,7 @@ const (
 	InboxSource                          NotificationSource = "inbox"
 	AllNewQuestionSource                 NotificationSource = "all_new_question"
 	AllNewQuestionForFollowingTagsSource NotificationSource = "all_new_question_for_following_tags"
+	DigestSource                         NotificationSource = "digest"
 )
 
 const (
@@ -98,6 +109,11 @@ var (
 		NotificationYourQuestionWasDeleted: 1,
 		NotificationYourAnswerWasDeleted:   1,
 		NotificationYourCommentWasDeleted:  1,
+		NotificationYourQuestionWasMerged:  1,
+		NotificationYourAnswerWasMoved:     1,
+		NotificationBountyAwarded:          1,
+		NotificationBountyExpired:          1,
 		NotificationInvitedYouToAnswer:     3,
+		NotificationSavedSearchMatched:     1,
 	}
 )
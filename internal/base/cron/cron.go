@@ -22,52 +22,290 @@ package cron
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
 
-	"github.com/apache/incubator-answer/internal/service/content"
-	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/pkg/token"
+	"github.com/apache/incubator-answer/plugin"
 	"github.com/robfig/cron/v3"
+	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
 )
 
-// ScheduledTaskManager scheduled task manager
+const defaultJobTimeout = time.Hour
+
+// Job scheduled job
+type Job struct {
+	// Name the unique name of the job
+	Name string
+	// Spec the cron expression, such as "0 */1 * * *"
+	Spec        string
+	Description string
+	// Timeout the max duration of one execution, it is also the duration of the lease. Default is 1 hour.
+	Timeout time.Duration
+	// RunOnStart run the job once when the application starts
+	RunOnStart bool
+	Run        func(ctx context.Context) error
+}
+
+// JobRepo scheduled job repository
+type JobRepo interface {
+	AddJobIfNotExist(ctx context.Context, name string) (err error)
+	GetJobList(ctx context.Context) (jobs []*entity.CronJob, err error)
+	GetJob(ctx context.Context, name string) (job *entity.CronJob, exist bool, err error)
+	UpdateJobPaused(ctx context.Context, name string, paused bool) (err error)
+	AcquireLease(ctx context.Context, name, owner string, leaseExpiredAt, scheduledAt time.Time) (acquired bool, err error)
+	ReleaseLease(ctx context.Context, job *entity.CronJob) (err error)
+}
+
+type registeredJob struct {
+	*Job
+	entryID cron.EntryID
+}
+
+// ScheduledTaskManager scheduled task manager.
+// Jobs are registered by name, and the job state is stored in the database,
+// so that a job runs on only one instance at a time and can be paused for all instances.
+// The services register their own jobs when they are created.
 type ScheduledTaskManager struct {
-	siteInfoService siteinfo_common.SiteInfoCommonService
-	questionService *content.QuestionService
+	jobRepo JobRepo
+	cron    *cron.Cron
+	owner   string
+	lock    sync.RWMutex
+	jobs    map[string]*registeredJob
+	started bool
 }
 
 // NewScheduledTaskManager new scheduled task manager
-func NewScheduledTaskManager(
-	siteInfoService siteinfo_common.SiteInfoCommonService,
-	questionService *content.QuestionService,
-) *ScheduledTaskManager {
-	manager := &ScheduledTaskManager{
-		siteInfoService: siteInfoService,
-		questionService: questionService,
+func NewScheduledTaskManager(jobRepo JobRepo) *ScheduledTaskManager {
+	hostname, _ := os.Hostname()
+	return &ScheduledTaskManager{
+		jobRepo: jobRepo,
+		cron:    cron.New(),
+		owner:   fmt.Sprintf("%s-%s", hostname, token.GenerateToken()),
+		jobs:    make(map[string]*registeredJob),
+	}
+}
+
+// Register register a job, the job will be scheduled immediately if the manager has been started
+func (s *ScheduledTaskManager) Register(job *Job) error {
+	if len(job.Name) == 0 || job.Run == nil {
+		return fmt.Errorf("cron job name and run function are required")
+	}
+	if _, err := cron.ParseStandard(job.Spec); err != nil {
+		return fmt.Errorf("cron job %s spec is invalid: %w", job.Name, err)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = defaultJobTimeout
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("cron job %s is already registered", job.Name)
+	}
+	s.jobs[job.Name] = &registeredJob{Job: job}
+	if s.started {
+		return s.schedule(context.Background(), s.jobs[job.Name])
 	}
-	return manager
+	return nil
 }
 
+// Run start all registered jobs
 func (s *ScheduledTaskManager) Run() {
-	fmt.Println("start cron")
-	s.questionService.SitemapCron(context.Background())
-	c := cron.New()
-	_, err := c.AddFunc("0 */1 * * *", func() {
-		ctx := context.Background()
-		fmt.Println("sitemap cron execution")
-		s.questionService.SitemapCron(ctx)
+	log.Info("start cron")
+	s.registerPluginJobs()
+
+	ctx := context.Background()
+	s.lock.Lock()
+	for _, job := range s.jobs {
+		if err := s.schedule(ctx, job); err != nil {
+			log.Error(err)
+		}
+	}
+	s.started = true
+	s.lock.Unlock()
+
+	s.cron.Start()
+}
+
+func (s *ScheduledTaskManager) registerPluginJobs() {
+	_ = plugin.CallCronJob(func(fn plugin.CronJob) error {
+		slugName := fn.Info().SlugName
+		for _, info := range fn.CronJobs() {
+			info := info
+			err := s.Register(&Job{
+				Name:        slugName + "." + info.Name,
+				Spec:        info.Spec,
+				Description: info.Description,
+				Run: func(ctx context.Context) error {
+					if !plugin.StatusManager.IsEnabled(slugName) {
+						return nil
+					}
+					return info.Run(ctx)
+				},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+		}
+		return nil
+	})
+}
+
+// schedule add the job to cron, the caller must hold the lock
+func (s *ScheduledTaskManager) schedule(ctx context.Context, job *registeredJob) (err error) {
+	if err = s.jobRepo.AddJobIfNotExist(ctx, job.Name); err != nil {
+		return err
+	}
+	job.entryID, err = s.cron.AddFunc(job.Spec, func() {
+		// the cron spec is accurate to the minute, so all instances get the same scheduled time
+		s.execute(context.Background(), job.Job, time.Now().Truncate(time.Minute))
 	})
 	if err != nil {
-		log.Error(err)
+		return fmt.Errorf("schedule cron job %s failed: %w", job.Name, err)
+	}
+	if job.RunOnStart {
+		go s.execute(ctx, job.Job, time.Time{})
 	}
+	return nil
+}
 
-	_, err = c.AddFunc("0 */1 * * *", func() {
-		ctx := context.Background()
-		fmt.Println("refresh hottest cron execution")
-		s.questionService.RefreshHottestCron(ctx)
-	})
+// execute run the job if the job is not paused and this instance gets the lease.
+// The scheduled time is claimed with the lease, so that it will not run again on other instances.
+// Zero scheduled time means the job is not run by schedule, such as run on start.
+func (s *ScheduledTaskManager) execute(ctx context.Context, job *Job, scheduledAt time.Time) {
+	state, exist, err := s.jobRepo.GetJob(ctx, job.Name)
+	if err != nil {
+		log.Errorf("get cron job %s failed: %v", job.Name, err)
+		return
+	}
+	if exist && state.Paused {
+		log.Debugf("cron job %s is paused", job.Name)
+		return
+	}
+	acquired, err := s.jobRepo.AcquireLease(ctx, job.Name, s.owner, time.Now().Add(job.Timeout), scheduledAt)
 	if err != nil {
-		log.Error(err)
+		log.Errorf("acquire cron job %s lease failed: %v", job.Name, err)
+		return
 	}
+	if !acquired {
+		log.Debugf("cron job %s is running or has run on other instance", job.Name)
+		return
+	}
+	s.run(ctx, job)
+}
+
+// run the job and record the result, the lease must be held by this instance
+func (s *ScheduledTaskManager) run(ctx context.Context, job *Job) {
+	log.Infof("cron job %s execution", job.Name)
+	startAt := time.Now()
+	err := s.call(ctx, job)
+	state := &entity.CronJob{
+		Name:         job.Name,
+		LeaseOwner:   s.owner,
+		LastRunAt:    startAt,
+		LastDuration: time.Since(startAt).Milliseconds(),
+		LastStatus:   entity.CronJobStatusSuccess,
+	}
+	if err != nil {
+		log.Errorf("cron job %s failed: %v", job.Name, err)
+		state.LastStatus = entity.CronJobStatusFailed
+		state.LastError = err.Error()
+	}
+	if err = s.jobRepo.ReleaseLease(context.WithoutCancel(ctx), state); err != nil {
+		log.Errorf("release cron job %s lease failed: %v", job.Name, err)
+	}
+}
 
-	c.Start()
+func (s *ScheduledTaskManager) call(ctx context.Context, job *Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cron job %s panic: %v", job.Name, r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// GetJobList get all registered jobs with their state
+func (s *ScheduledTaskManager) GetJobList(ctx context.Context) (resp []*schema.CronJobInfo, err error) {
+	states, err := s.jobRepo.GetJobList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stateMapping := make(map[string]*entity.CronJob, len(states))
+	for _, state := range states {
+		stateMapping[state.Name] = state
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	resp = make([]*schema.CronJobInfo, 0, len(s.jobs))
+	now := time.Now()
+	for _, job := range s.jobs {
+		info := &schema.CronJobInfo{
+			Name:        job.Name,
+			Spec:        job.Spec,
+			Description: job.Description,
+		}
+		if next := s.cron.Entry(job.entryID).Next; !next.IsZero() {
+			info.NextRunAt = next.Unix()
+		}
+		if state := stateMapping[job.Name]; state != nil {
+			info.Paused = state.Paused
+			info.Running = state.LeaseExpiredAt.After(now)
+			info.LastStatus = schema.CronJobStatusMap[state.LastStatus]
+			info.LastError = state.LastError
+			info.LastDuration = state.LastDuration
+			if !state.LastRunAt.IsZero() {
+				info.LastRunAt = state.LastRunAt.Unix()
+			}
+		}
+		resp = append(resp, info)
+	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Name < resp[j].Name })
+	return resp, nil
+}
+
+// TriggerJob run the job immediately even if the job is paused
+func (s *ScheduledTaskManager) TriggerJob(ctx context.Context, req *schema.TriggerCronJobReq) (err error) {
+	job, err := s.getJob(req.Name)
+	if err != nil {
+		return err
+	}
+	acquired, err := s.jobRepo.AcquireLease(ctx, job.Name, s.owner, time.Now().Add(job.Timeout), time.Time{})
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return errors.BadRequest(reason.CronJobRunning)
+	}
+	go s.run(context.WithoutCancel(ctx), job)
+	return nil
+}
+
+// UpdateJobStatus pause or resume the job for all instances
+func (s *ScheduledTaskManager) UpdateJobStatus(ctx context.Context, req *schema.UpdateCronJobStatusReq) (err error) {
+	job, err := s.getJob(req.Name)
+	if err != nil {
+		return err
+	}
+	return s.jobRepo.UpdateJobPaused(ctx, job.Name, req.Paused)
+}
+
+func (s *ScheduledTaskManager) getJob(name string) (job *Job, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	registered, ok := s.jobs[name]
+	if !ok {
+		return nil, errors.BadRequest(reason.CronJobNotFound)
+	}
+	return registered.Job, nil
 }
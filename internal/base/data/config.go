@@ -28,7 +28,26 @@ type Database struct {
 	MaxIdleConn     int    `json:"max_idle_conn" mapstructure:"max_idle_conn" yaml:"max_idle_conn,omitempty"`
 }
 
+const (
+	CacheTypeMemory = "memory"
+	CacheTypeRedis  = "redis"
+)
+
 // CacheConf cache
 type CacheConf struct {
-	FilePath string `json:"file_path" mapstructure:"file_path" yaml:"file_path"`
+	// Type cache type, memory or redis, default is memory
+	Type     string     `json:"type" mapstructure:"type" yaml:"type,omitempty"`
+	FilePath string     `json:"file_path" mapstructure:"file_path" yaml:"file_path"`
+	Redis    *RedisConf `json:"redis" mapstructure:"redis" yaml:"redis,omitempty"`
+}
+
+// RedisConf redis cache config
+type RedisConf struct {
+	Addr     string `json:"addr" mapstructure:"addr" yaml:"addr"`
+	Password string `json:"password" mapstructure:"password" yaml:"password,omitempty"`
+	DB       int    `json:"db" mapstructure:"db" yaml:"db,omitempty"`
+	// TLS connect to redis with tls
+	TLS bool `json:"tls" mapstructure:"tls" yaml:"tls,omitempty"`
+	// KeyPrefix the prefix of all keys, so that multiple sites can share one redis database
+	KeyPrefix string `json:"key_prefix" mapstructure:"key_prefix" yaml:"key_prefix,omitempty"`
 }
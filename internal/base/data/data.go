@@ -20,6 +20,7 @@
 package data
 
 import (
+	"context"
 	"path/filepath"
 	"time"
 
@@ -94,6 +95,11 @@ func NewDB(debug bool, dataConf *Database) (*xorm.Engine, error) {
 	return engine, nil
 }
 
+// CounterCache the cache that can increase a counter and set its ttl atomically
+type CounterCache interface {
+	IncreaseWithTTL(ctx context.Context, key string, value int64, ttl time.Duration) (data int64, err error)
+}
+
 // NewCache new cache instance
 func NewCache(c *CacheConf) (cache.Cache, func(), error) {
 	var pluginCache plugin.Cache
@@ -105,7 +111,10 @@ func NewCache(c *CacheConf) (cache.Cache, func(), error) {
 		return pluginCache, func() {}, nil
 	}
 
-	// TODO What cache type should be initialized according to the configuration file
+	if c.Type == CacheTypeRedis {
+		return NewRedisCache(c.Redis)
+	}
+
 	memCache := memory.NewCache()
 
 	if len(c.FilePath) > 0 {
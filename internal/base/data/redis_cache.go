@@ -0,0 +1,158 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentfault/pacman/cache"
+	"github.com/segmentfault/pacman/log"
+)
+
+// redisCache cache implemented by redis, so that the cache can be shared by multiple instances
+type redisCache struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisCache new redis cache instance
+func NewRedisCache(c *RedisConf) (cache.Cache, func(), error) {
+	if c == nil || len(c.Addr) == 0 {
+		return nil, nil, fmt.Errorf("redis address is required when the cache type is redis")
+	}
+	opts := &redis.Options{
+		Addr:     c.Addr,
+		Password: c.Password,
+		DB:       c.DB,
+	}
+	if c.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("connect to redis %s failed: %w", c.Addr, err)
+	}
+	cleanup := func() {
+		log.Info("closing the redis cache")
+		if err := client.Close(); err != nil {
+			log.Warn(err)
+		}
+	}
+	return &redisCache{client: client, keyPrefix: c.KeyPrefix}, cleanup, nil
+}
+
+// GetString get string value by key
+func (rc *redisCache) GetString(ctx context.Context, key string) (data string, exist bool, err error) {
+	data, err = rc.client.Get(ctx, rc.keyPrefix+key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return data, true, nil
+}
+
+// SetString set string value, zero ttl means never expire
+func (rc *redisCache) SetString(ctx context.Context, key, value string, ttl time.Duration) (err error) {
+	return rc.client.Set(ctx, rc.keyPrefix+key, value, ttl).Err()
+}
+
+// GetInt64 get int64 value by key
+func (rc *redisCache) GetInt64(ctx context.Context, key string) (data int64, exist bool, err error) {
+	value, exist, err := rc.GetString(ctx, key)
+	if err != nil || !exist {
+		return 0, exist, err
+	}
+	data, err = strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return data, true, nil
+}
+
+// SetInt64 set int64 value, zero ttl means never expire
+func (rc *redisCache) SetInt64(ctx context.Context, key string, value int64, ttl time.Duration) (err error) {
+	return rc.client.Set(ctx, rc.keyPrefix+key, value, ttl).Err()
+}
+
+// Increase increase the value of key and return the new value, the ttl of key will be kept
+func (rc *redisCache) Increase(ctx context.Context, key string, value int64) (data int64, err error) {
+	return rc.client.IncrBy(ctx, rc.keyPrefix+key, value).Result()
+}
+
+// increaseWithTTLScript increases the counter and sets the ttl when the counter is created
+var increaseWithTTLScript = redis.NewScript(`
+local count = redis.call("INCRBY", KEYS[1], ARGV[1])
+if count == tonumber(ARGV[1]) then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return count
+`)
+
+// IncreaseWithTTL increase the value of key atomically, the ttl is set if the key is created by this increment
+func (rc *redisCache) IncreaseWithTTL(ctx context.Context, key string, value int64, ttl time.Duration) (
+	data int64, err error) {
+	return increaseWithTTLScript.Run(ctx, rc.client, []string{rc.keyPrefix + key}, value, ttl.Milliseconds()).Int64()
+}
+
+// Decrease decrease the value of key and return the new value, the ttl of key will be kept
+func (rc *redisCache) Decrease(ctx context.Context, key string, value int64) (data int64, err error) {
+	return rc.client.DecrBy(ctx, rc.keyPrefix+key, value).Result()
+}
+
+// Del delete key
+func (rc *redisCache) Del(ctx context.Context, key string) (err error) {
+	return rc.client.Del(ctx, rc.keyPrefix+key).Err()
+}
+
+// Flush delete all keys with the key prefix, or the whole database if there is no key prefix
+func (rc *redisCache) Flush(ctx context.Context) (err error) {
+	if len(rc.keyPrefix) == 0 {
+		return rc.client.FlushDB(ctx).Err()
+	}
+	iter := rc.client.Scan(ctx, 0, rc.keyPrefix+"*", 1000).Iterator()
+	keys := make([]string, 0)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) < 1000 {
+			continue
+		}
+		if err = rc.client.Del(ctx, keys...).Err(); err != nil {
+			return err
+		}
+		keys = keys[:0]
+	}
+	if err = iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return rc.client.Del(ctx, keys...).Err()
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c, cleanup, err := NewCache(&CacheConf{
+		Type:  CacheTypeRedis,
+		Redis: &RedisConf{Addr: mr.Addr(), KeyPrefix: "answer-test:"},
+	})
+	assert.NoError(t, err)
+	defer cleanup()
+	ctx := context.TODO()
+
+	_, exist, err := c.GetString(ctx, "string")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	err = c.SetString(ctx, "string", "value", time.Minute)
+	assert.NoError(t, err)
+	value, exist, err := c.GetString(ctx, "string")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "value", value)
+	assert.True(t, mr.Exists("answer-test:string"))
+
+	err = c.SetInt64(ctx, "count", 1, time.Minute)
+	assert.NoError(t, err)
+	count, err := c.Increase(ctx, "count", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	count, err = c.Decrease(ctx, "count", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	count, exist, err = c.GetInt64(ctx, "count")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, time.Minute, mr.TTL("answer-test:count"))
+
+	mr.FastForward(2 * time.Minute)
+	_, exist, err = c.GetInt64(ctx, "count")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	counter, ok := c.(CounterCache)
+	assert.True(t, ok)
+	count, err = counter.IncreaseWithTTL(ctx, "window", 1, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = counter.IncreaseWithTTL(ctx, "window", 1, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, time.Minute, mr.TTL("answer-test:window"))
+
+	err = c.Del(ctx, "string")
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists("answer-test:string"))
+
+	assert.NoError(t, mr.Set("other", "value"))
+	assert.NoError(t, c.SetString(ctx, "a", "1", 0))
+	assert.NoError(t, c.SetString(ctx, "b", "2", 0))
+	err = c.Flush(ctx)
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists("answer-test:a"))
+	assert.False(t, mr.Exists("answer-test:b"))
+	assert.True(t, mr.Exists("other"))
+}
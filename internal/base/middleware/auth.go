@@ -21,9 +21,13 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/api_key"
+	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/role"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
 	"github.com/apache/incubator-answer/ui"
@@ -38,20 +42,32 @@ import (
 	"github.com/segmentfault/pacman/log"
 )
 
-var ctxUUIDKey = "ctxUuidKey"
+var (
+	ctxUUIDKey   = "ctxUuidKey"
+	ctxPowersKey = "ctxPowersKey"
+)
 
 // AuthUserMiddleware auth user middleware
 type AuthUserMiddleware struct {
 	authService           *auth.AuthService
+	apiKeyService         *api_key.APIKeyService
+	rolePowerRelService   *role.RolePowerRelService
+	userRoleService       *role.UserRoleRelService
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService
 }
 
 // NewAuthUserMiddleware new auth user middleware
 func NewAuthUserMiddleware(
 	authService *auth.AuthService,
+	apiKeyService *api_key.APIKeyService,
+	rolePowerRelService *role.RolePowerRelService,
+	userRoleService *role.UserRoleRelService,
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService) *AuthUserMiddleware {
 	return &AuthUserMiddleware{
 		authService:           authService,
+		apiKeyService:         apiKeyService,
+		rolePowerRelService:   rolePowerRelService,
+		userRoleService:       userRoleService,
 		siteInfoCommonService: siteInfoCommonService,
 	}
 }
@@ -64,13 +80,26 @@ func (am *AuthUserMiddleware) Auth() gin.HandlerFunc {
 			ctx.Next()
 			return
 		}
+		if api_key.IsAPIKeyToken(token) {
+			userInfo, err := am.getAPIKeyUserCacheInfo(ctx, token)
+			if err != nil {
+				handler.HandleResponse(ctx, err, nil)
+				ctx.Abort()
+				return
+			}
+			if userInfo != nil {
+				am.setUserInfo(ctx, userInfo)
+			}
+			ctx.Next()
+			return
+		}
 		userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
 		if err != nil {
 			ctx.Next()
 			return
 		}
 		if userInfo != nil {
-			ctx.Set(ctxUUIDKey, userInfo)
+			am.setUserInfo(ctx, userInfo)
 		}
 		ctx.Next()
 	}
@@ -116,8 +145,13 @@ func (am *AuthUserMiddleware) MustAuthWithoutAccountAvailable() gin.HandlerFunc
 			ctx.Abort()
 			return
 		}
-		userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
-		if err != nil || userInfo == nil {
+		userInfo, err := am.getUserCacheInfo(ctx, token)
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			ctx.Abort()
+			return
+		}
+		if userInfo == nil {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
 			return
@@ -127,7 +161,7 @@ func (am *AuthUserMiddleware) MustAuthWithoutAccountAvailable() gin.HandlerFunc
 			ctx.Abort()
 			return
 		}
-		ctx.Set(ctxUUIDKey, userInfo)
+		am.setUserInfo(ctx, userInfo)
 		ctx.Next()
 	}
 }
@@ -141,8 +175,13 @@ func (am *AuthUserMiddleware) MustAuthAndAccountAvailable() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
-		if err != nil || userInfo == nil {
+		userInfo, err := am.getUserCacheInfo(ctx, token)
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			ctx.Abort()
+			return
+		}
+		if userInfo == nil {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
 			return
@@ -164,7 +203,7 @@ func (am *AuthUserMiddleware) MustAuthAndAccountAvailable() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		ctx.Set(ctxUUIDKey, userInfo)
+		am.setUserInfo(ctx, userInfo)
 		ctx.Next()
 	}
 }
@@ -177,7 +216,29 @@ func (am *AuthUserMiddleware) AdminAuth() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		userInfo, err := am.authService.GetAdminUserCacheInfo(ctx, token)
+		var (
+			userInfo *entity.UserCacheInfo
+			err      error
+		)
+		if api_key.IsAPIKeyToken(token) {
+			userInfo, err = am.getAPIKeyUserCacheInfo(ctx, token)
+			if err != nil {
+				handler.HandleResponse(ctx, err, nil)
+				ctx.Abort()
+				return
+			}
+			if userInfo != nil {
+				isAdmin, err := am.rolePowerRelService.RoleHasPower(ctx, userInfo.RoleID, permission.AdminAccess)
+				if err != nil || !isAdmin {
+					userInfo = nil
+				}
+			}
+		} else {
+			userInfo, err = am.authService.GetAdminUserCacheInfo(ctx, token)
+			if err == nil && userInfo != nil {
+				userInfo, err = am.checkAdminRole(ctx, token, userInfo)
+			}
+		}
 		if err != nil || userInfo == nil {
 			handler.HandleResponse(ctx, errors.Forbidden(reason.UnauthorizedError), nil)
 			ctx.Abort()
@@ -189,12 +250,76 @@ func (am *AuthUserMiddleware) AdminAuth() gin.HandlerFunc {
 				ctx.Abort()
 				return
 			}
-			ctx.Set(ctxUUIDKey, userInfo)
+			am.setUserInfo(ctx, userInfo)
 		}
 		ctx.Next()
 	}
 }
 
+// checkAdminRole check the current role of the admin user, the role may be changed or deleted, or lose the admin
+// power after login. The admin session is removed if the user is no longer an admin.
+func (am *AuthUserMiddleware) checkAdminRole(ctx *gin.Context, token string, userInfo *entity.UserCacheInfo) (
+	adminInfo *entity.UserCacheInfo, err error) {
+	roleID, err := am.userRoleService.GetUserRole(ctx, userInfo.UserID)
+	if err != nil {
+		return nil, err
+	}
+	isAdmin, err := am.rolePowerRelService.RoleHasPower(ctx, roleID, permission.AdminAccess)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		if err = am.authService.RemoveAdminUserCacheInfo(ctx, token); err != nil {
+			log.Error(err)
+		}
+		return nil, nil
+	}
+	userInfo.RoleID = roleID
+	return userInfo, nil
+}
+
+// setUserInfo set the user info to the context, the powers of the user's role are loaded when they are needed
+func (am *AuthUserMiddleware) setUserInfo(ctx *gin.Context, userInfo *entity.UserCacheInfo) {
+	ctx.Set(ctxUUIDKey, userInfo)
+	ctx.Set(ctxPowersKey, sync.OnceValue(func() []string {
+		powers, err := am.rolePowerRelService.GetRolePowerList(ctx, userInfo.RoleID)
+		if err != nil {
+			log.Error(err)
+		}
+		return powers
+	}))
+}
+
+// getUserCacheInfo get user info by token, the token can be a login token or an api key
+func (am *AuthUserMiddleware) getUserCacheInfo(ctx *gin.Context, token string) (
+	userInfo *entity.UserCacheInfo, err error) {
+	if api_key.IsAPIKeyToken(token) {
+		return am.getAPIKeyUserCacheInfo(ctx, token)
+	}
+	userInfo, err = am.authService.GetUserCacheInfo(ctx, token)
+	if err != nil {
+		return nil, errors.Unauthorized(reason.UnauthorizedError)
+	}
+	return userInfo, nil
+}
+
+// getAPIKeyUserCacheInfo get user info by api key and check the api key has the scope required by the request
+func (am *AuthUserMiddleware) getAPIKeyUserCacheInfo(ctx *gin.Context, token string) (
+	userInfo *entity.UserCacheInfo, err error) {
+	userInfo, apiKey, err := am.apiKeyService.GetUserCacheInfo(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if userInfo == nil {
+		return nil, errors.Unauthorized(reason.UnauthorizedError)
+	}
+	scope := api_key.RequiredScope(ctx.Request.Method, ctx.Request.URL.Path)
+	if len(scope) == 0 || !apiKey.HasScope(scope) {
+		return nil, errors.Forbidden(reason.APIKeyScopeInsufficient)
+	}
+	return userInfo, nil
+}
+
 func (am *AuthUserMiddleware) CheckPrivateMode() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		resp, err := am.siteInfoCommonService.GetSiteLogin(ctx)
@@ -234,11 +359,20 @@ func GetLoginUserIDFromContext(ctx *gin.Context) (userID string) {
 
 // GetIsAdminFromContext get user is admin from context
 func GetIsAdminFromContext(ctx *gin.Context) (isAdmin bool) {
-	userInfo := GetUserInfoFromContext(ctx)
-	if userInfo == nil {
+	return UserHasPower(ctx, permission.AdminAccess)
+}
+
+// UserHasPower check the login user has the power by the role of user
+func UserHasPower(ctx *gin.Context, powerType string) (has bool) {
+	value, exist := ctx.Get(ctxPowersKey)
+	if !exist {
 		return false
 	}
-	return userInfo.RoleID == role.RoleAdminID
+	powers, ok := value.(func() []string)
+	if !ok {
+		return false
+	}
+	return slices.Contains(powers(), powerType)
 }
 
 // GetUserInfoFromContext get user info from context
@@ -254,19 +388,9 @@ func GetUserInfoFromContext(ctx *gin.Context) (u *entity.UserCacheInfo) {
 	return u
 }
 
+// GetUserIsAdminModerator check the login user has the power to moderate the content, such as admin and moderator
 func GetUserIsAdminModerator(ctx *gin.Context) (isAdminModerator bool) {
-	userInfo, exist := ctx.Get(ctxUUIDKey)
-	if !exist {
-		return false
-	}
-	u, ok := userInfo.(*entity.UserCacheInfo)
-	if !ok {
-		return false
-	}
-	if u.RoleID == role.RoleAdminID || u.RoleID == role.RoleModeratorID {
-		return true
-	}
-	return false
+	return UserHasPower(ctx, permission.ContentModerate)
 }
 
 func GetLoginUserIDInt64FromContext(ctx *gin.Context) (userID int64) {
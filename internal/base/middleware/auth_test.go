@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/permission"
+	"github.com/apache/incubator-answer/internal/service/role"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCustomRoleID = 10
+
+type fakeRolePowerRelRepo struct {
+	rolePowers map[int][]string
+}
+
+func (r *fakeRolePowerRelRepo) GetRolePowerTypeList(_ context.Context, roleID int) ([]string, error) {
+	return r.rolePowers[roleID], nil
+}
+
+func (r *fakeRolePowerRelRepo) GetRoleIDListByPowerTypes(_ context.Context, _ []string) ([]int, error) {
+	return nil, nil
+}
+
+func newTestAuthUserMiddleware() *AuthUserMiddleware {
+	repo := &fakeRolePowerRelRepo{rolePowers: map[int][]string{
+		role.RoleAdminID:     {permission.AdminAccess, permission.ContentModerate},
+		role.RoleModeratorID: {permission.ContentModerate},
+		testCustomRoleID:     {permission.ContentModerate},
+	}}
+	return &AuthUserMiddleware{rolePowerRelService: role.NewRolePowerRelService(repo, nil)}
+}
+
+func newTestUserContext(am *AuthUserMiddleware, roleID int) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if am != nil {
+		am.setUserInfo(ctx, &entity.UserCacheInfo{UserID: "1", RoleID: roleID})
+	}
+	return ctx
+}
+
+func TestUserHasPower(t *testing.T) {
+	am := newTestAuthUserMiddleware()
+
+	ctx := newTestUserContext(nil, 0)
+	assert.False(t, GetUserIsAdminModerator(ctx))
+	assert.False(t, GetIsAdminFromContext(ctx))
+
+	ctx = newTestUserContext(am, role.RoleUserID)
+	assert.False(t, GetUserIsAdminModerator(ctx))
+	assert.False(t, GetIsAdminFromContext(ctx))
+
+	ctx = newTestUserContext(am, role.RoleAdminID)
+	assert.True(t, GetUserIsAdminModerator(ctx))
+	assert.True(t, GetIsAdminFromContext(ctx))
+
+	ctx = newTestUserContext(am, role.RoleModeratorID)
+	assert.True(t, GetUserIsAdminModerator(ctx))
+	assert.False(t, GetIsAdminFromContext(ctx))
+
+	// the custom role which holds the power is regarded as moderator
+	ctx = newTestUserContext(am, testCustomRoleID)
+	assert.True(t, GetUserIsAdminModerator(ctx))
+	assert.False(t, GetIsAdminFromContext(ctx))
+	assert.True(t, UserHasPower(ctx, permission.ContentModerate))
+}
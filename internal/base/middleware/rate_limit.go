@@ -22,24 +22,178 @@ package middleware
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/apache/incubator-answer/internal/base/handler"
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/repo/limit"
+	"github.com/apache/incubator-answer/internal/service/service_config"
 	"github.com/apache/incubator-answer/pkg/encryption"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
 )
 
+// defaultRateLimitRules used when no rule is configured
+var defaultRateLimitRules = []*service_config.RateLimitRule{
+	{
+		Name:      "api",
+		Window:    60,
+		Anonymous: 300,
+		User:      600,
+	},
+	{
+		Name:      "search",
+		Paths:     []string{"/answer/api/v1/search"},
+		Methods:   []string{http.MethodGet},
+		Window:    60,
+		Anonymous: 20,
+		User:      60,
+	},
+	{
+		Name:    "post",
+		Paths:   []string{"/answer/api/v1/question", "/answer/api/v1/answer", "/answer/api/v1/comment"},
+		Methods: []string{http.MethodPost},
+		Window:  60,
+		User:    10,
+	},
+}
+
 type RateLimitMiddleware struct {
 	limitRepo *limit.LimitRepo
+	disabled  bool
+	rules     []*service_config.RateLimitRule
 }
 
 // NewRateLimitMiddleware new rate limit middleware
-func NewRateLimitMiddleware(limitRepo *limit.LimitRepo) *RateLimitMiddleware {
-	return &RateLimitMiddleware{
+func NewRateLimitMiddleware(limitRepo *limit.LimitRepo, serviceConfig *service_config.ServiceConfig) *RateLimitMiddleware {
+	rm := &RateLimitMiddleware{
 		limitRepo: limitRepo,
+		rules:     defaultRateLimitRules,
+	}
+	if conf := serviceConfig.RateLimit; conf != nil {
+		rm.disabled = conf.Disabled
+		if len(conf.Rules) > 0 {
+			rm.rules = conf.Rules
+		}
+	}
+	return rm
+}
+
+// RateLimit limits the request rate by the rules, the user is limited by user id and the visitor is limited by ip.
+// It must be used after the auth middleware, so that the user info can be got from context.
+func (rm *RateLimitMiddleware) RateLimit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if rm.disabled {
+			ctx.Next()
+			return
+		}
+		subject, quotaOf := "ip:"+ctx.ClientIP(), anonymousQuota
+		if userInfo := GetUserInfoFromContext(ctx); userInfo != nil {
+			subject, quotaOf = "user:"+userInfo.UserID, userQuota
+			if GetIsAdminFromContext(ctx) {
+				ctx.Next()
+				return
+			}
+			if GetUserIsAdminModerator(ctx) {
+				quotaOf = moderatorQuota
+			}
+		}
+
+		now := time.Now()
+		var (
+			headerSet bool
+			remaining int64
+		)
+		for _, rule := range rm.rules {
+			quota := quotaOf(rule)
+			if quota <= 0 || rule.Window <= 0 || !rateLimitRuleMatched(rule, ctx.Request.Method, ctx.Request.URL.Path) {
+				continue
+			}
+			allowed, left, resetAt := rm.allow(ctx, rule, subject, quota, now)
+			if !allowed || !headerSet || left < remaining {
+				headerSet, remaining = true, left
+				ctx.Header("X-RateLimit-Limit", strconv.Itoa(quota))
+				ctx.Header("X-RateLimit-Remaining", strconv.FormatInt(left, 10))
+				ctx.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+			}
+			if !allowed {
+				retryAfter := int(math.Ceil(resetAt.Sub(now).Seconds()))
+				ctx.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+				handler.HandleResponse(ctx, errors.New(http.StatusTooManyRequests, reason.TooManyRequestsError), nil)
+				ctx.Abort()
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// allow checks the request with the sliding window counter.
+// The count of the previous window is weighted by its overlap with the sliding window.
+// The request is counted before checking, so that concurrent requests can not exceed the quota together,
+// and the count is given back if the request is rejected.
+func (rm *RateLimitMiddleware) allow(ctx *gin.Context, rule *service_config.RateLimitRule, subject string,
+	quota int, now time.Time) (allowed bool, remaining int64, resetAt time.Time) {
+	window := time.Duration(rule.Window) * time.Second
+	current := now.Truncate(window)
+	resetAt = current.Add(window)
+	keyPrefix := fmt.Sprintf("%s:%s:", rule.Name, subject)
+
+	previousCount, err := rm.limitRepo.GetWindowCount(ctx, keyPrefix+strconv.FormatInt(current.Add(-window).Unix(), 10))
+	if err != nil {
+		log.Errorf("get rate limit window count error: %s", err.Error())
+		return true, int64(quota), resetAt
+	}
+	currentKey := keyPrefix + strconv.FormatInt(current.Unix(), 10)
+	currentCount, err := rm.limitRepo.IncreaseWindowCount(ctx, currentKey, 2*window)
+	if err != nil {
+		log.Errorf("increase rate limit window count error: %s", err.Error())
+		return true, int64(quota), resetAt
+	}
+	weight := 1 - float64(now.Sub(current))/float64(window)
+	estimated := int64(math.Floor(float64(previousCount)*weight)) + currentCount
+	if estimated > int64(quota) {
+		if err = rm.limitRepo.DecreaseWindowCount(ctx, currentKey); err != nil {
+			log.Errorf("decrease rate limit window count error: %s", err.Error())
+		}
+		return false, 0, resetAt
+	}
+	return true, int64(quota) - estimated, resetAt
+}
+
+func anonymousQuota(rule *service_config.RateLimitRule) int { return rule.Anonymous }
+
+func userQuota(rule *service_config.RateLimitRule) int { return rule.User }
+
+func moderatorQuota(rule *service_config.RateLimitRule) int { return rule.Moderator }
+
+func rateLimitRuleMatched(rule *service_config.RateLimitRule, method, path string) bool {
+	if len(rule.Methods) > 0 {
+		matched := false
+		for _, m := range rule.Methods {
+			if strings.EqualFold(m, method) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(rule.Paths) == 0 {
+		return true
+	}
+	for _, prefix := range rule.Paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
 	}
+	return false
 }
 
 // DuplicateRequestRejection detects and rejects duplicate requests
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/limit"
+	"github.com/apache/incubator-answer/internal/service/role"
+	"github.com/apache/incubator-answer/internal/service/service_config"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/contrib/cache/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimitEngine(rules []*service_config.RateLimitRule, userInfo *entity.UserCacheInfo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	limitRepo := limit.NewRateLimitRepo(&data.Data{Cache: memory.NewCache()})
+	rm := NewRateLimitMiddleware(limitRepo, &service_config.ServiceConfig{
+		RateLimit: &service_config.RateLimitConfig{Rules: rules},
+	})
+	r := gin.New()
+	_ = r.SetTrustedProxies(nil)
+	am := newTestAuthUserMiddleware()
+	r.Use(func(ctx *gin.Context) {
+		if userInfo != nil {
+			am.setUserInfo(ctx, userInfo)
+		}
+	}, rm.RateLimit())
+	r.GET("/answer/api/v1/search", func(ctx *gin.Context) { ctx.String(http.StatusOK, "OK") })
+	return r
+}
+
+func doRateLimitRequest(r *gin.Engine, remoteAddr string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/answer/api/v1/search", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware_RateLimit(t *testing.T) {
+	r := newTestRateLimitEngine([]*service_config.RateLimitRule{
+		{Name: "search", Window: 3600, Anonymous: 2, User: 5},
+	}, nil)
+
+	w := doRateLimitRequest(r, "10.0.0.1:1234", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+	assert.NoError(t, err)
+	assert.Greater(t, reset, time.Now().Unix())
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	w = doRateLimitRequest(r, "10.0.0.1:1234", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = doRateLimitRequest(r, "10.0.0.1:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, retryAfter, 1)
+	assert.LessOrEqual(t, retryAfter, 3600)
+
+	// another client has its own budget
+	w = doRateLimitRequest(r, "10.0.0.2:1234", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitMiddleware_RoleQuota(t *testing.T) {
+	rules := []*service_config.RateLimitRule{{Name: "search", Window: 3600, User: 1, Moderator: 2}}
+
+	r := newTestRateLimitEngine(rules, &entity.UserCacheInfo{UserID: "1", RoleID: role.RoleUserID})
+	assert.Equal(t, "1", doRateLimitRequest(r, "10.0.0.1:1234", nil).Header().Get("X-RateLimit-Limit"))
+
+	// the custom role with the content moderate power has the moderator quota
+	r = newTestRateLimitEngine(rules, &entity.UserCacheInfo{UserID: "2", RoleID: testCustomRoleID})
+	assert.Equal(t, "2", doRateLimitRequest(r, "10.0.0.1:1234", nil).Header().Get("X-RateLimit-Limit"))
+
+	// admin is never limited
+	r = newTestRateLimitEngine(rules, &entity.UserCacheInfo{UserID: "3", RoleID: role.RoleAdminID})
+	for i := 0; i < 3; i++ {
+		w := doRateLimitRequest(r, "10.0.0.1:1234", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitMiddleware_ForwardedForNotTrusted(t *testing.T) {
+	r := newTestRateLimitEngine([]*service_config.RateLimitRule{
+		{Name: "search", Window: 3600, Anonymous: 1},
+	}, nil)
+
+	w := doRateLimitRequest(r, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.1.1.1"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the forged header does not give the client a new budget
+	w = doRateLimitRequest(r, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "2.2.2.2"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestRateLimitMiddleware_Concurrent(t *testing.T) {
+	r := newTestRateLimitEngine([]*service_config.RateLimitRule{
+		{Name: "search", Window: 3600, User: 10},
+	}, &entity.UserCacheInfo{UserID: "1", RoleID: 1})
+
+	var (
+		wg      sync.WaitGroup
+		lock    sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := doRateLimitRequest(r, "10.0.0.1:1234", nil)
+			if w.Code == http.StatusOK {
+				lock.Lock()
+				allowed++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, allowed)
+}
@@ -0,0 +1,344 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/service_config"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	defaultWorkers      = 1
+	defaultMaxAttempts  = 8
+	defaultPollInterval = 5 * time.Second
+	// lockDuration the message will be redelivered if the handler does not finish within this duration
+	lockDuration   = 5 * time.Minute
+	baseRetryDelay = 10 * time.Second
+	maxRetryDelay  = time.Hour
+)
+
+// MessageRepo persisted queue message repository
+type MessageRepo interface {
+	AddMessage(ctx context.Context, msg *entity.QueueMessage) (err error)
+	ClaimMessages(ctx context.Context, queueName string, limit int, lockFor time.Duration) (
+		messages []*entity.QueueMessage, err error)
+	RemoveMessage(ctx context.Context, id int64) (err error)
+	RetryMessage(ctx context.Context, msg *entity.QueueMessage) (err error)
+	DeadMessage(ctx context.Context, msg *entity.QueueMessage) (err error)
+}
+
+// permanentError the error that can never succeed by retrying
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent mark the error returned by handler as permanent, such as the object of the message does not exist.
+// The message failed with permanent error is moved to the dead letter state at once without retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent check the error is permanent or not. If the errors are joined, all of them must be permanent.
+func IsPermanent(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		for _, e := range errs {
+			if !IsPermanent(e) {
+				return false
+			}
+		}
+		return len(errs) > 0
+	}
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+type attemptKey struct{}
+
+type attempt struct {
+	messageID   int64
+	attempts    int
+	maxAttempts int
+}
+
+// MessageID returns the id of the message being handled, it is the same for all attempts of the message.
+// The handler can use it to skip the work that has been done in the previous attempts. Zero means not in a queue.
+func MessageID(ctx context.Context) int64 {
+	if a, ok := ctx.Value(attemptKey{}).(attempt); ok {
+		return a.messageID
+	}
+	return 0
+}
+
+// Attempts returns the number of times the message being handled has been delivered, starting from 1
+func Attempts(ctx context.Context) int {
+	if a, ok := ctx.Value(attemptKey{}).(attempt); ok {
+		return a.attempts
+	}
+	return 0
+}
+
+// IsLastAttempt reports whether the message being handled will be moved to the dead letter state if the handler fails
+func IsLastAttempt(ctx context.Context) bool {
+	if a, ok := ctx.Value(attemptKey{}).(attempt); ok {
+		return a.attempts >= a.maxAttempts
+	}
+	return false
+}
+
+type namedHandler[T any] struct {
+	name    string
+	handler func(ctx context.Context, msg T) error
+}
+
+// Queue is a persisted queue with at-least-once delivery.
+// Messages are stored in the queue_message table before being handled, so pending messages survive a restart.
+// Each message is delivered to every registered handler. The handlers that finished are recorded in the message,
+// so only the failed handlers are called again when retrying.
+// Failed messages are retried with an exponential backoff (see RetryDelay) and moved to the dead letter state
+// after max attempts, or at once if the error is permanent. This is the only retry schedule of a message,
+// handlers should return the error rather than retrying by themselves.
+type Queue[T any] struct {
+	name         string
+	repo         MessageRepo
+	workers      int
+	maxAttempts  int
+	pollInterval time.Duration
+	handlers     []namedHandler[T]
+	handlersLock sync.RWMutex
+	notify       chan struct{}
+	startOnce    sync.Once
+}
+
+// New create a new persisted queue
+func New[T any](name string, repo MessageRepo, conf *service_config.QueueConfig) *Queue[T] {
+	q := &Queue[T]{
+		name:         name,
+		repo:         repo,
+		workers:      defaultWorkers,
+		maxAttempts:  defaultMaxAttempts,
+		pollInterval: defaultPollInterval,
+		notify:       make(chan struct{}, 1),
+	}
+	if conf != nil {
+		if conf.Workers > 0 {
+			q.workers = conf.Workers
+		}
+		if conf.MaxAttempts > 0 {
+			q.maxAttempts = conf.MaxAttempts
+		}
+		if conf.PollInterval > 0 {
+			q.pollInterval = time.Duration(conf.PollInterval) * time.Second
+		}
+	}
+	return q
+}
+
+// Send persists the message, it will be handled asynchronously
+func (q *Queue[T]) Send(ctx context.Context, msg T) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Errorf("marshal %s queue message failed: %v", q.name, err)
+		return
+	}
+	err = q.repo.AddMessage(context.WithoutCancel(ctx), &entity.QueueMessage{
+		QueueName: q.name,
+		Payload:   string(payload),
+		Status:    entity.QueueMessageStatusPending,
+		NextRunAt: time.Now(),
+	})
+	if err != nil {
+		log.Errorf("add %s queue message failed: %v", q.name, err)
+		return
+	}
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// RegisterHandler register the handler and start consuming.
+// Every message is handled by all registered handlers, and it will be redelivered only to the failed ones.
+// The handler is identified by its function name, so the name must be stable between restarts.
+// Messages sent before the first handler registered are kept in the table until then.
+func (q *Queue[T]) RegisterHandler(handler func(ctx context.Context, msg T) error) {
+	q.handlersLock.Lock()
+	q.handlers = append(q.handlers, namedHandler[T]{name: q.handlerName(handler), handler: handler})
+	q.handlersLock.Unlock()
+	q.startOnce.Do(func() {
+		go q.working()
+	})
+}
+
+// handlerName returns the function name of the handler, a suffix is added if the same function is registered twice
+func (q *Queue[T]) handlerName(handler func(ctx context.Context, msg T) error) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	count := 0
+	for _, h := range q.handlers {
+		if h.name == name || strings.HasPrefix(h.name, name+"#") {
+			count++
+		}
+	}
+	if count > 0 {
+		name = fmt.Sprintf("%s#%d", name, count+1)
+	}
+	return name
+}
+
+func (q *Queue[T]) working() {
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+	for {
+		q.consume()
+		select {
+		case <-ticker.C:
+		case <-q.notify:
+		}
+	}
+}
+
+// consume claims and handles messages until there is no available message
+func (q *Queue[T]) consume() {
+	for {
+		messages, err := q.repo.ClaimMessages(context.Background(), q.name, q.workers, lockDuration)
+		if err != nil {
+			log.Errorf("claim %s queue messages failed: %v", q.name, err)
+			return
+		}
+		if len(messages) == 0 {
+			return
+		}
+		wg := &sync.WaitGroup{}
+		for _, message := range messages {
+			wg.Add(1)
+			go func(message *entity.QueueMessage) {
+				defer wg.Done()
+				q.handle(message)
+			}(message)
+		}
+		wg.Wait()
+	}
+}
+
+func (q *Queue[T]) handle(message *entity.QueueMessage) {
+	ctx := context.Background()
+	var msg T
+	if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil {
+		log.Errorf("unmarshal %s queue message %d failed: %v", q.name, message.ID, err)
+		message.LastError = err.Error()
+		if err = q.repo.DeadMessage(ctx, message); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	log.Debugf("received %s queue message %+v", q.name, msg)
+
+	ctx = context.WithValue(ctx, attemptKey{}, attempt{
+		messageID:   message.ID,
+		attempts:    message.Attempts,
+		maxAttempts: q.maxAttempts,
+	})
+	done, err := q.call(ctx, msg, message.GetDoneHandlers())
+	if err == nil {
+		if err = q.repo.RemoveMessage(ctx, message.ID); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	log.Errorf("handle %s queue message %d failed, attempts %d: %v", q.name, message.ID, message.Attempts, err)
+	message.SetDoneHandlers(done)
+	message.LastError = err.Error()
+	if IsPermanent(err) || message.Attempts >= q.maxAttempts {
+		err = q.repo.DeadMessage(ctx, message)
+	} else {
+		message.NextRunAt = time.Now().Add(RetryDelay(message.Attempts))
+		err = q.repo.RetryMessage(ctx, message)
+	}
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// call calls the handlers that have not finished, returns the names of all finished handlers.
+// The handler failed with a permanent error is regarded as finished, it would never succeed by retrying.
+func (q *Queue[T]) call(ctx context.Context, msg T, done []string) ([]string, error) {
+	q.handlersLock.RLock()
+	handlers := q.handlers
+	q.handlersLock.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if slices.Contains(done, h.name) {
+			continue
+		}
+		err := callHandler(ctx, h.handler, msg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+		if err == nil || IsPermanent(err) {
+			done = append(done, h.name)
+		}
+	}
+	return done, errors.Join(errs...)
+}
+
+func callHandler[T any](ctx context.Context, handler func(ctx context.Context, msg T) error, msg T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, msg)
+}
+
+// RetryDelay returns the exponential backoff delay after the given number of attempts
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := baseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,305 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMessage struct {
+	ID string `json:"id"`
+}
+
+// fakeMessageRepo records the state transitions of the messages in memory
+type fakeMessageRepo struct {
+	messages map[int64]*entity.QueueMessage
+}
+
+func newFakeMessageRepo() *fakeMessageRepo {
+	return &fakeMessageRepo{messages: make(map[int64]*entity.QueueMessage)}
+}
+
+func (r *fakeMessageRepo) AddMessage(_ context.Context, msg *entity.QueueMessage) error {
+	msg.ID = int64(len(r.messages) + 1)
+	r.messages[msg.ID] = msg
+	return nil
+}
+
+func (r *fakeMessageRepo) ClaimMessages(_ context.Context, queueName string, limit int, lockFor time.Duration) (
+	[]*entity.QueueMessage, error) {
+	now := time.Now()
+	messages := make([]*entity.QueueMessage, 0)
+	for _, msg := range r.messages {
+		if len(messages) >= limit || msg.QueueName != queueName {
+			continue
+		}
+		ready := msg.Status == entity.QueueMessageStatusPending && !msg.NextRunAt.After(now)
+		expired := msg.Status == entity.QueueMessageStatusProcessing && !msg.LockedUntil.After(now)
+		if !ready && !expired {
+			continue
+		}
+		msg.Status = entity.QueueMessageStatusProcessing
+		msg.Attempts++
+		msg.LockedUntil = now.Add(lockFor)
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func (r *fakeMessageRepo) RemoveMessage(_ context.Context, id int64) error {
+	delete(r.messages, id)
+	return nil
+}
+
+func (r *fakeMessageRepo) RetryMessage(_ context.Context, msg *entity.QueueMessage) error {
+	msg.Status = entity.QueueMessageStatusPending
+	r.messages[msg.ID] = msg
+	return nil
+}
+
+func (r *fakeMessageRepo) DeadMessage(_ context.Context, msg *entity.QueueMessage) error {
+	msg.Status = entity.QueueMessageStatusDead
+	r.messages[msg.ID] = msg
+	return nil
+}
+
+func newTestQueue(repo MessageRepo, handlers ...func(ctx context.Context, msg *testMessage) error) *Queue[*testMessage] {
+	q := New[*testMessage]("test", repo, nil)
+	q.maxAttempts = 3
+	for _, handler := range handlers {
+		q.handlers = append(q.handlers, namedHandler[*testMessage]{name: q.handlerName(handler), handler: handler})
+	}
+	return q
+}
+
+// claimOne sends a message and claims it like the worker does
+func claimOne(t *testing.T, q *Queue[*testMessage], repo *fakeMessageRepo) *entity.QueueMessage {
+	q.Send(context.TODO(), &testMessage{ID: "1"})
+	messages, err := repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	return messages[0]
+}
+
+func TestQueue_HandleSuccess(t *testing.T) {
+	repo := newFakeMessageRepo()
+	received := ""
+	q := newTestQueue(repo, func(ctx context.Context, msg *testMessage) error {
+		received = msg.ID
+		return nil
+	})
+	message := claimOne(t, q, repo)
+	assert.Equal(t, entity.QueueMessageStatusProcessing, message.Status)
+
+	q.handle(message)
+	assert.Equal(t, "1", received)
+	assert.Empty(t, repo.messages)
+}
+
+func TestQueue_HandleRetryThenDead(t *testing.T) {
+	repo := newFakeMessageRepo()
+	q := newTestQueue(repo, func(ctx context.Context, msg *testMessage) error {
+		return fmt.Errorf("temporary error")
+	})
+	message := claimOne(t, q, repo)
+
+	before := time.Now()
+	q.handle(message)
+	assert.Equal(t, entity.QueueMessageStatusPending, message.Status)
+	assert.Contains(t, message.LastError, "temporary error")
+	assert.WithinDuration(t, before.Add(RetryDelay(1)), message.NextRunAt, time.Second)
+
+	// not ready before the next run time
+	messages, _ := repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+	assert.Len(t, messages, 0)
+
+	for attempts := 2; attempts <= q.maxAttempts; attempts++ {
+		message.NextRunAt = time.Now()
+		messages, _ = repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, attempts, messages[0].Attempts)
+		q.handle(messages[0])
+	}
+	assert.Equal(t, entity.QueueMessageStatusDead, message.Status)
+	assert.Equal(t, q.maxAttempts, message.Attempts)
+
+	message.NextRunAt = time.Now()
+	messages, _ = repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+	assert.Len(t, messages, 0)
+}
+
+func TestQueue_HandlePermanentError(t *testing.T) {
+	repo := newFakeMessageRepo()
+	q := newTestQueue(repo, func(ctx context.Context, msg *testMessage) error {
+		return Permanent(fmt.Errorf("object not found"))
+	})
+	message := claimOne(t, q, repo)
+
+	q.handle(message)
+	assert.Equal(t, entity.QueueMessageStatusDead, message.Status)
+	assert.Equal(t, 1, message.Attempts)
+	assert.Contains(t, message.LastError, "object not found")
+}
+
+func TestQueue_HandleUnmarshalError(t *testing.T) {
+	repo := newFakeMessageRepo()
+	called := false
+	q := newTestQueue(repo, func(ctx context.Context, msg *testMessage) error {
+		called = true
+		return nil
+	})
+	_ = repo.AddMessage(context.TODO(), &entity.QueueMessage{
+		QueueName: q.name,
+		Payload:   "not json",
+		Status:    entity.QueueMessageStatusPending,
+		NextRunAt: time.Now(),
+	})
+	messages, _ := repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+	assert.Len(t, messages, 1)
+
+	q.handle(messages[0])
+	assert.False(t, called)
+	assert.Equal(t, entity.QueueMessageStatusDead, messages[0].Status)
+}
+
+func TestQueue_HandlePanic(t *testing.T) {
+	repo := newFakeMessageRepo()
+	q := newTestQueue(repo, func(ctx context.Context, msg *testMessage) error {
+		panic("boom")
+	})
+	message := claimOne(t, q, repo)
+
+	q.handle(message)
+	assert.Equal(t, entity.QueueMessageStatusPending, message.Status)
+	assert.Contains(t, message.LastError, "panic: boom")
+}
+
+func TestQueue_RetryOnlyFailedHandlers(t *testing.T) {
+	repo := newFakeMessageRepo()
+	calledA, calledB, calledC := 0, 0, 0
+	q := newTestQueue(repo,
+		func(ctx context.Context, msg *testMessage) error {
+			calledA++
+			return nil
+		},
+		func(ctx context.Context, msg *testMessage) error {
+			calledB++
+			if calledB == 1 {
+				return fmt.Errorf("temporary error")
+			}
+			return nil
+		},
+		func(ctx context.Context, msg *testMessage) error {
+			calledC++
+			return Permanent(fmt.Errorf("object not found"))
+		},
+	)
+	message := claimOne(t, q, repo)
+
+	q.handle(message)
+	assert.Equal(t, entity.QueueMessageStatusPending, message.Status)
+	assert.Len(t, message.GetDoneHandlers(), 2)
+
+	message.NextRunAt = time.Now()
+	messages, _ := repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+	assert.Len(t, messages, 1)
+	q.handle(messages[0])
+	assert.Empty(t, repo.messages)
+	assert.Equal(t, 1, calledA)
+	assert.Equal(t, 2, calledB)
+	assert.Equal(t, 1, calledC)
+}
+
+func TestQueue_HandlerName(t *testing.T) {
+	handler := func(ctx context.Context, msg *testMessage) error { return nil }
+	q := newTestQueue(newFakeMessageRepo(), handler, handler)
+	assert.Len(t, q.handlers, 2)
+	assert.NotEqual(t, q.handlers[0].name, q.handlers[1].name)
+	assert.Equal(t, q.handlers[0].name+"#2", q.handlers[1].name)
+}
+
+func TestQueue_Attempts(t *testing.T) {
+	repo := newFakeMessageRepo()
+	var attempts []int
+	var last []bool
+	var messageIDs []int64
+	q := newTestQueue(repo, func(ctx context.Context, msg *testMessage) error {
+		attempts = append(attempts, Attempts(ctx))
+		messageIDs = append(messageIDs, MessageID(ctx))
+		last = append(last, IsLastAttempt(ctx))
+		return fmt.Errorf("temporary error")
+	})
+	message := claimOne(t, q, repo)
+	for i := 0; i < q.maxAttempts; i++ {
+		message.NextRunAt = time.Now()
+		messages, _ := repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+		if i > 0 {
+			assert.Len(t, messages, 1)
+		}
+		q.handle(message)
+	}
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+	assert.Equal(t, []bool{false, false, true}, last)
+	assert.Equal(t, []int64{message.ID, message.ID, message.ID}, messageIDs)
+}
+
+func TestQueue_ExpiredLockReclaimed(t *testing.T) {
+	repo := newFakeMessageRepo()
+	q := newTestQueue(repo)
+	message := claimOne(t, q, repo)
+
+	// the worker is still holding the lock
+	messages, _ := repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+	assert.Len(t, messages, 0)
+
+	// the worker died, the message is redelivered after the lock expired
+	message.LockedUntil = time.Now().Add(-time.Second)
+	messages, _ = repo.ClaimMessages(context.TODO(), q.name, 1, lockDuration)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, 2, messages[0].Attempts)
+}
+
+func TestIsPermanent(t *testing.T) {
+	permanent := Permanent(fmt.Errorf("not found"))
+	temporary := fmt.Errorf("timeout")
+
+	assert.Nil(t, Permanent(nil))
+	assert.False(t, IsPermanent(nil))
+	assert.False(t, IsPermanent(temporary))
+	assert.True(t, IsPermanent(permanent))
+	assert.True(t, IsPermanent(fmt.Errorf("wrap: %w", permanent)))
+	assert.True(t, IsPermanent(errors.Join(permanent, Permanent(fmt.Errorf("gone")))))
+	assert.False(t, IsPermanent(errors.Join(permanent, temporary)))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, baseRetryDelay, RetryDelay(0))
+	assert.Equal(t, baseRetryDelay, RetryDelay(1))
+	assert.Equal(t, 2*baseRetryDelay, RetryDelay(2))
+	assert.Equal(t, 8*baseRetryDelay, RetryDelay(4))
+	assert.Equal(t, maxRetryDelay, RetryDelay(100))
+}
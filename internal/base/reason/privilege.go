@@ -47,4 +47,5 @@ const (
 	RankTagAuditLabel                  = "privilege.rank_tag_audit_label"
 	RankTagEditWithoutReviewLabel      = "privilege.rank_tag_edit_without_review_label"
 	RankTagSynonymLabel                = "privilege.rank_tag_synonym_label"
+	RankQuestionBountyLabel            = "privilege.rank_question_bounty_label"
 )
@@ -34,6 +34,8 @@ const (
 	ForbiddenError = "base.forbidden_error"
 	// DuplicateRequestError duplicate request error
 	DuplicateRequestError = "base.duplicate_request_error"
+	// TooManyRequestsError too many requests error
+	TooManyRequestsError = "base.too_many_requests_error"
 )
 
 const (
@@ -46,6 +48,19 @@ const (
 	QuestionCannotUpdate             = "error.question.cannot_update"
 	QuestionAlreadyDeleted           = "error.question.already_deleted"
 	QuestionUnderReview              = "error.question.under_review"
+	QuestionCannotMergeIntoItself    = "error.question.cannot_merge_into_itself"
+	QuestionAlreadyMerged            = "error.question.already_merged"
+	QuestionMergeNotFound            = "error.question.merge_not_found"
+	QuestionMergeCannotRevert        = "error.question.merge_cannot_revert"
+	BountyNotFound                   = "error.bounty.not_found"
+	BountyAlreadyExist               = "error.bounty.already_exist"
+	BountyRankNotEnough              = "error.bounty.rank_not_enough"
+	BountyQuestionNotAvailable       = "error.bounty.question_not_available"
+	BountyCannotAwardOwnAnswer       = "error.bounty.cannot_award_own_answer"
+	BountyOnlyOffererCanAward        = "error.bounty.only_offerer_can_award"
+	BountyDisabled                   = "error.bounty.disabled"
+	DraftNotFound                    = "error.draft.not_found"
+	DraftObjectInvalid               = "error.draft.object_invalid"
 	AnswerNotFound                   = "error.answer.not_found"
 	AnswerCannotDeleted              = "error.answer.cannot_deleted"
 	AnswerCannotUpdate               = "error.answer.cannot_update"
@@ -94,6 +109,11 @@ const (
 	RevisionNoPermission             = "error.revision.no_permission"
 	UserCannotUpdateYourRole         = "error.user.cannot_update_your_role"
 	TagCannotSetSynonymAsItself      = "error.tag.cannot_set_synonym_as_itself"
+	TagCannotMergeIntoItself         = "error.tag.cannot_merge_into_itself"
+	TagCannotMergeIntoSynonym        = "error.tag.cannot_merge_into_synonym"
+	TagCannotMergeIntoSubTag         = "error.tag.cannot_merge_into_sub_tag"
+	TagInvalidParent                 = "error.tag.invalid_parent"
+	TagHasSubTagsCannotDelete        = "error.tag.has_sub_tags_cannot_delete"
 	NotAllowedRegistration           = "error.user.not_allowed_registration"
 	NotAllowedLoginViaPassword       = "error.user.not_allowed_login_via_password"
 	SMTPConfigFromNameCannotBeEmail  = "error.smtp.config_from_name_cannot_be_email"
@@ -108,6 +128,30 @@ const (
 	MetaObjectNotFound               = "error.meta.object_not_found"
 	BadgeObjectNotFound              = "error.badge.object_not_found"
 	StatusInvalid                    = "error.common.status_invalid"
+	WebhookNotFound                  = "error.webhook.not_found"
+	WebhookDeliveryNotFound          = "error.webhook.delivery_not_found"
+	WebhookEventTypeInvalid          = "error.webhook.event_type_invalid"
+	APIKeyNotFound                   = "error.api_key.not_found"
+	APIKeyScopeNotAllowed            = "error.api_key.scope_not_allowed"
+	APIKeyLimitExceeded              = "error.api_key.limit_exceeded"
+	APIKeyExpiredTimeInvalid         = "error.api_key.expired_time_invalid"
+	APIKeyScopeInsufficient          = "error.api_key.scope_insufficient"
+	RoleNotFound                     = "error.role.not_found"
+	RoleNameDuplicate                = "error.role.name_duplicate"
+	RoleCannotModifyBuiltIn          = "error.role.cannot_modify_built_in"
+	PowerTypeInvalid                 = "error.role.power_type_invalid"
+	CronJobNotFound                  = "error.cron_job.not_found"
+	CronJobRunning                   = "error.cron_job.running"
+	TwoFactorCodeInvalid             = "error.two_factor.code_invalid"
+	TwoFactorAlreadyEnabled          = "error.two_factor.already_enabled"
+	TwoFactorNotEnabled              = "error.two_factor.not_enabled"
+	TwoFactorNotEnrolled             = "error.two_factor.not_enrolled"
+	TwoFactorTokenInvalid            = "error.two_factor.token_invalid"
+	SearchPluginNotEnabled           = "error.search.plugin_not_enabled"
+	SearchReindexRunning             = "error.search.reindex_running"
+	SavedSearchNotFound              = "error.saved_search.not_found"
+	SavedSearchTooMany               = "error.saved_search.too_many"
+	SavedSearchAlertChannelRequired  = "error.saved_search.alert_channel_required"
 )
 
 // user external login reasons
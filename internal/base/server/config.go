@@ -22,6 +22,10 @@ package server
 // HTTP http config
 type HTTP struct {
 	Addr string `json:"addr" mapstructure:"addr"`
+	// TrustedProxies the client ip is only read from the X-Forwarded-For header sent by these proxies.
+	// All proxies are trusted if it is empty, so the client ip used by the rate limits can be forged by the header.
+	// Set it to the address of the reverse proxy, or to the loopback address if the server is exposed directly.
+	TrustedProxies []string `json:"trusted_proxies" mapstructure:"trusted_proxies" yaml:"trusted_proxies,omitempty"`
 }
 
 // UI ui config
@@ -22,6 +22,7 @@ package server
 import (
 	"html/template"
 	"io/fs"
+	"strings"
 
 	brotli "github.com/anargu/gin-brotli"
 	"github.com/apache/incubator-answer/internal/base/middleware"
@@ -29,6 +30,7 @@ import (
 	"github.com/apache/incubator-answer/plugin"
 	"github.com/apache/incubator-answer/ui"
 	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/log"
 )
 
 // NewHTTPServer new http server.
@@ -40,8 +42,10 @@ func NewHTTPServer(debug bool,
 	authUserMiddleware *middleware.AuthUserMiddleware,
 	avatarMiddleware *middleware.AvatarMiddleware,
 	shortIDMiddleware *middleware.ShortIDMiddleware,
+	rateLimitMiddleware *middleware.RateLimitMiddleware,
 	templateRouter *router.TemplateRouter,
 	pluginAPIRouter *router.PluginAPIRouter,
+	httpConf *HTTP,
 	uiConf *UI,
 ) *gin.Engine {
 
@@ -51,7 +55,13 @@ func NewHTTPServer(debug bool,
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.New()
-	r.Use(brotli.Brotli(brotli.DefaultCompression), middleware.ExtractAndSetAcceptLanguage, shortIDMiddleware.SetShortIDFlag())
+	// gin trusts all proxies by default, only the configured proxies are trusted if they are set
+	if httpConf != nil && len(httpConf.TrustedProxies) > 0 {
+		if err := r.SetTrustedProxies(httpConf.TrustedProxies); err != nil {
+			log.Errorf("set trusted proxies failed: %v", err)
+		}
+	}
+	r.Use(compress(), middleware.ExtractAndSetAcceptLanguage, shortIDMiddleware.SetShortIDFlag())
 	r.GET("/healthz", func(ctx *gin.Context) { ctx.String(200, "OK") })
 
 	html, _ := fs.Sub(ui.Template, "template")
@@ -68,21 +78,22 @@ func NewHTTPServer(debug bool,
 
 	// The route must be available without logging in
 	mustUnAuthV1 := r.Group("/answer/api/v1")
+	mustUnAuthV1.Use(rateLimitMiddleware.RateLimit())
 	answerRouter.RegisterMustUnAuthAnswerAPIRouter(authUserMiddleware, mustUnAuthV1)
 
 	// register api that no need to login
 	unAuthV1 := r.Group("/answer/api/v1")
-	unAuthV1.Use(authUserMiddleware.Auth(), authUserMiddleware.EjectUserBySiteInfo())
+	unAuthV1.Use(authUserMiddleware.Auth(), authUserMiddleware.EjectUserBySiteInfo(), rateLimitMiddleware.RateLimit())
 	answerRouter.RegisterUnAuthAnswerAPIRouter(unAuthV1)
 
 	// register api that must be authenticated but no need to check account status
 	authWithoutStatusV1 := r.Group("/answer/api/v1")
-	authWithoutStatusV1.Use(authUserMiddleware.MustAuthWithoutAccountAvailable())
+	authWithoutStatusV1.Use(authUserMiddleware.MustAuthWithoutAccountAvailable(), rateLimitMiddleware.RateLimit())
 	answerRouter.RegisterAuthUserWithAnyStatusAnswerAPIRouter(authWithoutStatusV1)
 
 	// register api that must be authenticated
 	authV1 := r.Group("/answer/api/v1")
-	authV1.Use(authUserMiddleware.MustAuthAndAccountAvailable())
+	authV1.Use(authUserMiddleware.MustAuthAndAccountAvailable(), rateLimitMiddleware.RateLimit())
 	answerRouter.RegisterAnswerAPIRouter(authV1)
 
 	adminauthV1 := r.Group("/answer/admin/api")
@@ -104,3 +115,14 @@ func NewHTTPServer(debug bool,
 	})
 	return r
 }
+
+// compress the response by brotli except the event stream, which must be flushed to the client immediately
+func compress() gin.HandlerFunc {
+	brotliCompress := brotli.Brotli(brotli.DefaultCompression)
+	return func(ctx *gin.Context) {
+		if strings.Contains(ctx.GetHeader("Accept"), "text/event-stream") {
+			return
+		}
+		brotliCompress(ctx)
+	}
+}
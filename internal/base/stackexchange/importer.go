@@ -0,0 +1,719 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package stackexchange
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/migrations"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/pkg/checker"
+	"github.com/apache/incubator-answer/pkg/converter"
+	"github.com/apache/incubator-answer/pkg/random"
+	"github.com/mozillazg/go-pinyin"
+	"xorm.io/xorm"
+)
+
+const (
+	SourceTypeUser     = "user"
+	SourceTypeTag      = "tag"
+	SourceTypeQuestion = "question"
+	SourceTypeAnswer   = "answer"
+	SourceTypeComment  = "comment"
+	SourceTypePost     = "post"
+
+	// ghostUserSourceID the owner of the posts whose user is deleted in Stack Exchange
+	ghostUserSourceID = "ghost"
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Options the options of importing Stack Exchange data dump
+type Options struct {
+	// Dir the directory contains Posts.xml, Users.xml and so on
+	Dir string
+	// Source the objects imported from the same source are skipped, so the import could be resumed
+	Source string
+	// EmailDomain the dump has no email of users, the users get a placeholder email in this domain
+	EmailDomain string
+}
+
+// Report the result of importing
+type Report struct {
+	// Imported the count of objects imported in this run, grouped by source type
+	Imported map[string]int
+	// Resumed the count of objects imported by the previous runs, grouped by source type
+	Resumed map[string]int
+	Skipped []*SkippedRecord
+}
+
+// SkippedRecord the record of dump that is not imported
+type SkippedRecord struct {
+	SourceType string
+	SourceID   string
+	Reason     string
+}
+
+type answerStat struct {
+	count          int
+	lastAnswerID   string
+	lastAnsweredAt time.Time
+}
+
+type importer struct {
+	db     *xorm.Engine
+	opts   *Options
+	report *Report
+	// records the object id of the imported records, source type -> source id -> object id
+	records map[string]map[string]string
+	// postMarkdown the latest markdown of the post body in PostHistory.xml
+	postMarkdown map[string]string
+	// voteCount the vote count of the post in Votes.xml, nil if Votes.xml does not exist
+	voteCount map[string]int
+	// acceptedVotes the answers accepted by the question owner in Votes.xml
+	acceptedVotes map[string]bool
+	// answerQuestion the question source id of the answer
+	answerQuestion map[string]string
+	// acceptedAnswer the accepted answer source id of the question
+	acceptedAnswer map[string]string
+
+	// the following are counted by object id and updated at the end of import
+	userQuestionCount map[string]int
+	userAnswerCount   map[string]int
+	questionAnswers   map[string]*answerStat
+	// answerComments the comment count of answer, the question has no comment count
+	answerComments map[string]int
+	touchedTags    map[string]bool
+}
+
+// Import import the users, tags, questions, answers, comments and votes of Stack Exchange data dump.
+// The objects imported are recorded, so run it again with the same source will resume the import.
+func Import(ctx context.Context, dbConf *data.Database, opts *Options) (report *Report, err error) {
+	for _, name := range []string{"Users.xml", "Posts.xml"} {
+		if _, err = os.Stat(filepath.Join(opts.Dir, name)); err != nil {
+			return nil, fmt.Errorf("%s is required: %w", name, err)
+		}
+	}
+	if len(opts.Source) == 0 {
+		absDir, err := filepath.Abs(opts.Dir)
+		if err != nil {
+			return nil, err
+		}
+		opts.Source = "stackexchange:" + filepath.Base(absDir)
+	}
+	if len(opts.EmailDomain) == 0 {
+		opts.EmailDomain = "stackexchange.invalid"
+	}
+
+	db, err := data.NewDB(false, dbConf)
+	if err != nil {
+		return nil, fmt.Errorf("connect database failed: %w", err)
+	}
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("connect database failed: %w", err)
+	}
+	version, err := migrations.GetCurrentDBVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	if expected := migrations.ExpectedVersion(); version != expected {
+		return nil, fmt.Errorf("the database version is %d, but this answer requires %d, "+
+			"please run upgrade command first", version, expected)
+	}
+
+	im := &importer{
+		db:   db,
+		opts: opts,
+		report: &Report{
+			Imported: make(map[string]int),
+			Resumed:  make(map[string]int),
+		},
+		records:           make(map[string]map[string]string),
+		postMarkdown:      make(map[string]string),
+		acceptedVotes:     make(map[string]bool),
+		answerQuestion:    make(map[string]string),
+		acceptedAnswer:    make(map[string]string),
+		userQuestionCount: make(map[string]int),
+		userAnswerCount:   make(map[string]int),
+		questionAnswers:   make(map[string]*answerStat),
+		answerComments:    make(map[string]int),
+		touchedTags:       make(map[string]bool),
+	}
+	if err = im.loadRecords(ctx); err != nil {
+		return im.report, err
+	}
+	steps := []struct {
+		file string
+		fn   func(ctx context.Context, path string) error
+	}{
+		{"Users.xml", im.importUsers},
+		{"Tags.xml", im.importTags},
+		{"PostHistory.xml", im.loadPostHistory},
+		{"Votes.xml", im.loadVotes},
+		{"Posts.xml", im.importQuestions},
+		{"Posts.xml", im.importAnswers},
+		{"Comments.xml", im.importComments},
+	}
+	for _, step := range steps {
+		path := filepath.Join(opts.Dir, step.file)
+		if _, err = os.Stat(path); err != nil {
+			fmt.Printf("[import] %s not found, skipped\n", step.file)
+			continue
+		}
+		if err = step.fn(ctx, path); err != nil {
+			return im.report, fmt.Errorf("import %s failed: %w", step.file, err)
+		}
+	}
+	if err = im.updateCounts(ctx); err != nil {
+		return im.report, fmt.Errorf("update counts failed: %w", err)
+	}
+	return im.report, nil
+}
+
+func (im *importer) loadRecords(ctx context.Context) error {
+	for _, sourceType := range []string{SourceTypeUser, SourceTypeTag, SourceTypeQuestion, SourceTypeAnswer, SourceTypeComment} {
+		im.records[sourceType] = make(map[string]string)
+	}
+	return im.db.Context(ctx).Where("source = ?", im.opts.Source).Iterate(&entity.ImportRecord{},
+		func(_ int, bean any) error {
+			record := bean.(*entity.ImportRecord)
+			im.records[record.SourceType][record.SourceID] = record.ObjectID
+			return nil
+		})
+}
+
+// save create the object and its import record in a transaction, the import could be stopped at any time
+func (im *importer) save(ctx context.Context, sourceType, sourceID string,
+	create func(session *xorm.Session) (objectID string, err error)) (objectID string, err error) {
+	_, err = im.db.Transaction(func(session *xorm.Session) (any, error) {
+		session = session.Context(ctx)
+		objectID, err = create(session)
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.Insert(&entity.ImportRecord{
+			CreatedAt:  time.Now(),
+			Source:     im.opts.Source,
+			SourceType: sourceType,
+			SourceID:   sourceID,
+			ObjectID:   objectID,
+		})
+		return nil, err
+	})
+	if err != nil {
+		return "", err
+	}
+	im.records[sourceType][sourceID] = objectID
+	im.report.Imported[sourceType]++
+	return objectID, nil
+}
+
+func (im *importer) imported(sourceType, sourceID string) (objectID string, ok bool) {
+	objectID, ok = im.records[sourceType][sourceID]
+	return
+}
+
+func (im *importer) skip(sourceType, sourceID, format string, args ...any) {
+	im.report.Skipped = append(im.report.Skipped, &SkippedRecord{
+		SourceType: sourceType,
+		SourceID:   sourceID,
+		Reason:     fmt.Sprintf(format, args...),
+	})
+}
+
+func (im *importer) importUsers(ctx context.Context, path string) error {
+	err := eachRow(path, func(row *userRow) error {
+		if _, ok := im.imported(SourceTypeUser, row.ID); ok {
+			im.report.Resumed[SourceTypeUser]++
+			return nil
+		}
+		_, err := im.createUser(ctx, row)
+		return err
+	})
+	fmt.Printf("[import] users imported: %d\n", im.report.Imported[SourceTypeUser])
+	return err
+}
+
+func (im *importer) createUser(ctx context.Context, row *userRow) (userID string, err error) {
+	username, err := im.makeUsername(ctx, row.DisplayName, row.ID)
+	if err != nil {
+		return "", err
+	}
+	createdAt := row.CreationDate.Or(time.Now())
+	user := &entity.User{
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+		LastLoginDate:  row.LastAccessDate.Or(createdAt),
+		Username:       username,
+		EMail:          username + "@" + im.opts.EmailDomain,
+		MailStatus:     entity.EmailStatusToBeVerified,
+		NoticeStatus:   schema.NoticeStatusOff,
+		Rank:           max(row.Reputation, 1),
+		Status:         entity.UserStatusAvailable,
+		AuthorityGroup: 1,
+		DisplayName:    truncate(row.DisplayName, 30),
+		Bio:            row.AboutMe,
+		BioHTML:        converter.Markdown2HTML(row.AboutMe),
+		Website:        truncate(row.WebsiteURL, 255),
+		Location:       truncate(row.Location, 100),
+	}
+	return im.save(ctx, SourceTypeUser, row.ID, func(session *xorm.Session) (string, error) {
+		_, err := session.NoAutoTime().Insert(user)
+		return user.ID, err
+	})
+}
+
+// makeUsername make a valid and unique username from the display name
+func (im *importer) makeUsername(ctx context.Context, displayName, sourceID string) (username string, err error) {
+	if checker.IsChinese(displayName) {
+		displayName = strings.Join(pinyin.LazyConvert(displayName, nil), "")
+	}
+	username = usernameInvalidChars.ReplaceAllString(strings.ToLower(displayName), "-")
+	username = strings.Trim(username, "-._")
+	// leave room for the suffix
+	if len(username) > 24 {
+		username = strings.Trim(username[:24], "-._")
+	}
+	if checker.IsInvalidUsername(username) || checker.IsReservedUsername(username) {
+		username = "user" + strings.TrimPrefix(sourceID, "-")
+	}
+	candidate := username
+	for {
+		exist, err := im.db.Context(ctx).Where("username = ?", candidate).Exist(&entity.User{})
+		if err != nil {
+			return "", err
+		}
+		if !exist {
+			return candidate, nil
+		}
+		candidate = username + "-" + random.UsernameSuffix()
+	}
+}
+
+// ownerID get the user id of the owner, the posts whose owner is not found belong to a ghost user
+func (im *importer) ownerID(ctx context.Context, sourceUserID string) (userID string, err error) {
+	if userID, ok := im.imported(SourceTypeUser, sourceUserID); ok && len(sourceUserID) > 0 {
+		return userID, nil
+	}
+	if userID, ok := im.imported(SourceTypeUser, ghostUserSourceID); ok {
+		return userID, nil
+	}
+	return im.createUser(ctx, &userRow{ID: ghostUserSourceID, DisplayName: "deleted user"})
+}
+
+// editorID get the user id of the last editor, 0 if the editor is not found
+func (im *importer) editorID(sourceUserID string) string {
+	if userID, ok := im.imported(SourceTypeUser, sourceUserID); ok && len(sourceUserID) > 0 {
+		return userID
+	}
+	return "0"
+}
+
+func (im *importer) importTags(ctx context.Context, path string) error {
+	err := eachRow(path, func(row *tagRow) error {
+		_, err := im.tagID(ctx, row.TagName)
+		return err
+	})
+	fmt.Printf("[import] tags imported: %d\n", im.report.Imported[SourceTypeTag])
+	return err
+}
+
+// tagID get the id of the tag, the tag is created if it does not exist. The tags are recorded by slug name,
+// because the posts refer to the tags by name.
+func (im *importer) tagID(ctx context.Context, tagName string) (tagID string, err error) {
+	slugName := strings.ToLower(strings.TrimSpace(tagName))
+	if tagID, ok := im.imported(SourceTypeTag, slugName); ok {
+		im.touchedTags[tagID] = true
+		return tagID, nil
+	}
+	tagID, err = im.save(ctx, SourceTypeTag, slugName, func(session *xorm.Session) (string, error) {
+		existTag := &entity.Tag{}
+		exist, err := session.Where("slug_name = ?", slugName).Get(existTag)
+		if err != nil || exist {
+			return existTag.ID, err
+		}
+		tag := &entity.Tag{
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+			SlugName:    truncate(slugName, 35),
+			DisplayName: truncate(tagName, 35),
+			Status:      entity.TagStatusAvailable,
+			RevisionID:  "0",
+			UserID:      "0",
+		}
+		tag.ID, err = genUniqueID(session, constant.TagObjectType)
+		if err != nil {
+			return "", err
+		}
+		_, err = session.NoAutoTime().Insert(tag)
+		return tag.ID, err
+	})
+	if err != nil {
+		return "", err
+	}
+	im.touchedTags[tagID] = true
+	return tagID, nil
+}
+
+func (im *importer) loadPostHistory(_ context.Context, path string) error {
+	// the history is in chronological order, so the latest body is the last one
+	return eachRow(path, func(row *postHistoryRow) error {
+		switch row.PostHistoryTypeID {
+		case postHistoryInitialBody, postHistoryEditBody, postHistoryRollbackBody:
+			im.postMarkdown[row.PostID] = row.Text
+		}
+		return nil
+	})
+}
+
+func (im *importer) loadVotes(_ context.Context, path string) error {
+	im.voteCount = make(map[string]int)
+	return eachRow(path, func(row *voteRow) error {
+		switch row.VoteTypeID {
+		case voteTypeUpMod:
+			im.voteCount[row.PostID]++
+		case voteTypeDownMod:
+			im.voteCount[row.PostID]--
+		case voteTypeAcceptedByOriginator:
+			im.acceptedVotes[row.PostID] = true
+		}
+		return nil
+	})
+}
+
+// postContent get the markdown and html of post, the body in Posts.xml is html
+// and the markdown is only in PostHistory.xml
+func (im *importer) postContent(row *postRow) (originalText, parsedText string) {
+	originalText, ok := im.postMarkdown[row.ID]
+	if !ok {
+		originalText = row.Body
+	}
+	return originalText, converter.Markdown2HTML(originalText)
+}
+
+// postVoteCount the anonymous votes in Votes.xml is preferred, the score is used if there is no Votes.xml
+func (im *importer) postVoteCount(row *postRow) int {
+	if im.voteCount == nil {
+		return row.Score
+	}
+	return im.voteCount[row.ID]
+}
+
+func (im *importer) importQuestions(ctx context.Context, path string) error {
+	err := eachRow(path, func(row *postRow) error {
+		if row.PostTypeID == postTypeAnswer {
+			return nil
+		}
+		if row.PostTypeID != postTypeQuestion {
+			im.skip(SourceTypePost, row.ID, "unsupported post type %d", row.PostTypeID)
+			return nil
+		}
+		if len(row.AcceptedAnswerID) > 0 {
+			im.acceptedAnswer[row.ID] = row.AcceptedAnswerID
+		}
+		tagIDs := make([]string, 0)
+		for _, tagName := range converter.UniqueArray(row.TagNames()) {
+			tagID, err := im.tagID(ctx, tagName)
+			if err != nil {
+				return err
+			}
+			tagIDs = append(tagIDs, tagID)
+		}
+		userID, err := im.ownerID(ctx, row.OwnerUserID)
+		if err != nil {
+			return err
+		}
+		if _, ok := im.imported(SourceTypeQuestion, row.ID); ok {
+			im.userQuestionCount[userID]++
+			im.report.Resumed[SourceTypeQuestion]++
+			return nil
+		}
+		if len(strings.TrimSpace(row.Title)) == 0 {
+			im.skip(SourceTypeQuestion, row.ID, "the title is empty")
+			return nil
+		}
+
+		createdAt := row.CreationDate.Or(time.Now())
+		question := &entity.Question{
+			CreatedAt:        createdAt,
+			UpdatedAt:        row.LastEditDate.Or(createdAt),
+			UserID:           userID,
+			LastEditUserID:   im.editorID(row.LastEditorUserID),
+			Title:            truncate(row.Title, 150),
+			Pin:              entity.QuestionUnPin,
+			Show:             entity.QuestionShow,
+			Status:           entity.QuestionStatusAvailable,
+			ViewCount:        row.ViewCount,
+			UniqueViewCount:  row.ViewCount,
+			VoteCount:        im.postVoteCount(row),
+			AcceptedAnswerID: "0",
+			LastAnswerID:     "0",
+			PostUpdateTime:   row.LastActivityDate.Or(createdAt),
+			RevisionID:       "0",
+		}
+		question.OriginalText, question.ParsedText = im.postContent(row)
+		if !row.ClosedDate.IsZero() {
+			question.Status = entity.QuestionStatusClosed
+		}
+		_, err = im.save(ctx, SourceTypeQuestion, row.ID, func(session *xorm.Session) (string, error) {
+			question.ID, err = genUniqueID(session, constant.QuestionObjectType)
+			if err != nil {
+				return "", err
+			}
+			if _, err = session.NoAutoTime().Insert(question); err != nil {
+				return "", err
+			}
+			for _, tagID := range tagIDs {
+				_, err = session.NoAutoTime().Insert(&entity.TagRel{
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+					ObjectID:  question.ID,
+					TagID:     tagID,
+					Status:    entity.TagRelStatusAvailable,
+				})
+				if err != nil {
+					return "", err
+				}
+			}
+			return question.ID, nil
+		})
+		if err != nil {
+			return err
+		}
+		im.userQuestionCount[userID]++
+		return nil
+	})
+	fmt.Printf("[import] questions imported: %d\n", im.report.Imported[SourceTypeQuestion])
+	return err
+}
+
+func (im *importer) importAnswers(ctx context.Context, path string) error {
+	err := eachRow(path, func(row *postRow) error {
+		if row.PostTypeID != postTypeAnswer {
+			return nil
+		}
+		questionID, ok := im.imported(SourceTypeQuestion, row.ParentID)
+		if !ok {
+			im.skip(SourceTypeAnswer, row.ID, "the question %s is not imported", row.ParentID)
+			return nil
+		}
+		im.answerQuestion[row.ID] = row.ParentID
+		userID, err := im.ownerID(ctx, row.OwnerUserID)
+		if err != nil {
+			return err
+		}
+		createdAt := row.CreationDate.Or(time.Now())
+		answerID, ok := im.imported(SourceTypeAnswer, row.ID)
+		if ok {
+			im.report.Resumed[SourceTypeAnswer]++
+		} else {
+			answer := &entity.Answer{
+				CreatedAt:      createdAt,
+				UpdatedAt:      row.LastEditDate.Or(createdAt),
+				QuestionID:     questionID,
+				UserID:         userID,
+				LastEditUserID: im.editorID(row.LastEditorUserID),
+				Status:         entity.AnswerStatusAvailable,
+				Accepted:       schema.AnswerAcceptedFailed,
+				VoteCount:      im.postVoteCount(row),
+				RevisionID:     "0",
+			}
+			answer.OriginalText, answer.ParsedText = im.postContent(row)
+			answerID, err = im.save(ctx, SourceTypeAnswer, row.ID, func(session *xorm.Session) (string, error) {
+				answer.ID, err = genUniqueID(session, constant.AnswerObjectType)
+				if err != nil {
+					return "", err
+				}
+				_, err = session.NoAutoTime().Insert(answer)
+				return answer.ID, err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		im.userAnswerCount[userID]++
+		stat, ok := im.questionAnswers[questionID]
+		if !ok {
+			stat = &answerStat{}
+			im.questionAnswers[questionID] = stat
+		}
+		stat.count++
+		if !createdAt.Before(stat.lastAnsweredAt) {
+			stat.lastAnswerID, stat.lastAnsweredAt = answerID, createdAt
+		}
+		return nil
+	})
+	fmt.Printf("[import] answers imported: %d\n", im.report.Imported[SourceTypeAnswer])
+	return err
+}
+
+func (im *importer) importComments(ctx context.Context, path string) error {
+	err := eachRow(path, func(row *commentRow) error {
+		objectID, questionID, isAnswer := "", "", false
+		if id, ok := im.imported(SourceTypeQuestion, row.PostID); ok {
+			objectID, questionID = id, id
+		} else if id, ok := im.imported(SourceTypeAnswer, row.PostID); ok {
+			objectID, isAnswer = id, true
+			questionID, _ = im.imported(SourceTypeQuestion, im.answerQuestion[row.PostID])
+		}
+		if len(objectID) == 0 || len(questionID) == 0 {
+			im.skip(SourceTypeComment, row.ID, "the post %s is not imported", row.PostID)
+			return nil
+		}
+		if _, ok := im.imported(SourceTypeComment, row.ID); ok {
+			if isAnswer {
+				im.answerComments[objectID]++
+			}
+			im.report.Resumed[SourceTypeComment]++
+			return nil
+		}
+		if len(strings.TrimSpace(row.Text)) == 0 {
+			im.skip(SourceTypeComment, row.ID, "the content is empty")
+			return nil
+		}
+		userID, err := im.ownerID(ctx, row.UserID)
+		if err != nil {
+			return err
+		}
+		createdAt := row.CreationDate.Or(time.Now())
+		comment := &entity.Comment{
+			CreatedAt:    createdAt,
+			UpdatedAt:    createdAt,
+			UserID:       userID,
+			ObjectID:     objectID,
+			QuestionID:   questionID,
+			VoteCount:    row.Score,
+			Status:       entity.CommentStatusAvailable,
+			OriginalText: row.Text,
+			ParsedText:   converter.Markdown2HTML(row.Text),
+		}
+		_, err = im.save(ctx, SourceTypeComment, row.ID, func(session *xorm.Session) (string, error) {
+			comment.ID, err = genUniqueID(session, constant.CommentObjectType)
+			if err != nil {
+				return "", err
+			}
+			_, err = session.NoAutoTime().Insert(comment)
+			return comment.ID, err
+		})
+		if err != nil {
+			return err
+		}
+		if isAnswer {
+			im.answerComments[objectID]++
+		}
+		return nil
+	})
+	fmt.Printf("[import] comments imported: %d\n", im.report.Imported[SourceTypeComment])
+	return err
+}
+
+// updateCounts update the counts and accepted answers, they are calculated from all imported records,
+// so it is fine to update them again when the import is resumed
+func (im *importer) updateCounts(ctx context.Context) error {
+	for answerSourceID := range im.acceptedVotes {
+		questionSourceID := im.answerQuestion[answerSourceID]
+		if _, ok := im.acceptedAnswer[questionSourceID]; !ok && len(questionSourceID) > 0 {
+			im.acceptedAnswer[questionSourceID] = answerSourceID
+		}
+	}
+	for questionSourceID, answerSourceID := range im.acceptedAnswer {
+		questionID, ok := im.imported(SourceTypeQuestion, questionSourceID)
+		if !ok {
+			continue
+		}
+		answerID, ok := im.imported(SourceTypeAnswer, answerSourceID)
+		if !ok {
+			continue
+		}
+		_, err := im.db.Context(ctx).ID(questionID).Cols("accepted_answer_id").
+			Update(&entity.Question{AcceptedAnswerID: answerID})
+		if err != nil {
+			return err
+		}
+		_, err = im.db.Context(ctx).ID(answerID).Cols("adopted").
+			Update(&entity.Answer{Accepted: schema.AnswerAcceptedEnable})
+		if err != nil {
+			return err
+		}
+	}
+	for questionID, stat := range im.questionAnswers {
+		_, err := im.db.Context(ctx).ID(questionID).Cols("answer_count", "last_answer_id").
+			Update(&entity.Question{AnswerCount: stat.count, LastAnswerID: stat.lastAnswerID})
+		if err != nil {
+			return err
+		}
+	}
+	for answerID, count := range im.answerComments {
+		_, err := im.db.Context(ctx).ID(answerID).Cols("comment_count").Update(&entity.Answer{CommentCount: count})
+		if err != nil {
+			return err
+		}
+	}
+	for _, userID := range im.records[SourceTypeUser] {
+		_, err := im.db.Context(ctx).ID(userID).NoAutoTime().Cols("question_count", "answer_count").
+			Update(&entity.User{QuestionCount: im.userQuestionCount[userID], AnswerCount: im.userAnswerCount[userID]})
+		if err != nil {
+			return err
+		}
+	}
+	// the tags may be used by the questions not imported, so count them all
+	for tagID := range im.touchedTags {
+		count, err := im.db.Context(ctx).Where("tag_id = ? AND status = ?", tagID, entity.TagRelStatusAvailable).
+			Count(&entity.TagRel{})
+		if err != nil {
+			return err
+		}
+		_, err = im.db.Context(ctx).ID(tagID).NoAutoTime().Cols("question_count").
+			Update(&entity.Tag{QuestionCount: int(count)})
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println("[import] counts updated")
+	return nil
+}
+
+// genUniqueID generate the object id in the same way as the unique id repository
+func genUniqueID(session *xorm.Session, objectType string) (string, error) {
+	bean := &entity.Uniqid{UniqidType: constant.ObjectTypeStrMapping[objectType]}
+	if _, err := session.Insert(bean); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("1%03d%013d", bean.UniqidType, bean.ID), nil
+}
+
+// truncate truncate the string to the max length of runes
+func truncate(s string, maxLength int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLength {
+		return s
+	}
+	return string(runes[:maxLength])
+}
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package stackexchange
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/migrations"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Import(t *testing.T) {
+	ctx := context.TODO()
+	dbConf := &data.Database{Driver: "sqlite3", Connection: filepath.Join(t.TempDir(), "answer.db")}
+	db, err := data.NewDB(false, dbConf)
+	require.NoError(t, err)
+	defer db.Close()
+	err = migrations.NewMentor(ctx, db, &migrations.InitNeedUserInputData{
+		Language:      "en_US",
+		SiteName:      "ANSWER",
+		SiteURL:       "http://127.0.0.1:8080/",
+		ContactEmail:  "answer@answer.com",
+		AdminName:     "admin",
+		AdminPassword: "admin",
+		AdminEmail:    "answer@answer.com",
+	}).InitDB()
+	require.NoError(t, err)
+
+	opts := &Options{Dir: filepath.Join("testdata", "dump")}
+	report, err := Import(ctx, dbConf, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "stackexchange:dump", opts.Source)
+	// the 3 users in dump and a ghost user for the question without owner
+	assert.Equal(t, 4, report.Imported[SourceTypeUser])
+	assert.Equal(t, 3, report.Imported[SourceTypeTag])
+	assert.Equal(t, 2, report.Imported[SourceTypeQuestion])
+	assert.Equal(t, 2, report.Imported[SourceTypeAnswer])
+	assert.Equal(t, 2, report.Imported[SourceTypeComment])
+	assert.ElementsMatch(t, []*SkippedRecord{
+		{SourceType: SourceTypePost, SourceID: "6", Reason: "unsupported post type 4"},
+		{SourceType: SourceTypeAnswer, SourceID: "5", Reason: "the question 99 is not imported"},
+		{SourceType: SourceTypeComment, SourceID: "3", Reason: "the post 5 is not imported"},
+	}, report.Skipped)
+
+	records := make([]*entity.ImportRecord, 0)
+	require.NoError(t, db.Find(&records))
+	objectIDs := make(map[string]string)
+	for _, record := range records {
+		objectIDs[record.SourceType+record.SourceID] = record.ObjectID
+	}
+
+	question := &entity.Question{}
+	_, err = db.ID(objectIDs["question1"]).Get(question)
+	require.NoError(t, err)
+	assert.Equal(t, "How to read a file in Go", question.Title)
+	assert.Equal(t, "How do I read a **file**?", question.OriginalText)
+	assert.Contains(t, question.ParsedText, "<strong>file</strong>")
+	assert.Equal(t, objectIDs["user2"], question.UserID)
+	assert.Equal(t, objectIDs["user3"], question.LastEditUserID)
+	assert.Equal(t, time.Date(2010, 7, 28, 19, 4, 21, 300000000, time.UTC).Unix(), question.CreatedAt.Unix())
+	assert.Equal(t, 2, question.VoteCount)
+	assert.Equal(t, 120, question.ViewCount)
+	assert.Equal(t, 2, question.AnswerCount)
+	assert.Equal(t, objectIDs["answer4"], question.LastAnswerID)
+	assert.Equal(t, objectIDs["answer3"], question.AcceptedAnswerID)
+	tagCount, err := db.Where("object_id = ?", question.ID).Count(&entity.TagRel{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), tagCount)
+
+	closedQuestion := &entity.Question{}
+	_, err = db.ID(objectIDs["question2"]).Get(closedQuestion)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionStatusClosed, closedQuestion.Status)
+	assert.Equal(t, objectIDs["userghost"], closedQuestion.UserID)
+
+	answer := &entity.Answer{}
+	_, err = db.ID(objectIDs["answer3"]).Get(answer)
+	require.NoError(t, err)
+	assert.Equal(t, schema.AnswerAcceptedEnable, answer.Accepted)
+	assert.Equal(t, -1, answer.VoteCount)
+	assert.Equal(t, 1, answer.CommentCount)
+
+	user := &entity.User{}
+	_, err = db.ID(objectIDs["user2"]).Get(user)
+	require.NoError(t, err)
+	assert.Equal(t, "jeff-atwood", user.Username)
+	assert.Equal(t, 101, user.Rank)
+	assert.Equal(t, 1, user.QuestionCount)
+	assert.Equal(t, 1, user.AnswerCount)
+	user = &entity.User{}
+	_, err = db.ID(objectIDs["user3"]).Get(user)
+	require.NoError(t, err)
+	assert.Equal(t, "lilei", user.Username)
+
+	tag := &entity.Tag{}
+	_, err = db.ID(objectIDs["taggo"]).Get(tag)
+	require.NoError(t, err)
+	assert.Equal(t, 2, tag.QuestionCount)
+
+	// import again, all records are imported before
+	questionCount, err := db.Count(&entity.Question{})
+	require.NoError(t, err)
+	report, err = Import(ctx, dbConf, &Options{Dir: filepath.Join("testdata", "dump")})
+	require.NoError(t, err)
+	assert.Empty(t, report.Imported)
+	assert.Equal(t, 2, report.Resumed[SourceTypeQuestion])
+	newQuestionCount, err := db.Count(&entity.Question{})
+	require.NoError(t, err)
+	assert.Equal(t, questionCount, newQuestionCount)
+	user = &entity.User{}
+	_, err = db.ID(objectIDs["user2"]).Get(user)
+	require.NoError(t, err)
+	assert.Equal(t, 1, user.QuestionCount)
+}
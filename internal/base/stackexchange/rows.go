@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package stackexchange
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	postTypeQuestion = 1
+	postTypeAnswer   = 2
+
+	// the post history types that contain the markdown of post body
+	postHistoryInitialBody  = 2
+	postHistoryEditBody     = 5
+	postHistoryRollbackBody = 8
+
+	voteTypeAcceptedByOriginator = 1
+	voteTypeUpMod                = 2
+	voteTypeDownMod              = 3
+)
+
+// seTimeLayout the time in dump is in UTC without zone, eg: 2008-07-31T21:42:52.667
+const seTimeLayout = "2006-01-02T15:04:05.999"
+
+type seTime struct {
+	time.Time
+}
+
+func (t *seTime) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := time.ParseInLocation(seTimeLayout, attr.Value, time.UTC)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// Or returns the time if it is set, otherwise returns the default time
+func (t seTime) Or(defaultTime time.Time) time.Time {
+	if t.IsZero() {
+		return defaultTime
+	}
+	return t.Time
+}
+
+type userRow struct {
+	ID             string `xml:"Id,attr"`
+	Reputation     int    `xml:"Reputation,attr"`
+	CreationDate   seTime `xml:"CreationDate,attr"`
+	DisplayName    string `xml:"DisplayName,attr"`
+	LastAccessDate seTime `xml:"LastAccessDate,attr"`
+	WebsiteURL     string `xml:"WebsiteUrl,attr"`
+	Location       string `xml:"Location,attr"`
+	AboutMe        string `xml:"AboutMe,attr"`
+}
+
+type tagRow struct {
+	ID      string `xml:"Id,attr"`
+	TagName string `xml:"TagName,attr"`
+}
+
+type postRow struct {
+	ID               string `xml:"Id,attr"`
+	PostTypeID       int    `xml:"PostTypeId,attr"`
+	ParentID         string `xml:"ParentId,attr"`
+	AcceptedAnswerID string `xml:"AcceptedAnswerId,attr"`
+	CreationDate     seTime `xml:"CreationDate,attr"`
+	Score            int    `xml:"Score,attr"`
+	ViewCount        int    `xml:"ViewCount,attr"`
+	Body             string `xml:"Body,attr"`
+	OwnerUserID      string `xml:"OwnerUserId,attr"`
+	LastEditorUserID string `xml:"LastEditorUserId,attr"`
+	LastEditDate     seTime `xml:"LastEditDate,attr"`
+	LastActivityDate seTime `xml:"LastActivityDate,attr"`
+	Title            string `xml:"Title,attr"`
+	Tags             string `xml:"Tags,attr"`
+	ClosedDate       seTime `xml:"ClosedDate,attr"`
+}
+
+// TagNames the old dumps use "<a><b>" and the new dumps use "|a|b|"
+func (p *postRow) TagNames() []string {
+	return strings.FieldsFunc(p.Tags, func(r rune) bool {
+		return r == '<' || r == '>' || r == '|'
+	})
+}
+
+type postHistoryRow struct {
+	ID                string `xml:"Id,attr"`
+	PostHistoryTypeID int    `xml:"PostHistoryTypeId,attr"`
+	PostID            string `xml:"PostId,attr"`
+	Text              string `xml:"Text,attr"`
+}
+
+type voteRow struct {
+	ID         string `xml:"Id,attr"`
+	PostID     string `xml:"PostId,attr"`
+	VoteTypeID int    `xml:"VoteTypeId,attr"`
+}
+
+type commentRow struct {
+	ID           string `xml:"Id,attr"`
+	PostID       string `xml:"PostId,attr"`
+	Score        int    `xml:"Score,attr"`
+	Text         string `xml:"Text,attr"`
+	CreationDate seTime `xml:"CreationDate,attr"`
+	UserID       string `xml:"UserId,attr"`
+}
+
+// eachRow decode the rows of the dump file one by one, the dump file is usually too large to be loaded at once
+func eachRow[T any](path string, handle func(row *T) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(bufio.NewReader(file))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		row := new(T)
+		if err = decoder.DecodeElement(row, &start); err != nil {
+			return err
+		}
+		if err = handle(row); err != nil {
+			return err
+		}
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/apache/incubator-answer/internal/base/middleware"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/activity"
-	"github.com/apache/incubator-answer/internal/service/role"
 	"github.com/apache/incubator-answer/pkg/uid"
 	"github.com/gin-gonic/gin"
 )
@@ -58,9 +57,7 @@ func (ac *ActivityController) GetObjectTimeline(ctx *gin.Context) {
 	req.ObjectID = uid.DeShortID(req.ObjectID)
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	if userInfo := middleware.GetUserInfoFromContext(ctx); userInfo != nil {
-		req.IsAdmin = userInfo.RoleID == role.RoleAdminID
-	}
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
 
 	resp, err := ac.activityService.GetObjectTimeline(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
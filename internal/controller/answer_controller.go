@@ -31,6 +31,7 @@ import (
 	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/action"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/content"
 	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/rank"
@@ -47,6 +48,7 @@ type AnswerController struct {
 	actionService         *action.CaptchaService
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService
 	rateLimitMiddleware   *middleware.RateLimitMiddleware
+	auditLogService       *audit_log.AuditLogService
 }
 
 // NewAnswerController new controller
@@ -56,6 +58,7 @@ func NewAnswerController(
 	actionService *action.CaptchaService,
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
 	rateLimitMiddleware *middleware.RateLimitMiddleware,
+	auditLogService *audit_log.AuditLogService,
 ) *AnswerController {
 	return &AnswerController{
 		answerService:         answerService,
@@ -63,6 +66,7 @@ func NewAnswerController(
 		actionService:         actionService,
 		siteInfoCommonService: siteInfoCommonService,
 		rateLimitMiddleware:   rateLimitMiddleware,
+		auditLogService:       auditLogService,
 	}
 }
 
@@ -459,5 +463,15 @@ func (ac *AnswerController) AdminUpdateAnswerStatus(ctx *gin.Context) {
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
 	err := ac.answerService.AdminSetAnswerStatus(ctx, req)
+	if err == nil {
+		ac.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionUpdateAnswer,
+			ObjectType: schema.AuditObjectAnswer,
+			ObjectID:   req.AnswerID,
+			After:      req,
+		})
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
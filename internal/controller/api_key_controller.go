@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/api_key"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController api key controller
+type APIKeyController struct {
+	apiKeyService *api_key.APIKeyService
+}
+
+// NewAPIKeyController new controller
+func NewAPIKeyController(apiKeyService *api_key.APIKeyService) *APIKeyController {
+	return &APIKeyController{apiKeyService: apiKeyService}
+}
+
+// GetUserAPIKeyList get the api keys of current user
+// @Summary get the api keys of current user
+// @Description get the api keys of current user
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.APIKeyInfo}
+// @Router /answer/api/v1/user/api-keys [get]
+func (ac *APIKeyController) GetUserAPIKeyList(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := ac.apiKeyService.GetUserAPIKeyList(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddAPIKey add api key for current user
+// @Summary add api key for current user
+// @Description add api key for current user, the token is only returned once
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddAPIKeyReq true "api key"
+// @Success 200 {object} handler.RespBody{data=schema.AddAPIKeyResp}
+// @Router /answer/api/v1/user/api-key [post]
+func (ac *APIKeyController) AddAPIKey(ctx *gin.Context) {
+	req := &schema.AddAPIKeyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.CreatorUserID = req.UserID
+	resp, err := ac.apiKeyService.AddAPIKey(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RevokeAPIKey revoke api key of current user
+// @Summary revoke api key of current user
+// @Description revoke api key of current user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RevokeAPIKeyReq true "api key"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/api-key [delete]
+func (ac *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	req := &schema.RevokeAPIKeyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := ac.apiKeyService.RevokeAPIKey(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/bounty"
+	"github.com/apache/incubator-answer/internal/service/permission"
+	"github.com/apache/incubator-answer/internal/service/rank"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// BountyController bounty controller
+type BountyController struct {
+	bountyService *bounty.BountyService
+	rankService   *rank.RankService
+}
+
+// NewBountyController new controller
+func NewBountyController(
+	bountyService *bounty.BountyService,
+	rankService *rank.RankService,
+) *BountyController {
+	return &BountyController{
+		bountyService: bountyService,
+		rankService:   rankService,
+	}
+}
+
+// GetQuestionBounty get the bounty of question
+// @Summary get the bounty of question
+// @Description get the latest bounty of question, the data is null if the question never has a bounty
+// @Tags Bounty
+// @Produce json
+// @Param question_id query string true "question id"
+// @Success 200 {object} handler.RespBody{data=schema.QuestionBountyInfo}
+// @Router /answer/api/v1/question/bounty [get]
+func (bc *BountyController) GetQuestionBounty(ctx *gin.Context) {
+	req := &schema.GetQuestionBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := bc.bountyService.GetQuestionBounty(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// OfferBounty offer bounty on question
+// @Summary offer bounty on question
+// @Description escrow the reputation on the question, the bounty is awarded to the chosen answer or the top answer on expiry
+// @Tags Bounty
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.OfferBountyReq true "bounty"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/bounty [post]
+func (bc *BountyController) OfferBounty(ctx *gin.Context) {
+	req := &schema.OfferBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := bc.rankService.CheckOperationPermission(ctx, req.UserID, permission.QuestionBounty, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	err = bc.bountyService.OfferBounty(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// AwardBounty award bounty to answer
+// @Summary award bounty to answer
+// @Description award the active bounty of question to the chosen answer, only the user who offered the bounty can award it
+// @Tags Bounty
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AwardBountyReq true "bounty"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/bounty/award [post]
+func (bc *BountyController) AwardBounty(ctx *gin.Context) {
+	req := &schema.AwardBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := bc.bountyService.AwardBounty(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -53,4 +53,11 @@ var ProviderSetController = wire.NewSet(
 	NewEmbedController,
 	NewBadgeController,
 	NewRenderController,
+	NewAPIKeyController,
+	NewTwoFactorController,
+	NewQuestionMergeController,
+	NewBountyController,
+	NewDraftController,
+	NewFeedController,
+	NewSavedSearchController,
 )
@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package controller
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/service/feed"
+	"github.com/gin-gonic/gin"
+)
+
+// FeedController feed controller
+type FeedController struct {
+	feedService *feed.FeedService
+}
+
+// NewFeedController new controller
+func NewFeedController(feedService *feed.FeedService) *FeedController {
+	return &FeedController{feedService: feedService}
+}
+
+// GetFeedToken get feed token
+// @Summary get feed token
+// @Description get the feed token of user, it is required to read the feeds of private site
+// @Tags Feed
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.GetFeedTokenResp}
+// @Router /answer/api/v1/user/feed/token [get]
+func (fc *FeedController) GetFeedToken(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := fc.feedService.GetFeedToken(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// ResetFeedToken reset feed token
+// @Summary reset feed token
+// @Description generate a new feed token, the feed urls with the old token will be unavailable
+// @Tags Feed
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.GetFeedTokenResp}
+// @Router /answer/api/v1/user/feed/token/reset [post]
+func (fc *FeedController) ResetFeedToken(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := fc.feedService.ResetFeedToken(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -20,29 +20,52 @@
 package controller
 
 import (
+	"time"
+
 	"github.com/apache/incubator-answer/internal/base/handler"
 	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/api_key"
+	"github.com/apache/incubator-answer/internal/service/auth"
 	"github.com/apache/incubator-answer/internal/service/notification"
+	"github.com/apache/incubator-answer/internal/service/notification_push"
 	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/rank"
+	"github.com/apache/incubator-answer/pkg/uid"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// notificationStreamHeartbeat keep the stream alive through the proxies
+	notificationStreamHeartbeat = 30 * time.Second
+	// notificationStreamCheckInterval check the token and the user status of the stream, the same as the push poll
+	notificationStreamCheckInterval = time.Second
+)
+
 // NotificationController notification controller
 type NotificationController struct {
-	notificationService *notification.NotificationService
-	rankService         *rank.RankService
+	notificationService     *notification.NotificationService
+	notificationPushService *notification_push.NotificationPushService
+	rankService             *rank.RankService
+	authService             *auth.AuthService
+	apiKeyService           *api_key.APIKeyService
 }
 
 // NewNotificationController new controller
 func NewNotificationController(
 	notificationService *notification.NotificationService,
+	notificationPushService *notification_push.NotificationPushService,
 	rankService *rank.RankService,
+	authService *auth.AuthService,
+	apiKeyService *api_key.APIKeyService,
 ) *NotificationController {
 	return &NotificationController{
-		notificationService: notificationService,
-		rankService:         rankService,
+		notificationService:     notificationService,
+		notificationPushService: notificationPushService,
+		rankService:             rankService,
+		authService:             authService,
+		apiKeyService:           apiKeyService,
 	}
 }
 
@@ -56,7 +79,121 @@ func NewNotificationController(
 // @Success 200 {object} handler.RespBody
 // @Router /answer/api/v1/notification/status [get]
 func (nc *NotificationController) GetRedDot(ctx *gin.Context) {
-	req := &schema.GetRedDot{}
+	req, err := nc.getRedDotReq(ctx)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+
+	resp, err := nc.notificationService.GetRedDot(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetNotificationStream
+// @Summary get notification stream
+// @Description keep the connection and push the new notifications, red dot, new answers of the viewing question
+// @Description and review queue changes by server-sent events
+// @Tags Notification
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param question_id query string false "the question being viewed"
+// @Success 200 {string} string "event stream"
+// @Router /answer/api/v1/notification/stream [get]
+func (nc *NotificationController) GetNotificationStream(ctx *gin.Context) {
+	req := &schema.GetNotificationStreamReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	redDotReq, err := nc.getRedDotReq(ctx)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	canReview := redDotReq.IsAdmin || redDotReq.CanReviewQuestion || redDotReq.CanReviewAnswer ||
+		redDotReq.CanReviewTag
+	if len(req.QuestionID) > 0 {
+		req.QuestionID = uid.DeShortID(req.QuestionID)
+	}
+
+	token := middleware.ExtractToken(ctx)
+
+	sub := nc.notificationPushService.Subscribe(redDotReq.UserID, req.QuestionID)
+	defer nc.notificationPushService.Unsubscribe(sub)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	getRedDot := func() (any, error) {
+		return nc.notificationService.GetRedDot(ctx, redDotReq)
+	}
+	// the red dot is computed once for all the sessions of user receiving the same event
+	pushRedDot := func(eventType string, seq int64) {
+		redDot, err := nc.notificationPushService.GetRedDot(redDotReq.UserID, seq, getRedDot)
+		if err == nil {
+			ctx.SSEvent(eventType, redDot)
+		}
+	}
+	if redDot, err := getRedDot(); err == nil {
+		ctx.SSEvent(schema.PushEventTypeRedDot, redDot)
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(notificationStreamHeartbeat)
+	defer heartbeat.Stop()
+	check := time.NewTicker(notificationStreamCheckInterval)
+	defer check.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-check.C:
+			// the stream is closed once the user logs out, is suspended or deleted
+			if !nc.checkStreamUser(ctx, token) {
+				return
+			}
+			continue
+		case <-heartbeat.C:
+			_, _ = ctx.Writer.WriteString(": heartbeat\n\n")
+		case event := <-sub.Events:
+			switch event.Type {
+			case schema.PushEventTypeNotification:
+				ctx.SSEvent(event.Type, event.Data)
+				pushRedDot(schema.PushEventTypeRedDot, event.Seq)
+			case schema.PushEventTypeRedDot:
+				pushRedDot(schema.PushEventTypeRedDot, event.Seq)
+			case schema.PushEventTypeReview:
+				if !canReview {
+					continue
+				}
+				pushRedDot(schema.PushEventTypeReview, event.Seq)
+			case schema.PushEventTypeNewAnswer:
+				ctx.SSEvent(event.Type, event.Data)
+			}
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// checkStreamUser check the token of the stream is still valid and the user is still available
+func (nc *NotificationController) checkStreamUser(ctx *gin.Context, token string) bool {
+	var (
+		userInfo *entity.UserCacheInfo
+		err      error
+	)
+	if api_key.IsAPIKeyToken(token) {
+		userInfo, _, err = nc.apiKeyService.GetUserCacheInfo(ctx, token)
+	} else {
+		userInfo, err = nc.authService.GetUserCacheInfo(ctx, token)
+	}
+	if err != nil || userInfo == nil {
+		return false
+	}
+	return userInfo.UserStatus != entity.UserStatusSuspended && userInfo.UserStatus != entity.UserStatusDeleted
+}
+
+func (nc *NotificationController) getRedDotReq(ctx *gin.Context) (req *schema.GetRedDot, err error) {
+	req = &schema.GetRedDot{}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	canList, err := nc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
 		permission.QuestionAudit,
@@ -64,16 +201,13 @@ func (nc *NotificationController) GetRedDot(ctx *gin.Context) {
 		permission.TagAudit,
 	})
 	if err != nil {
-		handler.HandleResponse(ctx, err, nil)
-		return
+		return nil, err
 	}
 	req.CanReviewQuestion = canList[0]
 	req.CanReviewAnswer = canList[1]
 	req.CanReviewTag = canList[2]
 	req.IsAdmin = middleware.GetUserIsAdminModerator(ctx)
-
-	resp, err := nc.notificationService.GetRedDot(ctx, req)
-	handler.HandleResponse(ctx, err, resp)
+	return req, nil
 }
 
 // ClearRedDot
@@ -106,6 +240,9 @@ func (nc *NotificationController) ClearRedDot(ctx *gin.Context) {
 	req.CanReviewTag = canList[2]
 
 	resp, err := nc.notificationService.ClearRedDot(ctx, req)
+	if err == nil {
+		nc.notificationPushService.PushRedDot(ctx, req.UserID)
+	}
 	handler.HandleResponse(ctx, err, resp)
 }
 
@@ -31,6 +31,7 @@ import (
 	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/action"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/content"
 	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/rank"
@@ -49,6 +50,7 @@ type QuestionController struct {
 	siteInfoService     siteinfo_common.SiteInfoCommonService
 	actionService       *action.CaptchaService
 	rateLimitMiddleware *middleware.RateLimitMiddleware
+	auditLogService     *audit_log.AuditLogService
 }
 
 // NewQuestionController new controller
@@ -59,6 +61,7 @@ func NewQuestionController(
 	siteInfoService siteinfo_common.SiteInfoCommonService,
 	actionService *action.CaptchaService,
 	rateLimitMiddleware *middleware.RateLimitMiddleware,
+	auditLogService *audit_log.AuditLogService,
 ) *QuestionController {
 	return &QuestionController{
 		questionService:     questionService,
@@ -67,6 +70,7 @@ func NewQuestionController(
 		siteInfoService:     siteInfoService,
 		actionService:       actionService,
 		rateLimitMiddleware: rateLimitMiddleware,
+		auditLogService:     auditLogService,
 	}
 }
 
@@ -976,6 +980,16 @@ func (qc *QuestionController) AdminUpdateQuestionStatus(ctx *gin.Context) {
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
 	err := qc.questionService.AdminSetQuestionStatus(ctx, req)
+	if err == nil {
+		qc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionUpdateQuestion,
+			ObjectType: schema.AuditObjectQuestion,
+			ObjectID:   req.QuestionID,
+			After:      req,
+		})
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
+	"github.com/apache/incubator-answer/internal/service/permission"
+	"github.com/apache/incubator-answer/internal/service/question_merge"
+	"github.com/apache/incubator-answer/internal/service/rank"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// QuestionMergeController question merge controller
+type QuestionMergeController struct {
+	questionMergeService *question_merge.QuestionMergeService
+	auditLogService      *audit_log.AuditLogService
+	rankService          *rank.RankService
+}
+
+// NewQuestionMergeController new controller
+func NewQuestionMergeController(
+	questionMergeService *question_merge.QuestionMergeService,
+	auditLogService *audit_log.AuditLogService,
+	rankService *rank.RankService,
+) *QuestionMergeController {
+	return &QuestionMergeController{
+		questionMergeService: questionMergeService,
+		auditLogService:      auditLogService,
+		rankService:          rankService,
+	}
+}
+
+// MergeQuestion merge the duplicate question into the canonical question
+// @Summary merge the duplicate question into the canonical question
+// @Description move the answers (and optionally the comments) to the target question and close the question as a duplicate
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.MergeQuestionReq true "question merge"
+// @Success 200 {object} handler.RespBody{data=schema.MergeQuestionResp}
+// @Router /answer/api/v1/question/merge [post]
+func (qc *QuestionMergeController) MergeQuestion(ctx *gin.Context) {
+	req := &schema.MergeQuestionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := qc.rankService.CheckOperationPermission(ctx, req.UserID, permission.QuestionMerge, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	resp, err := qc.questionMergeService.MergeQuestion(ctx, req)
+	if err == nil {
+		qc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionMergeQuestion,
+			ObjectType: schema.AuditObjectQuestion,
+			ObjectID:   req.QuestionID,
+			After:      resp,
+		})
+	}
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RevertQuestionMerge revert the question merge
+// @Summary revert the question merge
+// @Description move the answers and comments back to the merged question and restore its status
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RevertQuestionMergeReq true "question merge"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/merge/revert [post]
+func (qc *QuestionMergeController) RevertQuestionMerge(ctx *gin.Context) {
+	req := &schema.RevertQuestionMergeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := qc.rankService.CheckOperationPermission(ctx, req.UserID, permission.QuestionMerge, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	err = qc.questionMergeService.RevertQuestionMerge(ctx, req)
+	if err == nil {
+		qc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionRevertQuestionMerge,
+			ObjectType: schema.AuditObjectQuestion,
+			ObjectID:   req.QuestionID,
+		})
+	}
+	handler.HandleResponse(ctx, err, nil)
+}
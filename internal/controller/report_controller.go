@@ -28,6 +28,7 @@ import (
 	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/action"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/rank"
 	"github.com/apache/incubator-answer/internal/service/report"
@@ -38,9 +39,10 @@ import (
 
 // ReportController report controller
 type ReportController struct {
-	reportService *report.ReportService
-	rankService   *rank.RankService
-	actionService *action.CaptchaService
+	reportService   *report.ReportService
+	rankService     *rank.RankService
+	actionService   *action.CaptchaService
+	auditLogService *audit_log.AuditLogService
 }
 
 // NewReportController new controller
@@ -48,11 +50,13 @@ func NewReportController(
 	reportService *report.ReportService,
 	rankService *rank.RankService,
 	actionService *action.CaptchaService,
+	auditLogService *audit_log.AuditLogService,
 ) *ReportController {
 	return &ReportController{
-		reportService: reportService,
-		rankService:   rankService,
-		actionService: actionService,
+		reportService:   reportService,
+		rankService:     rankService,
+		actionService:   actionService,
+		auditLogService: auditLogService,
 	}
 }
 
@@ -152,5 +156,15 @@ func (rc *ReportController) ReviewReport(ctx *gin.Context) {
 	}
 
 	err := rc.reportService.ReviewReport(ctx, req)
+	if err == nil {
+		rc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionReviewReport,
+			ObjectType: schema.AuditObjectReport,
+			ObjectID:   req.FlagID,
+			After:      req,
+		})
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
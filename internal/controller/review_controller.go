@@ -20,11 +20,14 @@
 package controller
 
 import (
+	"strconv"
+
 	"github.com/apache/incubator-answer/internal/base/handler"
 	"github.com/apache/incubator-answer/internal/base/middleware"
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/action"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/rank"
 	"github.com/apache/incubator-answer/internal/service/review"
 	"github.com/apache/incubator-answer/plugin"
@@ -34,9 +37,10 @@ import (
 
 // ReviewController review controller
 type ReviewController struct {
-	reviewService *review.ReviewService
-	rankService   *rank.RankService
-	actionService *action.CaptchaService
+	reviewService   *review.ReviewService
+	rankService     *rank.RankService
+	actionService   *action.CaptchaService
+	auditLogService *audit_log.AuditLogService
 }
 
 // NewReviewController new controller
@@ -44,11 +48,13 @@ func NewReviewController(
 	reviewService *review.ReviewService,
 	rankService *rank.RankService,
 	actionService *action.CaptchaService,
+	auditLogService *audit_log.AuditLogService,
 ) *ReviewController {
 	return &ReviewController{
-		reviewService: reviewService,
-		rankService:   rankService,
-		actionService: actionService,
+		reviewService:   reviewService,
+		rankService:     rankService,
+		actionService:   actionService,
+		auditLogService: auditLogService,
 	}
 }
 
@@ -108,5 +114,15 @@ func (rc *ReviewController) UpdateReview(ctx *gin.Context) {
 	}
 
 	err := rc.reviewService.UpdateReview(ctx, req)
+	if err == nil {
+		rc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionReviewRevision,
+			ObjectType: schema.AuditObjectReview,
+			ObjectID:   strconv.Itoa(req.ReviewID),
+			After:      req,
+		})
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
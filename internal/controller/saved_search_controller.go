@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/saved_search"
+	"github.com/gin-gonic/gin"
+)
+
+// SavedSearchController saved search controller
+type SavedSearchController struct {
+	savedSearchService *saved_search.SavedSearchService
+}
+
+// NewSavedSearchController new controller
+func NewSavedSearchController(savedSearchService *saved_search.SavedSearchService) *SavedSearchController {
+	return &SavedSearchController{savedSearchService: savedSearchService}
+}
+
+// GetSavedSearchList get saved search list
+// @Summary get saved search list
+// @Description get all saved searches of current user, the latest created first
+// @Tags SavedSearch
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.SavedSearchInfo}
+// @Router /answer/api/v1/user/saved-searches [get]
+func (sc *SavedSearchController) GetSavedSearchList(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.savedSearchService.GetSavedSearchList(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddSavedSearch add saved search
+// @Summary add saved search
+// @Description save the search query, the new contents matched by it can be alerted by inbox, email or notification plugin
+// @Tags SavedSearch
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddSavedSearchReq true "saved search"
+// @Success 200 {object} handler.RespBody{data=schema.SavedSearchInfo}
+// @Router /answer/api/v1/user/saved-search [post]
+func (sc *SavedSearchController) AddSavedSearch(ctx *gin.Context) {
+	req := &schema.AddSavedSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.savedSearchService.AddSavedSearch(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateSavedSearch update saved search
+// @Summary update saved search
+// @Description update the query and alert settings of saved search
+// @Tags SavedSearch
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateSavedSearchReq true "saved search"
+// @Success 200 {object} handler.RespBody{data=schema.SavedSearchInfo}
+// @Router /answer/api/v1/user/saved-search [put]
+func (sc *SavedSearchController) UpdateSavedSearch(ctx *gin.Context) {
+	req := &schema.UpdateSavedSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.savedSearchService.UpdateSavedSearch(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveSavedSearch remove saved search
+// @Summary remove saved search
+// @Description remove saved search
+// @Tags SavedSearch
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RemoveSavedSearchReq true "saved search"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/saved-search [delete]
+func (sc *SavedSearchController) RemoveSavedSearch(ctx *gin.Context) {
+	req := &schema.RemoveSavedSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.savedSearchService.RemoveSavedSearch(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -59,6 +59,8 @@ func NewSearchController(
 // @Security ApiKeyAuth
 // @Param q query string true "query string"
 // @Param order query string true "order" Enums(newest,active,score,relevance)
+// @Param include_sub_tags query bool false "the [tag] in query also matches the sub tags"
+// @Param facets query bool false "return the facets of all matched contents"
 // @Success 200 {object} handler.RespBody{data=schema.SearchResp}
 // @Router /answer/api/v1/search [get]
 func (sc *SearchController) Search(ctx *gin.Context) {
@@ -25,6 +25,7 @@ import (
 	"github.com/apache/incubator-answer/internal/base/pager"
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/rank"
 	"github.com/apache/incubator-answer/internal/service/tag"
@@ -38,6 +39,7 @@ type TagController struct {
 	tagService       *tag.TagService
 	tagCommonService *tag_common.TagCommonService
 	rankService      *rank.RankService
+	auditLogService  *audit_log.AuditLogService
 }
 
 // NewTagController new controller
@@ -45,8 +47,14 @@ func NewTagController(
 	tagService *tag.TagService,
 	tagCommonService *tag_common.TagCommonService,
 	rankService *rank.RankService,
+	auditLogService *audit_log.AuditLogService,
 ) *TagController {
-	return &TagController{tagService: tagService, tagCommonService: tagCommonService, rankService: rankService}
+	return &TagController{
+		tagService:       tagService,
+		tagCommonService: tagCommonService,
+		rankService:      rankService,
+		auditLogService:  auditLogService,
+	}
 }
 
 // SearchTagLike get tag list
@@ -346,3 +354,107 @@ func (tc *TagController) UpdateTagSynonym(ctx *gin.Context) {
 	err = tc.tagService.UpdateTagSynonym(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
+
+// MergeTag merge tag
+// @Summary merge tag
+// @Description move the questions and followers of source tag to target tag, the source tag is deleted or kept as a synonym
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.MergeTagReq true "tag"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/merge [post]
+func (tc *TagController) MergeTag(ctx *gin.Context) {
+	req := &schema.MergeTagReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	// merging makes the source tag a synonym, and deletes it if it is not kept
+	actions := []string{permission.TagSynonym}
+	if !req.KeepAsSynonym {
+		actions = append(actions, permission.TagDelete)
+	}
+	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, actions)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	for _, can := range canList {
+		if !can {
+			handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+			return
+		}
+	}
+
+	err = tc.tagService.MergeTag(ctx, req)
+	if err == nil {
+		tc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionMergeTag,
+			ObjectType: schema.AuditObjectTag,
+			ObjectID:   req.SourceTagID,
+			After:      req,
+		})
+	}
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateTagParent update the parent of tag
+// @Summary update the parent of tag
+// @Description set the parent of tag to build the tag hierarchy, the empty parent tag id means the tag is a root tag
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateTagParentReq true "tag"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/parent [put]
+func (tc *TagController) UpdateTagParent(ctx *gin.Context) {
+	req := &schema.UpdateTagParentReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	if !middleware.GetUserIsAdminModerator(ctx) {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.ForbiddenError), nil)
+		return
+	}
+
+	err := tc.tagService.UpdateTagParent(ctx, req)
+	if err == nil {
+		tc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			IP:         ctx.ClientIP(),
+			Action:     schema.AuditActionUpdateTagParent,
+			ObjectType: schema.AuditObjectTag,
+			ObjectID:   req.TagID,
+			After:      req,
+		})
+	}
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetTagTree get tag tree
+// @Summary get tag tree
+// @Description get the direct sub tags of tag, or the root tags if tag_id is empty
+// @Tags Tag
+// @Produce json
+// @Param tag_id query string false "parent tag id"
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetTagTreeResp}}
+// @Router /answer/api/v1/tags/tree [get]
+func (tc *TagController) GetTagTree(ctx *gin.Context) {
+	req := &schema.GetTagTreeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := tc.tagService.GetTagTree(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/apache/incubator-answer/internal/base/pager"
 	"github.com/apache/incubator-answer/internal/service/content"
 	"github.com/apache/incubator-answer/internal/service/event_queue"
+	"github.com/apache/incubator-answer/internal/service/feed"
+	"github.com/apache/incubator-answer/internal/service/question_merge"
 	"github.com/apache/incubator-answer/plugin"
 	"html/template"
 	"net/http"
@@ -43,6 +45,7 @@ import (
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
 	"github.com/apache/incubator-answer/pkg/checker"
 	"github.com/apache/incubator-answer/pkg/converter"
+	"github.com/apache/incubator-answer/pkg/display"
 	"github.com/apache/incubator-answer/pkg/htmltext"
 	"github.com/apache/incubator-answer/pkg/obj"
 	"github.com/apache/incubator-answer/pkg/uid"
@@ -61,6 +64,8 @@ type TemplateController struct {
 	eventQueueService        event_queue.EventQueueService
 	userService              *content.UserService
 	questionService          *content.QuestionService
+	questionMergeService     *question_merge.QuestionMergeService
+	feedService              *feed.FeedService
 }
 
 // NewTemplateController new controller
@@ -70,6 +75,8 @@ func NewTemplateController(
 	eventQueueService event_queue.EventQueueService,
 	userService *content.UserService,
 	questionService *content.QuestionService,
+	questionMergeService *question_merge.QuestionMergeService,
+	feedService *feed.FeedService,
 ) *TemplateController {
 	script, css := GetStyle()
 	return &TemplateController{
@@ -80,6 +87,8 @@ func NewTemplateController(
 		eventQueueService:        eventQueueService,
 		userService:              userService,
 		questionService:          questionService,
+		questionMergeService:     questionMergeService,
+		feedService:              feedService,
 	}
 }
 func GetStyle() (script []string, css string) {
@@ -221,6 +230,14 @@ func (tc *TemplateController) QuestionList(ctx *gin.Context) {
 func (tc *TemplateController) QuestionInfoRedirect(ctx *gin.Context, siteInfo *schema.TemplateSiteInfoResp, correctTitle bool) (jump bool, url string) {
 	questionID := ctx.Param("id")
 	title := ctx.Param("title")
+	// the merged question always jumps to the question it is merged into
+	if targetQuestionID, merged := tc.questionMergeService.GetMergedTargetQuestionID(ctx, questionID); merged {
+		targetDetail, err := tc.templateRenderController.QuestionDetail(ctx, targetQuestionID)
+		if err == nil {
+			return true, display.QuestionURL(siteInfo.SiteSeo.Permalink, siteInfo.General.SiteUrl,
+				targetQuestionID, targetDetail.Title)
+		}
+	}
 	answerID := uid.DeShortID(title)
 	titleIsAnswerID := false
 	needChangeShortID := false
@@ -667,6 +684,163 @@ func (tc *TemplateController) SitemapPage(ctx *gin.Context) {
 	}
 }
 
+// FeedQuestions the feed of the newest or active questions
+func (tc *TemplateController) FeedQuestions(ctx *gin.Context) {
+	format, ok := tc.checkFeedAccess(ctx)
+	if !ok {
+		tc.Page404(ctx)
+		return
+	}
+	req := &schema.FeedQuestionListReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	if len(req.OrderCond) == 0 {
+		req.OrderCond = schema.QuestionOrderCondNewest
+	}
+	items, err := tc.templateRenderController.QuestionFeedItems(ctx, &schema.QuestionPageReq{OrderCond: req.OrderCond})
+	if err != nil {
+		tc.Page404(ctx)
+		return
+	}
+
+	siteInfo := tc.SiteInfo(ctx)
+	title := translator.Tr(handler.GetLang(ctx), constant.FeedNewestQuestionsTitleTrKey)
+	link := fmt.Sprintf("%s/questions", siteInfo.General.SiteUrl)
+	if req.OrderCond == schema.QuestionOrderCondActive {
+		title = translator.Tr(handler.GetLang(ctx), constant.FeedActiveQuestionsTitleTrKey)
+		link = fmt.Sprintf("%s/questions?order=%s", siteInfo.General.SiteUrl, req.OrderCond)
+	}
+	tc.renderFeed(ctx, format, siteInfo, title, link, items)
+}
+
+// FeedTag the feed of the newest questions of the tag
+func (tc *TemplateController) FeedTag(ctx *gin.Context) {
+	format, ok := tc.checkFeedAccess(ctx)
+	if !ok {
+		tc.Page404(ctx)
+		return
+	}
+	tagInfo, items, err := tc.templateRenderController.TagFeedItems(ctx, ctx.Param("tag"))
+	if err != nil {
+		tc.Page404(ctx)
+		return
+	}
+
+	siteInfo := tc.SiteInfo(ctx)
+	title := translator.TrWithData(handler.GetLang(ctx), constant.FeedTagQuestionsTitleTrKey,
+		&schema.FeedTitleTplData{TagName: tagInfo.DisplayName})
+	link := fmt.Sprintf("%s/tags/%s", siteInfo.General.SiteUrl, url.PathEscape(tagInfo.SlugName))
+	tc.renderFeed(ctx, format, siteInfo, title, link, items)
+}
+
+// FeedUser the feed of the newest questions and answers of the user
+func (tc *TemplateController) FeedUser(ctx *gin.Context) {
+	format, ok := tc.checkFeedAccess(ctx)
+	if !ok {
+		tc.Page404(ctx)
+		return
+	}
+	userInfo, err := tc.templateRenderController.UserInfo(ctx,
+		&schema.GetOtherUserInfoByUsernameReq{Username: ctx.Param("username")})
+	if err != nil {
+		tc.Page404(ctx)
+		return
+	}
+	items, err := tc.templateRenderController.UserFeedItems(ctx, userInfo)
+	if err != nil {
+		tc.Page404(ctx)
+		return
+	}
+
+	siteInfo := tc.SiteInfo(ctx)
+	title := translator.TrWithData(handler.GetLang(ctx), constant.FeedUserActivityTitleTrKey,
+		&schema.FeedTitleTplData{DisplayName: userInfo.DisplayName})
+	tc.renderFeed(ctx, format, siteInfo, title, display.UserURL(siteInfo.General.SiteUrl, userInfo.Username), items)
+}
+
+// FeedQuestionAnswers the feed of the newest answers of the question
+func (tc *TemplateController) FeedQuestionAnswers(ctx *gin.Context) {
+	format, ok := tc.checkFeedAccess(ctx)
+	if !ok {
+		tc.Page404(ctx)
+		return
+	}
+	question, err := tc.templateRenderController.QuestionDetail(ctx, ctx.Param("id"))
+	if err != nil {
+		tc.Page404(ctx)
+		return
+	}
+	items, err := tc.templateRenderController.AnswerFeedItems(ctx, question)
+	if err != nil {
+		tc.Page404(ctx)
+		return
+	}
+
+	siteInfo := tc.SiteInfo(ctx)
+	title := translator.TrWithData(handler.GetLang(ctx), constant.FeedQuestionAnswersTitleTrKey,
+		&schema.FeedTitleTplData{QuestionTitle: question.Title})
+	link := display.QuestionURL(siteInfo.SiteSeo.Permalink, siteInfo.General.SiteUrl, question.ID, question.Title)
+	tc.renderFeed(ctx, format, siteInfo, title, link, items)
+}
+
+// FeedSearch the feed of the newest results of the search query
+func (tc *TemplateController) FeedSearch(ctx *gin.Context) {
+	format, ok := tc.checkFeedAccess(ctx)
+	if !ok {
+		tc.Page404(ctx)
+		return
+	}
+	req := &schema.FeedSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	items, err := tc.templateRenderController.SearchFeedItems(ctx, req.Query)
+	if err != nil {
+		tc.Page404(ctx)
+		return
+	}
+
+	siteInfo := tc.SiteInfo(ctx)
+	title := translator.TrWithData(handler.GetLang(ctx), constant.FeedSearchTitleTrKey,
+		&schema.FeedTitleTplData{Query: req.Query})
+	link := fmt.Sprintf("%s/search?q=%s", siteInfo.General.SiteUrl, url.QueryEscape(req.Query))
+	tc.renderFeed(ctx, format, siteInfo, title, link, items)
+}
+
+func (tc *TemplateController) renderFeed(ctx *gin.Context, format string, siteInfo *schema.TemplateSiteInfoResp,
+	title, link string, items []*schema.FeedItem) {
+	// the request uri already contains the base path of site url
+	selfLink := siteInfo.General.SiteUrl + ctx.Request.URL.RequestURI()
+	if parsedUrl, err := url.Parse(siteInfo.General.SiteUrl); err == nil {
+		selfLink = parsedUrl.Scheme + "://" + parsedUrl.Host + ctx.Request.URL.RequestURI()
+	}
+	tc.templateRenderController.Feed(ctx, format, &schema.FeedInfo{
+		SiteName:    siteInfo.General.Name,
+		Title:       fmt.Sprintf("%s - %s", title, siteInfo.General.Name),
+		Description: siteInfo.General.Description,
+		Link:        link,
+		SelfLink:    selfLink,
+		Items:       items,
+	})
+}
+
+// checkFeedAccess check the format of feed, the feed token is required when the site is private
+func (tc *TemplateController) checkFeedAccess(ctx *gin.Context) (format string, ok bool) {
+	format = ctx.Param("format")
+	if format != schema.FeedFormatAtom && format != schema.FeedFormatRSS {
+		return "", false
+	}
+	if !tc.checkPrivateMode(ctx) {
+		return format, true
+	}
+	valid, err := tc.feedService.CheckFeedToken(ctx, ctx.Query(schema.FeedTokenQueryKey))
+	if err != nil {
+		log.Error(err)
+	}
+	return format, valid
+}
+
 func (tc *TemplateController) checkPrivateMode(ctx *gin.Context) bool {
 	resp, err := tc.siteInfoService.GetSiteLogin(ctx)
 	if err != nil {
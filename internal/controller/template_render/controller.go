@@ -46,6 +46,7 @@ type TemplateRenderController struct {
 	commentService  *comment.CommentService
 	siteInfoService siteinfo_common.SiteInfoCommonService
 	questionRepo    questioncommon.QuestionRepo
+	searchService   *content.SearchService
 }
 
 func NewTemplateRenderController(
@@ -56,6 +57,7 @@ func NewTemplateRenderController(
 	commentService *comment.CommentService,
 	siteInfoService siteinfo_common.SiteInfoCommonService,
 	questionRepo questioncommon.QuestionRepo,
+	searchService *content.SearchService,
 ) *TemplateRenderController {
 	return &TemplateRenderController{
 		questionService: questionService,
@@ -65,6 +67,7 @@ func NewTemplateRenderController(
 		commentService:  commentService,
 		questionRepo:    questionRepo,
 		siteInfoService: siteInfoService,
+		searchService:   searchService,
 	}
 }
 
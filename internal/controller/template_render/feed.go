@@ -0,0 +1,262 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package templaterender
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/translator"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/pkg/display"
+	"github.com/apache/incubator-answer/pkg/uid"
+	"github.com/gin-gonic/gin"
+)
+
+// Feed render the feed in atom or rss format
+func (t *TemplateRenderController) Feed(ctx *gin.Context, format string, feed *schema.FeedInfo) {
+	if feed.UpdatedAt.IsZero() {
+		feed.UpdatedAt = time.Now()
+		if len(feed.Items) > 0 {
+			feed.UpdatedAt = feed.Items[0].UpdatedAt
+		}
+		for _, item := range feed.Items {
+			if item.UpdatedAt.After(feed.UpdatedAt) {
+				feed.UpdatedAt = item.UpdatedAt
+			}
+		}
+	}
+	tpl := "feed-atom.xml"
+	ctx.Header("Content-Type", "application/atom+xml; charset=utf-8")
+	if format == schema.FeedFormatRSS {
+		tpl = "feed-rss.xml"
+		ctx.Header("Content-Type", "application/rss+xml; charset=utf-8")
+	}
+	ctx.HTML(http.StatusOK, tpl, gin.H{
+		"xmlHeader": template.HTML(`<?xml version="1.0" encoding="UTF-8"?>`),
+		"feed":      feed,
+	})
+}
+
+// QuestionFeedItems get the first page of questions as feed items
+func (t *TemplateRenderController) QuestionFeedItems(ctx *gin.Context, req *schema.QuestionPageReq) (
+	items []*schema.FeedItem, err error) {
+	siteUrl, permalink, err := t.feedLinkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Page, req.PageSize = 1, schema.FeedMaxSize
+	questions, _, err := t.questionService.GetQuestionPage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	items = make([]*schema.FeedItem, 0, len(questions))
+	for _, question := range questions {
+		item := &schema.FeedItem{
+			ID:        feedQuestionID(siteUrl, question.ID),
+			Title:     question.Title,
+			Link:      display.QuestionURL(permalink, siteUrl, question.ID, question.Title),
+			Content:   question.Description,
+			CreatedAt: time.Unix(question.CreatedAt, 0),
+			UpdatedAt: time.Unix(question.OperatedAt, 0),
+		}
+		if question.Operator != nil {
+			item.Author = question.Operator.DisplayName
+		}
+		for _, tag := range question.Tags {
+			item.Tags = append(item.Tags, tag.DisplayName)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// TagFeedItems get the newest questions of the tag as feed items
+func (t *TemplateRenderController) TagFeedItems(ctx *gin.Context, tagName string) (
+	tagInfo *schema.GetTagResp, items []*schema.FeedItem, err error) {
+	tagInfo, err = t.tagService.GetTagInfo(ctx, &schema.GetTagInfoReq{Name: tagName})
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err = t.QuestionFeedItems(ctx, &schema.QuestionPageReq{
+		OrderCond: schema.QuestionOrderCondNewest,
+		Tag:       tagInfo.SlugName,
+	})
+	return tagInfo, items, err
+}
+
+// AnswerFeedItems get the newest answers of the question as feed items
+func (t *TemplateRenderController) AnswerFeedItems(ctx *gin.Context, question *schema.QuestionInfoResp) (
+	items []*schema.FeedItem, err error) {
+	siteUrl, permalink, err := t.feedLinkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	answers, _, err := t.answerService.SearchList(ctx, &schema.AnswerListReq{
+		QuestionID: question.ID,
+		Order:      entity.AnswerSearchOrderByTime,
+		Page:       1,
+		PageSize:   schema.FeedMaxSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	title := translator.TrWithData(handler.GetLang(ctx), constant.FeedAnswerTitleTrKey,
+		&schema.FeedTitleTplData{QuestionTitle: question.Title})
+	items = make([]*schema.FeedItem, 0, len(answers))
+	for _, answer := range answers {
+		item := &schema.FeedItem{
+			ID:        feedAnswerID(siteUrl, question.ID, answer.ID),
+			Title:     title,
+			Link:      display.AnswerURL(permalink, siteUrl, question.ID, question.Title, answer.ID),
+			Content:   answer.HTML,
+			CreatedAt: time.Unix(answer.CreateTime, 0),
+			UpdatedAt: time.Unix(max(answer.CreateTime, answer.UpdateTime), 0),
+		}
+		if answer.UserInfo != nil {
+			item.Author = answer.UserInfo.DisplayName
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// UserFeedItems get the newest questions and answers of the user as feed items
+func (t *TemplateRenderController) UserFeedItems(ctx *gin.Context, userInfo *schema.GetOtherUserInfoByUsernameResp) (
+	items []*schema.FeedItem, err error) {
+	siteUrl, permalink, err := t.feedLinkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err = t.QuestionFeedItems(ctx, &schema.QuestionPageReq{
+		OrderCond: schema.QuestionOrderCondNewest,
+		Username:  userInfo.Username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	answerPage, err := t.questionService.PersonalAnswerPage(ctx, &schema.PersonalAnswerPageReq{
+		Page:      1,
+		PageSize:  schema.FeedMaxSize,
+		OrderCond: schema.QuestionOrderCondNewest,
+		Username:  userInfo.Username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	answers, _ := answerPage.List.([]*schema.UserAnswerInfo)
+	lang := handler.GetLang(ctx)
+	for _, answer := range answers {
+		createdAt := time.Unix(int64(answer.CreateTime), 0)
+		items = append(items, &schema.FeedItem{
+			ID: feedAnswerID(siteUrl, answer.QuestionID, answer.AnswerID),
+			Title: translator.TrWithData(lang, constant.FeedAnswerTitleTrKey,
+				&schema.FeedTitleTplData{QuestionTitle: answer.QuestionInfo.Title}),
+			Link: display.AnswerURL(permalink, siteUrl, answer.QuestionID,
+				answer.QuestionInfo.Title, answer.AnswerID),
+			Author:    userInfo.DisplayName,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		})
+	}
+	// the questions and answers are mixed, only the latest ones are kept
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	if len(items) > schema.FeedMaxSize {
+		items = items[:schema.FeedMaxSize]
+	}
+	return items, nil
+}
+
+// SearchFeedItems get the newest search results of the query as feed items
+func (t *TemplateRenderController) SearchFeedItems(ctx *gin.Context, query string) (
+	items []*schema.FeedItem, err error) {
+	siteUrl, permalink, err := t.feedLinkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dto := &schema.SearchDTO{
+		Query: query,
+		Page:  1,
+		Size:  schema.FeedMaxSize,
+		Order: schema.QuestionOrderCondNewest,
+	}
+	_, _ = dto.Check()
+	resp, err := t.searchService.Search(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	lang := handler.GetLang(ctx)
+	items = make([]*schema.FeedItem, 0, len(resp.SearchResults))
+	for _, result := range resp.SearchResults {
+		object := result.Object
+		item := &schema.FeedItem{
+			ID:        feedQuestionID(siteUrl, object.QuestionID),
+			Title:     object.Title,
+			Link:      display.QuestionURL(permalink, siteUrl, object.QuestionID, object.Title),
+			Content:   object.Excerpt,
+			CreatedAt: time.Unix(object.CreatedAtParsed, 0),
+			UpdatedAt: time.Unix(object.CreatedAtParsed, 0),
+		}
+		if result.ObjectType == constant.AnswerObjectType {
+			item.ID = feedAnswerID(siteUrl, object.QuestionID, object.ID)
+			item.Title = translator.TrWithData(lang, constant.FeedAnswerTitleTrKey,
+				&schema.FeedTitleTplData{QuestionTitle: object.Title})
+			item.Link = display.AnswerURL(permalink, siteUrl, object.QuestionID, object.Title, object.ID)
+		}
+		if object.UserInfo != nil {
+			item.Author = object.UserInfo.DisplayName
+		}
+		for _, tag := range object.Tags {
+			item.Tags = append(item.Tags, tag.DisplayName)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (t *TemplateRenderController) feedLinkInfo(ctx context.Context) (siteUrl string, permalink int, err error) {
+	general, err := t.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	seo, err := t.siteInfoService.GetSiteSeo(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	return general.SiteUrl, seo.Permalink, nil
+}
+
+// feedQuestionID the unique id of question entry, it does not change with permalink setting or title
+func feedQuestionID(siteUrl, questionID string) string {
+	return fmt.Sprintf("%s/questions/%s", siteUrl, uid.DeShortID(questionID))
+}
+
+// feedAnswerID the unique id of answer entry, it does not change with permalink setting or title
+func feedAnswerID(siteUrl, questionID, answerID string) string {
+	return fmt.Sprintf("%s/%s", feedQuestionID(siteUrl, questionID), uid.DeShortID(answerID))
+}
@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/two_factor"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorController two factor controller
+type TwoFactorController struct {
+	twoFactorService *two_factor.TwoFactorService
+}
+
+// NewTwoFactorController new controller
+func NewTwoFactorController(twoFactorService *two_factor.TwoFactorService) *TwoFactorController {
+	return &TwoFactorController{twoFactorService: twoFactorService}
+}
+
+// GetTwoFactorStatus get the two factor status of current user
+// @Summary get the two factor status of current user
+// @Description get the two factor status of current user
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.GetTwoFactorStatusResp}
+// @Router /answer/api/v1/user/2fa [get]
+func (tc *TwoFactorController) GetTwoFactorStatus(ctx *gin.Context) {
+	req := &schema.GetTwoFactorStatusReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.GetTwoFactorStatus(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// EnrollTwoFactor set up two factor for current user
+// @Summary set up two factor for current user
+// @Description generate a new secret for the authenticator, it takes effect after it is enabled
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorEnrollResp}
+// @Router /answer/api/v1/user/2fa/enroll [post]
+func (tc *TwoFactorController) EnrollTwoFactor(ctx *gin.Context) {
+	req := &schema.TwoFactorEnrollReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.Enroll(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// EnrollLoginTwoFactor set up two factor for the pending login
+// @Summary set up two factor for the pending login
+// @Description set up two factor for the pending login that requires it, such as the staff login from an external login
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorChallengeEnrollReq true "TwoFactorChallengeEnrollReq"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorEnrollResp}
+// @Router /answer/api/v1/user/login/2fa/enroll [post]
+func (tc *TwoFactorController) EnrollLoginTwoFactor(ctx *gin.Context) {
+	req := &schema.TwoFactorChallengeEnrollReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := tc.twoFactorService.EnrollChallenge(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// EnableTwoFactor enable two factor for current user
+// @Summary enable two factor for current user
+// @Description enable two factor with the code of authenticator, the recovery codes are only returned once
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.TwoFactorEnableReq true "code"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorRecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/enable [post]
+func (tc *TwoFactorController) EnableTwoFactor(ctx *gin.Context) {
+	req := &schema.TwoFactorEnableReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.Enable(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// DisableTwoFactor disable two factor for current user
+// @Summary disable two factor for current user
+// @Description disable two factor with the code of authenticator or a recovery code
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.TwoFactorCodeReq true "code"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/2fa/disable [post]
+func (tc *TwoFactorController) DisableTwoFactor(ctx *gin.Context) {
+	req := &schema.TwoFactorCodeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.twoFactorService.Disable(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RegenerateRecoveryCodes regenerate the recovery codes for current user
+// @Summary regenerate the recovery codes for current user
+// @Description all old recovery codes become invalid, the new codes are only returned once
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.TwoFactorCodeReq true "code"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorRecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/recovery-codes [post]
+func (tc *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	req := &schema.TwoFactorCodeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.RegenerateRecoveryCodes(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -162,6 +162,33 @@ func (uc *UserController) UserEmailLogin(ctx *gin.Context) {
 	if !isAdmin {
 		uc.actionService.ActionRecordDel(ctx, entity.CaptchaActionPassword, ctx.ClientIP())
 	}
+	if resp.TwoFactorRequired {
+		handler.HandleResponse(ctx, nil, resp)
+		return
+	}
+	uc.setVisitCookies(ctx, resp.VisitToken, true)
+	handler.HandleResponse(ctx, nil, resp)
+}
+
+// UserTwoFactorLogin complete the email login with two factor
+// @Summary complete the email login with two factor
+// @Description complete the email login with the code of authenticator or a recovery code
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.UserTwoFactorLoginReq true "UserTwoFactorLoginReq"
+// @Success 200 {object} handler.RespBody{data=schema.UserLoginResp}
+// @Router /answer/api/v1/user/login/2fa [post]
+func (uc *UserController) UserTwoFactorLogin(ctx *gin.Context) {
+	req := &schema.UserTwoFactorLoginReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.userService.UserTwoFactorLogin(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
 	uc.setVisitCookies(ctx, resp.VisitToken, true)
 	handler.HandleResponse(ctx, nil, resp)
 }
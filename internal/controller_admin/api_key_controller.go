@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller_admin
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/api_key"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController api key controller
+type APIKeyController struct {
+	apiKeyService *api_key.APIKeyService
+}
+
+// NewAPIKeyController new controller
+func NewAPIKeyController(apiKeyService *api_key.APIKeyService) *APIKeyController {
+	return &APIKeyController{apiKeyService: apiKeyService}
+}
+
+// GetAPIKeyPage get api key page
+// @Summary get api key page
+// @Description get api key page
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Param user_id query string false "owner user id"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.APIKeyInfo}}
+// @Router /answer/admin/api/api-keys/page [get]
+func (ac *APIKeyController) GetAPIKeyPage(ctx *gin.Context) {
+	req := &schema.GetAPIKeyPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, total, err := ac.apiKeyService.GetAPIKeyPage(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	handler.HandleResponse(ctx, nil, pager.NewPageModel(total, resp))
+}
+
+// AddAPIKey add api key for user
+// @Summary add api key for user
+// @Description add api key for user, the token is only returned once
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AdminAddAPIKeyReq true "api key"
+// @Success 200 {object} handler.RespBody{data=schema.AddAPIKeyResp}
+// @Router /answer/admin/api/api-keys [post]
+func (ac *APIKeyController) AddAPIKey(ctx *gin.Context) {
+	req := &schema.AdminAddAPIKeyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = req.OwnerUserID
+	req.CreatorUserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := ac.apiKeyService.AddAPIKey(ctx, &req.AddAPIKeyReq)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RevokeAPIKey revoke api key
+// @Summary revoke api key
+// @Description revoke any api key
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RevokeAPIKeyReq true "api key"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/api-keys [delete]
+func (ac *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	req := &schema.RevokeAPIKeyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := ac.apiKeyService.RevokeAPIKey(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
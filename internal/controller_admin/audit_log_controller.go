@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller_admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/log"
+)
+
+// AuditLogController audit log controller
+type AuditLogController struct {
+	auditLogService *audit_log.AuditLogService
+}
+
+// NewAuditLogController new controller
+func NewAuditLogController(auditLogService *audit_log.AuditLogService) *AuditLogController {
+	return &AuditLogController{auditLogService: auditLogService}
+}
+
+// GetAuditLogPage get audit log page
+// @Summary get audit log page
+// @Description get the audit log of admin and moderator operations, the latest first
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data query schema.GetAuditLogPageReq true "filter"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.AuditLogInfo}}
+// @Router /answer/admin/api/audit-logs/page [get]
+func (ac *AuditLogController) GetAuditLogPage(ctx *gin.Context) {
+	req := &schema.GetAuditLogPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := ac.auditLogService.GetAuditLogPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// ExportAuditLog export audit log as csv
+// @Summary export audit log as csv
+// @Description export all audit logs that match the filter as csv, the page and page_size are ignored
+// @Tags admin
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param data query schema.GetAuditLogPageReq true "filter"
+// @Success 200 {file} file
+// @Router /answer/admin/api/audit-logs/export [get]
+func (ac *AuditLogController) ExportAuditLog(ctx *gin.Context) {
+	req := &schema.GetAuditLogPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	fileName := fmt.Sprintf("audit_log_%s.csv", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	ctx.Status(http.StatusOK)
+	// the csv is streamed, so the error can only be logged after the response is started
+	if err := ac.auditLogService.ExportAuditLog(ctx, req, ctx.Writer); err != nil {
+		log.Errorf("export audit log failed: %v", err)
+	}
+}
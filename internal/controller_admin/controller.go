@@ -29,4 +29,9 @@ var ProviderSetController = wire.NewSet(
 	NewRoleController,
 	NewPluginController,
 	NewBadgeController,
+	NewWebhookController,
+	NewAPIKeyController,
+	NewCronJobController,
+	NewAuditLogController,
+	NewSearchReindexController,
 )
@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller_admin
+
+import (
+	"github.com/apache/incubator-answer/internal/base/cron"
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/gin-gonic/gin"
+)
+
+// CronJobController cron job controller
+type CronJobController struct {
+	scheduledTaskManager *cron.ScheduledTaskManager
+}
+
+// NewCronJobController new controller
+func NewCronJobController(scheduledTaskManager *cron.ScheduledTaskManager) *CronJobController {
+	return &CronJobController{scheduledTaskManager: scheduledTaskManager}
+}
+
+// GetCronJobList get cron job list
+// @Summary get cron job list
+// @Description get all scheduled jobs with their last run and next run
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.CronJobInfo}
+// @Router /answer/admin/api/cron-jobs [get]
+func (cc *CronJobController) GetCronJobList(ctx *gin.Context) {
+	resp, err := cc.scheduledTaskManager.GetJobList(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// TriggerCronJob trigger cron job
+// @Summary trigger cron job
+// @Description run the scheduled job immediately, even if it is paused
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.TriggerCronJobReq true "cron job"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/cron-jobs/trigger [post]
+func (cc *CronJobController) TriggerCronJob(ctx *gin.Context) {
+	req := &schema.TriggerCronJobReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := cc.scheduledTaskManager.TriggerJob(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateCronJobStatus pause or resume cron job
+// @Summary pause or resume cron job
+// @Description pause or resume the scheduled job for all instances
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateCronJobStatusReq true "cron job"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/cron-jobs/status [put]
+func (cc *CronJobController) UpdateCronJobStatus(ctx *gin.Context) {
+	req := &schema.UpdateCronJobStatusReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := cc.scheduledTaskManager.UpdateJobStatus(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -21,9 +21,12 @@ package controller_admin
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/middleware"
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/plugin_common"
 	"github.com/apache/incubator-answer/plugin"
 	"github.com/gin-gonic/gin"
@@ -32,11 +35,18 @@ import (
 // PluginController role controller
 type PluginController struct {
 	pluginCommonService *plugin_common.PluginCommonService
+	auditLogService     *audit_log.AuditLogService
 }
 
 // NewPluginController new controller
-func NewPluginController(pluginCommonService *plugin_common.PluginCommonService) *PluginController {
-	return &PluginController{pluginCommonService: pluginCommonService}
+func NewPluginController(
+	pluginCommonService *plugin_common.PluginCommonService,
+	auditLogService *audit_log.AuditLogService,
+) *PluginController {
+	return &PluginController{
+		pluginCommonService: pluginCommonService,
+		auditLogService:     auditLogService,
+	}
 }
 
 // GetAllPluginStatus get all plugins status
@@ -149,8 +159,13 @@ func (pc *PluginController) UpdatePluginStatus(ctx *gin.Context) {
 		return
 	}
 
+	enabled := plugin.StatusManager.IsEnabled(req.PluginSlugName)
 	plugin.StatusManager.Enable(req.PluginSlugName, req.Enabled)
 	err := pc.pluginCommonService.UpdatePluginStatus(ctx)
+	if err == nil {
+		pc.addPluginAuditLog(ctx, schema.AuditActionUpdatePluginStatus, req.PluginSlugName,
+			map[string]any{"enabled": enabled}, map[string]any{"enabled": req.Enabled})
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -221,5 +236,27 @@ func (pc *PluginController) UpdatePluginConfig(ctx *gin.Context) {
 	}
 
 	err = pc.pluginCommonService.UpdatePluginConfig(ctx, req)
+	if err == nil {
+		// the config may contain credentials, so only the names of fields are recorded
+		fieldNames := make([]string, 0, len(req.ConfigFields))
+		for name := range req.ConfigFields {
+			fieldNames = append(fieldNames, name)
+		}
+		sort.Strings(fieldNames)
+		pc.addPluginAuditLog(ctx, schema.AuditActionUpdatePluginConfig, req.PluginSlugName,
+			nil, map[string]any{"config_fields": fieldNames})
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
+
+func (pc *PluginController) addPluginAuditLog(ctx *gin.Context, action, slugName string, before, after any) {
+	pc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     middleware.GetLoginUserIDFromContext(ctx),
+		IP:         ctx.ClientIP(),
+		Action:     action,
+		ObjectType: schema.AuditObjectPlugin,
+		ObjectID:   slugName,
+		Before:     before,
+		After:      after,
+	})
+}
@@ -51,3 +51,91 @@ func (rc *RoleController) GetRoleList(ctx *gin.Context) {
 	resp, err := rc.roleService.GetRoleList(ctx)
 	handler.HandleResponse(ctx, err, resp)
 }
+
+// GetPowerList get all powers
+// @Summary get all powers
+// @Description get all powers that can be assigned to roles
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.GetPowerResp}
+// @Router /answer/admin/api/powers [get]
+func (rc *RoleController) GetPowerList(ctx *gin.Context) {
+	resp, err := rc.roleService.GetPowerList(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetRolePowerList get the powers of role
+// @Summary get the powers of role
+// @Description get the power types of role
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param role_id query int true "role id"
+// @Success 200 {object} handler.RespBody{data=[]string}
+// @Router /answer/admin/api/role/powers [get]
+func (rc *RoleController) GetRolePowerList(ctx *gin.Context) {
+	req := &schema.GetRolePowerReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := rc.roleService.GetRolePowerList(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddRole add role
+// @Summary add role
+// @Description add custom role with powers
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddRoleReq true "role"
+// @Success 200 {object} handler.RespBody{data=schema.AddRoleResp}
+// @Router /answer/admin/api/role [post]
+func (rc *RoleController) AddRole(ctx *gin.Context) {
+	req := &schema.AddRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := rc.roleService.AddRole(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateRole update role
+// @Summary update role
+// @Description update role and replace its powers, the built-in roles can not be renamed
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateRoleReq true "role"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role [put]
+func (rc *RoleController) UpdateRole(ctx *gin.Context) {
+	req := &schema.UpdateRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.roleService.UpdateRole(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// DeleteRole delete role
+// @Summary delete role
+// @Description delete custom role, the users of the role will become normal users
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.DeleteRoleReq true "role"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role [delete]
+func (rc *RoleController) DeleteRole(ctx *gin.Context) {
+	req := &schema.DeleteRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.roleService.DeleteRole(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller_admin
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/search_reindex"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchReindexController search reindex controller
+type SearchReindexController struct {
+	searchReindexService *search_reindex.SearchReindexService
+}
+
+// NewSearchReindexController new controller
+func NewSearchReindexController(searchReindexService *search_reindex.SearchReindexService) *SearchReindexController {
+	return &SearchReindexController{searchReindexService: searchReindexService}
+}
+
+// StartSearchReindex start search reindex
+// @Summary start search reindex
+// @Description send all questions and answers to the search plugin in background,
+// @Description it continues from the checkpoint of last unfinished reindexing unless restart is set.
+// @Description The dry-run sends nothing, it only reports the drift between the index and database.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.SearchReindexReq true "search reindex"
+// @Success 200 {object} handler.RespBody{data=schema.SearchReindexResp}
+// @Router /answer/admin/api/search/reindex [post]
+func (sc *SearchReindexController) StartSearchReindex(ctx *gin.Context) {
+	req := &schema.SearchReindexReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := sc.searchReindexService.StartReindex(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSearchReindexProgress get search reindex progress
+// @Summary get search reindex progress
+// @Description get the progress of the running or last search reindexing
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.SearchReindexResp}
+// @Router /answer/admin/api/search/reindex [get]
+func (sc *SearchReindexController) GetSearchReindexProgress(ctx *gin.Context) {
+	resp, err := sc.searchReindexService.GetReindexProgress(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -23,22 +23,32 @@ import (
 	"html"
 	"net/http"
 
+	"github.com/apache/incubator-answer/internal/base/constant"
 	"github.com/apache/incubator-answer/internal/base/handler"
 	"github.com/apache/incubator-answer/internal/base/middleware"
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/siteinfo"
 	"github.com/gin-gonic/gin"
 )
 
+// siteInfoTypeSMTP the smtp config is not stored as site info, it is only used to record audit log
+const siteInfoTypeSMTP = "smtp"
+
 // SiteInfoController site info controller
 type SiteInfoController struct {
 	siteInfoService *siteinfo.SiteInfoService
+	auditLogService *audit_log.AuditLogService
 }
 
 // NewSiteInfoController new site info controller
-func NewSiteInfoController(siteInfoService *siteinfo.SiteInfoService) *SiteInfoController {
+func NewSiteInfoController(
+	siteInfoService *siteinfo.SiteInfoService,
+	auditLogService *audit_log.AuditLogService,
+) *SiteInfoController {
 	return &SiteInfoController{
 		siteInfoService: siteInfoService,
+		auditLogService: auditLogService,
 	}
 }
 
@@ -219,7 +229,11 @@ func (sc *SiteInfoController) UpdateSeo(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, &req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSeo(ctx)
 	err := sc.siteInfoService.SaveSeo(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeSeo, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -237,7 +251,11 @@ func (sc *SiteInfoController) UpdateGeneral(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, &req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteGeneral(ctx)
 	err := sc.siteInfoService.SaveSiteGeneral(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeGeneral, before, req)
+	}
 	req.Name = html.UnescapeString(req.Name)
 	handler.HandleResponse(ctx, err, req)
 }
@@ -256,7 +274,11 @@ func (sc *SiteInfoController) UpdateInterface(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, &req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteInterface(ctx)
 	err := sc.siteInfoService.SaveSiteInterface(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeInterface, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -274,7 +296,11 @@ func (sc *SiteInfoController) UpdateBranding(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteBranding(ctx)
 	err := sc.siteInfoService.SaveSiteBranding(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeBranding, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -294,7 +320,11 @@ func (sc *SiteInfoController) UpdateSiteWrite(ctx *gin.Context) {
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
+	before, _ := sc.siteInfoService.GetSiteWrite(ctx)
 	resp, err := sc.siteInfoService.SaveSiteWrite(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeWrite, before, req)
+	}
 	handler.HandleResponse(ctx, err, resp)
 }
 
@@ -312,7 +342,11 @@ func (sc *SiteInfoController) UpdateSiteLegal(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteLegal(ctx)
 	err := sc.siteInfoService.SaveSiteLegal(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeLegal, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -330,7 +364,11 @@ func (sc *SiteInfoController) UpdateSiteLogin(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteLogin(ctx)
 	err := sc.siteInfoService.SaveSiteLogin(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeLogin, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -348,7 +386,11 @@ func (sc *SiteInfoController) UpdateSiteCustomCssHTML(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteCustomCssHTML(ctx)
 	err := sc.siteInfoService.SaveSiteCustomCssHTML(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeCustomCssHTML, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -366,7 +408,11 @@ func (sc *SiteInfoController) SaveSiteTheme(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteTheme(ctx)
 	err := sc.siteInfoService.SaveSiteTheme(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeTheme, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -384,7 +430,11 @@ func (sc *SiteInfoController) UpdateSiteUsers(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSiteUsers(ctx)
 	err := sc.siteInfoService.SaveSiteUsers(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypeUsers, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -415,7 +465,11 @@ func (sc *SiteInfoController) UpdateSMTPConfig(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetSMTPConfig(ctx)
 	err := sc.siteInfoService.UpdateSMTPConfig(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, siteInfoTypeSMTP, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -446,6 +500,22 @@ func (sc *SiteInfoController) UpdatePrivilegesConfig(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	before, _ := sc.siteInfoService.GetPrivilegesConfig(ctx)
 	err := sc.siteInfoService.UpdatePrivilegesConfig(ctx, req)
+	if err == nil {
+		sc.addSiteInfoAuditLog(ctx, constant.SiteTypePrivileges, before, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
+
+func (sc *SiteInfoController) addSiteInfoAuditLog(ctx *gin.Context, siteInfoType string, before, after any) {
+	sc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     middleware.GetLoginUserIDFromContext(ctx),
+		IP:         ctx.ClientIP(),
+		Action:     schema.AuditActionUpdateSiteInfo,
+		ObjectType: schema.AuditObjectSiteInfo,
+		ObjectID:   siteInfoType,
+		Before:     before,
+		After:      after,
+	})
+}
@@ -25,6 +25,8 @@ import (
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/base/translator"
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
+	"github.com/apache/incubator-answer/internal/service/two_factor"
 	"github.com/apache/incubator-answer/internal/service/user_admin"
 	"github.com/apache/incubator-answer/plugin"
 	"github.com/gin-gonic/gin"
@@ -33,12 +35,22 @@ import (
 
 // UserAdminController user controller
 type UserAdminController struct {
-	userService *user_admin.UserAdminService
+	userService      *user_admin.UserAdminService
+	twoFactorService *two_factor.TwoFactorService
+	auditLogService  *audit_log.AuditLogService
 }
 
 // NewUserAdminController new controller
-func NewUserAdminController(userService *user_admin.UserAdminService) *UserAdminController {
-	return &UserAdminController{userService: userService}
+func NewUserAdminController(
+	userService *user_admin.UserAdminService,
+	twoFactorService *two_factor.TwoFactorService,
+	auditLogService *audit_log.AuditLogService,
+) *UserAdminController {
+	return &UserAdminController{
+		userService:      userService,
+		twoFactorService: twoFactorService,
+		auditLogService:  auditLogService,
+	}
 }
 
 // UpdateUserStatus update user
@@ -63,7 +75,12 @@ func (uc *UserAdminController) UpdateUserStatus(ctx *gin.Context) {
 
 	req.LoginUserID = middleware.GetLoginUserIDFromContext(ctx)
 
+	before, _ := uc.userService.GetUserAuditState(ctx, req.UserID)
 	err := uc.userService.UpdateUserStatus(ctx, req)
+	if err == nil {
+		after, _ := uc.userService.GetUserAuditState(ctx, req.UserID)
+		uc.addUserAuditLog(ctx, schema.AuditActionUpdateUserStatus, req.UserID, before, after)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -85,7 +102,12 @@ func (uc *UserAdminController) UpdateUserRole(ctx *gin.Context) {
 
 	req.LoginUserID = middleware.GetLoginUserIDFromContext(ctx)
 
+	before, _ := uc.userService.GetUserAuditState(ctx, req.UserID)
 	err := uc.userService.UpdateUserRole(ctx, req)
+	if err == nil {
+		after, _ := uc.userService.GetUserAuditState(ctx, req.UserID)
+		uc.addUserAuditLog(ctx, schema.AuditActionUpdateUserRole, req.UserID, before, after)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -150,9 +172,46 @@ func (uc *UserAdminController) UpdateUserPassword(ctx *gin.Context) {
 	req.LoginUserID = middleware.GetLoginUserIDFromContext(ctx)
 
 	err := uc.userService.UpdateUserPassword(ctx, req)
+	if err == nil {
+		uc.addUserAuditLog(ctx, schema.AuditActionUpdateUserPassword, req.UserID, nil, req)
+	}
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// ResetUserTwoFactor reset the two factor of user
+// @Summary reset the two factor of user
+// @Description remove the two factor of user, for example the user lost the authenticator and recovery codes
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.ResetUserTwoFactorReq true "user"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/2fa/reset [put]
+func (uc *UserAdminController) ResetUserTwoFactor(ctx *gin.Context) {
+	req := &schema.ResetUserTwoFactorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := uc.twoFactorService.ResetUserTwoFactor(ctx, req)
+	if err == nil {
+		uc.addUserAuditLog(ctx, schema.AuditActionResetUserTwoFactor, req.UserID, nil, req)
+	}
 	handler.HandleResponse(ctx, err, nil)
 }
 
+func (uc *UserAdminController) addUserAuditLog(ctx *gin.Context, action, userID string, before, after any) {
+	uc.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     middleware.GetLoginUserIDFromContext(ctx),
+		IP:         ctx.ClientIP(),
+		Action:     action,
+		ObjectType: schema.AuditObjectUser,
+		ObjectID:   userID,
+		Before:     before,
+		After:      after,
+	})
+}
+
 // EditUserProfile edit user profile
 // @Summary edit user profile
 // @Description edit user profile
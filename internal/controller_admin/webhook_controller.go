@@ -0,0 +1,164 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller_admin
+
+import (
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController webhook controller
+type WebhookController struct {
+	webhookService *webhook.WebhookService
+}
+
+// NewWebhookController new controller
+func NewWebhookController(webhookService *webhook.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: webhookService}
+}
+
+// GetWebhookList get webhook list
+// @Summary get webhook list
+// @Description get webhook list
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.WebhookInfo}
+// @Router /answer/admin/api/webhooks [get]
+func (wc *WebhookController) GetWebhookList(ctx *gin.Context) {
+	resp, err := wc.webhookService.GetWebhookList(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetEventTypeList get the event types that can be subscribed
+// @Summary get the event types that can be subscribed
+// @Description get the event types that can be subscribed
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]string}
+// @Router /answer/admin/api/webhooks/events [get]
+func (wc *WebhookController) GetEventTypeList(ctx *gin.Context) {
+	resp, err := wc.webhookService.GetEventTypeList(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddWebhook add webhook
+// @Summary add webhook
+// @Description add webhook, the secret will be generated if it is empty
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddWebhookReq true "webhook"
+// @Success 200 {object} handler.RespBody{data=schema.AddWebhookResp}
+// @Router /answer/admin/api/webhooks [post]
+func (wc *WebhookController) AddWebhook(ctx *gin.Context) {
+	req := &schema.AddWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := wc.webhookService.AddWebhook(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateWebhook update webhook
+// @Summary update webhook
+// @Description update webhook
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateWebhookReq true "webhook"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/webhooks [put]
+func (wc *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	req := &schema.UpdateWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := wc.webhookService.UpdateWebhook(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// DeleteWebhook delete webhook
+// @Summary delete webhook
+// @Description delete webhook and its delivery log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.DeleteWebhookReq true "webhook"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/webhooks [delete]
+func (wc *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	req := &schema.DeleteWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := wc.webhookService.DeleteWebhook(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetDeliveryPage get webhook delivery log page
+// @Summary get webhook delivery log page
+// @Description get webhook delivery log page
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Param webhook_id query string true "webhook id"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.WebhookDeliveryInfo}}
+// @Router /answer/admin/api/webhooks/deliveries/page [get]
+func (wc *WebhookController) GetDeliveryPage(ctx *gin.Context) {
+	req := &schema.GetWebhookDeliveryPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, total, err := wc.webhookService.GetDeliveryPage(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	handler.HandleResponse(ctx, nil, pager.NewPageModel(total, resp))
+}
+
+// Redeliver redeliver the webhook delivery
+// @Summary redeliver the webhook delivery
+// @Description create a new delivery with the same payload
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RedeliverWebhookReq true "delivery"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/webhooks/deliveries/redeliver [post]
+func (wc *WebhookController) Redeliver(ctx *gin.Context) {
+	req := &schema.RedeliverWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := wc.webhookService.Redeliver(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
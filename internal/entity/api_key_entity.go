@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	APIKeyStatusAvailable = 1
+	APIKeyStatusRevoked   = 10
+)
+
+// APIKey long-lived access token for the REST API, only the hash of the token is stored
+type APIKey struct {
+	ID            string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt     time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt     time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	UserID        string    `xorm:"not null default 0 BIGINT(20) index user_id"`
+	CreatorUserID string    `xorm:"not null default 0 BIGINT(20) creator_user_id"`
+	Name          string    `xorm:"not null default '' VARCHAR(100) name"`
+	TokenHash     string    `xorm:"not null VARCHAR(64) unique token_hash"`
+	TokenPrefix   string    `xorm:"not null default '' VARCHAR(16) token_prefix"`
+	// Scopes granted scopes joined by comma
+	Scopes     string    `xorm:"not null default '' VARCHAR(255) scopes"`
+	ExpiredAt  time.Time `xorm:"TIMESTAMP expired_at"`
+	LastUsedAt time.Time `xorm:"TIMESTAMP last_used_at"`
+	Status     int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName api key table name
+func (APIKey) TableName() string {
+	return "api_key"
+}
+
+// GetScopes get granted scopes
+func (a *APIKey) GetScopes() []string {
+	if len(a.Scopes) == 0 {
+		return []string{}
+	}
+	return strings.Split(a.Scopes, ",")
+}
+
+// HasScope check the scope is granted or not
+func (a *APIKey) HasScope(scope string) bool {
+	for _, s := range a.GetScopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired check the api key is expired or not, the zero expired time means never expire
+func (a *APIKey) IsExpired() bool {
+	return !a.ExpiredAt.IsZero() && a.ExpiredAt.Before(time.Now())
+}
@@ -0,0 +1,42 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+// AuditLog the append-only log of admin and moderator operations
+type AuditLog struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP INDEX created_at"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	Action     string    `xorm:"not null default '' VARCHAR(64) INDEX action"`
+	ObjectType string    `xorm:"not null default '' VARCHAR(32) object_type"`
+	ObjectID   string    `xorm:"not null default '' VARCHAR(64) INDEX object_id"`
+	// Before the json of the changed fields before the operation
+	Before string `xorm:"not null TEXT before_value"`
+	// After the json of the changed fields after the operation
+	After string `xorm:"not null TEXT after_value"`
+	IP    string `xorm:"not null default '' VARCHAR(64) ip"`
+}
+
+// TableName audit log table name
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	CronJobStatusSuccess = 1
+	CronJobStatusFailed  = 2
+)
+
+// CronJob the state of scheduled job shared by all instances.
+// The instance holding an unexpired lease is the only one allowed to run the job,
+// and each scheduled time is claimed by only one instance, so that a finished tick does not run again.
+type CronJob struct {
+	ID             int64     `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt      time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt      time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	Name           string    `xorm:"not null default '' VARCHAR(100) unique name"`
+	Paused         bool      `xorm:"not null default false BOOL paused"`
+	LastRunAt      time.Time `xorm:"TIMESTAMP last_run_at"`
+	LastDuration   int64     `xorm:"not null default 0 BIGINT(20) last_duration"`
+	LastStatus     int       `xorm:"not null default 0 INT(11) last_status"`
+	LastError      string    `xorm:"TEXT last_error"`
+	LeaseOwner     string    `xorm:"not null default '' VARCHAR(100) lease_owner"`
+	LeaseExpiredAt time.Time `xorm:"TIMESTAMP lease_expired_at"`
+	// LastScheduledAt the unix time of the latest scheduled time claimed by an instance
+	LastScheduledAt int64 `xorm:"not null default 0 BIGINT(20) last_scheduled_at"`
+}
+
+// TableName cron job table name
+func (CronJob) TableName() string {
+	return "cron_job"
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+// Draft the unpublished content of user, one draft for each object of user
+type Draft struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP INDEX updated_at"`
+	UserID    string    `xorm:"not null default 0 UNIQUE(uk_draft) BIGINT(20) user_id"`
+	// ObjectType question, answer, question_edit or answer_edit
+	ObjectType string `xorm:"not null default '' UNIQUE(uk_draft) VARCHAR(32) object_type"`
+	// ObjectID 0 for new question, question id for new answer, post id for edit
+	ObjectID string `xorm:"not null default 0 UNIQUE(uk_draft) BIGINT(20) object_id"`
+	Title    string `xorm:"not null default '' VARCHAR(150) title"`
+	Content  string `xorm:"not null MEDIUMTEXT content"`
+	// Tags the json of tags
+	Tags string `xorm:"not null TEXT tags"`
+}
+
+// TableName draft table name
+func (Draft) TableName() string {
+	return "draft"
+}
@@ -0,0 +1,36 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package entity
+
+import "time"
+
+// ImportRecord the mapping from the object of the imported data source to the object created by importer
+type ImportRecord struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	Source     string    `xorm:"not null default '' VARCHAR(100) UNIQUE(uk_import_source) source"`
+	SourceType string    `xorm:"not null default '' VARCHAR(50) UNIQUE(uk_import_source) source_type"`
+	SourceID   string    `xorm:"not null default '' VARCHAR(100) UNIQUE(uk_import_source) source_id"`
+	ObjectID   string    `xorm:"not null default 0 BIGINT(20) object_id"`
+}
+
+// TableName import record table name
+func (ImportRecord) TableName() string {
+	return "import_record"
+}
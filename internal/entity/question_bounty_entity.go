@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	QuestionBountyStatusActive      = 1
+	QuestionBountyStatusAwarded     = 2
+	QuestionBountyStatusAutoAwarded = 3
+	QuestionBountyStatusExpired     = 4
+)
+
+// QuestionBounty the reputation escrowed on a question
+type QuestionBounty struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	QuestionID string    `xorm:"not null default 0 BIGINT(20) INDEX UNIQUE(uq_question_closed) question_id"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	Amount     int       `xorm:"not null default 0 INT(11) amount"`
+	ExpiredAt  time.Time `xorm:"TIMESTAMP INDEX expired_at"`
+	Status     int       `xorm:"not null default 1 INT(11) INDEX status"`
+	// AnswerID the answer which the bounty is awarded to
+	AnswerID  string    `xorm:"not null default 0 BIGINT(20) answer_id"`
+	AwardedAt time.Time `xorm:"TIMESTAMP awarded_at"`
+	// ClosedID zero while the bounty is active, then it is the id of the bounty itself.
+	// Only one bounty of the question can be active, it is guaranteed by the unique index.
+	ClosedID string `xorm:"not null default 0 BIGINT(20) UNIQUE(uq_question_closed) closed_id"`
+}
+
+// TableName question bounty table name
+func (QuestionBounty) TableName() string {
+	return "question_bounty"
+}
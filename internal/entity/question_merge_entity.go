@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	QuestionMergeStatusMerged   = 1
+	QuestionMergeStatusReverted = 2
+)
+
+// QuestionMerge the record of merging a duplicate question into the canonical question
+type QuestionMerge struct {
+	ID               string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt        time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt        time.Time `xorm:"updated TIMESTAMP updated_at"`
+	SourceQuestionID string    `xorm:"not null default 0 BIGINT(20) INDEX UNIQUE(uq_source_revert) source_question_id"`
+	TargetQuestionID string    `xorm:"not null default 0 BIGINT(20) INDEX target_question_id"`
+	UserID           string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	// AnswerIDs the json array of the answers moved from source question
+	AnswerIDs string `xorm:"not null TEXT answer_ids"`
+	// CommentIDs the json array of the question comments moved from source question
+	CommentIDs string `xorm:"not null TEXT comment_ids"`
+	// SourceStatus the status of source question before merging
+	SourceStatus int `xorm:"not null default 0 INT(11) source_status"`
+	// SourceAcceptedAnswerID the accepted answer of source question before merging
+	SourceAcceptedAnswerID string `xorm:"not null default 0 BIGINT(20) source_accepted_answer_id"`
+	Status                 int    `xorm:"not null default 1 INT(11) status"`
+	// RevertID zero while the merge is not reverted, then it is the id of the merge itself.
+	// Only one merge of the source question can be not reverted, it is guaranteed by the unique index.
+	RevertID string `xorm:"not null default 0 BIGINT(20) UNIQUE(uq_source_revert) revert_id"`
+}
+
+// TableName question merge table name
+func (QuestionMerge) TableName() string {
+	return "question_merge"
+}
@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	QueueMessageStatusPending    = 1
+	QueueMessageStatusProcessing = 2
+	QueueMessageStatusDead       = 10
+)
+
+// QueueMessage queue message, a persisted outbox row shared by all queue services
+type QueueMessage struct {
+	ID          int64     `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt   time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt   time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	QueueName   string    `xorm:"not null default '' VARCHAR(64) index(queue_status) queue_name"`
+	Payload     string    `xorm:"not null MEDIUMTEXT payload"`
+	Status      int       `xorm:"not null default 1 INT(11) index(queue_status) status"`
+	Attempts    int       `xorm:"not null default 0 INT(11) attempts"`
+	NextRunAt   time.Time `xorm:"not null default CURRENT_TIMESTAMP TIMESTAMP index next_run_at"`
+	LockedUntil time.Time `xorm:"TIMESTAMP locked_until"`
+	LastError   string    `xorm:"TEXT last_error"`
+	// DoneHandlers comma separated names of the handlers that have finished, they are skipped when retrying
+	DoneHandlers string `xorm:"TEXT done_handlers"`
+}
+
+// GetDoneHandlers get the names of the handlers that have finished
+func (m *QueueMessage) GetDoneHandlers() (names []string) {
+	if len(m.DoneHandlers) == 0 {
+		return nil
+	}
+	return strings.Split(m.DoneHandlers, ",")
+}
+
+// SetDoneHandlers set the names of the handlers that have finished
+func (m *QueueMessage) SetDoneHandlers(names []string) {
+	m.DoneHandlers = strings.Join(names, ",")
+}
+
+// TableName queue message table name
+func (QueueMessage) TableName() string {
+	return "queue_message"
+}
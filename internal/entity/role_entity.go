@@ -28,6 +28,8 @@ type Role struct {
 	UpdatedAt   time.Time `xorm:"updated TIMESTAMP updated_at"`
 	Name        string    `xorm:"not null default '' VARCHAR(50) name"`
 	Description string    `xorm:"not null default '' VARCHAR(200) description"`
+	// BuiltIn the built-in roles can not be renamed or deleted
+	BuiltIn bool `xorm:"not null default false BOOL built_in"`
 }
 
 // TableName user table name
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// SavedSearch the search query saved by user, the new contents matched by it can be alerted to user
+type SavedSearch struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 INDEX BIGINT(20) user_id"`
+	Name      string    `xorm:"not null default '' VARCHAR(100) name"`
+	// Query the search query in the syntax of search, such as "[tag] isaccepted:no"
+	Query          string `xorm:"not null default '' VARCHAR(255) query"`
+	IncludeSubTags bool   `xorm:"not null default false BOOL include_sub_tags"`
+	AlertEnabled   bool   `xorm:"not null default false BOOL INDEX alert_enabled"`
+	// AlertChannels the channels to deliver the alert joined by comma
+	AlertChannels string `xorm:"not null default '' VARCHAR(64) alert_channels"`
+	// LastAlertAt the contents created before it have been evaluated
+	LastAlertAt time.Time `xorm:"TIMESTAMP last_alert_at"`
+}
+
+// TableName saved search table name
+func (SavedSearch) TableName() string {
+	return "saved_search"
+}
+
+// GetAlertChannels get the channels to deliver the alert
+func (s *SavedSearch) GetAlertChannels() []string {
+	if len(s.AlertChannels) == 0 {
+		return []string{}
+	}
+	return strings.Split(s.AlertChannels, ",")
+}
+
+// SetAlertChannels set the channels to deliver the alert
+func (s *SavedSearch) SetAlertChannels(channels []string) {
+	s.AlertChannels = strings.Join(channels, ",")
+}
+
+// HasAlertChannel check the alert is delivered by the channel or not
+func (s *SavedSearch) HasAlertChannel(channel string) bool {
+	for _, c := range s.GetAlertChannels() {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
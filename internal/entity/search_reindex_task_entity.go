@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	SearchReindexStatusRunning  = "running"
+	SearchReindexStatusFinished = "finished"
+	SearchReindexStatusFailed   = "failed"
+)
+
+// SearchReindexTask the checkpoint of rebuilding the index of search plugin
+type SearchReindexTask struct {
+	ID             string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt      time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt      time.Time `xorm:"updated TIMESTAMP updated_at"`
+	PluginSlugName string    `xorm:"not null default '' VARCHAR(100) UNIQUE plugin_slug_name"`
+	Status         string    `xorm:"not null default '' VARCHAR(20) status"`
+	ObjectType     string    `xorm:"not null default '' VARCHAR(20) object_type"`
+	LastObjectID   string    `xorm:"not null default 0 BIGINT(20) last_object_id"`
+	Processed      int64     `xorm:"not null default 0 BIGINT(20) processed"`
+	Updated        int64     `xorm:"not null default 0 BIGINT(20) updated"`
+	Deleted        int64     `xorm:"not null default 0 BIGINT(20) deleted"`
+	Failed         int64     `xorm:"not null default 0 BIGINT(20) failed"`
+	Error          string    `xorm:"TEXT error"`
+}
+
+// TableName search reindex task table name
+func (SearchReindexTask) TableName() string {
+	return "search_reindex_task"
+}
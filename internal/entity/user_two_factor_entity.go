@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	UserTwoFactorStatusPending = 1
+	UserTwoFactorStatusEnabled = 2
+)
+
+// UserTwoFactor the TOTP two-factor authentication of user, only the hash of recovery codes is stored
+type UserTwoFactor struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) unique user_id"`
+	Secret    string    `xorm:"not null default '' VARCHAR(64) secret"`
+	// RecoveryCodes the json array of the hash of unused recovery codes
+	RecoveryCodes string `xorm:"not null TEXT recovery_codes"`
+	// LastUsedStep the time step of the last used code, the code of the same step can't be used again
+	LastUsedStep int64 `xorm:"not null default 0 BIGINT(20) last_used_step"`
+	Status       int   `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName user two factor table name
+func (UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+// IsEnabled check the two-factor authentication is enabled or not
+func (u *UserTwoFactor) IsEnabled() bool {
+	return u.Status == UserTwoFactorStatusEnabled
+}
+
+// GetRecoveryCodes get the hash list of unused recovery codes
+func (u *UserTwoFactor) GetRecoveryCodes() []string {
+	codes := make([]string, 0)
+	if len(u.RecoveryCodes) > 0 {
+		_ = json.Unmarshal([]byte(u.RecoveryCodes), &codes)
+	}
+	return codes
+}
+
+// SetRecoveryCodes set the hash list of unused recovery codes
+func (u *UserTwoFactor) SetRecoveryCodes(codes []string) {
+	content, _ := json.Marshal(codes)
+	u.RecoveryCodes = string(content)
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	WebhookStatusActive   = 1
+	WebhookStatusInactive = 2
+
+	WebhookDeliveryStatusPending  = 1
+	WebhookDeliveryStatusSuccess  = 2
+	WebhookDeliveryStatusRetrying = 3
+	WebhookDeliveryStatusFailed   = 4
+)
+
+// Webhook webhook endpoint
+type Webhook struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	Name      string    `xorm:"not null default '' VARCHAR(100) name"`
+	URL       string    `xorm:"not null VARCHAR(1024) url"`
+	Secret    string    `xorm:"not null default '' VARCHAR(255) secret"`
+	// Events subscribed event types joined by comma
+	Events string `xorm:"not null TEXT events"`
+	Status int    `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName webhook table name
+func (Webhook) TableName() string {
+	return "webhook"
+}
+
+// GetEvents get subscribed event types
+func (w *Webhook) GetEvents() []string {
+	if len(w.Events) == 0 {
+		return []string{}
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// SetEvents set subscribed event types
+func (w *Webhook) SetEvents(events []string) {
+	w.Events = strings.Join(events, ",")
+}
+
+// IsSubscribed check the event type is subscribed or not
+func (w *Webhook) IsSubscribed(eventType string) bool {
+	for _, event := range w.GetEvents() {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery webhook delivery log
+type WebhookDelivery struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	WebhookID string    `xorm:"not null default 0 BIGINT(20) index webhook_id"`
+	// EventID the queue message id of the event, the delivery of an event is created once for each webhook.
+	// It is 0 if the delivery is created by redelivering.
+	EventID      string    `xorm:"not null default 0 BIGINT(20) index event_id"`
+	EventType    string    `xorm:"not null default '' VARCHAR(64) event_type"`
+	Payload      string    `xorm:"not null MEDIUMTEXT payload"`
+	Status       int       `xorm:"not null default 1 INT(11) status"`
+	Attempts     int       `xorm:"not null default 0 INT(11) attempts"`
+	ResponseCode int       `xorm:"not null default 0 INT(11) response_code"`
+	ResponseBody string    `xorm:"TEXT response_body"`
+	LastError    string    `xorm:"TEXT last_error"`
+	NextRetryAt  time.Time `xorm:"TIMESTAMP next_retry_at"`
+	DeliveredAt  time.Time `xorm:"TIMESTAMP delivered_at"`
+}
+
+// TableName webhook delivery table name
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
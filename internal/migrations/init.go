@@ -61,7 +61,7 @@ type InitNeedUserInputData struct {
 
 func (m *Mentor) InitDB() error {
 	m.do("check table exist", m.checkTableExist)
-	m.do("sync table", m.syncTable)
+	m.do("init schema", m.initSchema)
 	m.do("init version table", m.initVersionTable)
 	m.do("init admin user", m.initAdminUser)
 	m.do("init config", m.initConfig)
@@ -100,8 +100,20 @@ func (m *Mentor) checkTableExist() {
 	}
 }
 
-func (m *Mentor) syncTable() {
-	m.err = m.engine.Context(m.ctx).Sync(tables...)
+func (m *Mentor) initSchema() {
+	m.err = InitSchema(m.ctx, m.engine)
+}
+
+// InitSchema create all tables and the schema objects that are not described by the entities,
+// such as the full-text indexes, so that a new database has the same schema as an upgraded one.
+func InitSchema(ctx context.Context, x *xorm.Engine) error {
+	if err := x.Context(ctx).Sync(tables...); err != nil {
+		return fmt.Errorf("sync table failed: %w", err)
+	}
+	if err := InitFullTextIndex(ctx, x); err != nil {
+		return fmt.Errorf("init full-text index failed: %w", err)
+	}
+	return nil
 }
 
 func (m *Mentor) initVersionTable() {
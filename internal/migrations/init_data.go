@@ -73,12 +73,27 @@ var (
 		&entity.Badge{},
 		&entity.BadgeGroup{},
 		&entity.BadgeAward{},
+		&entity.QueueMessage{},
+		&entity.Webhook{},
+		&entity.WebhookDelivery{},
+		&entity.APIKey{},
+		&entity.CronJob{},
+		&entity.UserTwoFactor{},
+		&entity.AuditLog{},
+		&entity.QuestionMerge{},
+		&entity.QuestionBounty{},
+		&entity.Draft{},
+		&entity.NotificationDigest{},
+		&entity.FeedToken{},
+		&entity.ImportRecord{},
+		&entity.SearchReindexTask{},
+		&entity.SavedSearch{},
 	}
 
 	roles = []*entity.Role{
-		{ID: 1, Name: "User", Description: "Default with no special access."},
-		{ID: 2, Name: "Admin", Description: "Have the full power to access the site."},
-		{ID: 3, Name: "Moderator", Description: "Has access to all posts except admin settings."},
+		{ID: 1, Name: "User", Description: "Default with no special access.", BuiltIn: true},
+		{ID: 2, Name: "Admin", Description: "Have the full power to access the site.", BuiltIn: true},
+		{ID: 3, Name: "Moderator", Description: "Has access to all posts except admin settings.", BuiltIn: true},
 	}
 
 	powers = []*entity.Power{
@@ -123,6 +138,8 @@ var (
 		{ID: 39, Name: "recover answer", PowerType: permission.AnswerUnDelete, Description: "recover deleted answer"},
 		{ID: 40, Name: "recover question", PowerType: permission.QuestionUnDelete, Description: "recover deleted question"},
 		{ID: 41, Name: "recover tag", PowerType: permission.TagUnDelete, Description: "recover deleted tag"},
+		{ID: 42, Name: "content moderate", PowerType: permission.ContentModerate, Description: "moderate the content and the users"},
+		{ID: 43, Name: "question merge", PowerType: permission.QuestionMerge, Description: "merge the duplicate question into another question"},
 	}
 
 	rolePowerRels = []*entity.RolePowerRel{
@@ -168,6 +185,8 @@ var (
 		{RoleID: 2, PowerType: permission.AnswerUnDelete},
 		{RoleID: 2, PowerType: permission.QuestionUnDelete},
 		{RoleID: 2, PowerType: permission.TagUnDelete},
+		{RoleID: 2, PowerType: permission.ContentModerate},
+		{RoleID: 2, PowerType: permission.QuestionMerge},
 
 		{RoleID: 3, PowerType: permission.QuestionAdd},
 		{RoleID: 3, PowerType: permission.QuestionEdit},
@@ -210,6 +229,8 @@ var (
 		{RoleID: 3, PowerType: permission.AnswerUnDelete},
 		{RoleID: 3, PowerType: permission.QuestionUnDelete},
 		{RoleID: 3, PowerType: permission.TagUnDelete},
+		{RoleID: 3, PowerType: permission.ContentModerate},
+		{RoleID: 3, PowerType: permission.QuestionMerge},
 	}
 
 	adminUserRoleRel = &entity.UserRoleRel{
@@ -347,6 +368,9 @@ var (
 		{ID: 128, Key: "rank.answer.undeleted", Value: `-1`},
 		{ID: 129, Key: "rank.question.undeleted", Value: `-1`},
 		{ID: 130, Key: "rank.tag.undeleted", Value: `-1`},
+		{ID: 131, Key: "rank.question.bounty", Value: `75`},
+		{ID: 132, Key: "question.bounty_offered", Value: `0`},
+		{ID: 133, Key: "answer.bounty_awarded", Value: `0`},
 	}
 
 	defaultBadgeGroupTable = []*entity.BadgeGroup{
@@ -100,12 +100,34 @@ var migrations = []Migration{
 	NewMigration("v1.4.0", "add badge/badge_group/badge_award table", addBadges, true),
 	NewMigration("v1.4.1", "add question link", addQuestionLink, true),
 	NewMigration("v1.4.2", "add the number of question links", addQuestionLinkedCount, true),
+	NewMigration("v1.4.3", "add queue message table", addQueueMessage, false),
+	NewMigration("v1.4.4", "add webhook and webhook delivery table", addWebhook, false),
+	NewMigration("v1.4.5", "add api key table", addAPIKey, false),
+	NewMigration("v1.4.6", "mark the built-in roles and add content moderate power", markBuiltInRoles, false),
+	NewMigration("v1.4.7", "add cron job table", addCronJob, false),
+	NewMigration("v1.4.8", "add full-text index of question and answer", addFullTextIndex, false),
+	NewMigration("v1.4.9", "add user two factor table", addUserTwoFactor, false),
+	NewMigration("v1.4.10", "add audit log table", addAuditLog, false),
+	NewMigration("v1.4.11", "add parent tag of tag", addTagParent, false),
+	NewMigration("v1.4.12", "add question merge table and power", addQuestionMerge, false),
+	NewMigration("v1.4.13", "add question bounty", addQuestionBounty, false),
+	NewMigration("v1.4.14", "add draft table", addDraft, false),
+	NewMigration("v1.4.15", "add notification digest table", addNotificationDigest, false),
+	NewMigration("v1.4.16", "add feed token table", addFeedToken, false),
+	NewMigration("v1.4.17", "add import record table", addImportRecord, false),
+	NewMigration("v1.4.18", "add search reindex task table", addSearchReindexTask, false),
+	NewMigration("v1.4.19", "add saved search table", addSavedSearch, false),
 }
 
 func GetMigrations() []Migration {
 	return migrations
 }
 
+// GetTables returns all tables created by answer
+func GetTables() []any {
+	return tables
+}
+
 // GetCurrentDBVersion returns the current db version
 func GetCurrentDBVersion(engine *xorm.Engine) (int64, error) {
 	if err := engine.Sync(new(entity.Version)); err != nil {
@@ -169,7 +191,7 @@ func Migrate(debug bool, dbConf *data.Database, cacheConf *data.CacheConf, upgra
 			fmt.Printf("[migrate] migrate to db version %d failed: %s\n", currentDBVersion+1, err.Error())
 			return err
 		}
-		if migrationFunc.ShouldCleanCache() {
+		if cache != nil && migrationFunc.ShouldCleanCache() {
 			if err := cache.Flush(context.Background()); err != nil {
 				fmt.Printf("[migrate] flush cache failed: %s\n", err.Error())
 			}
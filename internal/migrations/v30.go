@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentfault/pacman/log"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+func addFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	return InitFullTextIndex(ctx, x)
+}
+
+// InitFullTextIndex create the native full-text index of question and answer for the database.
+// mysql: FULLTEXT index with ngram parser, skipped if the parser is not supported (e.g. MariaDB), postgres: GIN index on tsvector, sqlite: FTS5 virtual tables kept by triggers.
+// It can be executed repeatedly, the sqlite FTS5 tables will be rebuilt from the question and answer tables.
+func InitFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	switch x.Dialect().URI().DBType {
+	case schemas.MYSQL:
+		return initMySQLFullTextIndex(ctx, x)
+	case schemas.POSTGRES:
+		return initPostgresFullTextIndex(ctx, x)
+	case schemas.SQLITE:
+		return initSQLiteFullTextIndex(ctx, x)
+	}
+	return nil
+}
+
+func initMySQLFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	// the ngram parser is required to segment CJK words, the search will use LIKE without the index
+	var parserCount int64
+	_, err := x.Context(ctx).SQL(`SELECT COUNT(*) FROM information_schema.plugins
+		WHERE plugin_name = 'ngram' AND plugin_status = 'ACTIVE'`).Get(&parserCount)
+	if err != nil {
+		return fmt.Errorf("check full-text parser failed: %w", err)
+	}
+	if parserCount == 0 {
+		log.Warnf("the ngram full-text parser is not supported by the database, skip creating full-text index")
+		return nil
+	}
+	indexes := []struct {
+		table, name, columns string
+	}{
+		{"question", "ft_question", "title, original_text"},
+		{"answer", "ft_answer", "original_text"},
+	}
+	for _, idx := range indexes {
+		var count int64
+		_, err := x.Context(ctx).SQL(`SELECT COUNT(*) FROM information_schema.statistics
+			WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?`, idx.table, idx.name).Get(&count)
+		if err != nil {
+			return fmt.Errorf("check full-text index failed: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+		_, err = x.Context(ctx).Exec(fmt.Sprintf("ALTER TABLE `%s` ADD FULLTEXT INDEX `%s` (%s) WITH PARSER ngram",
+			idx.table, idx.name, idx.columns))
+		if err != nil {
+			return fmt.Errorf("create full-text index failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func initPostgresFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	sqlList := []string{
+		`CREATE INDEX IF NOT EXISTS ft_question ON question USING GIN (to_tsvector('simple', title || ' ' || original_text))`,
+		`CREATE INDEX IF NOT EXISTS ft_answer ON answer USING GIN (to_tsvector('simple', original_text))`,
+	}
+	for _, sql := range sqlList {
+		if _, err := x.Context(ctx).Exec(sql); err != nil {
+			return fmt.Errorf("create full-text index failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func initSQLiteFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	sqlList := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS question_fts USING fts5(title, original_text, tokenize='unicode61')`,
+		`CREATE TRIGGER IF NOT EXISTS question_fts_insert AFTER INSERT ON question BEGIN
+			INSERT INTO question_fts(rowid, title, original_text) VALUES (new.id, new.title, new.original_text);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS question_fts_update AFTER UPDATE OF title, original_text ON question BEGIN
+			DELETE FROM question_fts WHERE rowid = old.id;
+			INSERT INTO question_fts(rowid, title, original_text) VALUES (new.id, new.title, new.original_text);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS question_fts_delete AFTER DELETE ON question BEGIN
+			DELETE FROM question_fts WHERE rowid = old.id;
+		END`,
+		`CREATE VIRTUAL TABLE IF NOT EXISTS answer_fts USING fts5(original_text, tokenize='unicode61')`,
+		`CREATE TRIGGER IF NOT EXISTS answer_fts_insert AFTER INSERT ON answer BEGIN
+			INSERT INTO answer_fts(rowid, original_text) VALUES (new.id, new.original_text);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS answer_fts_update AFTER UPDATE OF original_text ON answer BEGIN
+			DELETE FROM answer_fts WHERE rowid = old.id;
+			INSERT INTO answer_fts(rowid, original_text) VALUES (new.id, new.original_text);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS answer_fts_delete AFTER DELETE ON answer BEGIN
+			DELETE FROM answer_fts WHERE rowid = old.id;
+		END`,
+		`DELETE FROM question_fts`,
+		`INSERT INTO question_fts(rowid, title, original_text) SELECT id, title, original_text FROM question`,
+		`DELETE FROM answer_fts`,
+		`INSERT INTO answer_fts(rowid, original_text) SELECT id, original_text FROM answer`,
+	}
+	_, err := x.Transaction(func(session *xorm.Session) (interface{}, error) {
+		session = session.Context(ctx)
+		for _, sql := range sqlList {
+			if _, err := session.Exec(sql); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("create full-text index failed: %w", err)
+	}
+	return nil
+}
@@ -17,28 +17,15 @@
  * under the License.
  */
 
-package cli
+package migrations
 
 import (
-	"fmt"
-	"path/filepath"
-	"time"
+	"context"
 
-	"github.com/apache/incubator-answer/internal/base/data"
-	"xorm.io/xorm/schemas"
+	"github.com/apache/incubator-answer/internal/entity"
+	"xorm.io/xorm"
 )
 
-// DumpAllData dump all database data to sql
-func DumpAllData(dataConf *data.Database, dumpDataPath string) error {
-	db, err := data.NewDB(false, dataConf)
-	if err != nil {
-		return err
-	}
-	defer db.Close()
-	if err = db.Ping(); err != nil {
-		return err
-	}
-
-	name := filepath.Join(dumpDataPath, fmt.Sprintf("answer_dump_data_%s.sql", time.Now().Format("2006-01-02")))
-	return db.DumpAllToFile(name, schemas.MYSQL)
+func addSearchReindexTask(ctx context.Context, x *xorm.Engine) error {
+	return x.Context(ctx).Sync(new(entity.SearchReindexTask))
 }
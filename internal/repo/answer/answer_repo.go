@@ -459,6 +459,11 @@ func (ar *answerRepo) SumVotesByQuestionID(ctx context.Context, questionID strin
 	return count, nil
 }
 
+// UpdateSearch update the search index of answer
+func (ar *answerRepo) UpdateSearch(ctx context.Context, answerID string) (err error) {
+	return ar.updateSearch(ctx, answerID)
+}
+
 // updateSearch update search, if search plugin not enable, do nothing
 func (ar *answerRepo) updateSearch(ctx context.Context, answerID string) (err error) {
 	answerID = uid.DeShortID(answerID)
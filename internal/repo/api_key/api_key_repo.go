@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api_key
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/api_key"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// apiKeyRepo api key repository
+type apiKeyRepo struct {
+	data *data.Data
+}
+
+// NewAPIKeyRepo new repository
+func NewAPIKeyRepo(data *data.Data) api_key.APIKeyRepo {
+	return &apiKeyRepo{
+		data: data,
+	}
+}
+
+// AddAPIKey add api key
+func (ar *apiKeyRepo) AddAPIKey(ctx context.Context, apiKey *entity.APIKey) (err error) {
+	_, err = ar.data.DB.Context(ctx).Insert(apiKey)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetAPIKey get api key one
+func (ar *apiKeyRepo) GetAPIKey(ctx context.Context, id string) (
+	apiKey *entity.APIKey, exist bool, err error) {
+	apiKey = &entity.APIKey{}
+	exist, err = ar.data.DB.Context(ctx).ID(id).Get(apiKey)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetAPIKeyByTokenHash get api key by token hash
+func (ar *apiKeyRepo) GetAPIKeyByTokenHash(ctx context.Context, tokenHash string) (
+	apiKey *entity.APIKey, exist bool, err error) {
+	apiKey = &entity.APIKey{}
+	exist, err = ar.data.DB.Context(ctx).Where("token_hash = ?", tokenHash).Get(apiKey)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserAPIKeyList get all api keys of the user, the newest first
+func (ar *apiKeyRepo) GetUserAPIKeyList(ctx context.Context, userID string) (apiKeys []*entity.APIKey, err error) {
+	apiKeys = make([]*entity.APIKey, 0)
+	err = ar.data.DB.Context(ctx).Where("user_id = ?", userID).Desc("id").Find(&apiKeys)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetAPIKeyPage get api key page, filter by user if user id is not empty
+func (ar *apiKeyRepo) GetAPIKeyPage(ctx context.Context, page, pageSize int, userID string) (
+	apiKeys []*entity.APIKey, total int64, err error) {
+	apiKeys = make([]*entity.APIKey, 0)
+	session := ar.data.DB.Context(ctx).Desc("id")
+	if len(userID) > 0 {
+		session.Where("user_id = ?", userID)
+	}
+	total, err = pager.Help(page, pageSize, &apiKeys, &entity.APIKey{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountUserAvailableAPIKeys count the available api keys of the user
+func (ar *apiKeyRepo) CountUserAvailableAPIKeys(ctx context.Context, userID string) (count int64, err error) {
+	count, err = ar.data.DB.Context(ctx).Where("user_id = ? AND status = ?", userID, entity.APIKeyStatusAvailable).
+		Count(&entity.APIKey{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateAPIKeyStatus update api key status
+func (ar *apiKeyRepo) UpdateAPIKeyStatus(ctx context.Context, id string, status int) (err error) {
+	_, err = ar.data.DB.Context(ctx).ID(id).Cols("status").Update(&entity.APIKey{Status: status})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateLastUsedAt update the last used time of api key
+func (ar *apiKeyRepo) UpdateLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) (err error) {
+	_, err = ar.data.DB.Context(ctx).ID(id).NoAutoTime().Cols("last_used_at").
+		Update(&entity.APIKey{LastUsedAt: lastUsedAt})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package audit_log
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+const exportBatchSize = 500
+
+// auditLogRepo audit log repository
+type auditLogRepo struct {
+	data *data.Data
+}
+
+// NewAuditLogRepo new repository
+func NewAuditLogRepo(data *data.Data) audit_log.AuditLogRepo {
+	return &auditLogRepo{
+		data: data,
+	}
+}
+
+// AddAuditLog add audit log
+func (ar *auditLogRepo) AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) (err error) {
+	_, err = ar.data.DB.Context(ctx).Insert(auditLog)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetAuditLogPage get audit log page, the latest first
+func (ar *auditLogRepo) GetAuditLogPage(ctx context.Context, req *schema.GetAuditLogPageReq) (
+	auditLogs []*entity.AuditLog, total int64, err error) {
+	auditLogs = make([]*entity.AuditLog, 0)
+	session := ar.filterSession(ar.data.DB.Context(ctx), req)
+	session.Desc("id")
+	total, err = pager.Help(req.Page, req.PageSize, &auditLogs, &entity.AuditLog{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ForEachAuditLog iterate all audit logs that match the filter in batches, the oldest first
+func (ar *auditLogRepo) ForEachAuditLog(ctx context.Context, req *schema.GetAuditLogPageReq,
+	fn func(auditLog *entity.AuditLog) error) (err error) {
+	session := ar.filterSession(ar.data.DB.Context(ctx), req)
+	err = session.Asc("id").BufferSize(exportBatchSize).Iterate(&entity.AuditLog{}, func(_ int, bean any) error {
+		return fn(bean.(*entity.AuditLog))
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ar *auditLogRepo) filterSession(session *xorm.Session, req *schema.GetAuditLogPageReq) *xorm.Session {
+	if len(req.UserID) > 0 {
+		session.Where("user_id = ?", req.UserID)
+	}
+	if len(req.Action) > 0 {
+		session.Where("action = ?", req.Action)
+	}
+	if len(req.ObjectType) > 0 {
+		session.Where("object_type = ?", req.ObjectType)
+	}
+	if len(req.ObjectID) > 0 {
+		session.Where("object_id = ?", req.ObjectID)
+	}
+	if req.StartTime > 0 {
+		session.Where("created_at >= ?", time.Unix(req.StartTime, 0))
+	}
+	if req.EndTime > 0 {
+		session.Where("created_at <= ?", time.Unix(req.EndTime, 0))
+	}
+	return session
+}
@@ -223,6 +223,9 @@ func (ar *authRepo) RemoveUserTokens(ctx context.Context, userID string, remainT
 		if token == remainToken {
 			continue
 		}
+		if err := ar.RemoveAdminUserCacheInfo(ctx, token); err != nil {
+			log.Error(err)
+		}
 		if err := ar.RemoveUserCacheInfo(ctx, token); err != nil {
 			log.Error(err)
 		} else {
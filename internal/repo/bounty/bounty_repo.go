@@ -0,0 +1,211 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package bounty
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/bounty"
+	"github.com/apache/incubator-answer/internal/service/rank"
+	"github.com/apache/incubator-answer/pkg/converter"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// bountyRepo bounty repository
+type bountyRepo struct {
+	data         *data.Data
+	userRankRepo rank.UserRankRepo
+}
+
+// NewBountyRepo new repository
+func NewBountyRepo(data *data.Data, userRankRepo rank.UserRankRepo) bounty.BountyRepo {
+	return &bountyRepo{
+		data:         data,
+		userRankRepo: userRankRepo,
+	}
+}
+
+// AddBounty escrow the reputation of user and add the bounty.
+// If the question already has an active bounty or the user has not enough reputation, nothing is changed.
+// The unique index rejects the concurrent bounty of the same question, then the escrowed reputation is rolled back.
+func (br *bountyRepo) AddBounty(ctx context.Context, bounty *entity.QuestionBounty, activityType int) (
+	added bool, err error) {
+	_, err = br.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		exist, err := session.Where(builder.Eq{"question_id": bounty.QuestionID}).
+			And(builder.Eq{"status": entity.QuestionBountyStatusActive}).Exist(&entity.QuestionBounty{})
+		if err != nil || exist {
+			return nil, err
+		}
+
+		// the user keeps at least 1 reputation after escrowing
+		affected, err := session.Where(builder.Eq{"id": bounty.UserID}).And(builder.Gt{"`rank`": bounty.Amount}).
+			Decr("`rank`", bounty.Amount).Update(&entity.User{})
+		if err != nil || affected == 0 {
+			return nil, err
+		}
+		if _, err = session.Insert(bounty); err != nil {
+			return nil, err
+		}
+		_, err = session.Insert(&entity.Activity{
+			UserID:           bounty.UserID,
+			TriggerUserID:    converter.StringToInt64(bounty.UserID),
+			ObjectID:         bounty.QuestionID,
+			OriginalObjectID: bounty.QuestionID,
+			ActivityType:     activityType,
+			Rank:             -bounty.Amount,
+			HasRank:          1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		added = true
+		return nil, nil
+	})
+	if err != nil {
+		// another bounty of the question is added at the same time
+		if _, exist, getErr := br.GetActiveBounty(ctx, bounty.QuestionID); getErr == nil && exist {
+			return false, nil
+		}
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return added, nil
+}
+
+// AwardBounty award the active bounty to the answer, the status of bounty decides whether it is auto awarded
+func (br *bountyRepo) AwardBounty(ctx context.Context, bounty *entity.QuestionBounty, answer *entity.Answer,
+	activityType int) (awarded bool, err error) {
+	_, err = br.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		affected, err := session.ID(bounty.ID).And(builder.Eq{"status": entity.QuestionBountyStatusActive}).
+			Cols("status", "answer_id", "awarded_at", "closed_id").Update(&entity.QuestionBounty{
+			Status:    bounty.Status,
+			AnswerID:  answer.ID,
+			AwardedAt: time.Now(),
+			ClosedID:  bounty.ID,
+		})
+		if err != nil || affected == 0 {
+			return nil, err
+		}
+		if err = br.userRankRepo.ChangeUserRank(ctx, session, answer.UserID, 0, bounty.Amount); err != nil {
+			return nil, err
+		}
+		_, err = session.Insert(&entity.Activity{
+			UserID:           answer.UserID,
+			TriggerUserID:    converter.StringToInt64(bounty.UserID),
+			ObjectID:         answer.ID,
+			OriginalObjectID: bounty.QuestionID,
+			ActivityType:     activityType,
+			Rank:             bounty.Amount,
+			HasRank:          1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		awarded = true
+		return nil, nil
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return awarded, nil
+}
+
+// ExpireBounty expire the active bounty without awarding, the escrowed reputation is not refunded
+func (br *bountyRepo) ExpireBounty(ctx context.Context, bountyID string) (expired bool, err error) {
+	affected, err := br.data.DB.Context(ctx).ID(bountyID).And(builder.Eq{"status": entity.QuestionBountyStatusActive}).
+		Cols("status", "closed_id").
+		Update(&entity.QuestionBounty{Status: entity.QuestionBountyStatusExpired, ClosedID: bountyID})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// GetActiveBounty get the active bounty of question
+func (br *bountyRepo) GetActiveBounty(ctx context.Context, questionID string) (
+	bounty *entity.QuestionBounty, exist bool, err error) {
+	bounty = &entity.QuestionBounty{}
+	exist, err = br.data.DB.Context(ctx).Where(builder.Eq{"question_id": questionID}).
+		And(builder.Eq{"status": entity.QuestionBountyStatusActive}).Get(bounty)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return bounty, exist, nil
+}
+
+// GetLatestBounty get the latest bounty of question whatever its status
+func (br *bountyRepo) GetLatestBounty(ctx context.Context, questionID string) (
+	bounty *entity.QuestionBounty, exist bool, err error) {
+	bounty = &entity.QuestionBounty{}
+	exist, err = br.data.DB.Context(ctx).Where(builder.Eq{"question_id": questionID}).Desc("id").Get(bounty)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return bounty, exist, nil
+}
+
+// GetActiveBountyList get the active bounties of questions
+func (br *bountyRepo) GetActiveBountyList(ctx context.Context, questionIDs []string) (
+	bounties []*entity.QuestionBounty, err error) {
+	bounties = make([]*entity.QuestionBounty, 0)
+	if len(questionIDs) == 0 {
+		return bounties, nil
+	}
+	err = br.data.DB.Context(ctx).In("question_id", questionIDs).
+		And(builder.Eq{"status": entity.QuestionBountyStatusActive}).Find(&bounties)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return bounties, nil
+}
+
+// GetExpiredBountyList get the active bounties which are expired before deadline
+func (br *bountyRepo) GetExpiredBountyList(ctx context.Context, deadline time.Time, limit int) (
+	bounties []*entity.QuestionBounty, err error) {
+	bounties = make([]*entity.QuestionBounty, 0)
+	err = br.data.DB.Context(ctx).Where(builder.Eq{"status": entity.QuestionBountyStatusActive}).
+		And(builder.Lte{"expired_at": deadline}).Asc("expired_at").Limit(limit).Find(&bounties)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return bounties, nil
+}
+
+// GetTopAnswer get the available answer with the highest positive votes of question, excluding the answers of user
+func (br *bountyRepo) GetTopAnswer(ctx context.Context, questionID, excludeUserID string) (
+	answer *entity.Answer, exist bool, err error) {
+	answer = &entity.Answer{}
+	exist, err = br.data.DB.Context(ctx).Where(builder.Eq{"question_id": questionID}).
+		And(builder.Eq{"status": entity.AnswerStatusAvailable}).
+		And(builder.Neq{"user_id": excludeUserID}).
+		And(builder.Gt{"vote_count": 0}).
+		Desc("vote_count").Asc("created_at").Get(answer)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return answer, exist, nil
+}
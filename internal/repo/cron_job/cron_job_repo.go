@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package cron_job
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/cron"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// cronJobRepo cron job repository
+type cronJobRepo struct {
+	data *data.Data
+}
+
+// NewCronJobRepo new repository
+func NewCronJobRepo(data *data.Data) cron.JobRepo {
+	return &cronJobRepo{
+		data: data,
+	}
+}
+
+// AddJobIfNotExist add the job state if it does not exist
+func (cr *cronJobRepo) AddJobIfNotExist(ctx context.Context, name string) (err error) {
+	exist, err := cr.data.DB.Context(ctx).Where("name = ?", name).Exist(&entity.CronJob{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		return nil
+	}
+	_, err = cr.data.DB.Context(ctx).Insert(&entity.CronJob{Name: name})
+	if err != nil {
+		// the job may be added by other instance at the same time
+		exist, _ = cr.data.DB.Context(ctx).Where("name = ?", name).Exist(&entity.CronJob{})
+		if exist {
+			return nil
+		}
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetJobList get all job states
+func (cr *cronJobRepo) GetJobList(ctx context.Context) (jobs []*entity.CronJob, err error) {
+	jobs = make([]*entity.CronJob, 0)
+	err = cr.data.DB.Context(ctx).Asc("name").Find(&jobs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetJob get job state
+func (cr *cronJobRepo) GetJob(ctx context.Context, name string) (job *entity.CronJob, exist bool, err error) {
+	job = &entity.CronJob{}
+	exist, err = cr.data.DB.Context(ctx).Where("name = ?", name).Get(job)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateJobPaused pause or resume the job
+func (cr *cronJobRepo) UpdateJobPaused(ctx context.Context, name string, paused bool) (err error) {
+	_, err = cr.data.DB.Context(ctx).Where("name = ?", name).Cols("paused").
+		Update(&entity.CronJob{Paused: paused})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AcquireLease acquire the lease of job if the lease is released or expired.
+// If the scheduled time is not zero, it is claimed too, and the lease is not acquired if the time has been claimed.
+func (cr *cronJobRepo) AcquireLease(ctx context.Context, name, owner string, leaseExpiredAt, scheduledAt time.Time) (
+	acquired bool, err error) {
+	cond := builder.And(
+		builder.Eq{"name": name},
+		builder.Or(
+			builder.IsNull{"lease_expired_at"},
+			builder.Lt{"lease_expired_at": time.Now()},
+		),
+	)
+	cols := []string{"lease_owner", "lease_expired_at"}
+	if !scheduledAt.IsZero() {
+		cond = cond.And(builder.Lt{"last_scheduled_at": scheduledAt.Unix()})
+		cols = append(cols, "last_scheduled_at")
+	}
+	affected, err := cr.data.DB.Context(ctx).Where(cond).Cols(cols...).
+		Update(&entity.CronJob{LeaseOwner: owner, LeaseExpiredAt: leaseExpiredAt, LastScheduledAt: scheduledAt.Unix()})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected == 1, nil
+}
+
+// ReleaseLease record the execution result and release the lease held by the owner
+func (cr *cronJobRepo) ReleaseLease(ctx context.Context, job *entity.CronJob) (err error) {
+	job.LeaseExpiredAt = time.Now()
+	_, err = cr.data.DB.Context(ctx).Where("name = ? AND lease_owner = ?", job.Name, job.LeaseOwner).
+		Cols("last_run_at", "last_duration", "last_status", "last_error", "lease_expired_at").Update(job)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
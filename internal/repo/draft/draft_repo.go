@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package draft
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/draft"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// draftRepo draft repository
+type draftRepo struct {
+	data *data.Data
+}
+
+// NewDraftRepo new repository
+func NewDraftRepo(data *data.Data) draft.DraftRepo {
+	return &draftRepo{
+		data: data,
+	}
+}
+
+// SaveDraft add the draft or update the existing draft of the same object
+func (dr *draftRepo) SaveDraft(ctx context.Context, draft *entity.Draft) (err error) {
+	old := &entity.Draft{}
+	exist, err := dr.data.DB.Context(ctx).Where(builder.Eq{"user_id": draft.UserID}).
+		And(builder.Eq{"object_type": draft.ObjectType}).
+		And(builder.Eq{"object_id": draft.ObjectID}).Get(old)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		draft.ID = old.ID
+		draft.CreatedAt = old.CreatedAt
+		_, err = dr.data.DB.Context(ctx).ID(old.ID).Cols("title", "content", "tags").Update(draft)
+	} else {
+		_, err = dr.data.DB.Context(ctx).Insert(draft)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetDraft get the draft of user for the object
+func (dr *draftRepo) GetDraft(ctx context.Context, userID, objectType, objectID string) (
+	draft *entity.Draft, exist bool, err error) {
+	draft = &entity.Draft{}
+	exist, err = dr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).
+		And(builder.Eq{"object_type": objectType}).
+		And(builder.Eq{"object_id": objectID}).Get(draft)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDraftPage get the drafts of user, the latest updated first
+func (dr *draftRepo) GetDraftPage(ctx context.Context, userID string, page, pageSize int) (
+	drafts []*entity.Draft, total int64, err error) {
+	drafts = make([]*entity.Draft, 0)
+	session := dr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).Desc("updated_at", "id")
+	total, err = pager.Help(page, pageSize, &drafts, &entity.Draft{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveDraft remove the draft of user for the object
+func (dr *draftRepo) RemoveDraft(ctx context.Context, userID, objectType, objectID string) (err error) {
+	_, err = dr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).
+		And(builder.Eq{"object_type": objectType}).
+		And(builder.Eq{"object_id": objectID}).Delete(&entity.Draft{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveUserOverflowDrafts keep the latest updated drafts of user and remove the others
+func (dr *draftRepo) RemoveUserOverflowDrafts(ctx context.Context, userID string, keep int) (err error) {
+	overflow := make([]*entity.Draft, 0)
+	err = dr.data.DB.Context(ctx).Cols("id").Where(builder.Eq{"user_id": userID}).
+		Desc("updated_at", "id").Limit(1000, keep).Find(&overflow)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if len(overflow) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(overflow))
+	for _, d := range overflow {
+		ids = append(ids, d.ID)
+	}
+	_, err = dr.data.DB.Context(ctx).In("id", ids).Delete(&entity.Draft{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveExpiredDrafts remove the drafts not updated since the deadline
+func (dr *draftRepo) RemoveExpiredDrafts(ctx context.Context, deadline time.Time) (affected int64, err error) {
+	affected, err = dr.data.DB.Context(ctx).Where(builder.Lt{"updated_at": deadline}).Delete(&entity.Draft{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package feed
+
+import (
+	"context"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/feed"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// feedRepo feed repository
+type feedRepo struct {
+	data *data.Data
+}
+
+// NewFeedRepo new repository
+func NewFeedRepo(data *data.Data) feed.FeedRepo {
+	return &feedRepo{
+		data: data,
+	}
+}
+
+// GetFeedTokenByUserID get the feed token of user
+func (fr *feedRepo) GetFeedTokenByUserID(ctx context.Context, userID string) (
+	feedToken *entity.FeedToken, exist bool, err error) {
+	feedToken = &entity.FeedToken{}
+	exist, err = fr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).Get(feedToken)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetFeedTokenByToken get the feed token by token
+func (fr *feedRepo) GetFeedTokenByToken(ctx context.Context, token string) (
+	feedToken *entity.FeedToken, exist bool, err error) {
+	feedToken = &entity.FeedToken{}
+	exist, err = fr.data.DB.Context(ctx).Where(builder.Eq{"token": token}).Get(feedToken)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveFeedToken save the feed token of user, the old one will be replaced
+func (fr *feedRepo) SaveFeedToken(ctx context.Context, userID, token string) (err error) {
+	affected, err := fr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).
+		Cols("token").Update(&entity.FeedToken{Token: token})
+	if err == nil && affected == 0 {
+		_, err = fr.data.DB.Context(ctx).Insert(&entity.FeedToken{UserID: userID, Token: token})
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package import_record
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/importer"
+	"github.com/apache/incubator-answer/pkg/obj"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// importRecordRepo import record repository
+type importRecordRepo struct {
+	data *data.Data
+}
+
+// NewImportRecordRepo new repository
+func NewImportRecordRepo(data *data.Data) importer.ImportRecordRepo {
+	return &importRecordRepo{
+		data: data,
+	}
+}
+
+// GetImportRecord get the import record of the object in the source
+func (ir *importRecordRepo) GetImportRecord(ctx context.Context, source, sourceType, sourceID string) (
+	record *entity.ImportRecord, exist bool, err error) {
+	record = &entity.ImportRecord{}
+	exist, err = ir.data.DB.Context(ctx).Where(builder.Eq{
+		"source":      source,
+		"source_type": sourceType,
+		"source_id":   sourceID,
+	}).Get(record)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ClaimImportRecord add the import record before the object is created, the record keeps the object id 0 until
+// the import is completed. The unique index of the source makes sure that only one import claims the source id,
+// claimed is false if the source id has been claimed before.
+func (ir *importRecordRepo) ClaimImportRecord(ctx context.Context, record *entity.ImportRecord) (
+	claimed bool, err error) {
+	record.ObjectID = "0"
+	_, err = ir.data.DB.Context(ctx).Insert(record)
+	if err == nil {
+		return true, nil
+	}
+	// the insert fails on the unique index if the source id has been claimed
+	_, exist, getErr := ir.GetImportRecord(ctx, record.Source, record.SourceType, record.SourceID)
+	if getErr != nil {
+		return false, getErr
+	}
+	if exist {
+		return false, nil
+	}
+	return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+}
+
+// CompleteImportRecord set the object id of the claimed import record and the original time of the object
+// in one transaction, the time is kept if the created time is zero.
+// ReclaimImportRecord replace the stale claim that is not completed with the new claim,
+// claimed is false if the stale claim has been completed or reclaimed by others in the meantime.
+func (ir *importRecordRepo) ReclaimImportRecord(ctx context.Context, staleRecordID string, record *entity.ImportRecord) (
+	claimed bool, err error) {
+	record.ObjectID = "0"
+	_, err = ir.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		affected, err := session.ID(staleRecordID).Where(builder.Eq{"object_id": 0}).Delete(&entity.ImportRecord{})
+		if err != nil || affected == 0 {
+			return nil, err
+		}
+		if _, err = session.Insert(record); err != nil {
+			return nil, err
+		}
+		claimed = true
+		return nil, nil
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return claimed, nil
+}
+
+func (ir *importRecordRepo) CompleteImportRecord(ctx context.Context, recordID, objectID string,
+	createdAt, updatedAt time.Time) (err error) {
+	var table string
+	var cols map[string]any
+	if !createdAt.IsZero() {
+		table, cols, err = objectTimeCols(objectID, createdAt, updatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = ir.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		if len(table) > 0 {
+			if err = updateObjectTime(session, table, objectID, cols, createdAt); err != nil {
+				return nil, err
+			}
+		}
+		_, err = session.ID(recordID).Cols("object_id").Update(&entity.ImportRecord{ObjectID: objectID})
+		return nil, err
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveImportRecord remove the claimed import record when the object fails to be created
+func (ir *importRecordRepo) RemoveImportRecord(ctx context.Context, recordID string) (err error) {
+	_, err = ir.data.DB.Context(ctx).ID(recordID).Where(builder.Eq{"object_id": 0}).Delete(&entity.ImportRecord{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateObjectTime update the created and updated time of the question, answer or comment,
+// the updated time is kept if it is zero. The revisions of the object are moved to the created time too.
+func (ir *importRecordRepo) UpdateObjectTime(ctx context.Context, objectID string, createdAt, updatedAt time.Time) (
+	err error) {
+	table, cols, err := objectTimeCols(objectID, createdAt, updatedAt)
+	if err != nil {
+		return err
+	}
+	_, err = ir.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		return nil, updateObjectTime(session.Context(ctx), table, objectID, cols, createdAt)
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// objectTimeCols get the table and the time columns of the object to update
+func objectTimeCols(objectID string, createdAt, updatedAt time.Time) (table string, cols map[string]any, err error) {
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return "", nil, err
+	}
+	cols = map[string]any{"created_at": createdAt}
+	if !updatedAt.IsZero() {
+		cols["updated_at"] = updatedAt
+	}
+	switch objectType {
+	case constant.QuestionObjectType:
+		table = entity.Question{}.TableName()
+		cols["post_update_time"] = latestTime(createdAt, updatedAt)
+	case constant.AnswerObjectType:
+		table = entity.Answer{}.TableName()
+	case constant.CommentObjectType:
+		table = (&entity.Comment{}).TableName()
+		cols["updated_at"] = latestTime(createdAt, updatedAt)
+	default:
+		return "", nil, errors.BadRequest(reason.ObjectNotFound)
+	}
+	return table, cols, nil
+}
+
+func updateObjectTime(session *xorm.Session, table, objectID string, cols map[string]any, createdAt time.Time) (
+	err error) {
+	_, err = session.Table(table).Where(builder.Eq{"id": objectID}).Update(cols)
+	if err != nil {
+		return err
+	}
+	_, err = session.Table(entity.Revision{}.TableName()).Where(builder.Eq{"object_id": objectID}).
+		Update(map[string]any{"created_at": createdAt, "updated_at": createdAt})
+	return err
+}
+
+// RefreshQuestionPostTime set the post update time of question to the latest time of the question and its answers
+func (ir *importRecordRepo) RefreshQuestionPostTime(ctx context.Context, questionID string) (err error) {
+	question := &entity.Question{}
+	exist, err := ir.data.DB.Context(ctx).ID(questionID).Cols("created_at", "updated_at").Get(question)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	postUpdateTime := latestTime(question.CreatedAt, question.UpdatedAt)
+
+	answers := make([]*entity.Answer, 0)
+	err = ir.data.DB.Context(ctx).Where(builder.Eq{"question_id": questionID}).
+		And(builder.Eq{"status": entity.AnswerStatusAvailable}).Cols("created_at", "updated_at").Find(&answers)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, answer := range answers {
+		postUpdateTime = latestTime(postUpdateTime, latestTime(answer.CreatedAt, answer.UpdatedAt))
+	}
+	_, err = ir.data.DB.Context(ctx).Table(entity.Question{}.TableName()).Where(builder.Eq{"id": questionID}).
+		Update(map[string]any{"post_update_time": postUpdateTime})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+func latestTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
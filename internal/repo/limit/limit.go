@@ -22,13 +22,18 @@ package limit
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
+
 	"github.com/apache/incubator-answer/internal/base/constant"
 	"github.com/apache/incubator-answer/internal/base/data"
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/segmentfault/pacman/errors"
-	"time"
 )
 
+// windowCountLock makes the increment of the window count atomic for the cache that is not shared, such as memory
+var windowCountLock sync.Mutex
+
 // LimitRepo auth repository
 type LimitRepo struct {
 	data *data.Data
@@ -62,3 +67,53 @@ func (lr *LimitRepo) CheckAndRecord(ctx context.Context, key string) (limit bool
 func (lr *LimitRepo) ClearRecord(ctx context.Context, key string) error {
 	return lr.data.Cache.Del(ctx, constant.RateLimitCacheKeyPrefix+key)
 }
+
+// GetWindowCount get the request count of the window
+func (lr *LimitRepo) GetWindowCount(ctx context.Context, key string) (count int64, err error) {
+	count, _, err = lr.data.Cache.GetInt64(ctx, constant.RateLimitWindowCacheKeyPrefix+key)
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return count, nil
+}
+
+// IncreaseWindowCount increase the request count of the window, the count will be expired after ttl.
+// The count is increased and its ttl is set atomically, so that concurrent requests are all counted
+// and the count never lives without ttl.
+func (lr *LimitRepo) IncreaseWindowCount(ctx context.Context, key string, ttl time.Duration) (count int64, err error) {
+	key = constant.RateLimitWindowCacheKeyPrefix + key
+	if counter, ok := lr.data.Cache.(data.CounterCache); ok {
+		count, err = counter.IncreaseWithTTL(ctx, key, 1, ttl)
+		if err != nil {
+			return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		return count, nil
+	}
+
+	windowCountLock.Lock()
+	defer windowCountLock.Unlock()
+	_, exist, err := lr.data.Cache.GetInt64(ctx, key)
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		count, err = lr.data.Cache.Increase(ctx, key, 1)
+		if err == nil {
+			return count, nil
+		}
+		// the key is expired just now, start a new count
+	}
+	if err = lr.data.Cache.SetInt64(ctx, key, 1, ttl); err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return 1, nil
+}
+
+// DecreaseWindowCount give back the request count of the window, such as the request is rejected
+func (lr *LimitRepo) DecreaseWindowCount(ctx context.Context, key string) (err error) {
+	_, err = lr.data.Cache.Decrease(ctx, constant.RateLimitWindowCacheKeyPrefix+key, 1)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
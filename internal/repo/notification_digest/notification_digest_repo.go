@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package notification_digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/notification_digest"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// notificationDigestRepo notification digest repository
+type notificationDigestRepo struct {
+	data *data.Data
+}
+
+// NewNotificationDigestRepo new repository
+func NewNotificationDigestRepo(data *data.Data) notification_digest.NotificationDigestRepo {
+	return &notificationDigestRepo{
+		data: data,
+	}
+}
+
+// GetLastDigest get the last digest sent to user
+func (nr *notificationDigestRepo) GetLastDigest(ctx context.Context, userID string) (
+	digest *entity.NotificationDigest, exist bool, err error) {
+	digest = &entity.NotificationDigest{}
+	exist, err = nr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).Get(digest)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveLastDigest save the time of the last digest sent to user
+func (nr *notificationDigestRepo) SaveLastDigest(ctx context.Context, userID string, sentAt time.Time) (err error) {
+	affected, err := nr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).
+		Cols("last_sent_at").Update(&entity.NotificationDigest{LastSentAt: sentAt})
+	if err == nil && affected == 0 {
+		_, err = nr.data.DB.Context(ctx).Insert(&entity.NotificationDigest{UserID: userID, LastSentAt: sentAt})
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetTagsNewQuestions get the questions created since the time in the tags, the latest first
+func (nr *notificationDigestRepo) GetTagsNewQuestions(ctx context.Context, tagIDs []string, excludeUserID string,
+	since time.Time, limit int) (questions []*entity.Question, err error) {
+	questions = make([]*entity.Question, 0)
+	if len(tagIDs) == 0 {
+		return questions, nil
+	}
+	taggedQuestionIDs := builder.Select("object_id").From(entity.TagRel{}.TableName()).
+		Where(builder.In("tag_id", tagIDs).And(builder.Eq{"status": entity.TagRelStatusAvailable}))
+	err = nr.data.DB.Context(ctx).Cols("id", "user_id", "title", "created_at").
+		Where(builder.In("id", taggedQuestionIDs)).
+		And(builder.Eq{"status": entity.QuestionStatusAvailable}).
+		And(builder.Eq{"`show`": entity.QuestionShow}).
+		And(builder.Neq{"user_id": excludeUserID}).
+		And(builder.Gt{"created_at": since}).
+		Desc("created_at").Limit(limit).Find(&questions)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetQuestionsNewAnswers get the answers created since the time to the questions, the latest first
+func (nr *notificationDigestRepo) GetQuestionsNewAnswers(ctx context.Context, questionIDs []string,
+	excludeUserID string, since time.Time, limit int) (answers []*entity.Answer, err error) {
+	answers = make([]*entity.Answer, 0)
+	if len(questionIDs) == 0 {
+		return answers, nil
+	}
+	err = nr.data.DB.Context(ctx).Cols("id", "question_id", "user_id", "created_at").
+		Where(builder.In("question_id", questionIDs)).
+		And(builder.Eq{"status": entity.AnswerStatusAvailable}).
+		And(builder.Neq{"user_id": excludeUserID}).
+		And(builder.Gt{"created_at": since}).
+		Desc("created_at").Limit(limit).Find(&answers)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -24,34 +24,48 @@ import (
 	"github.com/apache/incubator-answer/internal/repo/activity"
 	"github.com/apache/incubator-answer/internal/repo/activity_common"
 	"github.com/apache/incubator-answer/internal/repo/answer"
+	"github.com/apache/incubator-answer/internal/repo/api_key"
+	"github.com/apache/incubator-answer/internal/repo/audit_log"
 	"github.com/apache/incubator-answer/internal/repo/auth"
 	"github.com/apache/incubator-answer/internal/repo/badge"
 	"github.com/apache/incubator-answer/internal/repo/badge_award"
 	"github.com/apache/incubator-answer/internal/repo/badge_group"
+	"github.com/apache/incubator-answer/internal/repo/bounty"
 	"github.com/apache/incubator-answer/internal/repo/captcha"
 	"github.com/apache/incubator-answer/internal/repo/collection"
 	"github.com/apache/incubator-answer/internal/repo/comment"
 	"github.com/apache/incubator-answer/internal/repo/config"
+	"github.com/apache/incubator-answer/internal/repo/cron_job"
+	"github.com/apache/incubator-answer/internal/repo/draft"
 	"github.com/apache/incubator-answer/internal/repo/export"
+	"github.com/apache/incubator-answer/internal/repo/feed"
+	"github.com/apache/incubator-answer/internal/repo/import_record"
 	"github.com/apache/incubator-answer/internal/repo/limit"
 	"github.com/apache/incubator-answer/internal/repo/meta"
 	"github.com/apache/incubator-answer/internal/repo/notification"
+	"github.com/apache/incubator-answer/internal/repo/notification_digest"
 	"github.com/apache/incubator-answer/internal/repo/plugin_config"
 	"github.com/apache/incubator-answer/internal/repo/question"
+	"github.com/apache/incubator-answer/internal/repo/question_merge"
+	"github.com/apache/incubator-answer/internal/repo/queue"
 	"github.com/apache/incubator-answer/internal/repo/rank"
 	"github.com/apache/incubator-answer/internal/repo/reason"
 	"github.com/apache/incubator-answer/internal/repo/report"
 	"github.com/apache/incubator-answer/internal/repo/review"
 	"github.com/apache/incubator-answer/internal/repo/revision"
 	"github.com/apache/incubator-answer/internal/repo/role"
+	"github.com/apache/incubator-answer/internal/repo/saved_search"
 	"github.com/apache/incubator-answer/internal/repo/search_common"
+	"github.com/apache/incubator-answer/internal/repo/search_sync"
 	"github.com/apache/incubator-answer/internal/repo/site_info"
 	"github.com/apache/incubator-answer/internal/repo/tag"
 	"github.com/apache/incubator-answer/internal/repo/tag_common"
+	"github.com/apache/incubator-answer/internal/repo/two_factor"
 	"github.com/apache/incubator-answer/internal/repo/unique"
 	"github.com/apache/incubator-answer/internal/repo/user"
 	"github.com/apache/incubator-answer/internal/repo/user_external_login"
 	"github.com/apache/incubator-answer/internal/repo/user_notification_config"
+	"github.com/apache/incubator-answer/internal/repo/webhook"
 	"github.com/google/wire"
 )
 
@@ -107,4 +121,19 @@ var ProviderSetRepo = wire.NewSet(
 	badge.NewEventRuleRepo,
 	badge_group.NewBadgeGroupRepo,
 	badge_award.NewBadgeAwardRepo,
+	queue.NewQueueMessageRepo,
+	webhook.NewWebhookRepo,
+	webhook.NewWebhookDeliveryRepo,
+	api_key.NewAPIKeyRepo,
+	cron_job.NewCronJobRepo,
+	two_factor.NewTwoFactorRepo,
+	audit_log.NewAuditLogRepo,
+	question_merge.NewQuestionMergeRepo,
+	bounty.NewBountyRepo,
+	draft.NewDraftRepo,
+	notification_digest.NewNotificationDigestRepo,
+	feed.NewFeedRepo,
+	import_record.NewImportRecordRepo,
+	search_sync.NewSearchReindexRepo,
+	saved_search.NewSavedSearchRepo,
 )
@@ -412,6 +412,10 @@ func (qr *questionRepo) GetQuestionPage(ctx context.Context, page, pageSize int,
 		session.OrderBy("question.pin desc,question.created_at DESC")
 	case "frequent":
 		session.OrderBy("question.pin DESC, question.linked_count DESC, question.updated_at DESC")
+	case "bounty":
+		session.Join("INNER", "question_bounty",
+			"question.id = question_bounty.question_id AND question_bounty.status = ?", entity.QuestionBountyStatusActive)
+		session.OrderBy("question_bounty.amount DESC, question_bounty.expired_at ASC")
 	}
 
 	total, err = pager.Help(page, pageSize, &questionList, &entity.Question{}, session)
@@ -0,0 +1,213 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package question_merge
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/question_merge"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// questionMergeRepo question merge repository
+type questionMergeRepo struct {
+	data *data.Data
+}
+
+// NewQuestionMergeRepo new repository
+func NewQuestionMergeRepo(data *data.Data) question_merge.QuestionMergeRepo {
+	return &questionMergeRepo{
+		data: data,
+	}
+}
+
+// MergeQuestion move the answers (and the question comments if moveComments is true) of source question
+// to target question, close source question and record the merge. The moved ids are filled into merge.
+func (qr *questionMergeRepo) MergeQuestion(ctx context.Context, merge *entity.QuestionMerge, moveComments bool) (err error) {
+	_, err = qr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+
+		// the record is inserted first, the unique index rejects the concurrent merge of the same source question
+		if _, err = session.Insert(merge); err != nil {
+			return nil, err
+		}
+
+		answerIDs := make([]string, 0)
+		err = session.Table(new(entity.Answer).TableName()).
+			Where(builder.Eq{"question_id": merge.SourceQuestionID}).Cols("id").Find(&answerIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(answerIDs) > 0 {
+			// the target question keeps its own accepted answer, so the moved answers are no longer accepted
+			_, err = session.In("id", answerIDs).Cols("question_id", "adopted").Update(&entity.Answer{
+				QuestionID: merge.TargetQuestionID,
+				Accepted:   schema.AnswerAcceptedFailed,
+			})
+			if err != nil {
+				return nil, err
+			}
+			_, err = session.In("object_id", answerIDs).Cols("question_id").
+				Update(&entity.Comment{QuestionID: merge.TargetQuestionID})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		commentIDs := make([]string, 0)
+		if moveComments {
+			err = session.Table(new(entity.Comment).TableName()).
+				Where(builder.Eq{"object_id": merge.SourceQuestionID}).Cols("id").Find(&commentIDs)
+			if err != nil {
+				return nil, err
+			}
+			if len(commentIDs) > 0 {
+				_, err = session.In("id", commentIDs).Cols("object_id", "question_id").Update(&entity.Comment{
+					ObjectID:   merge.TargetQuestionID,
+					QuestionID: merge.TargetQuestionID,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		_, err = session.ID(merge.SourceQuestionID).Cols("status", "accepted_answer_id").Update(&entity.Question{
+			Status:           entity.QuestionStatusClosed,
+			AcceptedAnswerID: "0",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		answerIDsJSON, _ := json.Marshal(answerIDs)
+		commentIDsJSON, _ := json.Marshal(commentIDs)
+		merge.AnswerIDs = string(answerIDsJSON)
+		merge.CommentIDs = string(commentIDsJSON)
+		_, err = session.ID(merge.ID).Cols("answer_ids", "comment_ids").Update(merge)
+		return nil, err
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RevertQuestionMerge move the recorded answers and comments back to source question
+// and restore the status and accepted answer of source question.
+// It returns false if the merge has been reverted by others.
+func (qr *questionMergeRepo) RevertQuestionMerge(ctx context.Context, merge *entity.QuestionMerge) (ok bool, err error) {
+	answerIDs, commentIDs := make([]string, 0), make([]string, 0)
+	_ = json.Unmarshal([]byte(merge.AnswerIDs), &answerIDs)
+	_ = json.Unmarshal([]byte(merge.CommentIDs), &commentIDs)
+
+	_, err = qr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+
+		// claim the merge first, so that the concurrent revert does nothing
+		affected, err := session.ID(merge.ID).Where(builder.Eq{"status": entity.QuestionMergeStatusMerged}).
+			Cols("status", "revert_id").
+			Update(&entity.QuestionMerge{Status: entity.QuestionMergeStatusReverted, RevertID: merge.ID})
+		if err != nil || affected == 0 {
+			return nil, err
+		}
+		ok = true
+
+		if len(answerIDs) > 0 {
+			_, err = session.In("id", answerIDs).Cols("question_id").
+				Update(&entity.Answer{QuestionID: merge.SourceQuestionID})
+			if err != nil {
+				return nil, err
+			}
+			_, err = session.In("object_id", answerIDs).Cols("question_id").
+				Update(&entity.Comment{QuestionID: merge.SourceQuestionID})
+			if err != nil {
+				return nil, err
+			}
+			// the moved answer may be accepted by target question after merging
+			target := &entity.Question{}
+			_, err = session.ID(merge.TargetQuestionID).Cols("accepted_answer_id").Get(target)
+			if err != nil {
+				return nil, err
+			}
+			if slices.Contains(answerIDs, target.AcceptedAnswerID) {
+				_, err = session.ID(merge.TargetQuestionID).Cols("accepted_answer_id").
+					Update(&entity.Question{AcceptedAnswerID: "0"})
+				if err != nil {
+					return nil, err
+				}
+				_, err = session.ID(target.AcceptedAnswerID).Cols("adopted").
+					Update(&entity.Answer{Accepted: schema.AnswerAcceptedFailed})
+				if err != nil {
+					return nil, err
+				}
+			}
+			if len(merge.SourceAcceptedAnswerID) > 0 && merge.SourceAcceptedAnswerID != "0" {
+				_, err = session.ID(merge.SourceAcceptedAnswerID).Cols("adopted").
+					Update(&entity.Answer{Accepted: schema.AnswerAcceptedEnable})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		if len(commentIDs) > 0 {
+			_, err = session.In("id", commentIDs).Cols("object_id", "question_id").Update(&entity.Comment{
+				ObjectID:   merge.SourceQuestionID,
+				QuestionID: merge.SourceQuestionID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = session.ID(merge.SourceQuestionID).Cols("status", "accepted_answer_id").Update(&entity.Question{
+			Status:           merge.SourceStatus,
+			AcceptedAnswerID: merge.SourceAcceptedAnswerID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return ok, nil
+}
+
+// GetQuestionMerge get the merge of source question which is not reverted
+func (qr *questionMergeRepo) GetQuestionMerge(ctx context.Context, sourceQuestionID string) (
+	merge *entity.QuestionMerge, exist bool, err error) {
+	merge = &entity.QuestionMerge{}
+	exist, err = qr.data.DB.Context(ctx).Where(builder.Eq{"source_question_id": sourceQuestionID}).
+		And(builder.Eq{"status": entity.QuestionMergeStatusMerged}).Desc("id").Get(merge)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return merge, exist, nil
+}
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/queue"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// queueMessageRepo queue message repository
+type queueMessageRepo struct {
+	data *data.Data
+}
+
+// NewQueueMessageRepo new repository
+func NewQueueMessageRepo(data *data.Data) queue.MessageRepo {
+	return &queueMessageRepo{
+		data: data,
+	}
+}
+
+// AddMessage add message
+func (qr *queueMessageRepo) AddMessage(ctx context.Context, msg *entity.QueueMessage) (err error) {
+	_, err = qr.data.DB.Context(ctx).Insert(msg)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ClaimMessages claim the messages that are ready to run, the message whose lock is expired will be claimed again.
+// The attempts column is used as an optimistic lock, so that a message is claimed by only one instance.
+func (qr *queueMessageRepo) ClaimMessages(ctx context.Context, queueName string, limit int, lockFor time.Duration) (
+	messages []*entity.QueueMessage, err error) {
+	now := time.Now()
+	candidates := make([]*entity.QueueMessage, 0)
+	err = qr.data.DB.Context(ctx).
+		Where("queue_name = ?", queueName).
+		And("(status = ? AND next_run_at <= ?) OR (status = ? AND locked_until <= ?)",
+			entity.QueueMessageStatusPending, now, entity.QueueMessageStatusProcessing, now).
+		Asc("id").Limit(limit).Find(&candidates)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+
+	messages = make([]*entity.QueueMessage, 0, len(candidates))
+	for _, candidate := range candidates {
+		claimed := &entity.QueueMessage{
+			Status:      entity.QueueMessageStatusProcessing,
+			Attempts:    candidate.Attempts + 1,
+			LockedUntil: now.Add(lockFor),
+		}
+		affected, err := qr.data.DB.Context(ctx).ID(candidate.ID).
+			Where("attempts = ?", candidate.Attempts).
+			Cols("status", "attempts", "locked_until").Update(claimed)
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if affected == 0 {
+			continue
+		}
+		candidate.Status = claimed.Status
+		candidate.Attempts = claimed.Attempts
+		candidate.LockedUntil = claimed.LockedUntil
+		messages = append(messages, candidate)
+	}
+	return messages, nil
+}
+
+// RemoveMessage remove the message that has been handled successfully
+func (qr *queueMessageRepo) RemoveMessage(ctx context.Context, id int64) (err error) {
+	_, err = qr.data.DB.Context(ctx).ID(id).Delete(&entity.QueueMessage{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RetryMessage set the message back to pending, it will be claimed again after the next run time
+func (qr *queueMessageRepo) RetryMessage(ctx context.Context, msg *entity.QueueMessage) (err error) {
+	msg.Status = entity.QueueMessageStatusPending
+	_, err = qr.data.DB.Context(ctx).ID(msg.ID).Cols("status", "next_run_at", "last_error", "done_handlers").
+		Update(msg)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// DeadMessage move the message to the dead letter state, it will not be claimed any more
+func (qr *queueMessageRepo) DeadMessage(ctx context.Context, msg *entity.QueueMessage) (err error) {
+	msg.Status = entity.QueueMessageStatusDead
+	_, err = qr.data.DB.Context(ctx).ID(msg.ID).Cols("status", "last_error", "done_handlers").
+		Update(msg)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/api_key"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_apiKeyRepo_GetAPIKeyByTokenHash(t *testing.T) {
+	apiKeyRepo := api_key.NewAPIKeyRepo(testDataSource)
+	apiKey := &entity.APIKey{
+		UserID:        "1",
+		CreatorUserID: "1",
+		Name:          "test",
+		TokenHash:     "2f3c2b4d6a0f7a1e9b8c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a",
+		TokenPrefix:   "ans_2f3c2b4d",
+		Scopes:        "read,write:question",
+		Status:        entity.APIKeyStatusAvailable,
+	}
+	err := apiKeyRepo.AddAPIKey(context.TODO(), apiKey)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, apiKey.ID)
+
+	got, exist, err := apiKeyRepo.GetAPIKeyByTokenHash(context.TODO(), apiKey.TokenHash)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, apiKey.ID, got.ID)
+	assert.True(t, got.HasScope("write:question"))
+	assert.False(t, got.HasScope("admin"))
+	assert.False(t, got.IsExpired())
+
+	now := time.Now()
+	err = apiKeyRepo.UpdateLastUsedAt(context.TODO(), apiKey.ID, now)
+	assert.NoError(t, err)
+	got, _, err = apiKeyRepo.GetAPIKey(context.TODO(), apiKey.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Unix(), got.LastUsedAt.Unix())
+
+	count, err := apiKeyRepo.CountUserAvailableAPIKeys(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = apiKeyRepo.UpdateAPIKeyStatus(context.TODO(), apiKey.ID, entity.APIKeyStatusRevoked)
+	assert.NoError(t, err)
+	count, err = apiKeyRepo.CountUserAvailableAPIKeys(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	apiKeys, total, err := apiKeyRepo.GetAPIKeyPage(context.TODO(), 1, 10, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, apiKeys, 1)
+}
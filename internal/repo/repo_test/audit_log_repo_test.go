@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/audit_log"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_auditLogRepo_AuditLog(t *testing.T) {
+	ctx := context.TODO()
+	auditLogRepo := audit_log.NewAuditLogRepo(testDataSource)
+
+	logs := []*entity.AuditLog{
+		{UserID: "1", Action: schema.AuditActionUpdateUserStatus, ObjectType: schema.AuditObjectUser, ObjectID: "2",
+			Before: `{"status":1}`, After: `{"status":9}`, IP: "127.0.0.1"},
+		{UserID: "1", Action: schema.AuditActionUpdatePluginStatus, ObjectType: schema.AuditObjectPlugin, ObjectID: "s3",
+			Before: `{"enabled":false}`, After: `{"enabled":true}`, IP: "127.0.0.1"},
+		{UserID: "3", Action: schema.AuditActionUpdateUserStatus, ObjectType: schema.AuditObjectUser, ObjectID: "4",
+			Before: `{}`, After: `{"status":1}`, IP: "127.0.0.2"},
+	}
+	for _, auditLog := range logs {
+		require.NoError(t, auditLogRepo.AddAuditLog(ctx, auditLog))
+	}
+	t.Cleanup(func() {
+		for _, auditLog := range logs {
+			_, _ = testDataSource.DB.Context(ctx).ID(auditLog.ID).Delete(&entity.AuditLog{})
+		}
+	})
+
+	got, total, err := auditLogRepo.GetAuditLogPage(ctx, &schema.GetAuditLogPageReq{
+		Page: 1, PageSize: 10, Action: schema.AuditActionUpdateUserStatus})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, got, 2)
+	// the latest first
+	assert.Equal(t, logs[2].ID, got[0].ID)
+	assert.Equal(t, logs[0].ID, got[1].ID)
+
+	got, total, err = auditLogRepo.GetAuditLogPage(ctx, &schema.GetAuditLogPageReq{
+		Page: 1, PageSize: 10, UserID: "1", ObjectType: schema.AuditObjectPlugin})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, got, 1)
+	assert.Equal(t, "s3", got[0].ObjectID)
+
+	ids := make([]string, 0)
+	err = auditLogRepo.ForEachAuditLog(ctx, &schema.GetAuditLogPageReq{UserID: "1"}, func(auditLog *entity.AuditLog) error {
+		ids = append(ids, auditLog.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{logs[0].ID, logs[1].ID}, ids)
+}
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/bounty"
+	"github.com/apache/incubator-answer/internal/repo/config"
+	"github.com/apache/incubator-answer/internal/repo/question"
+	"github.com/apache/incubator-answer/internal/repo/rank"
+	"github.com/apache/incubator-answer/internal/repo/unique"
+	config2 "github.com/apache/incubator-answer/internal/service/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_bountyRepo_OfferAndAward(t *testing.T) {
+	ctx := context.TODO()
+	configService := config2.NewConfigService(config.NewConfigRepo(testDataSource))
+	bountyRepo := bounty.NewBountyRepo(testDataSource, rank.NewUserRankRepo(testDataSource, configService))
+	const offeredType, awardedType = 9997, 9998
+
+	offerer := &entity.User{ID: "9101", Username: "bounty-offerer", EMail: "bounty-offerer@example.com",
+		DisplayName: "bounty-offerer", Rank: 100, Status: entity.UserStatusAvailable}
+	answerer := &entity.User{ID: "9102", Username: "bounty-answerer", EMail: "bounty-answerer@example.com",
+		DisplayName: "bounty-answerer", Rank: 1, Status: entity.UserStatusAvailable}
+	_, err := testDataSource.DB.Insert(offerer, answerer)
+	require.NoError(t, err)
+	answers := []*entity.Answer{
+		{ID: "10020000000000921", QuestionID: "10010000000000921", UserID: answerer.ID, OriginalText: "a",
+			ParsedText: "a", Status: entity.AnswerStatusAvailable, VoteCount: 3},
+		{ID: "10020000000000922", QuestionID: "10010000000000921", UserID: offerer.ID, OriginalText: "b",
+			ParsedText: "b", Status: entity.AnswerStatusAvailable, VoteCount: 5},
+	}
+	_, err = testDataSource.DB.Insert(answers)
+	require.NoError(t, err)
+	_, err = testDataSource.DB.Insert(&entity.Question{ID: "10010000000000921", UserID: offerer.ID, Title: "bounty",
+		OriginalText: "bounty", ParsedText: "bounty", Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.In("id", offerer.ID, answerer.ID).Delete(&entity.User{})
+		_, _ = testDataSource.DB.In("id", answers[0].ID, answers[1].ID).Delete(&entity.Answer{})
+		_, _ = testDataSource.DB.ID("10010000000000921").Delete(&entity.Question{})
+		_, _ = testDataSource.DB.Where("question_id = ?", "10010000000000921").Delete(&entity.QuestionBounty{})
+		_, _ = testDataSource.DB.In("activity_type", offeredType, awardedType).Delete(&entity.Activity{})
+	})
+
+	// the user must keep at least 1 reputation after escrowing
+	added, err := bountyRepo.AddBounty(ctx, &entity.QuestionBounty{QuestionID: "10010000000000921",
+		UserID: offerer.ID, Amount: 100, ExpiredAt: time.Now(), Status: entity.QuestionBountyStatusActive}, offeredType)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	bountyInfo := &entity.QuestionBounty{QuestionID: "10010000000000921", UserID: offerer.ID, Amount: 50,
+		ExpiredAt: time.Now().Add(-time.Minute), Status: entity.QuestionBountyStatusActive}
+	added, err = bountyRepo.AddBounty(ctx, bountyInfo, offeredType)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// only one active bounty on the question
+	added, err = bountyRepo.AddBounty(ctx, &entity.QuestionBounty{QuestionID: "10010000000000921",
+		UserID: offerer.ID, Amount: 10, ExpiredAt: time.Now(), Status: entity.QuestionBountyStatusActive}, offeredType)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// the unique index rejects the concurrent active bounty which passes the check
+	_, err = testDataSource.DB.Insert(&entity.QuestionBounty{QuestionID: "10010000000000921", UserID: offerer.ID,
+		Amount: 10, ExpiredAt: time.Now(), Status: entity.QuestionBountyStatusActive})
+	require.Error(t, err)
+
+	user := &entity.User{}
+	_, err = testDataSource.DB.ID(offerer.ID).Get(user)
+	require.NoError(t, err)
+	assert.Equal(t, 50, user.Rank)
+
+	// the question with active bounty is in the bounty list
+	questionRepo := question.NewQuestionRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+	questionList, total, err := questionRepo.GetQuestionPage(ctx, 1, 10, nil, "", "bounty", 0, false, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, "10010000000000921", questionList[0].ID)
+
+	expired, err := bountyRepo.GetExpiredBountyList(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, bountyInfo.ID, expired[0].ID)
+
+	// the answer of offerer is never the top answer
+	answer, exist, err := bountyRepo.GetTopAnswer(ctx, "10010000000000921", offerer.ID)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, answers[0].ID, answer.ID)
+
+	bountyInfo.Status = entity.QuestionBountyStatusAutoAwarded
+	awarded, err := bountyRepo.AwardBounty(ctx, bountyInfo, answer, awardedType)
+	require.NoError(t, err)
+	assert.True(t, awarded)
+	awarded, err = bountyRepo.AwardBounty(ctx, bountyInfo, answer, awardedType)
+	require.NoError(t, err)
+	assert.False(t, awarded)
+
+	user = &entity.User{}
+	_, err = testDataSource.DB.ID(answerer.ID).Get(user)
+	require.NoError(t, err)
+	assert.Equal(t, 51, user.Rank)
+
+	_, exist, err = bountyRepo.GetActiveBounty(ctx, "10010000000000921")
+	require.NoError(t, err)
+	assert.False(t, exist)
+	latest, exist, err := bountyRepo.GetLatestBounty(ctx, "10010000000000921")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, entity.QuestionBountyStatusAutoAwarded, latest.Status)
+	assert.Equal(t, answers[0].ID, latest.AnswerID)
+
+	activities := make([]*entity.Activity, 0)
+	require.NoError(t, testDataSource.DB.In("activity_type", offeredType, awardedType).Asc("id").Find(&activities))
+	require.Len(t, activities, 2)
+	assert.Equal(t, -50, activities[0].Rank)
+	assert.Equal(t, 50, activities[1].Rank)
+
+	// a new bounty can be offered after the bounty is awarded
+	added, err = bountyRepo.AddBounty(ctx, &entity.QuestionBounty{QuestionID: "10010000000000921",
+		UserID: offerer.ID, Amount: 10, ExpiredAt: time.Now(), Status: entity.QuestionBountyStatusActive}, offeredType)
+	require.NoError(t, err)
+	assert.True(t, added)
+}
@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/cron_job"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_cronJobRepo_AcquireLease(t *testing.T) {
+	cronJobRepo := cron_job.NewCronJobRepo(testDataSource)
+	err := cronJobRepo.AddJobIfNotExist(context.TODO(), "test_job")
+	assert.NoError(t, err)
+	err = cronJobRepo.AddJobIfNotExist(context.TODO(), "test_job")
+	assert.NoError(t, err)
+
+	acquired, err := cronJobRepo.AcquireLease(context.TODO(), "test_job", "instance-a", time.Now().Add(time.Hour), time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = cronJobRepo.AcquireLease(context.TODO(), "test_job", "instance-b", time.Now().Add(time.Hour), time.Time{})
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	err = cronJobRepo.ReleaseLease(context.TODO(), &entity.CronJob{
+		Name:         "test_job",
+		LeaseOwner:   "instance-a",
+		LastRunAt:    time.Now(),
+		LastDuration: 10,
+		LastStatus:   entity.CronJobStatusFailed,
+		LastError:    "failed",
+	})
+	assert.NoError(t, err)
+
+	job, exist, err := cronJobRepo.GetJob(context.TODO(), "test_job")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, entity.CronJobStatusFailed, job.LastStatus)
+	assert.Equal(t, "failed", job.LastError)
+
+	time.Sleep(time.Second)
+	acquired, err = cronJobRepo.AcquireLease(context.TODO(), "test_job", "instance-b", time.Now().Add(time.Hour), time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	err = cronJobRepo.UpdateJobPaused(context.TODO(), "test_job", true)
+	assert.NoError(t, err)
+	jobs, err := cronJobRepo.GetJobList(context.TODO())
+	assert.NoError(t, err)
+	var testJob *entity.CronJob
+	for _, job := range jobs {
+		if job.Name == "test_job" {
+			testJob = job
+		}
+	}
+	assert.NotNil(t, testJob)
+	assert.True(t, testJob.Paused)
+}
+
+func Test_cronJobRepo_ClaimScheduledTime(t *testing.T) {
+	cronJobRepo := cron_job.NewCronJobRepo(testDataSource)
+	err := cronJobRepo.AddJobIfNotExist(context.TODO(), "test_scheduled_job")
+	assert.NoError(t, err)
+	tick := time.Now().Truncate(time.Minute)
+
+	acquired, err := cronJobRepo.AcquireLease(context.TODO(), "test_scheduled_job", "instance-a",
+		time.Now().Add(time.Hour), tick)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	err = cronJobRepo.ReleaseLease(context.TODO(), &entity.CronJob{
+		Name:       "test_scheduled_job",
+		LeaseOwner: "instance-a",
+		LastRunAt:  time.Now(),
+		LastStatus: entity.CronJobStatusSuccess,
+	})
+	assert.NoError(t, err)
+
+	// the lease is released, but the tick has been run
+	time.Sleep(time.Second)
+	acquired, err = cronJobRepo.AcquireLease(context.TODO(), "test_scheduled_job", "instance-b",
+		time.Now().Add(time.Hour), tick)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	acquired, err = cronJobRepo.AcquireLease(context.TODO(), "test_scheduled_job", "instance-b",
+		time.Now().Add(time.Hour), tick.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	job, exist, err := cronJobRepo.GetJob(context.TODO(), "test_scheduled_job")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "instance-b", job.LeaseOwner)
+	assert.Equal(t, tick.Add(time.Minute).Unix(), job.LastScheduledAt)
+}
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/draft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_draftRepo_SaveAndRemove(t *testing.T) {
+	ctx := context.TODO()
+	draftRepo := draft.NewDraftRepo(testDataSource)
+	userID := "10010000000000931"
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("user_id = ?", userID).Delete(&entity.Draft{})
+	})
+
+	err := draftRepo.SaveDraft(ctx, &entity.Draft{UserID: userID, ObjectType: "question", ObjectID: "0",
+		Title: "title", Content: "content", Tags: "[]"})
+	require.NoError(t, err)
+
+	// the draft of the same object is overwritten
+	err = draftRepo.SaveDraft(ctx, &entity.Draft{UserID: userID, ObjectType: "question", ObjectID: "0",
+		Title: "new title", Content: "new content", Tags: "[]"})
+	require.NoError(t, err)
+	got, exist, err := draftRepo.GetDraft(ctx, userID, "question", "0")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "new title", got.Title)
+	assert.Equal(t, "new content", got.Content)
+
+	err = draftRepo.SaveDraft(ctx, &entity.Draft{UserID: userID, ObjectType: "answer", ObjectID: "10010000000000932",
+		Content: "answer", Tags: "[]"})
+	require.NoError(t, err)
+	drafts, total, err := draftRepo.GetDraftPage(ctx, userID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, drafts, 2)
+
+	err = draftRepo.RemoveDraft(ctx, userID, "answer", "10010000000000932")
+	require.NoError(t, err)
+	_, exist, err = draftRepo.GetDraft(ctx, userID, "answer", "10010000000000932")
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_draftRepo_Retention(t *testing.T) {
+	ctx := context.TODO()
+	draftRepo := draft.NewDraftRepo(testDataSource)
+	userID := "10010000000000933"
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("user_id = ?", userID).Delete(&entity.Draft{})
+	})
+
+	objectIDs := []string{"10010000000000934", "10010000000000935", "10010000000000936"}
+	for _, objectID := range objectIDs {
+		err := draftRepo.SaveDraft(ctx, &entity.Draft{UserID: userID, ObjectType: "answer", ObjectID: objectID,
+			Content: "answer", Tags: "[]"})
+		require.NoError(t, err)
+	}
+
+	err := draftRepo.RemoveUserOverflowDrafts(ctx, userID, 2)
+	require.NoError(t, err)
+	_, total, err := draftRepo.GetDraftPage(ctx, userID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	_, exist, err := draftRepo.GetDraft(ctx, userID, "answer", objectIDs[0])
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	affected, err := draftRepo.RemoveExpiredDrafts(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+}
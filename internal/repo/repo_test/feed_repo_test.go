@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/feed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_feedRepo_FeedToken(t *testing.T) {
+	ctx := context.TODO()
+	feedRepo := feed.NewFeedRepo(testDataSource)
+	userID := "10010000000000951"
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("user_id = ?", userID).Delete(&entity.FeedToken{})
+	})
+
+	_, exist, err := feedRepo.GetFeedTokenByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	require.NoError(t, feedRepo.SaveFeedToken(ctx, userID, "feed-token-old"))
+	require.NoError(t, feedRepo.SaveFeedToken(ctx, userID, "feed-token-new"))
+	feedToken, exist, err := feedRepo.GetFeedTokenByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "feed-token-new", feedToken.Token)
+
+	// the old token is unavailable after reset
+	_, exist, err = feedRepo.GetFeedTokenByToken(ctx, "feed-token-old")
+	require.NoError(t, err)
+	assert.False(t, exist)
+	feedToken, exist, err = feedRepo.GetFeedTokenByToken(ctx, "feed-token-new")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, userID, feedToken.UserID)
+}
@@ -0,0 +1,141 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/import_record"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_importRecordRepo_ImportRecord(t *testing.T) {
+	ctx := context.TODO()
+	importRecordRepo := import_record.NewImportRecordRepo(testDataSource)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("source = ?", "plugin:test").Delete(&entity.ImportRecord{})
+	})
+
+	_, exist, err := importRecordRepo.GetImportRecord(ctx, "plugin:test", "question", "q1")
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	claim := &entity.ImportRecord{Source: "plugin:test", SourceType: "question", SourceID: "q1"}
+	claimed, err := importRecordRepo.ClaimImportRecord(ctx, claim)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	// the source id is claimed only once
+	claimed, err = importRecordRepo.ClaimImportRecord(ctx,
+		&entity.ImportRecord{Source: "plugin:test", SourceType: "question", SourceID: "q1"})
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	require.NoError(t, importRecordRepo.CompleteImportRecord(ctx, claim.ID, "10010000000000961", time.Time{}, time.Time{}))
+	record, exist, err := importRecordRepo.GetImportRecord(ctx, "plugin:test", "question", "q1")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "10010000000000961", record.ObjectID)
+
+	// the completed record is kept
+	require.NoError(t, importRecordRepo.RemoveImportRecord(ctx, claim.ID))
+	_, exist, err = importRecordRepo.GetImportRecord(ctx, "plugin:test", "question", "q1")
+	require.NoError(t, err)
+	assert.True(t, exist)
+
+	// the same source id of other type is another object
+	_, exist, err = importRecordRepo.GetImportRecord(ctx, "plugin:test", "answer", "q1")
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_importRecordRepo_UpdateObjectTime(t *testing.T) {
+	ctx := context.TODO()
+	importRecordRepo := import_record.NewImportRecordRepo(testDataSource)
+	questionID, answerID := "10010000000000962", "10020000000000962"
+	_, err := testDataSource.DB.Insert(&entity.Question{ID: questionID, UserID: "1", Title: "imported",
+		OriginalText: "imported", ParsedText: "imported", Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow})
+	require.NoError(t, err)
+	_, err = testDataSource.DB.Insert(&entity.Answer{ID: answerID, QuestionID: questionID, UserID: "1",
+		OriginalText: "imported", ParsedText: "imported", Status: entity.AnswerStatusAvailable})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.ID(questionID).Delete(&entity.Question{})
+		_, _ = testDataSource.DB.ID(answerID).Delete(&entity.Answer{})
+	})
+
+	askedAt := time.Date(2015, 3, 1, 8, 0, 0, 0, time.UTC)
+	answeredAt := time.Date(2015, 3, 2, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, importRecordRepo.UpdateObjectTime(ctx, questionID, askedAt, time.Time{}))
+	require.NoError(t, importRecordRepo.UpdateObjectTime(ctx, answerID, answeredAt, time.Time{}))
+
+	question := &entity.Question{}
+	_, err = testDataSource.DB.ID(questionID).Get(question)
+	require.NoError(t, err)
+	assert.Equal(t, askedAt.Unix(), question.CreatedAt.Unix())
+	assert.Equal(t, askedAt.Unix(), question.PostUpdateTime.Unix())
+
+	// the latest answer is the last update of question
+	require.NoError(t, importRecordRepo.RefreshQuestionPostTime(ctx, questionID))
+	question = &entity.Question{}
+	_, err = testDataSource.DB.ID(questionID).Get(question)
+	require.NoError(t, err)
+	assert.Equal(t, answeredAt.Unix(), question.PostUpdateTime.Unix())
+}
+
+func Test_importRecordRepo_ReclaimImportRecord(t *testing.T) {
+	ctx := context.TODO()
+	importRecordRepo := import_record.NewImportRecordRepo(testDataSource)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("source = ?", "plugin:reclaim").Delete(&entity.ImportRecord{})
+	})
+
+	stale := &entity.ImportRecord{Source: "plugin:reclaim", SourceType: "question", SourceID: "q1"}
+	claimed, err := importRecordRepo.ClaimImportRecord(ctx, stale)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	claim := &entity.ImportRecord{Source: "plugin:reclaim", SourceType: "question", SourceID: "q1"}
+	claimed, err = importRecordRepo.ReclaimImportRecord(ctx, stale.ID, claim)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	assert.NotEqual(t, stale.ID, claim.ID)
+
+	// the stale claim can be reclaimed only once
+	claimed, err = importRecordRepo.ReclaimImportRecord(ctx, stale.ID,
+		&entity.ImportRecord{Source: "plugin:reclaim", SourceType: "question", SourceID: "q1"})
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	// the completed record can not be reclaimed
+	require.NoError(t, importRecordRepo.CompleteImportRecord(ctx, claim.ID, "10010000000000963", time.Time{}, time.Time{}))
+	claimed, err = importRecordRepo.ReclaimImportRecord(ctx, claim.ID,
+		&entity.ImportRecord{Source: "plugin:reclaim", SourceType: "question", SourceID: "q1"})
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	record, exist, err := importRecordRepo.GetImportRecord(ctx, "plugin:reclaim", "question", "q1")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "10010000000000963", record.ObjectID)
+}
@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/repo/limit"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_limitRepo_IncreaseWindowCount(t *testing.T) {
+	limitRepo := limit.NewRateLimitRepo(testDataSource)
+	key := "search:ip:127.0.0.1:1700000000"
+
+	count, err := limitRepo.GetWindowCount(context.TODO(), key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	for i := 1; i <= 3; i++ {
+		count, err = limitRepo.IncreaseWindowCount(context.TODO(), key, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(i), count)
+	}
+
+	count, err = limitRepo.GetWindowCount(context.TODO(), key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	err = limitRepo.DecreaseWindowCount(context.TODO(), key)
+	assert.NoError(t, err)
+	count, err = limitRepo.GetWindowCount(context.TODO(), key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/notification_digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_notificationDigestRepo_LastDigest(t *testing.T) {
+	ctx := context.TODO()
+	digestRepo := notification_digest.NewNotificationDigestRepo(testDataSource)
+	userID := "10010000000000941"
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("user_id = ?", userID).Delete(&entity.NotificationDigest{})
+	})
+
+	_, exist, err := digestRepo.GetLastDigest(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	sentAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, digestRepo.SaveLastDigest(ctx, userID, sentAt))
+	require.NoError(t, digestRepo.SaveLastDigest(ctx, userID, sentAt.Add(time.Minute)))
+	digest, exist, err := digestRepo.GetLastDigest(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, sentAt.Add(time.Minute).Unix(), digest.LastSentAt.Unix())
+}
+
+func Test_notificationDigestRepo_NewPosts(t *testing.T) {
+	ctx := context.TODO()
+	digestRepo := notification_digest.NewNotificationDigestRepo(testDataSource)
+	userID, authorID := "10010000000000942", "10010000000000943"
+	tagID := "10030000000000942"
+	questions := []*entity.Question{
+		{ID: "10010000000000944", UserID: authorID, Title: "tagged question", OriginalText: "content",
+			ParsedText: "content", Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow, CreatedAt: time.Now()},
+		{ID: "10010000000000945", UserID: userID, Title: "own question", OriginalText: "content",
+			ParsedText: "content", Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow, CreatedAt: time.Now()},
+	}
+	_, err := testDataSource.DB.Insert(questions)
+	require.NoError(t, err)
+	_, err = testDataSource.DB.Insert([]*entity.TagRel{
+		{ObjectID: questions[0].ID, TagID: tagID, Status: entity.TagRelStatusAvailable},
+		{ObjectID: questions[1].ID, TagID: tagID, Status: entity.TagRelStatusAvailable},
+	})
+	require.NoError(t, err)
+	answers := []*entity.Answer{
+		{ID: "10020000000000946", QuestionID: questions[1].ID, UserID: authorID, OriginalText: "answer",
+			ParsedText: "answer", Status: entity.AnswerStatusAvailable},
+		{ID: "10020000000000947", QuestionID: questions[1].ID, UserID: userID, OriginalText: "answer",
+			ParsedText: "answer", Status: entity.AnswerStatusAvailable},
+	}
+	_, err = testDataSource.DB.Insert(answers)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.In("id", questions[0].ID, questions[1].ID).Delete(&entity.Question{})
+		_, _ = testDataSource.DB.Where("tag_id = ?", tagID).Delete(&entity.TagRel{})
+		_, _ = testDataSource.DB.In("id", answers[0].ID, answers[1].ID).Delete(&entity.Answer{})
+	})
+
+	since := time.Now().Add(-time.Hour)
+	newQuestions, err := digestRepo.GetTagsNewQuestions(ctx, []string{tagID}, userID, since, 10)
+	require.NoError(t, err)
+	require.Len(t, newQuestions, 1)
+	assert.Equal(t, questions[0].ID, newQuestions[0].ID)
+
+	newAnswers, err := digestRepo.GetQuestionsNewAnswers(ctx, []string{questions[1].ID}, userID, since, 10)
+	require.NoError(t, err)
+	require.Len(t, newAnswers, 1)
+	assert.Equal(t, answers[0].ID, newAnswers[0].ID)
+
+	newQuestions, err = digestRepo.GetTagsNewQuestions(ctx, []string{tagID}, userID, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, newQuestions)
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/question_merge"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_questionMergeRepo_MergeAndRevert(t *testing.T) {
+	ctx := context.TODO()
+	questionMergeRepo := question_merge.NewQuestionMergeRepo(testDataSource)
+
+	source := &entity.Question{ID: "10010000000000911", UserID: "1", Title: "merge source", OriginalText: "source",
+		ParsedText: "source", Status: entity.QuestionStatusAvailable, AcceptedAnswerID: "10020000000000911"}
+	target := &entity.Question{ID: "10010000000000912", UserID: "1", Title: "merge target", OriginalText: "target",
+		ParsedText: "target", Status: entity.QuestionStatusAvailable, AcceptedAnswerID: "0"}
+	_, err := testDataSource.DB.Insert(source, target)
+	require.NoError(t, err)
+	answers := []*entity.Answer{
+		{ID: "10020000000000911", QuestionID: source.ID, UserID: "1", OriginalText: "a", ParsedText: "a",
+			Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedEnable},
+		{ID: "10020000000000912", QuestionID: source.ID, UserID: "1", OriginalText: "b", ParsedText: "b",
+			Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedFailed},
+	}
+	_, err = testDataSource.DB.Insert(answers)
+	require.NoError(t, err)
+	comments := []*entity.Comment{
+		{ID: "10040000000000911", ObjectID: source.ID, QuestionID: source.ID, UserID: "1",
+			OriginalText: "c", ParsedText: "c", Status: entity.CommentStatusAvailable},
+		{ID: "10040000000000912", ObjectID: "10020000000000912", QuestionID: source.ID, UserID: "1",
+			OriginalText: "d", ParsedText: "d", Status: entity.CommentStatusAvailable},
+	}
+	_, err = testDataSource.DB.Insert(comments)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.In("id", source.ID, target.ID).Delete(&entity.Question{})
+		_, _ = testDataSource.DB.In("id", answers[0].ID, answers[1].ID).Delete(&entity.Answer{})
+		_, _ = testDataSource.DB.In("id", comments[0].ID, comments[1].ID).Delete(&entity.Comment{})
+		_, _ = testDataSource.DB.Where("source_question_id = ?", source.ID).Delete(&entity.QuestionMerge{})
+	})
+
+	merge := &entity.QuestionMerge{
+		SourceQuestionID:       source.ID,
+		TargetQuestionID:       target.ID,
+		UserID:                 "1",
+		SourceStatus:           source.Status,
+		SourceAcceptedAnswerID: source.AcceptedAnswerID,
+		Status:                 entity.QuestionMergeStatusMerged,
+	}
+	require.NoError(t, questionMergeRepo.MergeQuestion(ctx, merge, true))
+	assert.JSONEq(t, `["10020000000000911","10020000000000912"]`, merge.AnswerIDs)
+	assert.JSONEq(t, `["10040000000000911"]`, merge.CommentIDs)
+
+	gotAnswers := make([]*entity.Answer, 0)
+	require.NoError(t, testDataSource.DB.In("id", answers[0].ID, answers[1].ID).Find(&gotAnswers))
+	for _, answer := range gotAnswers {
+		assert.Equal(t, target.ID, answer.QuestionID)
+		assert.Equal(t, schema.AnswerAcceptedFailed, answer.Accepted)
+	}
+	gotComments := make([]*entity.Comment, 0)
+	require.NoError(t, testDataSource.DB.In("id", comments[0].ID, comments[1].ID).Asc("id").Find(&gotComments))
+	assert.Equal(t, target.ID, gotComments[0].ObjectID)
+	assert.Equal(t, target.ID, gotComments[0].QuestionID)
+	assert.Equal(t, "10020000000000912", gotComments[1].ObjectID)
+	assert.Equal(t, target.ID, gotComments[1].QuestionID)
+
+	gotSource := &entity.Question{}
+	_, err = testDataSource.DB.ID(source.ID).Get(gotSource)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionStatusClosed, gotSource.Status)
+	assert.Equal(t, "0", gotSource.AcceptedAnswerID)
+
+	got, exist, err := questionMergeRepo.GetQuestionMerge(ctx, source.ID)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, target.ID, got.TargetQuestionID)
+
+	// the source question can not be merged again while the merge is not reverted
+	again := &entity.QuestionMerge{
+		SourceQuestionID: source.ID,
+		TargetQuestionID: target.ID,
+		UserID:           "1",
+		Status:           entity.QuestionMergeStatusMerged,
+	}
+	assert.Error(t, questionMergeRepo.MergeQuestion(ctx, again, true))
+
+	// the moved answer accepted by target question is unaccepted after reverting
+	_, err = testDataSource.DB.ID(target.ID).Cols("accepted_answer_id").
+		Update(&entity.Question{AcceptedAnswerID: answers[1].ID})
+	require.NoError(t, err)
+	_, err = testDataSource.DB.ID(answers[1].ID).Cols("adopted").
+		Update(&entity.Answer{Accepted: schema.AnswerAcceptedEnable})
+	require.NoError(t, err)
+
+	ok, err := questionMergeRepo.RevertQuestionMerge(ctx, got)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	// the merge can only be reverted once
+	ok, err = questionMergeRepo.RevertQuestionMerge(ctx, got)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	gotAnswers = make([]*entity.Answer, 0)
+	require.NoError(t, testDataSource.DB.In("id", answers[0].ID, answers[1].ID).Asc("id").Find(&gotAnswers))
+	assert.Equal(t, source.ID, gotAnswers[0].QuestionID)
+	assert.Equal(t, schema.AnswerAcceptedEnable, gotAnswers[0].Accepted)
+	assert.Equal(t, source.ID, gotAnswers[1].QuestionID)
+	assert.Equal(t, schema.AnswerAcceptedFailed, gotAnswers[1].Accepted)
+	gotComments = make([]*entity.Comment, 0)
+	require.NoError(t, testDataSource.DB.In("id", comments[0].ID, comments[1].ID).Asc("id").Find(&gotComments))
+	assert.Equal(t, source.ID, gotComments[0].ObjectID)
+	assert.Equal(t, source.ID, gotComments[1].QuestionID)
+
+	gotSource = &entity.Question{}
+	_, err = testDataSource.DB.ID(source.ID).Get(gotSource)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionStatusAvailable, gotSource.Status)
+	assert.Equal(t, source.AcceptedAnswerID, gotSource.AcceptedAnswerID)
+	gotTarget := &entity.Question{}
+	_, err = testDataSource.DB.ID(target.ID).Get(gotTarget)
+	require.NoError(t, err)
+	assert.Equal(t, "0", gotTarget.AcceptedAnswerID)
+
+	_, exist, err = questionMergeRepo.GetQuestionMerge(ctx, source.ID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	// the reverted merge doesn't prevent merging again
+	again.ID = ""
+	require.NoError(t, questionMergeRepo.MergeQuestion(ctx, again, false))
+}
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_queueMessageRepo_ClaimMessages(t *testing.T) {
+	queueMessageRepo := queue.NewQueueMessageRepo(testDataSource)
+	msg := &entity.QueueMessage{
+		QueueName: "test_claim",
+		Payload:   `{"user_id":"1"}`,
+		Status:    entity.QueueMessageStatusPending,
+		NextRunAt: time.Now().Add(-time.Second),
+	}
+	err := queueMessageRepo.AddMessage(context.TODO(), msg)
+	assert.NoError(t, err)
+
+	messages, err := queueMessageRepo.ClaimMessages(context.TODO(), "test_claim", 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, 1, messages[0].Attempts)
+
+	// the claimed message is locked, so it can not be claimed again
+	messages, err = queueMessageRepo.ClaimMessages(context.TODO(), "test_claim", 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 0)
+
+	err = queueMessageRepo.RemoveMessage(context.TODO(), msg.ID)
+	assert.NoError(t, err)
+}
+
+func Test_queueMessageRepo_RetryAndDeadMessage(t *testing.T) {
+	queueMessageRepo := queue.NewQueueMessageRepo(testDataSource)
+	msg := &entity.QueueMessage{
+		QueueName: "test_retry",
+		Payload:   `{}`,
+		Status:    entity.QueueMessageStatusPending,
+		NextRunAt: time.Now().Add(-time.Second),
+	}
+	err := queueMessageRepo.AddMessage(context.TODO(), msg)
+	assert.NoError(t, err)
+
+	messages, err := queueMessageRepo.ClaimMessages(context.TODO(), "test_retry", 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+
+	messages[0].NextRunAt = time.Now().Add(-time.Second)
+	messages[0].LastError = "error"
+	messages[0].SetDoneHandlers([]string{"handler_a"})
+	err = queueMessageRepo.RetryMessage(context.TODO(), messages[0])
+	assert.NoError(t, err)
+	messages, err = queueMessageRepo.ClaimMessages(context.TODO(), "test_retry", 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, 2, messages[0].Attempts)
+	assert.Equal(t, []string{"handler_a"}, messages[0].GetDoneHandlers())
+
+	err = queueMessageRepo.DeadMessage(context.TODO(), messages[0])
+	assert.NoError(t, err)
+	messages, err = queueMessageRepo.ClaimMessages(context.TODO(), "test_retry", 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 0)
+}
+
+func Test_queueMessageRepo_ClaimExpiredLock(t *testing.T) {
+	queueMessageRepo := queue.NewQueueMessageRepo(testDataSource)
+	msg := &entity.QueueMessage{
+		QueueName: "test_expired_lock",
+		Payload:   `{}`,
+		Status:    entity.QueueMessageStatusPending,
+		NextRunAt: time.Now().Add(-time.Second),
+	}
+	err := queueMessageRepo.AddMessage(context.TODO(), msg)
+	assert.NoError(t, err)
+
+	// claimed by a worker which never finishes, the lock expires at once
+	messages, err := queueMessageRepo.ClaimMessages(context.TODO(), "test_expired_lock", 10, -time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+
+	messages, err = queueMessageRepo.ClaimMessages(context.TODO(), "test_expired_lock", 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, 2, messages[0].Attempts)
+	assert.Equal(t, entity.QueueMessageStatusProcessing, messages[0].Status)
+
+	err = queueMessageRepo.RemoveMessage(context.TODO(), msg.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/role"
+	"github.com/apache/incubator-answer/internal/service/permission"
+	roleService "github.com/apache/incubator-answer/internal/service/role"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_roleRepo_CustomRole(t *testing.T) {
+	roleRepo := role.NewRoleRepo(testDataSource)
+	rolePowerRelRepo := role.NewRolePowerRelRepo(testDataSource)
+	userRoleRelRepo := role.NewUserRoleRelRepo(testDataSource)
+
+	builtIn, exist, err := roleRepo.GetRole(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.True(t, builtIn.BuiltIn)
+
+	curator := &entity.Role{Name: "Tag curator", Description: "manage tags"}
+	err = roleRepo.AddRole(context.TODO(), curator, []string{permission.TagEdit, permission.TagSynonym})
+	assert.NoError(t, err)
+	assert.Greater(t, curator.ID, 3)
+
+	got, exist, err := roleRepo.GetRoleByName(context.TODO(), "Tag curator")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, curator.ID, got.ID)
+	assert.False(t, got.BuiltIn)
+
+	powers, err := rolePowerRelRepo.GetRolePowerTypeList(context.TODO(), curator.ID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{permission.TagEdit, permission.TagSynonym}, powers)
+
+	curator.Name = "Tag editor"
+	err = roleRepo.UpdateRole(context.TODO(), curator, []string{permission.TagDelete})
+	assert.NoError(t, err)
+	powers, err = rolePowerRelRepo.GetRolePowerTypeList(context.TODO(), curator.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{permission.TagDelete}, powers)
+
+	err = userRoleRelRepo.SaveUserRoleRel(context.TODO(), "100", curator.ID)
+	assert.NoError(t, err)
+	err = roleRepo.DeleteRole(context.TODO(), curator.ID, 1)
+	assert.NoError(t, err)
+
+	_, exist, err = roleRepo.GetRole(context.TODO(), curator.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	rel, exist, err := userRoleRelRepo.GetUserRoleRel(context.TODO(), "100")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, 1, rel.RoleID)
+}
+
+func Test_rolePowerRelService_CustomRoleWithPower(t *testing.T) {
+	roleRepo := role.NewRoleRepo(testDataSource)
+	userRoleRelRepo := role.NewUserRoleRelRepo(testDataSource)
+	rolePowerRelService := roleService.NewRolePowerRelService(role.NewRolePowerRelRepo(testDataSource),
+		roleService.NewUserRoleRelService(userRoleRelRepo, nil))
+
+	moderator := &entity.Role{Name: "Community moderator", Description: "moderate content"}
+	err := roleRepo.AddRole(context.TODO(), moderator, []string{permission.ContentModerate})
+	assert.NoError(t, err)
+	defer func() {
+		_ = roleRepo.DeleteRole(context.TODO(), moderator.ID, roleService.RoleUserID)
+	}()
+	err = userRoleRelRepo.SaveUserRoleRel(context.TODO(), "101", moderator.ID)
+	assert.NoError(t, err)
+
+	has, err := rolePowerRelService.RoleHasPower(context.TODO(), moderator.ID, permission.ContentModerate)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = rolePowerRelService.RoleHasPower(context.TODO(), moderator.ID, permission.AdminAccess)
+	assert.NoError(t, err)
+	assert.False(t, has)
+	has, err = rolePowerRelService.UserHasPower(context.TODO(), "101", permission.ContentModerate)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	// the built-in admin and moderator and the custom role are all staff
+	roleIDs, err := rolePowerRelService.GetRoleIDListByPowers(context.TODO(), permission.AdminAccess, permission.ContentModerate)
+	assert.NoError(t, err)
+	assert.Subset(t, roleIDs, []int{roleService.RoleAdminID, roleService.RoleModeratorID, moderator.ID})
+	assert.NotContains(t, roleIDs, roleService.RoleUserID)
+}
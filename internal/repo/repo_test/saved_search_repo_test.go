@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/saved_search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_savedSearchRepo_CRUD(t *testing.T) {
+	ctx := context.TODO()
+	savedSearchRepo := saved_search.NewSavedSearchRepo(testDataSource)
+	userID := "10010000000000951"
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("user_id = ?", userID).Delete(&entity.SavedSearch{})
+	})
+
+	savedSearch := &entity.SavedSearch{UserID: userID, Name: "kafka", Query: "[kafka] isaccepted:no"}
+	require.NoError(t, savedSearchRepo.AddSavedSearch(ctx, savedSearch))
+	require.NotEmpty(t, savedSearch.ID)
+
+	savedSearch.Name = "unanswered kafka"
+	savedSearch.AlertEnabled = true
+	savedSearch.SetAlertChannels([]string{"inbox", "email"})
+	savedSearch.LastAlertAt = time.Now()
+	require.NoError(t, savedSearchRepo.UpdateSavedSearch(ctx, savedSearch))
+	got, exist, err := savedSearchRepo.GetSavedSearch(ctx, savedSearch.ID)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "unanswered kafka", got.Name)
+	assert.True(t, got.AlertEnabled)
+	assert.True(t, got.HasAlertChannel("email"))
+	assert.False(t, got.HasAlertChannel("plugin"))
+
+	require.NoError(t, savedSearchRepo.AddSavedSearch(ctx, &entity.SavedSearch{UserID: userID, Name: "go", Query: "[go]"}))
+	list, err := savedSearchRepo.GetUserSavedSearchList(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	count, err := savedSearchRepo.CountUserSavedSearch(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, savedSearchRepo.RemoveSavedSearch(ctx, savedSearch.ID))
+	_, exist, err = savedSearchRepo.GetSavedSearch(ctx, savedSearch.ID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_savedSearchRepo_Alert(t *testing.T) {
+	ctx := context.TODO()
+	savedSearchRepo := saved_search.NewSavedSearchRepo(testDataSource)
+	userID := "10010000000000952"
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("user_id = ?", userID).Delete(&entity.SavedSearch{})
+	})
+
+	alerted := &entity.SavedSearch{UserID: userID, Name: "alerted", Query: "kafka", AlertEnabled: true,
+		AlertChannels: "inbox", LastAlertAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, savedSearchRepo.AddSavedSearch(ctx, alerted))
+	require.NoError(t, savedSearchRepo.AddSavedSearch(ctx, &entity.SavedSearch{UserID: userID, Name: "silent", Query: "kafka"}))
+
+	list, err := savedSearchRepo.GetAlertSavedSearchList(ctx)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(list))
+	for _, item := range list {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []string{alerted.ID}, ids)
+
+	lastAlertAt := time.Now().Truncate(time.Second)
+	require.NoError(t, savedSearchRepo.UpdateLastAlertAt(ctx, alerted.ID, lastAlertAt))
+	got, _, err := savedSearchRepo.GetSavedSearch(ctx, alerted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, lastAlertAt.Unix(), got.LastAlertAt.Unix())
+}
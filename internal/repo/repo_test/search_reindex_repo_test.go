@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/search_sync"
+	"github.com/apache/incubator-answer/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_searchReindexRepo_Task(t *testing.T) {
+	ctx := context.TODO()
+	searchReindexRepo := search_sync.NewSearchReindexRepo(testDataSource)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("plugin_slug_name = ?", "reindex_test").Delete(&entity.SearchReindexTask{})
+	})
+
+	_, exist, err := searchReindexRepo.GetTask(ctx, "reindex_test")
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	task := &entity.SearchReindexTask{PluginSlugName: "reindex_test", Status: entity.SearchReindexStatusRunning,
+		ObjectType: "question", LastObjectID: "10010000000000001", Processed: 1}
+	require.NoError(t, searchReindexRepo.SaveTask(ctx, task))
+	task.ObjectType, task.LastObjectID, task.Processed = "answer", "10020000000000001", 2
+	require.NoError(t, searchReindexRepo.SaveTask(ctx, task))
+
+	got, exist, err := searchReindexRepo.GetTask(ctx, "reindex_test")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "answer", got.ObjectType)
+	assert.Equal(t, "10020000000000001", got.LastObjectID)
+	assert.Equal(t, int64(2), got.Processed)
+}
+
+func Test_searchReindexRepo_ClaimTask(t *testing.T) {
+	ctx := context.TODO()
+	searchReindexRepo := search_sync.NewSearchReindexRepo(testDataSource)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.Where("plugin_slug_name = ?", "reindex_claim_test").Delete(&entity.SearchReindexTask{})
+	})
+
+	task := &entity.SearchReindexTask{PluginSlugName: "reindex_claim_test", Status: entity.SearchReindexStatusRunning,
+		ObjectType: "question", LastObjectID: "0"}
+	claimed, err := searchReindexRepo.ClaimTask(ctx, task, nil)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	// the task of the plugin is inserted only once
+	claimed, err = searchReindexRepo.ClaimTask(ctx, &entity.SearchReindexTask{PluginSlugName: "reindex_claim_test",
+		Status: entity.SearchReindexStatusRunning, ObjectType: "question", LastObjectID: "0"}, nil)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	lastTask, exist, err := searchReindexRepo.GetTask(ctx, "reindex_claim_test")
+	require.NoError(t, err)
+	require.True(t, exist)
+	// the task is saved by the running reindexing after it is read
+	task.LastObjectID, task.Processed = "10010000000000001", 1
+	require.NoError(t, searchReindexRepo.SaveTask(ctx, task))
+	claimed, err = searchReindexRepo.ClaimTask(ctx, &entity.SearchReindexTask{PluginSlugName: "reindex_claim_test",
+		Status: entity.SearchReindexStatusRunning, ObjectType: "question", LastObjectID: "0"}, lastTask)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	lastTask, _, err = searchReindexRepo.GetTask(ctx, "reindex_claim_test")
+	require.NoError(t, err)
+	claimed, err = searchReindexRepo.ClaimTask(ctx, &entity.SearchReindexTask{PluginSlugName: "reindex_claim_test",
+		Status: entity.SearchReindexStatusRunning, ObjectType: "question", LastObjectID: "0"}, lastTask)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func Test_searchReindexRepo_GetContents(t *testing.T) {
+	ctx := context.TODO()
+	searchReindexRepo := search_sync.NewSearchReindexRepo(testDataSource)
+	questionID, answerID, orphanAnswerID := "10010000000000971", "10020000000000971", "10020000000000972"
+	_, err := testDataSource.DB.Insert(&entity.Question{ID: questionID, UserID: "1", Title: "reindex",
+		OriginalText: "reindex", ParsedText: "reindex", Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow})
+	require.NoError(t, err)
+	_, err = testDataSource.DB.Insert([]*entity.Answer{
+		{ID: answerID, QuestionID: questionID, UserID: "1", OriginalText: "a", ParsedText: "a",
+			Status: entity.AnswerStatusAvailable},
+		{ID: orphanAnswerID, QuestionID: "10010000000000972", UserID: "1", OriginalText: "b", ParsedText: "b",
+			Status: entity.AnswerStatusAvailable},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.ID(questionID).Delete(&entity.Question{})
+		_, _ = testDataSource.DB.In("id", answerID, orphanAnswerID).Delete(&entity.Answer{})
+	})
+
+	// the contents are paged by the last id
+	contents, err := searchReindexRepo.GetQuestionContents(ctx, "10010000000000970", 1)
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	assert.Equal(t, questionID, contents[0].ObjectID)
+	assert.Equal(t, "reindex", contents[0].Title)
+
+	contents, err = searchReindexRepo.GetAnswerContents(ctx, "10020000000000970", 10)
+	require.NoError(t, err)
+	require.Len(t, contents, 2)
+	assert.Equal(t, "reindex", contents[0].Title)
+	assert.Equal(t, plugin.SearchContentStatus(plugin.SearchContentStatusAvailable), contents[0].Status)
+	// the answer of the question that does not exist is deleted from the index
+	assert.Equal(t, orphanAnswerID, contents[1].ObjectID)
+	assert.Equal(t, plugin.SearchContentStatus(plugin.SearchContentStatusDeleted), contents[1].Status)
+}
@@ -0,0 +1,193 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/answer"
+	"github.com/apache/incubator-answer/internal/repo/question"
+	"github.com/apache/incubator-answer/internal/repo/search_common"
+	"github.com/apache/incubator-answer/internal/repo/site_info"
+	"github.com/apache/incubator-answer/internal/repo/tag"
+	"github.com/apache/incubator-answer/internal/repo/tag_common"
+	"github.com/apache/incubator-answer/internal/repo/unique"
+	"github.com/apache/incubator-answer/internal/repo/user"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	tagcommon "github.com/apache/incubator-answer/internal/service/tag_common"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/apache/incubator-answer/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_searchRepo_FullText(t *testing.T) {
+	var (
+		ctx             = context.TODO()
+		uniqueIDRepo    = unique.NewUniqueIDRepo(testDataSource)
+		questionRepo    = question.NewQuestionRepo(testDataSource, uniqueIDRepo)
+		answerRepo      = answer.NewAnswerRepo(testDataSource, uniqueIDRepo, nil, nil)
+		siteInfoService = siteinfo_common.NewSiteInfoCommonService(site_info.NewSiteInfo(testDataSource))
+		tagCommon       = tagcommon.NewTagCommonService(tag_common.NewTagCommonRepo(testDataSource, uniqueIDRepo),
+			tag.NewTagRelRepo(testDataSource, uniqueIDRepo), tag.NewTagRepo(testDataSource, uniqueIDRepo), nil, siteInfoService, nil)
+		userCommon = usercommon.NewUserCommon(user.NewUserRepo(testDataSource), nil, nil, nil, siteInfoService)
+		searchRepo = search_common.NewSearchRepo(testDataSource, uniqueIDRepo, userCommon, tagCommon)
+	)
+
+	q := &entity.Question{
+		UserID:       "1",
+		Title:        "How to configure quokka indexes",
+		OriginalText: "I want to tune the quokka storage engine",
+		ParsedText:   "I want to tune the quokka storage engine",
+		Status:       entity.QuestionStatusAvailable,
+		Show:         entity.QuestionShow,
+		RevisionID:   "0",
+	}
+	require.NoError(t, questionRepo.AddQuestion(ctx, q))
+	a := &entity.Answer{
+		QuestionID:   q.ID,
+		UserID:       "1",
+		OriginalText: "Rebuild the wombat cache before tuning quokka",
+		ParsedText:   "Rebuild the wombat cache before tuning quokka",
+		Status:       entity.AnswerStatusAvailable,
+		RevisionID:   "0",
+	}
+	require.NoError(t, answerRepo.AddAnswer(ctx, a))
+	t.Cleanup(func() {
+		_ = questionRepo.RemoveQuestion(ctx, q.ID)
+		_ = answerRepo.RemoveAnswer(ctx, a.ID)
+	})
+
+	resp, total, err := searchRepo.SearchQuestions(ctx, []string{"quokka"}, nil, false, -1, -1, 1, 20, "relevance")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, resp, 1)
+	assert.Equal(t, q.ID, resp[0].Object.ID)
+
+	resp, total, err = searchRepo.SearchAnswers(ctx, []string{"wombat"}, nil, false, "", 1, 20, "relevance")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, resp, 1)
+	assert.Equal(t, a.ID, resp[0].Object.ID)
+
+	_, total, err = searchRepo.SearchContents(ctx, []string{"quokka", "wombat"}, nil, "", -1, 1, 20, "relevance")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	// the tokenizer can not segment CJK words, they must be matched too
+	cjk := &entity.Question{
+		UserID:       "1",
+		Title:        "如何配置袋鼠索引",
+		OriginalText: "我想调整袋鼠存储引擎",
+		ParsedText:   "我想调整袋鼠存储引擎",
+		Status:       entity.QuestionStatusAvailable,
+		Show:         entity.QuestionShow,
+		RevisionID:   "0",
+	}
+	require.NoError(t, questionRepo.AddQuestion(ctx, cjk))
+	t.Cleanup(func() {
+		_ = questionRepo.RemoveQuestion(ctx, cjk.ID)
+	})
+	resp, total, err = searchRepo.SearchQuestions(ctx, []string{"袋鼠"}, nil, false, -1, -1, 1, 20, "relevance")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, resp, 1)
+	assert.Equal(t, cjk.ID, resp[0].Object.ID)
+
+	// the index must follow the update of question
+	q.Title = "How to configure platypus indexes"
+	require.NoError(t, questionRepo.UpdateQuestion(ctx, q, []string{"title"}))
+	_, total, err = searchRepo.SearchQuestions(ctx, []string{"platypus"}, nil, false, -1, -1, 1, 20, "newest")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func Test_searchRepo_SearchFacets(t *testing.T) {
+	var (
+		ctx             = context.TODO()
+		uniqueIDRepo    = unique.NewUniqueIDRepo(testDataSource)
+		questionRepo    = question.NewQuestionRepo(testDataSource, uniqueIDRepo)
+		answerRepo      = answer.NewAnswerRepo(testDataSource, uniqueIDRepo, nil, nil)
+		tagRelRepo      = tag.NewTagRelRepo(testDataSource, uniqueIDRepo)
+		siteInfoService = siteinfo_common.NewSiteInfoCommonService(site_info.NewSiteInfo(testDataSource))
+		tagCommon       = tagcommon.NewTagCommonService(tag_common.NewTagCommonRepo(testDataSource, uniqueIDRepo),
+			tagRelRepo, tag.NewTagRepo(testDataSource, uniqueIDRepo), nil, siteInfoService, nil)
+		userCommon = usercommon.NewUserCommon(user.NewUserRepo(testDataSource), nil, nil, nil, siteInfoService)
+		searchRepo = search_common.NewSearchRepo(testDataSource, uniqueIDRepo, userCommon, tagCommon)
+	)
+
+	q := &entity.Question{
+		UserID:           "1",
+		Title:            "How to feed an echidna",
+		OriginalText:     "My echidna does not eat ants",
+		ParsedText:       "My echidna does not eat ants",
+		Status:           entity.QuestionStatusAvailable,
+		Show:             entity.QuestionShow,
+		AcceptedAnswerID: "0",
+		RevisionID:       "0",
+		CreatedAt:        time.Now(),
+	}
+	require.NoError(t, questionRepo.AddQuestion(ctx, q))
+	a := &entity.Answer{
+		QuestionID:   q.ID,
+		UserID:       "2",
+		OriginalText: "Give the echidna some termites",
+		ParsedText:   "Give the echidna some termites",
+		Status:       entity.AnswerStatusAvailable,
+		Accepted:     schema.AnswerAcceptedEnable,
+		RevisionID:   "0",
+	}
+	require.NoError(t, answerRepo.AddAnswer(ctx, a))
+	require.NoError(t, tagRelRepo.AddTagRelList(ctx, []*entity.TagRel{
+		{ObjectID: q.ID, TagID: "10999", Status: entity.TagRelStatusAvailable},
+	}))
+	t.Cleanup(func() {
+		_ = questionRepo.RemoveQuestion(ctx, q.ID)
+		_ = answerRepo.RemoveAnswer(ctx, a.ID)
+		_ = tagRelRepo.RemoveTagRelListByObjectID(ctx, q.ID)
+	})
+
+	facets, err := searchRepo.SearchFacets(ctx, &schema.SearchCondition{
+		Words: []string{"echidna"}, VoteAmount: -1, Views: -1, AnswerAmount: -1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), facets.Questions)
+	assert.Equal(t, int64(1), facets.Answers)
+	assert.Equal(t, int64(1), facets.AcceptedAnswers)
+	assert.Equal(t, int64(1), facets.NotAcceptedQuestions)
+	// the answer is counted by the tags of its question
+	assert.Equal(t, []plugin.SearchFacetCount{{Value: "10999", Count: 2}}, facets.Tags)
+	assert.ElementsMatch(t, []plugin.SearchFacetCount{{Value: "1", Count: 1}, {Value: "2", Count: 1}}, facets.Authors)
+	require.Len(t, facets.Dates, 4)
+	for _, date := range facets.Dates {
+		assert.Equal(t, int64(2), date.Count, date.Value)
+	}
+
+	facets, err = searchRepo.SearchFacets(ctx, &schema.SearchCondition{
+		TargetType: constant.AnswerObjectType, Words: []string{"echidna"}, Accepted: true, VoteAmount: -1, Views: -1, AnswerAmount: -1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), facets.Questions)
+	assert.Equal(t, int64(1), facets.Answers)
+	assert.Equal(t, []plugin.SearchFacetCount{{Value: "2", Count: 1}}, facets.Authors)
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/apache/incubator-answer/internal/repo/unique"
 	"github.com/apache/incubator-answer/pkg/converter"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -197,3 +198,112 @@ func Test_tagRepo_UpdateTagSynonym(t *testing.T) {
 	assert.True(t, exist)
 	assert.Equal(t, testTagList[0].ID, fmt.Sprintf("%d", gotTag.MainTagID))
 }
+
+func Test_tagRepo_MergeTag(t *testing.T) {
+	ctx := context.TODO()
+	tagRepo := tag.NewTagRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+	const followActivityType = 9999
+
+	sourceTag := &entity.Tag{ID: "10030000000000901", SlugName: "merge-source", DisplayName: "merge-source",
+		FollowCount: 2, QuestionCount: 2, Status: entity.TagStatusAvailable}
+	targetTag := &entity.Tag{ID: "10030000000000902", SlugName: "merge-target", DisplayName: "merge-target",
+		FollowCount: 1, QuestionCount: 0, Status: entity.TagStatusAvailable}
+	synonymTag := &entity.Tag{ID: "10030000000000903", SlugName: "merge-synonym", DisplayName: "merge-synonym",
+		MainTagID: 10030000000000901, MainTagSlugName: "merge-source", Status: entity.TagStatusAvailable}
+	_, err := testDataSource.DB.Insert(sourceTag, targetTag, synonymTag)
+	require.NoError(t, err)
+	rels := []*entity.TagRel{
+		{ObjectID: "10010000000000901", TagID: sourceTag.ID, Status: entity.TagRelStatusAvailable},
+		{ObjectID: "10010000000000902", TagID: sourceTag.ID, Status: entity.TagRelStatusAvailable},
+		{ObjectID: "10010000000000902", TagID: targetTag.ID, Status: entity.TagRelStatusDeleted},
+	}
+	_, err = testDataSource.DB.Insert(rels)
+	require.NoError(t, err)
+	follows := []*entity.Activity{
+		{UserID: "901", ObjectID: sourceTag.ID, OriginalObjectID: sourceTag.ID, ActivityType: followActivityType},
+		{UserID: "902", ObjectID: sourceTag.ID, OriginalObjectID: sourceTag.ID, ActivityType: followActivityType},
+		{UserID: "902", ObjectID: targetTag.ID, OriginalObjectID: targetTag.ID, ActivityType: followActivityType},
+	}
+	_, err = testDataSource.DB.Insert(follows)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.In("id", sourceTag.ID, targetTag.ID, synonymTag.ID).Delete(&entity.Tag{})
+		_, _ = testDataSource.DB.In("tag_id", sourceTag.ID, targetTag.ID).Delete(&entity.TagRel{})
+		_, _ = testDataSource.DB.Where("activity_type = ?", followActivityType).Delete(&entity.Activity{})
+	})
+
+	questionIDs, err := tagRepo.MergeTag(ctx, sourceTag, targetTag, followActivityType, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"10010000000000901", "10010000000000902"}, questionIDs)
+
+	// all questions are on target tag and the deleted relation is restored
+	targetRels := make([]*entity.TagRel, 0)
+	require.NoError(t, testDataSource.DB.Where("tag_id = ?", targetTag.ID).Asc("object_id").Find(&targetRels))
+	require.Len(t, targetRels, 2)
+	for _, rel := range targetRels {
+		assert.Equal(t, entity.TagRelStatusAvailable, rel.Status)
+	}
+	count, err := testDataSource.DB.Where("tag_id = ?", sourceTag.ID).Count(&entity.TagRel{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// the follower of both tags is counted once
+	got, exist, err := tagRepo.MustGetTagByNameOrID(ctx, targetTag.ID, "")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, 2, got.FollowCount)
+
+	// source tag is kept as a synonym and its synonyms are moved to target tag
+	got, _, err = tagRepo.MustGetTagByNameOrID(ctx, sourceTag.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10030000000000902), got.MainTagID)
+	assert.Equal(t, 0, got.FollowCount)
+	assert.Equal(t, 0, got.QuestionCount)
+	assert.Equal(t, entity.TagStatusAvailable, got.Status)
+	got, _, err = tagRepo.MustGetTagByNameOrID(ctx, synonymTag.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, "merge-target", got.MainTagSlugName)
+}
+
+func Test_tagRepo_TagParent(t *testing.T) {
+	ctx := context.TODO()
+	tagRepo := tag.NewTagRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+
+	tags := []*entity.Tag{
+		{ID: "10030000000000911", SlugName: "tree-database", DisplayName: "database", Status: entity.TagStatusAvailable},
+		{ID: "10030000000000912", SlugName: "tree-postgres", DisplayName: "postgres", Status: entity.TagStatusAvailable},
+		{ID: "10030000000000913", SlugName: "tree-mysql", DisplayName: "mysql", Status: entity.TagStatusAvailable},
+	}
+	_, err := testDataSource.DB.Insert(tags)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = testDataSource.DB.In("id", tags[0].ID, tags[1].ID, tags[2].ID).Delete(&entity.Tag{})
+	})
+
+	require.NoError(t, tagRepo.UpdateTagParent(ctx, tags[1].ID, converter.StringToInt64(tags[0].ID)))
+	require.NoError(t, tagRepo.UpdateTagParent(ctx, tags[2].ID, converter.StringToInt64(tags[0].ID)))
+	subTagList, err := tagRepo.GetTagListByParentIDs(ctx, []string{tags[0].ID})
+	require.NoError(t, err)
+	require.Len(t, subTagList, 2)
+	// order by slug name
+	assert.Equal(t, tags[2].ID, subTagList[0].ID)
+	assert.Equal(t, tags[1].ID, subTagList[1].ID)
+
+	// the sub tags are paged, and counted for each parent tag
+	pageList, total, err := tagRepo.GetSubTagPage(ctx, tags[0].ID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, pageList, 1)
+	assert.Equal(t, tags[2].ID, pageList[0].ID)
+	counts, err := tagRepo.CountSubTags(ctx, []string{tags[0].ID, tags[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, counts[tags[0].ID])
+	assert.Equal(t, 0, counts[tags[1].ID])
+
+	// move to root
+	require.NoError(t, tagRepo.UpdateTagParent(ctx, tags[2].ID, 0))
+	subTagList, err = tagRepo.GetTagListByParentIDs(ctx, []string{tags[0].ID})
+	require.NoError(t, err)
+	require.Len(t, subTagList, 1)
+	assert.Equal(t, tags[1].ID, subTagList[0].ID)
+}
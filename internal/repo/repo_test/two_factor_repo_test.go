@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/two_factor"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_twoFactorRepo_UserTwoFactor(t *testing.T) {
+	ctx := context.TODO()
+	twoFactorRepo := two_factor.NewTwoFactorRepo(testDataSource)
+
+	tf := &entity.UserTwoFactor{UserID: "1", Secret: "SECRET1", Status: entity.UserTwoFactorStatusPending}
+	tf.SetRecoveryCodes(nil)
+	require.NoError(t, twoFactorRepo.SaveUserTwoFactor(ctx, tf))
+	t.Cleanup(func() {
+		_ = twoFactorRepo.RemoveUserTwoFactor(ctx, "1")
+	})
+
+	// save again will replace the pending secret
+	tf = &entity.UserTwoFactor{UserID: "1", Secret: "SECRET2", Status: entity.UserTwoFactorStatusPending}
+	tf.SetRecoveryCodes(nil)
+	require.NoError(t, twoFactorRepo.SaveUserTwoFactor(ctx, tf))
+	got, exist, err := twoFactorRepo.GetUserTwoFactor(ctx, "1")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "SECRET2", got.Secret)
+	assert.False(t, got.IsEnabled())
+
+	got.Status = entity.UserTwoFactorStatusEnabled
+	got.LastUsedStep = 100
+	got.SetRecoveryCodes([]string{"a", "b"})
+	require.NoError(t, twoFactorRepo.UpdateUserTwoFactor(ctx, got, "status", "last_used_step", "recovery_codes"))
+
+	// the same or an earlier time step can't be used again
+	ok, err := twoFactorRepo.UseTimeStep(ctx, "1", 100)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = twoFactorRepo.UseTimeStep(ctx, "1", 101)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// the recovery codes are replaced only if they are not changed
+	ok, err = twoFactorRepo.UpdateRecoveryCodes(ctx, "1", got.RecoveryCodes, `["b"]`)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = twoFactorRepo.UpdateRecoveryCodes(ctx, "1", got.RecoveryCodes, `[]`)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	got, _, err = twoFactorRepo.GetUserTwoFactor(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, got.IsEnabled())
+	assert.Equal(t, []string{"b"}, got.GetRecoveryCodes())
+	assert.Equal(t, int64(101), got.LastUsedStep)
+
+	require.NoError(t, twoFactorRepo.RemoveUserTwoFactor(ctx, "1"))
+	_, exist, err = twoFactorRepo.GetUserTwoFactor(ctx, "1")
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_twoFactorRepo_Challenge(t *testing.T) {
+	ctx := context.TODO()
+	twoFactorRepo := two_factor.NewTwoFactorRepo(testDataSource)
+
+	challenge := &schema.TwoFactorChallenge{UserID: "1", Enroll: true}
+	require.NoError(t, twoFactorRepo.SetChallenge(ctx, "token", challenge))
+
+	got, exist, err := twoFactorRepo.GetChallenge(ctx, "token")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, challenge, got)
+
+	// the attempts are increased atomically by concurrent requests
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := twoFactorRepo.IncreaseChallengeAttempts(ctx, "token")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	attempts, err := twoFactorRepo.IncreaseChallengeAttempts(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), attempts)
+
+	require.NoError(t, twoFactorRepo.RemoveChallenge(ctx, "token"))
+	_, exist, err = twoFactorRepo.GetChallenge(ctx, "token")
+	require.NoError(t, err)
+	assert.False(t, exist)
+	// the attempts are removed with the pending login
+	attempts, err = twoFactorRepo.IncreaseChallengeAttempts(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), attempts)
+	require.NoError(t, twoFactorRepo.RemoveChallenge(ctx, "token"))
+}
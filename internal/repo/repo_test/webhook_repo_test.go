@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/repo/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_webhookRepo_GetActiveWebhookList(t *testing.T) {
+	webhookRepo := webhook.NewWebhookRepo(testDataSource)
+	hook := &entity.Webhook{
+		Name:   "test",
+		URL:    "http://127.0.0.1/webhook",
+		Secret: "secret",
+		Status: entity.WebhookStatusActive,
+	}
+	hook.SetEvents([]string{"question.create", "answer.create"})
+	err := webhookRepo.AddWebhook(context.TODO(), hook)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hook.ID)
+
+	webhooks, err := webhookRepo.GetActiveWebhookList(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+	assert.True(t, webhooks[0].IsSubscribed("answer.create"))
+	assert.False(t, webhooks[0].IsSubscribed("comment.create"))
+
+	hook.Status = entity.WebhookStatusInactive
+	err = webhookRepo.UpdateWebhook(context.TODO(), hook)
+	assert.NoError(t, err)
+	webhooks, err = webhookRepo.GetActiveWebhookList(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 0)
+
+	err = webhookRepo.DeleteWebhook(context.TODO(), hook.ID)
+	assert.NoError(t, err)
+}
+
+func Test_webhookDeliveryRepo_GetDeliveryPage(t *testing.T) {
+	webhookDeliveryRepo := webhook.NewWebhookDeliveryRepo(testDataSource)
+	delivery := &entity.WebhookDelivery{
+		WebhookID: "1",
+		EventType: "question.create",
+		Payload:   `{"event":"question.create"}`,
+		Status:    entity.WebhookDeliveryStatusPending,
+	}
+	err := webhookDeliveryRepo.AddDelivery(context.TODO(), delivery)
+	assert.NoError(t, err)
+
+	delivery.Status = entity.WebhookDeliveryStatusSuccess
+	delivery.Attempts = 1
+	delivery.ResponseCode = 200
+	err = webhookDeliveryRepo.UpdateDelivery(context.TODO(), delivery)
+	assert.NoError(t, err)
+
+	deliveries, total, err := webhookDeliveryRepo.GetDeliveryPage(context.TODO(), 1, 10, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, entity.WebhookDeliveryStatusSuccess, deliveries[0].Status)
+	assert.Equal(t, 200, deliveries[0].ResponseCode)
+}
+
+func Test_webhookDeliveryRepo_GetEventWebhookIDs(t *testing.T) {
+	webhookDeliveryRepo := webhook.NewWebhookDeliveryRepo(testDataSource)
+	for _, webhookID := range []string{"2", "3"} {
+		err := webhookDeliveryRepo.AddDelivery(context.TODO(), &entity.WebhookDelivery{
+			EventID:   "100",
+			WebhookID: webhookID,
+			EventType: "question.create",
+			Payload:   `{"event":"question.create"}`,
+			Status:    entity.WebhookDeliveryStatusPending,
+		})
+		assert.NoError(t, err)
+	}
+
+	webhookIDs, err := webhookDeliveryRepo.GetEventWebhookIDs(context.TODO(), "100")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"2", "3"}, webhookIDs)
+
+	webhookIDs, err = webhookDeliveryRepo.GetEventWebhookIDs(context.TODO(), "101")
+	assert.NoError(t, err)
+	assert.Len(t, webhookIDs, 0)
+}
@@ -51,3 +51,14 @@ func (rr *rolePowerRelRepo) GetRolePowerTypeList(ctx context.Context, roleID int
 	}
 	return
 }
+
+// GetRoleIDListByPowerTypes get the roles that have any of the power types
+func (rr *rolePowerRelRepo) GetRoleIDListByPowerTypes(ctx context.Context, powerTypes []string) (roleIDs []int, err error) {
+	roleIDs = make([]int, 0)
+	err = rr.data.DB.Context(ctx).Table("role_power_rel").Distinct("role_id").
+		In("power_type", powerTypes).Find(&roleIDs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
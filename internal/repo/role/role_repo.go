@@ -27,6 +27,7 @@ import (
 	"github.com/apache/incubator-answer/internal/entity"
 	service "github.com/apache/incubator-answer/internal/service/role"
 	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
 )
 
 // roleRepo role repository
@@ -63,3 +64,95 @@ func (rr *roleRepo) GetRoleAllMapping(ctx context.Context) (roleMapping map[int]
 	}
 	return roleMapping, nil
 }
+
+// GetRole get role one
+func (rr *roleRepo) GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error) {
+	role = &entity.Role{}
+	exist, err = rr.data.DB.Context(ctx).ID(roleID).Get(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetRoleByName get role by name
+func (rr *roleRepo) GetRoleByName(ctx context.Context, name string) (role *entity.Role, exist bool, err error) {
+	role = &entity.Role{}
+	exist, err = rr.data.DB.Context(ctx).Where("name = ?", name).Get(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddRole add role with its powers.
+// The built-in roles are inserted with fixed ids, which does not move the sequence in some databases,
+// so the id of the new role is set explicitly.
+func (rr *roleRepo) AddRole(ctx context.Context, role *entity.Role, powerTypes []string) (err error) {
+	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		maxRole := &entity.Role{}
+		if _, err = session.Desc("id").Limit(1).Get(maxRole); err != nil {
+			return nil, err
+		}
+		role.ID = maxRole.ID + 1
+		if _, err = session.Insert(role); err != nil {
+			return nil, err
+		}
+		return nil, insertRolePowers(session, role.ID, powerTypes)
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateRole update role and replace all its powers
+func (rr *roleRepo) UpdateRole(ctx context.Context, role *entity.Role, powerTypes []string) (err error) {
+	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		if _, err = session.ID(role.ID).Cols("name", "description").Update(role); err != nil {
+			return nil, err
+		}
+		if _, err = session.Where("role_id = ?", role.ID).Delete(&entity.RolePowerRel{}); err != nil {
+			return nil, err
+		}
+		return nil, insertRolePowers(session, role.ID, powerTypes)
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// DeleteRole delete role and its powers, the users of the role will be moved to the default role
+func (rr *roleRepo) DeleteRole(ctx context.Context, roleID, defaultRoleID int) (err error) {
+	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		_, err = session.Where("role_id = ?", roleID).Cols("role_id").
+			Update(&entity.UserRoleRel{RoleID: defaultRoleID})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = session.Where("role_id = ?", roleID).Delete(&entity.RolePowerRel{}); err != nil {
+			return nil, err
+		}
+		return session.ID(roleID).Delete(&entity.Role{})
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func insertRolePowers(session *xorm.Session, roleID int, powerTypes []string) (err error) {
+	if len(powerTypes) == 0 {
+		return nil
+	}
+	rels := make([]*entity.RolePowerRel, 0, len(powerTypes))
+	for _, powerType := range powerTypes {
+		rels = append(rels, &entity.RolePowerRel{RoleID: roleID, PowerType: powerType})
+	}
+	_, err = session.Insert(rels)
+	return err
+}
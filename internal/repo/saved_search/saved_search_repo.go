@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package saved_search
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/saved_search"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// savedSearchRepo saved search repository
+type savedSearchRepo struct {
+	data *data.Data
+}
+
+// NewSavedSearchRepo new repository
+func NewSavedSearchRepo(data *data.Data) saved_search.SavedSearchRepo {
+	return &savedSearchRepo{
+		data: data,
+	}
+}
+
+// AddSavedSearch add saved search
+func (sr *savedSearchRepo) AddSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error) {
+	_, err = sr.data.DB.Context(ctx).Insert(savedSearch)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateSavedSearch update the query and alert settings of saved search
+func (sr *savedSearchRepo) UpdateSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error) {
+	_, err = sr.data.DB.Context(ctx).ID(savedSearch.ID).
+		Cols("name", "query", "include_sub_tags", "alert_enabled", "alert_channels", "last_alert_at").
+		Update(savedSearch)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetSavedSearch get saved search by id
+func (sr *savedSearchRepo) GetSavedSearch(ctx context.Context, id string) (
+	savedSearch *entity.SavedSearch, exist bool, err error) {
+	savedSearch = &entity.SavedSearch{}
+	exist, err = sr.data.DB.Context(ctx).ID(id).Get(savedSearch)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserSavedSearchList get all saved searches of user, the latest created first
+func (sr *savedSearchRepo) GetUserSavedSearchList(ctx context.Context, userID string) (
+	savedSearches []*entity.SavedSearch, err error) {
+	savedSearches = make([]*entity.SavedSearch, 0)
+	err = sr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).Desc("id").Find(&savedSearches)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountUserSavedSearch count the saved searches of user
+func (sr *savedSearchRepo) CountUserSavedSearch(ctx context.Context, userID string) (count int64, err error) {
+	count, err = sr.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).Count(&entity.SavedSearch{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveSavedSearch remove saved search
+func (sr *savedSearchRepo) RemoveSavedSearch(ctx context.Context, id string) (err error) {
+	_, err = sr.data.DB.Context(ctx).ID(id).Delete(&entity.SavedSearch{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetAlertSavedSearchList get the saved searches whose alert is enabled
+func (sr *savedSearchRepo) GetAlertSavedSearchList(ctx context.Context) (savedSearches []*entity.SavedSearch, err error) {
+	savedSearches = make([]*entity.SavedSearch, 0)
+	err = sr.data.DB.Context(ctx).Where(builder.Eq{"alert_enabled": true}).Asc("id").Find(&savedSearches)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateLastAlertAt update the time before which the new contents have been evaluated
+func (sr *savedSearchRepo) UpdateLastAlertAt(ctx context.Context, id string, lastAlertAt time.Time) (err error) {
+	_, err = sr.data.DB.Context(ctx).ID(id).NoAutoTime().Cols("last_alert_at").
+		Update(&entity.SavedSearch{LastAlertAt: lastAlertAt})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/segmentfault/pacman/log"
+	"xorm.io/builder"
+	"xorm.io/xorm/schemas"
+)
+
+const (
+	fullTextTargetQuestion = "question"
+	fullTextTargetAnswer   = "answer"
+)
+
+// fullTextMatch the condition and relevance field of the search words
+type fullTextMatch struct {
+	cond          builder.Cond
+	condArgs      []interface{}
+	relevance     string
+	relevanceArgs []interface{}
+}
+
+// newFullTextMatch build the full-text match with the native index of database.
+// It will return nil if there are no words. For the database that does not support, the LIKE is used.
+// The postgres and sqlite tokenizers can not segment CJK words, so the LIKE is used for them too.
+func (sr *searchRepo) newFullTextMatch(target string, words []string) *fullTextMatch {
+	if len(words) == 0 {
+		return nil
+	}
+	switch sr.data.DB.Dialect().URI().DBType {
+	case schemas.MYSQL:
+		if sr.mysqlFullTextEnabled() {
+			return newMySQLFullTextMatch(target, words)
+		}
+	case schemas.POSTGRES:
+		if !hasCJKWord(words) {
+			return newPostgresFullTextMatch(target, words)
+		}
+	case schemas.SQLITE:
+		if !hasCJKWord(words) {
+			return newSQLiteFullTextMatch(target, words)
+		}
+	}
+	return newLikeMatch(target, words)
+}
+
+// checkMySQLFullTextIndex check the full-text index is created, it is skipped when the ngram parser is not supported
+func (sr *searchRepo) checkMySQLFullTextIndex() bool {
+	var count int64
+	_, err := sr.data.DB.Context(context.Background()).SQL(`SELECT COUNT(*) FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND table_name = 'answer' AND index_name = 'ft_answer'`).Get(&count)
+	if err != nil {
+		log.Errorf("check full-text index failed: %v", err)
+		return false
+	}
+	return count > 0
+}
+
+// hasCJKWord whether any of the words contains CJK characters
+func hasCJKWord(words []string) bool {
+	for _, word := range words {
+		for _, r := range word {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newMySQLFullTextMatch every word is a phrase in boolean mode, the row matches any of them
+func newMySQLFullTextMatch(target string, words []string) *fullTextMatch {
+	columns := "`question`.`title`, `question`.`original_text`"
+	if target == fullTextTargetAnswer {
+		columns = "`answer`.`original_text`"
+	}
+	phrases := make([]string, 0, len(words))
+	for _, word := range words {
+		phrases = append(phrases, `"`+strings.ReplaceAll(word, `"`, "")+`"`)
+	}
+	query := strings.Join(phrases, " ")
+	match := fmt.Sprintf("MATCH(%s) AGAINST(? IN BOOLEAN MODE)", columns)
+	return &fullTextMatch{
+		cond:          builder.Expr(match, query),
+		condArgs:      []interface{}{query},
+		relevance:     match + " as relevance",
+		relevanceArgs: []interface{}{query},
+	}
+}
+
+// newPostgresFullTextMatch the tsvector expression must be the same as the GIN index
+func newPostgresFullTextMatch(target string, words []string) *fullTextMatch {
+	vector := "to_tsvector('simple', `question`.`title` || ' ' || `question`.`original_text`)"
+	if target == fullTextTargetAnswer {
+		vector = "to_tsvector('simple', `answer`.`original_text`)"
+	}
+	queries := make([]string, 0, len(words))
+	args := make([]interface{}, 0, len(words))
+	for _, word := range words {
+		queries = append(queries, "plainto_tsquery('simple', ?)")
+		args = append(args, word)
+	}
+	query := "(" + strings.Join(queries, " || ") + ")"
+	return &fullTextMatch{
+		cond:          builder.Expr(vector+" @@ "+query, args...),
+		condArgs:      args,
+		relevance:     "ts_rank(" + vector + ", " + query + ") as relevance",
+		relevanceArgs: args,
+	}
+}
+
+// newSQLiteFullTextMatch the FTS5 table use the id of question or answer as rowid
+func newSQLiteFullTextMatch(target string, words []string) *fullTextMatch {
+	table, idField := "question_fts", "`question`.`id`"
+	if target == fullTextTargetAnswer {
+		table, idField = "answer_fts", "`answer`.`id`"
+	}
+	phrases := make([]string, 0, len(words))
+	for _, word := range words {
+		phrases = append(phrases, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	query := strings.Join(phrases, " OR ")
+	return &fullTextMatch{
+		cond: builder.Expr(fmt.Sprintf("%s IN (SELECT rowid FROM %s WHERE %s MATCH ?)",
+			idField, table, table), query),
+		condArgs: []interface{}{query},
+		relevance: fmt.Sprintf("(SELECT -bm25(%s) FROM %s WHERE %s MATCH ? AND rowid = %s) as relevance",
+			table, table, table, idField),
+		relevanceArgs: []interface{}{query},
+	}
+}
+
+// newLikeMatch match the words with LIKE and count the matched words as relevance
+func newLikeMatch(target string, words []string) *fullTextMatch {
+	fields := []string{"`question`.`title`", "`question`.`original_text`"}
+	if target == fullTextTargetAnswer {
+		fields = []string{"`answer`.`original_text`"}
+	}
+	m := &fullTextMatch{cond: builder.NewCond()}
+	for _, word := range words {
+		for _, field := range fields {
+			m.cond = m.cond.Or(builder.Like{field, word})
+			m.condArgs = append(m.condArgs, "%"+word+"%")
+		}
+	}
+	relevanceFields, relevanceArgs := addRelevanceField(fields, words, nil)
+	m.relevance, m.relevanceArgs = relevanceFields[0], relevanceArgs
+	return m
+}
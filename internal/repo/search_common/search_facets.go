@@ -0,0 +1,233 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/pkg/converter"
+	"github.com/apache/incubator-answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+var (
+	facetQFields = []string{
+		"`question`.`id` as `id`",
+		"`question`.`id` as `question_id`",
+		"`question`.`user_id` as `user_id`",
+		"`question`.`created_at` as `created_at`",
+		"'question' as `object_type`",
+		"CASE WHEN `question`.`accepted_answer_id` > 0 THEN 2 ELSE 0 END as `accepted`",
+	}
+	facetAFields = []string{
+		"`answer`.`id` as `id`",
+		"`answer`.`question_id` as `question_id`",
+		"`answer`.`user_id` as `user_id`",
+		"`answer`.`created_at` as `created_at`",
+		"'answer' as `object_type`",
+		"`answer`.`adopted` as `accepted`",
+	}
+	facetDates = []struct {
+		value    string
+		duration time.Duration
+	}{
+		{plugin.SearchFacetDatePastDay, 24 * time.Hour},
+		{plugin.SearchFacetDatePastWeek, 7 * 24 * time.Hour},
+		{plugin.SearchFacetDatePastMonth, 30 * 24 * time.Hour},
+		{plugin.SearchFacetDatePastYear, 365 * 24 * time.Hour},
+	}
+)
+
+// SearchFacets count all contents matched by the search condition by facets
+func (sr *searchRepo) SearchFacets(ctx context.Context, cond *schema.SearchCondition) (facets *plugin.SearchFacets, err error) {
+	sql, args, err := sr.buildFacetSQL(cond)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+
+	facets = &plugin.SearchFacets{}
+	if err = sr.countFacetSummary(ctx, sql, args, facets); err != nil {
+		return nil, err
+	}
+
+	tagSQL := fmt.Sprintf("SELECT `tr`.`tag_id` as `facet_value`, COUNT(*) as `facet_count` FROM (%s) f "+
+		"INNER JOIN `tag_rel` `tr` ON `tr`.`object_id` = f.`question_id` WHERE `tr`.`status` = ? "+
+		"GROUP BY `tr`.`tag_id` ORDER BY `facet_count` DESC, `facet_value` LIMIT %d", sql, plugin.SearchFacetTopSize)
+	facets.Tags, err = sr.countFacetTop(ctx, tagSQL, append(append([]interface{}{}, args...), entity.TagRelStatusAvailable))
+	if err != nil {
+		return nil, err
+	}
+
+	authorSQL := fmt.Sprintf("SELECT f.`user_id` as `facet_value`, COUNT(*) as `facet_count` FROM (%s) f "+
+		"GROUP BY f.`user_id` ORDER BY `facet_count` DESC, `facet_value` LIMIT %d", sql, plugin.SearchFacetTopSize)
+	facets.Authors, err = sr.countFacetTop(ctx, authorSQL, args)
+	if err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// buildFacetSQL build the sql of all matched contents, it uses the same filters as the search of the condition
+func (sr *searchRepo) buildFacetSQL(cond *schema.SearchCondition) (sql string, args []interface{}, err error) {
+	words := filterWords(cond.Words)
+	switch {
+	case cond.SearchQuestion():
+		b := sr.newFacetQuestionBuilder(words, cond.Tags)
+		if cond.NotAccepted {
+			b.And(builder.Eq{"`question`.`accepted_answer_id`": 0})
+		}
+		if cond.Views > -1 {
+			b.And(builder.Gte{"`question`.`view_count`": cond.Views})
+		}
+		if cond.AnswerAmount == 0 {
+			b.And(builder.Eq{"`question`.`answer_count`": 0})
+		} else if cond.AnswerAmount > 0 {
+			b.And(builder.Gte{"`question`.`answer_count`": cond.AnswerAmount})
+		}
+		return b.ToSQL()
+	case cond.SearchAnswer():
+		b := sr.newFacetAnswerBuilder(words, cond.Tags)
+		if cond.Accepted {
+			b.And(builder.Eq{"`answer`.`adopted`": schema.AnswerAcceptedEnable})
+		}
+		if cond.QuestionID != "" {
+			b.And(builder.Eq{"`answer`.`question_id`": cond.QuestionID})
+		}
+		return b.ToSQL()
+	}
+
+	qb := sr.newFacetQuestionBuilder(words, cond.Tags)
+	ab := sr.newFacetAnswerBuilder(words, cond.Tags)
+	if cond.UserID != "" {
+		qb.And(builder.Eq{"`question`.`user_id`": cond.UserID})
+		ab.And(builder.Eq{"`answer`.`user_id`": cond.UserID})
+	}
+	if cond.VoteAmount == 0 {
+		qb.And(builder.Eq{"`question`.`vote_count`": 0})
+		ab.And(builder.Eq{"`answer`.`vote_count`": 0})
+	} else if cond.VoteAmount > 0 {
+		qb.And(builder.Gte{"`question`.`vote_count`": cond.VoteAmount})
+		ab.And(builder.Gte{"`answer`.`vote_count`": cond.VoteAmount})
+	}
+	qSQL, qArgs, err := qb.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+	aSQL, aArgs, err := ab.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s UNION ALL %s", qSQL, aSQL), append(qArgs, aArgs...), nil
+}
+
+func (sr *searchRepo) newFacetQuestionBuilder(words []string, tagIDs [][]string) *builder.Builder {
+	b := builder.MySQL().Select(facetQFields...).From("`question`").
+		Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
+		And(builder.Eq{"`question`.`show`": entity.QuestionShow})
+	if match := sr.newFullTextMatch(fullTextTargetQuestion, words); match != nil {
+		b.And(match.cond)
+	}
+	for ti, tagID := range tagIDs {
+		ast := "tag_rel" + strconv.Itoa(ti)
+		b.Join("INNER", "tag_rel as "+ast, "`question`.`id` = "+ast+".object_id").
+			And(builder.Eq{ast + ".status": entity.TagRelStatusAvailable}).
+			And(builder.In(ast+".tag_id", tagID))
+	}
+	return b
+}
+
+func (sr *searchRepo) newFacetAnswerBuilder(words []string, tagIDs [][]string) *builder.Builder {
+	b := builder.MySQL().Select(facetAFields...).From("`answer`").
+		LeftJoin("`question`", "`question`.`id` = `answer`.`question_id`").
+		Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
+		And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted}).
+		And(builder.Eq{"`question`.`show`": entity.QuestionShow})
+	if match := sr.newFullTextMatch(fullTextTargetAnswer, words); match != nil {
+		b.And(match.cond)
+	}
+	for ti, tagID := range tagIDs {
+		ast := "tag_rel" + strconv.Itoa(ti)
+		b.Join("INNER", "tag_rel as "+ast, "`answer`.`question_id` = "+ast+".object_id").
+			And(builder.Eq{ast + ".status": entity.TagRelStatusAvailable}).
+			And(builder.In(ast+".tag_id", tagID))
+	}
+	return b
+}
+
+// countFacetSummary count the types, accepted status and dates of all matched contents in one query
+func (sr *searchRepo) countFacetSummary(ctx context.Context, sql string, args []interface{}, facets *plugin.SearchFacets) error {
+	fields := []string{
+		"SUM(CASE WHEN f.`object_type` = 'question' THEN 1 ELSE 0 END) as `questions`",
+		"SUM(CASE WHEN f.`object_type` = 'answer' THEN 1 ELSE 0 END) as `answers`",
+		"SUM(CASE WHEN f.`object_type` = 'answer' AND f.`accepted` = 2 THEN 1 ELSE 0 END) as `accepted_answers`",
+		"SUM(CASE WHEN f.`object_type` = 'question' AND f.`accepted` = 0 THEN 1 ELSE 0 END) as `not_accepted_questions`",
+	}
+	queryArgs := []interface{}{}
+	now := time.Now()
+	for _, date := range facetDates {
+		fields = append(fields, fmt.Sprintf("SUM(CASE WHEN f.`created_at` >= ? THEN 1 ELSE 0 END) as `%s`", date.value))
+		queryArgs = append(queryArgs, now.Add(-date.duration).Format("2006-01-02 15:04:05"))
+	}
+	querySQL := fmt.Sprintf("SELECT %s FROM (%s) f", strings.Join(fields, ", "), sql)
+	queryArgs = append(append([]interface{}{querySQL}, queryArgs...), args...)
+
+	res, err := sr.data.DB.Context(ctx).Query(queryArgs...)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	facets.Questions = converter.StringToInt64(string(res[0]["questions"]))
+	facets.Answers = converter.StringToInt64(string(res[0]["answers"]))
+	facets.AcceptedAnswers = converter.StringToInt64(string(res[0]["accepted_answers"]))
+	facets.NotAcceptedQuestions = converter.StringToInt64(string(res[0]["not_accepted_questions"]))
+	for _, date := range facetDates {
+		facets.Dates = append(facets.Dates, plugin.SearchFacetCount{
+			Value: date.value,
+			Count: converter.StringToInt64(string(res[0][date.value])),
+		})
+	}
+	return nil
+}
+
+// countFacetTop query the top values, the query must select the facet_value and facet_count
+func (sr *searchRepo) countFacetTop(ctx context.Context, sql string, args []interface{}) (counts []plugin.SearchFacetCount, err error) {
+	res, err := sr.data.DB.Context(ctx).Query(append([]interface{}{sql}, args...)...)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	counts = make([]plugin.SearchFacetCount, 0, len(res))
+	for _, r := range res {
+		counts = append(counts, plugin.SearchFacetCount{
+			Value: string(r["facet_value"]),
+			Count: converter.StringToInt64(string(r["facet_count"])),
+		})
+	}
+	return counts, nil
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tagcommon "github.com/apache/incubator-answer/internal/service/tag_common"
@@ -81,6 +82,8 @@ type searchRepo struct {
 	userCommon   *usercommon.UserCommon
 	uniqueIDRepo unique.UniqueIDRepo
 	tagCommon    *tagcommon.TagCommonService
+	// mysqlFullTextEnabled whether the mysql full-text index exists, it is checked once
+	mysqlFullTextEnabled func() bool
 }
 
 // NewSearchRepo new repository
@@ -90,12 +93,14 @@ func NewSearchRepo(
 	userCommon *usercommon.UserCommon,
 	tagCommon *tagcommon.TagCommonService,
 ) search_common.SearchRepo {
-	return &searchRepo{
+	sr := &searchRepo{
 		data:         data,
 		uniqueIDRepo: uniqueIDRepo,
 		userCommon:   userCommon,
 		tagCommon:    tagCommon,
 	}
+	sr.mysqlFullTextEnabled = sync.OnceValue(sr.checkMySQLFullTextIndex)
+	return sr
 }
 
 // SearchContents search question and answer data
@@ -103,18 +108,22 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 	words = filterWords(words)
 
 	var (
-		b     *builder.Builder
-		ub    *builder.Builder
-		qfs   = qFields
-		afs   = aFields
-		argsQ = []interface{}{}
-		argsA = []interface{}{}
+		b      *builder.Builder
+		ub     *builder.Builder
+		qfs    = qFields
+		afs    = aFields
+		argsQ  = []interface{}{}
+		argsA  = []interface{}{}
+		matchQ = sr.newFullTextMatch(fullTextTargetQuestion, words)
+		matchA = sr.newFullTextMatch(fullTextTargetAnswer, words)
 	)
 
 	if order == "relevance" {
 		if len(words) > 0 {
-			qfs, argsQ = addRelevanceField([]string{"title", "original_text"}, words, qfs)
-			afs, argsA = addRelevanceField([]string{"`answer`.`original_text`"}, words, afs)
+			qfs = append(append([]string{}, qfs...), matchQ.relevance)
+			afs = append(append([]string{}, afs...), matchA.relevance)
+			argsQ = append(argsQ, matchQ.relevanceArgs...)
+			argsA = append(argsA, matchA.relevanceArgs...)
 		} else {
 			order = "newest"
 		}
@@ -133,21 +142,13 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 	argsQ = append(argsQ, entity.QuestionStatusDeleted, entity.QuestionShow)
 	argsA = append(argsA, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted, entity.QuestionShow)
 
-	likeConQ := builder.NewCond()
-	likeConA := builder.NewCond()
-	for _, word := range words {
-		likeConQ = likeConQ.Or(builder.Like{"title", word}).
-			Or(builder.Like{"original_text", word})
-		argsQ = append(argsQ, "%"+word+"%")
-		argsQ = append(argsQ, "%"+word+"%")
-
-		likeConA = likeConA.Or(builder.Like{"`answer`.original_text", word})
-		argsA = append(argsA, "%"+word+"%")
+	if len(words) > 0 {
+		b.Where(matchQ.cond)
+		ub.Where(matchA.cond)
+		argsQ = append(argsQ, matchQ.condArgs...)
+		argsA = append(argsA, matchA.condArgs...)
 	}
 
-	b.Where(likeConQ)
-	ub.Where(likeConA)
-
 	// check tag
 	for ti, tagID := range tagIDs {
 		ast := "tag_rel" + strconv.Itoa(ti)
@@ -245,12 +246,14 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, tagIDs [][]string, notAccepted bool, views, answers int, page, pageSize int, order string) (resp []*schema.SearchResult, total int64, err error) {
 	words = filterWords(words)
 	var (
-		qfs  = qFields
-		args = []interface{}{}
+		qfs   = qFields
+		args  = []interface{}{}
+		match = sr.newFullTextMatch(fullTextTargetQuestion, words)
 	)
 	if order == "relevance" {
 		if len(words) > 0 {
-			qfs, args = addRelevanceField([]string{"title", "original_text"}, words, qfs)
+			qfs = append(append([]string{}, qfs...), match.relevance)
+			args = append(args, match.relevanceArgs...)
 		} else {
 			order = "newest"
 		}
@@ -261,14 +264,10 @@ func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, tagID
 	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).And(builder.Eq{"`question`.`show`": entity.QuestionShow})
 	args = append(args, entity.QuestionStatusDeleted, entity.QuestionShow)
 
-	likeConQ := builder.NewCond()
-	for _, word := range words {
-		likeConQ = likeConQ.Or(builder.Like{"title", word}).
-			Or(builder.Like{"original_text", word})
-		args = append(args, "%"+word+"%")
-		args = append(args, "%"+word+"%")
+	if len(words) > 0 {
+		b.Where(match.cond)
+		args = append(args, match.condArgs...)
 	}
-	b.Where(likeConQ)
 
 	// check tag
 	for ti, tagID := range tagIDs {
@@ -357,12 +356,14 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 	words = filterWords(words)
 
 	var (
-		afs  = aFields
-		args = []interface{}{}
+		afs   = aFields
+		args  = []interface{}{}
+		match = sr.newFullTextMatch(fullTextTargetAnswer, words)
 	)
 	if order == "relevance" {
 		if len(words) > 0 {
-			afs, args = addRelevanceField([]string{"`answer`.`original_text`"}, words, afs)
+			afs = append(append([]string{}, afs...), match.relevance)
+			args = append(args, match.relevanceArgs...)
 		} else {
 			order = "newest"
 		}
@@ -375,14 +376,11 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 		And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted}).And(builder.Eq{"`question`.`show`": entity.QuestionShow})
 	args = append(args, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted, entity.QuestionShow)
 
-	likeConA := builder.NewCond()
-	for _, word := range words {
-		likeConA = likeConA.Or(builder.Like{"`answer`.original_text", word})
-		args = append(args, "%"+word+"%")
+	if len(words) > 0 {
+		b.Where(match.cond)
+		args = append(args, match.condArgs...)
 	}
 
-	b.Where(likeConA)
-
 	// check tag
 	for ti, tagID := range tagIDs {
 		ast := "tag_rel" + strconv.Itoa(ti)
@@ -0,0 +1,228 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_sync
+
+import (
+	"context"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/search_reindex"
+	"github.com/apache/incubator-answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// searchReindexRepo search reindex repository
+type searchReindexRepo struct {
+	data *data.Data
+}
+
+// NewSearchReindexRepo new repository
+func NewSearchReindexRepo(data *data.Data) search_reindex.SearchReindexRepo {
+	return &searchReindexRepo{
+		data: data,
+	}
+}
+
+// GetTask get the reindex task of the search plugin
+func (sr *searchReindexRepo) GetTask(ctx context.Context, pluginSlugName string) (
+	task *entity.SearchReindexTask, exist bool, err error) {
+	task = &entity.SearchReindexTask{}
+	exist, err = sr.data.DB.Context(ctx).Where(builder.Eq{"plugin_slug_name": pluginSlugName}).Get(task)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveTask save the reindex task, there is only one task for each search plugin
+func (sr *searchReindexRepo) SaveTask(ctx context.Context, task *entity.SearchReindexTask) (err error) {
+	affected, err := sr.data.DB.Context(ctx).Where(builder.Eq{"plugin_slug_name": task.PluginSlugName}).
+		AllCols().Omit("id", "created_at").Update(task)
+	if err == nil && affected == 0 {
+		_, err = sr.data.DB.Context(ctx).Insert(task)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// ClaimTask save the task as the running one if the task of the plugin is still the last task read before,
+// claimed is false if another reindexing has claimed the task in the meantime. The last task is nil if there is
+// no task of the plugin yet, then the unique index of the plugin makes sure that only one task is inserted.
+func (sr *searchReindexRepo) ClaimTask(ctx context.Context, task, lastTask *entity.SearchReindexTask) (
+	claimed bool, err error) {
+	if lastTask == nil {
+		_, err = sr.data.DB.Context(ctx).Insert(task)
+		if err == nil {
+			return true, nil
+		}
+		_, exist, getErr := sr.GetTask(ctx, task.PluginSlugName)
+		if getErr != nil {
+			return false, getErr
+		}
+		if exist {
+			return false, nil
+		}
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	affected, err := sr.data.DB.Context(ctx).Where(builder.Eq{
+		"id":             lastTask.ID,
+		"status":         lastTask.Status,
+		"last_object_id": lastTask.LastObjectID,
+		"processed":      lastTask.Processed,
+	}).AllCols().Omit("id", "created_at").Update(task)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// CountContents count all questions and answers
+func (sr *searchReindexRepo) CountContents(ctx context.Context) (total int64, err error) {
+	questionCount, err := sr.data.DB.Context(ctx).Count(&entity.Question{})
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	answerCount, err := sr.data.DB.Context(ctx).Count(&entity.Answer{})
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return questionCount + answerCount, nil
+}
+
+// GetQuestionContents get the search contents of the questions after the last question id
+func (sr *searchReindexRepo) GetQuestionContents(ctx context.Context, lastID string, limit int) (
+	contents []*plugin.SearchContent, err error) {
+	questions := make([]*entity.Question, 0)
+	err = sr.data.DB.Context(ctx).Where("id > ?", lastID).Asc("id").Limit(limit).Find(&questions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	questionIDs := make([]string, 0, len(questions))
+	for _, question := range questions {
+		questionIDs = append(questionIDs, question.ID)
+	}
+	tagMapping, err := sr.getTagMapping(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	contents = make([]*plugin.SearchContent, 0, len(questions))
+	for _, question := range questions {
+		contents = append(contents, &plugin.SearchContent{
+			ObjectID:    question.ID,
+			Title:       question.Title,
+			Type:        constant.QuestionObjectType,
+			Content:     question.OriginalText,
+			Answers:     int64(question.AnswerCount),
+			Status:      plugin.SearchContentStatus(question.Status),
+			Tags:        tagMapping[question.ID],
+			QuestionID:  question.ID,
+			UserID:      question.UserID,
+			Views:       int64(question.ViewCount),
+			Created:     question.CreatedAt.Unix(),
+			Active:      question.UpdatedAt.Unix(),
+			Score:       int64(question.VoteCount),
+			HasAccepted: question.AcceptedAnswerID != "" && question.AcceptedAnswerID != "0",
+		})
+	}
+	return contents, nil
+}
+
+// GetAnswerContents get the search contents of the answers after the last answer id,
+// the answer of the question that does not exist is marked as deleted.
+func (sr *searchReindexRepo) GetAnswerContents(ctx context.Context, lastID string, limit int) (
+	contents []*plugin.SearchContent, err error) {
+	answers := make([]*entity.Answer, 0)
+	err = sr.data.DB.Context(ctx).Where("id > ?", lastID).Asc("id").Limit(limit).Find(&answers)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	questionIDs := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		questionIDs = append(questionIDs, answer.QuestionID)
+	}
+	questions := make([]*entity.Question, 0)
+	err = sr.data.DB.Context(ctx).In("id", questionIDs).Find(&questions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	questionMapping := make(map[string]*entity.Question, len(questions))
+	for _, question := range questions {
+		questionMapping[question.ID] = question
+	}
+	tagMapping, err := sr.getTagMapping(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	contents = make([]*plugin.SearchContent, 0, len(answers))
+	for _, answer := range answers {
+		content := &plugin.SearchContent{
+			ObjectID:    answer.ID,
+			Type:        constant.AnswerObjectType,
+			Content:     answer.OriginalText,
+			Status:      plugin.SearchContentStatus(answer.Status),
+			Tags:        tagMapping[answer.QuestionID],
+			QuestionID:  answer.QuestionID,
+			UserID:      answer.UserID,
+			Created:     answer.CreatedAt.Unix(),
+			Active:      answer.UpdatedAt.Unix(),
+			Score:       int64(answer.VoteCount),
+			HasAccepted: answer.Accepted == schema.AnswerAcceptedEnable,
+		}
+		if question, ok := questionMapping[answer.QuestionID]; ok {
+			content.Title = question.Title
+			content.Views = int64(question.ViewCount)
+		} else {
+			content.Status = plugin.SearchContentStatusDeleted
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// getTagMapping get the tag ids of the questions
+func (sr *searchReindexRepo) getTagMapping(ctx context.Context, questionIDs []string) (
+	tagMapping map[string][]string, err error) {
+	tagMapping = make(map[string][]string, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return tagMapping, nil
+	}
+	tagRelList := make([]*entity.TagRel, 0)
+	err = sr.data.DB.Context(ctx).In("object_id", questionIDs).
+		Where("status = ?", entity.TagRelStatusAvailable).Asc("id").Find(&tagRelList)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, questionID := range questionIDs {
+		tagMapping[questionID] = make([]string, 0)
+	}
+	for _, tagRel := range tagRelList {
+		tagMapping[tagRel.ObjectID] = append(tagMapping[tagRel.ObjectID], tagRel.TagID)
+	}
+	return tagMapping, nil
+}
@@ -21,8 +21,10 @@ package tag
 
 import (
 	"context"
+	"time"
 
 	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/pager"
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/service/tag_common"
@@ -30,6 +32,7 @@ import (
 	"github.com/apache/incubator-answer/pkg/converter"
 	"github.com/segmentfault/pacman/errors"
 	"xorm.io/builder"
+	"xorm.io/xorm"
 )
 
 // tagRepo tag repository
@@ -138,3 +141,227 @@ func (tr *tagRepo) GetTagList(ctx context.Context, tag *entity.Tag) (tagList []*
 	}
 	return
 }
+
+// GetTagListByParentIDs get the available sub tags of parent tags
+func (tr *tagRepo) GetTagListByParentIDs(ctx context.Context, parentTagIDs []string) (tagList []*entity.Tag, err error) {
+	tagList = make([]*entity.Tag, 0)
+	if len(parentTagIDs) == 0 {
+		return tagList, nil
+	}
+	session := tr.data.DB.Context(ctx).In("parent_tag_id", parentTagIDs)
+	session.Where(builder.Eq{"status": entity.TagStatusAvailable})
+	err = session.Asc("slug_name").Find(&tagList)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetSubTagPage get the sub tags of parent tag by page
+func (tr *tagRepo) GetSubTagPage(ctx context.Context, parentTagID string, page, pageSize int) (
+	tagList []*entity.Tag, total int64, err error) {
+	tagList = make([]*entity.Tag, 0)
+	session := tr.data.DB.Context(ctx).Where(builder.Eq{
+		"parent_tag_id": parentTagID,
+		"main_tag_id":   0,
+		"status":        entity.TagStatusAvailable,
+	})
+	session.Asc("slug_name")
+	total, err = pager.Help(page, pageSize, &tagList, &entity.Tag{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountSubTags count the sub tags of each parent tag
+func (tr *tagRepo) CountSubTags(ctx context.Context, parentTagIDs []string) (counts map[string]int, err error) {
+	counts = make(map[string]int, len(parentTagIDs))
+	if len(parentTagIDs) == 0 {
+		return counts, nil
+	}
+	rows := make([]*struct {
+		ParentTagID int64 `xorm:"parent_tag_id"`
+		Count       int   `xorm:"count"`
+	}, 0)
+	err = tr.data.DB.Context(ctx).Table(new(entity.Tag)).Select("parent_tag_id, COUNT(*) AS count").
+		In("parent_tag_id", parentTagIDs).
+		Where(builder.Eq{"main_tag_id": 0, "status": entity.TagStatusAvailable}).
+		GroupBy("parent_tag_id").Find(&rows)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, row := range rows {
+		counts[converter.IntToString(row.ParentTagID)] = row.Count
+	}
+	return counts, nil
+}
+
+// UpdateTagParent update the parent of tag, zero means the tag has no parent
+func (tr *tagRepo) UpdateTagParent(ctx context.Context, tagID string, parentTagID int64) (err error) {
+	_, err = tr.data.DB.Context(ctx).ID(tagID).MustCols("parent_tag_id").Update(&entity.Tag{ParentTagID: parentTagID})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// MergeTag move the questions and followers of source tag to target tag in a transaction,
+// it returns the id of questions whose tags are changed
+func (tr *tagRepo) MergeTag(ctx context.Context, sourceTag, targetTag *entity.Tag, followActivityType int,
+	keepAsSynonym bool) (questionIDs []string, err error) {
+	_, err = tr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		questionIDs, err = tr.mergeTagRel(session, sourceTag.ID, targetTag.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err = tr.mergeTagFollow(session, sourceTag.ID, targetTag.ID, followActivityType); err != nil {
+			return nil, err
+		}
+
+		// the synonyms of source tag become the synonyms of target tag
+		targetMainTagID := converter.StringToInt64(targetTag.ID)
+		_, err = session.Where(builder.Eq{"main_tag_id": converter.StringToInt64(sourceTag.ID)}).
+			MustCols("main_tag_id", "main_tag_slug_name").
+			Update(&entity.Tag{MainTagID: targetMainTagID, MainTagSlugName: targetTag.SlugName})
+		if err != nil {
+			return nil, err
+		}
+
+		// the sub tags of source tag become the sub tags of target tag
+		_, err = session.Where(builder.Eq{"parent_tag_id": converter.StringToInt64(sourceTag.ID)}).
+			Cols("parent_tag_id").Update(&entity.Tag{ParentTagID: targetMainTagID})
+		if err != nil {
+			return nil, err
+		}
+
+		source := &entity.Tag{}
+		cols := []string{"follow_count", "question_count", "parent_tag_id"}
+		if keepAsSynonym {
+			source.MainTagID = targetMainTagID
+			source.MainTagSlugName = targetTag.SlugName
+			cols = append(cols, "main_tag_id", "main_tag_slug_name")
+		} else {
+			source.Status = entity.TagStatusDeleted
+			cols = append(cols, "status")
+		}
+		_, err = session.ID(sourceTag.ID).Cols(cols...).Update(source)
+		return nil, err
+	})
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return questionIDs, nil
+}
+
+// mergeTagRel move the tag relations of source tag to target tag. If the question already has the target tag,
+// the relation of source tag is removed and the relation of target tag is restored if needed.
+func (tr *tagRepo) mergeTagRel(session *xorm.Session, sourceTagID, targetTagID string) (
+	questionIDs []string, err error) {
+	sourceRelList := make([]*entity.TagRel, 0)
+	if err = session.Where(builder.Eq{"tag_id": sourceTagID}).Find(&sourceRelList); err != nil {
+		return nil, err
+	}
+	if len(sourceRelList) == 0 {
+		return nil, nil
+	}
+	objectIDs := make([]string, 0, len(sourceRelList))
+	for _, rel := range sourceRelList {
+		objectIDs = append(objectIDs, rel.ObjectID)
+	}
+	targetRelList := make([]*entity.TagRel, 0)
+	err = session.Where(builder.Eq{"tag_id": targetTagID}).In("object_id", objectIDs).Find(&targetRelList)
+	if err != nil {
+		return nil, err
+	}
+	targetRelMapping := make(map[string]*entity.TagRel, len(targetRelList))
+	for _, rel := range targetRelList {
+		targetRelMapping[rel.ObjectID] = rel
+	}
+
+	for _, rel := range sourceRelList {
+		if rel.Status != entity.TagRelStatusDeleted {
+			questionIDs = append(questionIDs, rel.ObjectID)
+		}
+		targetRel, ok := targetRelMapping[rel.ObjectID]
+		if !ok {
+			if _, err = session.ID(rel.ID).Cols("tag_id").Update(&entity.TagRel{TagID: targetTagID}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if targetRel.Status == entity.TagRelStatusDeleted && rel.Status != entity.TagRelStatusDeleted {
+			_, err = session.ID(targetRel.ID).Cols("status").Update(&entity.TagRel{Status: rel.Status})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if _, err = session.ID(rel.ID).Delete(&entity.TagRel{}); err != nil {
+			return nil, err
+		}
+	}
+	return questionIDs, nil
+}
+
+// mergeTagFollow move the followers of source tag to target tag and recount the followers of target tag
+func (tr *tagRepo) mergeTagFollow(session *xorm.Session, sourceTagID, targetTagID string, followActivityType int) (
+	err error) {
+	sourceFollowList := make([]*entity.Activity, 0)
+	err = session.Where(builder.Eq{"activity_type": followActivityType, "object_id": sourceTagID}).
+		Find(&sourceFollowList)
+	if err != nil {
+		return err
+	}
+	if len(sourceFollowList) > 0 {
+		userIDs := make([]string, 0, len(sourceFollowList))
+		for _, follow := range sourceFollowList {
+			userIDs = append(userIDs, follow.UserID)
+		}
+		targetFollowList := make([]*entity.Activity, 0)
+		err = session.Where(builder.Eq{"activity_type": followActivityType, "object_id": targetTagID}).
+			In("user_id", userIDs).Find(&targetFollowList)
+		if err != nil {
+			return err
+		}
+		targetFollowMapping := make(map[string]*entity.Activity, len(targetFollowList))
+		for _, follow := range targetFollowList {
+			targetFollowMapping[follow.UserID] = follow
+		}
+
+		for _, follow := range sourceFollowList {
+			targetFollow, ok := targetFollowMapping[follow.UserID]
+			if !ok {
+				_, err = session.ID(follow.ID).Cols("object_id", "original_object_id").
+					Update(&entity.Activity{ObjectID: targetTagID, OriginalObjectID: targetTagID})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if targetFollow.Cancelled == entity.ActivityCancelled && follow.Cancelled == entity.ActivityAvailable {
+				_, err = session.ID(targetFollow.ID).MustCols("cancelled").
+					Update(&entity.Activity{Cancelled: entity.ActivityAvailable})
+				if err != nil {
+					return err
+				}
+			}
+			if follow.Cancelled == entity.ActivityAvailable {
+				_, err = session.ID(follow.ID).Cols("cancelled", "cancelled_at").
+					Update(&entity.Activity{Cancelled: entity.ActivityCancelled, CancelledAt: time.Now()})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	followCount, err := session.Where(builder.Eq{
+		"activity_type": followActivityType, "object_id": targetTagID, "cancelled": entity.ActivityAvailable,
+	}).Count(&entity.Activity{})
+	if err != nil {
+		return err
+	}
+	_, err = session.ID(targetTagID).Cols("follow_count").Update(&entity.Tag{FollowCount: int(followCount)})
+	return err
+}
@@ -0,0 +1,188 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package two_factor
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/two_factor"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// challengeAttemptsLock makes the increment of the attempts atomic for the cache that is not shared, such as memory
+var challengeAttemptsLock sync.Mutex
+
+// twoFactorRepo two factor repository
+type twoFactorRepo struct {
+	data *data.Data
+}
+
+// NewTwoFactorRepo new repository
+func NewTwoFactorRepo(data *data.Data) two_factor.TwoFactorRepo {
+	return &twoFactorRepo{
+		data: data,
+	}
+}
+
+// GetUserTwoFactor get two factor of user
+func (tr *twoFactorRepo) GetUserTwoFactor(ctx context.Context, userID string) (
+	tf *entity.UserTwoFactor, exist bool, err error) {
+	tf = &entity.UserTwoFactor{}
+	exist, err = tr.data.DB.Context(ctx).Where("user_id = ?", userID).Get(tf)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveUserTwoFactor add or replace the two factor of user
+func (tr *twoFactorRepo) SaveUserTwoFactor(ctx context.Context, tf *entity.UserTwoFactor) (err error) {
+	old := &entity.UserTwoFactor{}
+	exist, err := tr.data.DB.Context(ctx).Where("user_id = ?", tf.UserID).Get(old)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		tf.ID = old.ID
+		_, err = tr.data.DB.Context(ctx).ID(tf.ID).
+			Cols("secret", "recovery_codes", "last_used_step", "status").Update(tf)
+	} else {
+		_, err = tr.data.DB.Context(ctx).Insert(tf)
+	}
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateUserTwoFactor update two factor by cols
+func (tr *twoFactorRepo) UpdateUserTwoFactor(ctx context.Context, tf *entity.UserTwoFactor, cols ...string) (err error) {
+	_, err = tr.data.DB.Context(ctx).Where("user_id = ?", tf.UserID).Cols(cols...).Update(tf)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UseTimeStep mark the time step as used, the condition makes sure the same code can't be used twice
+func (tr *twoFactorRepo) UseTimeStep(ctx context.Context, userID string, step int64) (ok bool, err error) {
+	affected, err := tr.data.DB.Context(ctx).Where("user_id = ? AND last_used_step < ?", userID, step).
+		Cols("last_used_step").Update(&entity.UserTwoFactor{LastUsedStep: step})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// UpdateRecoveryCodes replace the recovery codes only if they are not changed by others
+func (tr *twoFactorRepo) UpdateRecoveryCodes(ctx context.Context, userID, oldCodes, newCodes string) (ok bool, err error) {
+	affected, err := tr.data.DB.Context(ctx).Where("user_id = ? AND recovery_codes = ?", userID, oldCodes).
+		Cols("recovery_codes").Update(&entity.UserTwoFactor{RecoveryCodes: newCodes})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// RemoveUserTwoFactor remove the two factor of user
+func (tr *twoFactorRepo) RemoveUserTwoFactor(ctx context.Context, userID string) (err error) {
+	_, err = tr.data.DB.Context(ctx).Where("user_id = ?", userID).Delete(&entity.UserTwoFactor{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SetChallenge save the pending login
+func (tr *twoFactorRepo) SetChallenge(ctx context.Context, token string, challenge *schema.TwoFactorChallenge) (err error) {
+	content, _ := json.Marshal(challenge)
+	err = tr.data.Cache.SetString(ctx, constant.TwoFactorChallengeCacheKey+token, string(content),
+		constant.TwoFactorChallengeCacheTime)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetChallenge get the pending login
+func (tr *twoFactorRepo) GetChallenge(ctx context.Context, token string) (
+	challenge *schema.TwoFactorChallenge, exist bool, err error) {
+	content, exist, err := tr.data.Cache.GetString(ctx, constant.TwoFactorChallengeCacheKey+token)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return nil, false, nil
+	}
+	challenge = &schema.TwoFactorChallenge{}
+	if err = json.Unmarshal([]byte(content), challenge); err != nil {
+		return nil, false, nil
+	}
+	return challenge, true, nil
+}
+
+// IncreaseChallengeAttempts increase the attempts of the pending login, it expires with the pending login
+func (tr *twoFactorRepo) IncreaseChallengeAttempts(ctx context.Context, token string) (attempts int64, err error) {
+	key := constant.TwoFactorChallengeAttemptsCacheKey + token
+	if counter, ok := tr.data.Cache.(data.CounterCache); ok {
+		attempts, err = counter.IncreaseWithTTL(ctx, key, 1, constant.TwoFactorChallengeCacheTime)
+		if err != nil {
+			return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		return attempts, nil
+	}
+
+	challengeAttemptsLock.Lock()
+	defer challengeAttemptsLock.Unlock()
+	attempts, exist, err := tr.data.Cache.GetInt64(ctx, key)
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		attempts, err = tr.data.Cache.Increase(ctx, key, 1)
+		if err == nil {
+			return attempts, nil
+		}
+		// the key is expired just now, start a new count
+	}
+	if err = tr.data.Cache.SetInt64(ctx, key, 1, constant.TwoFactorChallengeCacheTime); err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return 1, nil
+}
+
+// RemoveChallenge remove the pending login and its attempts
+func (tr *twoFactorRepo) RemoveChallenge(ctx context.Context, token string) (err error) {
+	err = tr.data.Cache.Del(ctx, constant.TwoFactorChallengeCacheKey+token)
+	if err == nil {
+		err = tr.data.Cache.Del(ctx, constant.TwoFactorChallengeAttemptsCacheKey+token)
+	}
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/service/webhook"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+// webhookRepo webhook repository
+type webhookRepo struct {
+	data *data.Data
+}
+
+// NewWebhookRepo new repository
+func NewWebhookRepo(data *data.Data) webhook.WebhookRepo {
+	return &webhookRepo{
+		data: data,
+	}
+}
+
+// AddWebhook add webhook
+func (wr *webhookRepo) AddWebhook(ctx context.Context, webhook *entity.Webhook) (err error) {
+	_, err = wr.data.DB.Context(ctx).Insert(webhook)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateWebhook update webhook
+func (wr *webhookRepo) UpdateWebhook(ctx context.Context, webhook *entity.Webhook) (err error) {
+	_, err = wr.data.DB.Context(ctx).ID(webhook.ID).
+		Cols("name", "url", "secret", "events", "status").Update(webhook)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// DeleteWebhook delete webhook and its delivery log
+func (wr *webhookRepo) DeleteWebhook(ctx context.Context, webhookID string) (err error) {
+	_, err = wr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		if _, err = session.Where("webhook_id = ?", webhookID).Delete(&entity.WebhookDelivery{}); err != nil {
+			return nil, err
+		}
+		return session.ID(webhookID).Delete(&entity.Webhook{})
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetWebhook get webhook one
+func (wr *webhookRepo) GetWebhook(ctx context.Context, webhookID string) (
+	webhook *entity.Webhook, exist bool, err error) {
+	webhook = &entity.Webhook{}
+	exist, err = wr.data.DB.Context(ctx).ID(webhookID).Get(webhook)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetWebhookList get all webhooks
+func (wr *webhookRepo) GetWebhookList(ctx context.Context) (webhooks []*entity.Webhook, err error) {
+	webhooks = make([]*entity.Webhook, 0)
+	err = wr.data.DB.Context(ctx).Asc("id").Find(&webhooks)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetActiveWebhookList get all active webhooks
+func (wr *webhookRepo) GetActiveWebhookList(ctx context.Context) (webhooks []*entity.Webhook, err error) {
+	webhooks = make([]*entity.Webhook, 0)
+	err = wr.data.DB.Context(ctx).Where("status = ?", entity.WebhookStatusActive).Asc("id").Find(&webhooks)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// webhookDeliveryRepo webhook delivery repository
+type webhookDeliveryRepo struct {
+	data *data.Data
+}
+
+// NewWebhookDeliveryRepo new repository
+func NewWebhookDeliveryRepo(data *data.Data) webhook.WebhookDeliveryRepo {
+	return &webhookDeliveryRepo{
+		data: data,
+	}
+}
+
+// AddDelivery add delivery
+func (wr *webhookDeliveryRepo) AddDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	_, err = wr.data.DB.Context(ctx).Insert(delivery)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetEventWebhookIDs get the ids of the webhooks that the deliveries of the event have been created for
+func (wr *webhookDeliveryRepo) GetEventWebhookIDs(ctx context.Context, eventID string) (webhookIDs []string, err error) {
+	webhookIDs = make([]string, 0)
+	err = wr.data.DB.Context(ctx).Table(entity.WebhookDelivery{}.TableName()).
+		Where("event_id = ?", eventID).Cols("webhook_id").Find(&webhookIDs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateDelivery update delivery result
+func (wr *webhookDeliveryRepo) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	_, err = wr.data.DB.Context(ctx).ID(delivery.ID).
+		Cols("status", "attempts", "response_code", "response_body", "last_error", "next_retry_at", "delivered_at").
+		Update(delivery)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDelivery get delivery one
+func (wr *webhookDeliveryRepo) GetDelivery(ctx context.Context, deliveryID string) (
+	delivery *entity.WebhookDelivery, exist bool, err error) {
+	delivery = &entity.WebhookDelivery{}
+	exist, err = wr.data.DB.Context(ctx).ID(deliveryID).Get(delivery)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDeliveryPage get delivery page, the newest first
+func (wr *webhookDeliveryRepo) GetDeliveryPage(ctx context.Context, page, pageSize int, webhookID string) (
+	deliveries []*entity.WebhookDelivery, total int64, err error) {
+	deliveries = make([]*entity.WebhookDelivery, 0)
+	session := wr.data.DB.Context(ctx).Where("webhook_id = ?", webhookID).Desc("id")
+	total, err = pager.Help(page, pageSize, &deliveries, &entity.WebhookDelivery{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
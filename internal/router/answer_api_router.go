@@ -57,6 +57,18 @@ type AnswerAPIRouter struct {
 	metaController          *controller.MetaController
 	badgeController         *controller.BadgeController
 	adminBadgeController    *controller_admin.BadgeController
+	webhookController       *controller_admin.WebhookController
+	apiKeyController        *controller.APIKeyController
+	adminAPIKeyController   *controller_admin.APIKeyController
+	cronJobController       *controller_admin.CronJobController
+	twoFactorController     *controller.TwoFactorController
+	auditLogController      *controller_admin.AuditLogController
+	questionMergeController *controller.QuestionMergeController
+	bountyController        *controller.BountyController
+	draftController         *controller.DraftController
+	feedController          *controller.FeedController
+	searchReindexController *controller_admin.SearchReindexController
+	savedSearchController   *controller.SavedSearchController
 }
 
 func NewAnswerAPIRouter(
@@ -90,6 +102,18 @@ func NewAnswerAPIRouter(
 	metaController *controller.MetaController,
 	badgeController *controller.BadgeController,
 	adminBadgeController *controller_admin.BadgeController,
+	webhookController *controller_admin.WebhookController,
+	apiKeyController *controller.APIKeyController,
+	adminAPIKeyController *controller_admin.APIKeyController,
+	cronJobController *controller_admin.CronJobController,
+	twoFactorController *controller.TwoFactorController,
+	auditLogController *controller_admin.AuditLogController,
+	questionMergeController *controller.QuestionMergeController,
+	bountyController *controller.BountyController,
+	draftController *controller.DraftController,
+	feedController *controller.FeedController,
+	searchReindexController *controller_admin.SearchReindexController,
+	savedSearchController *controller.SavedSearchController,
 ) *AnswerAPIRouter {
 	return &AnswerAPIRouter{
 		langController:          langController,
@@ -122,6 +146,18 @@ func NewAnswerAPIRouter(
 		metaController:          metaController,
 		badgeController:         badgeController,
 		adminBadgeController:    adminBadgeController,
+		webhookController:       webhookController,
+		apiKeyController:        apiKeyController,
+		adminAPIKeyController:   adminAPIKeyController,
+		cronJobController:       cronJobController,
+		twoFactorController:     twoFactorController,
+		auditLogController:      auditLogController,
+		questionMergeController: questionMergeController,
+		bountyController:        bountyController,
+		draftController:         draftController,
+		feedController:          feedController,
+		searchReindexController: searchReindexController,
+		savedSearchController:   savedSearchController,
 	}
 }
 
@@ -139,6 +175,8 @@ func (a *AnswerAPIRouter) RegisterMustUnAuthAnswerAPIRouter(authUserMiddleware *
 	r.GET("/user/action/record", authUserMiddleware.Auth(), a.userController.ActionRecord)
 	routerGroup := r.Group("", middleware.BanAPIForUserCenter)
 	routerGroup.POST("/user/login/email", a.userController.UserEmailLogin)
+	routerGroup.POST("/user/login/2fa", a.userController.UserTwoFactorLogin)
+	routerGroup.POST("/user/login/2fa/enroll", a.twoFactorController.EnrollLoginTwoFactor)
 	routerGroup.POST("/user/register/email", a.userController.UserRegisterByEmail)
 	routerGroup.POST("/user/email/verification", a.userController.UserVerifyEmail)
 	routerGroup.PUT("/user/email", a.userController.UserChangeEmailVerify)
@@ -170,6 +208,7 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 	r.GET("/personal/qa/top", a.questionController.UserTop)
 	r.GET("/personal/question/page", a.questionController.PersonalQuestionPage)
 	r.GET("/question/link", a.questionController.GetQuestionLink)
+	r.GET("/question/bounty", a.bountyController.GetQuestionBounty)
 
 	// comment
 	r.GET("/comment/page", a.commentController.GetCommentWithPage)
@@ -185,6 +224,7 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 	r.GET("/tag", a.tagController.GetTagInfo)
 	r.GET("/tags", a.tagController.GetTagsBySlugName)
 	r.GET("/tag/synonyms", a.tagController.GetTagSynonyms)
+	r.GET("/tags/tree", a.tagController.GetTagTree)
 
 	// search
 	r.GET("/search", a.searchController.Search)
@@ -246,6 +286,8 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.POST("/tag/recover", a.tagController.RecoverTag)
 	r.DELETE("/tag", a.tagController.RemoveTag)
 	r.PUT("/tag/synonym", a.tagController.UpdateTagSynonym)
+	r.POST("/tag/merge", a.tagController.MergeTag)
+	r.PUT("/tag/parent", a.tagController.UpdateTagParent)
 
 	// collection
 	r.POST("/collection/switch", a.collectionController.CollectionSwitch)
@@ -262,6 +304,16 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/question/reopen", a.questionController.ReopenQuestion)
 	r.GET("/question/similar", a.questionController.GetSimilarQuestions)
 	r.POST("/question/recover", a.questionController.QuestionRecover)
+	r.POST("/question/merge", a.questionMergeController.MergeQuestion)
+	r.POST("/question/merge/revert", a.questionMergeController.RevertQuestionMerge)
+	r.POST("/question/bounty", a.bountyController.OfferBounty)
+	r.POST("/question/bounty/award", a.bountyController.AwardBounty)
+
+	// draft
+	r.GET("/draft", a.draftController.GetDraft)
+	r.POST("/draft", a.draftController.SaveDraft)
+	r.DELETE("/draft", a.draftController.RemoveDraft)
+	r.GET("/drafts/page", a.draftController.GetDraftPage)
 
 	// answer
 	r.POST("/answer", a.answerController.Add)
@@ -276,8 +328,30 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/user/interface", a.userController.UserUpdateInterface)
 	r.GET("/user/notification/config", a.userController.GetUserNotificationConfig)
 	r.PUT("/user/notification/config", a.userController.UpdateUserNotificationConfig)
+
+	// two factor
+	r.GET("/user/2fa", a.twoFactorController.GetTwoFactorStatus)
+	r.POST("/user/2fa/enroll", a.twoFactorController.EnrollTwoFactor)
+	r.POST("/user/2fa/enable", a.twoFactorController.EnableTwoFactor)
+	r.POST("/user/2fa/disable", a.twoFactorController.DisableTwoFactor)
+	r.POST("/user/2fa/recovery-codes", a.twoFactorController.RegenerateRecoveryCodes)
+
+	// api key
+	r.GET("/user/api-keys", a.apiKeyController.GetUserAPIKeyList)
+	r.POST("/user/api-key", a.apiKeyController.AddAPIKey)
+	r.DELETE("/user/api-key", a.apiKeyController.RevokeAPIKey)
 	r.GET("/user/info/search", a.userController.SearchUserListByName)
 
+	// feed token
+	r.GET("/user/feed/token", a.feedController.GetFeedToken)
+	r.POST("/user/feed/token/reset", a.feedController.ResetFeedToken)
+
+	// saved search
+	r.GET("/user/saved-searches", a.savedSearchController.GetSavedSearchList)
+	r.POST("/user/saved-search", a.savedSearchController.AddSavedSearch)
+	r.PUT("/user/saved-search", a.savedSearchController.UpdateSavedSearch)
+	r.DELETE("/user/saved-search", a.savedSearchController.RemoveSavedSearch)
+
 	// vote
 	r.GET("/personal/vote/page", a.voteController.UserVotes)
 
@@ -289,6 +363,7 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 
 	// notification
 	r.GET("/notification/status", a.notificationController.GetRedDot)
+	r.GET("/notification/stream", a.notificationController.GetNotificationStream)
 	r.PUT("/notification/status", a.notificationController.ClearRedDot)
 	r.GET("/notification/page", a.notificationController.GetList)
 	r.PUT("/notification/read/state/all", a.notificationController.ClearUnRead)
@@ -327,6 +402,7 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	r.POST("/users", a.adminUserController.AddUsers)
 	r.PUT("/user/password", a.adminUserController.UpdateUserPassword)
 	r.PUT("/user/profile", a.adminUserController.EditUserProfile)
+	r.PUT("/user/2fa/reset", a.adminUserController.ResetUserTwoFactor)
 
 	// reason
 	r.GET("/reasons", a.reasonController.Reasons)
@@ -368,6 +444,11 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 
 	// roles
 	r.GET("/roles", a.roleController.GetRoleList)
+	r.POST("/role", a.roleController.AddRole)
+	r.PUT("/role", a.roleController.UpdateRole)
+	r.DELETE("/role", a.roleController.DeleteRole)
+	r.GET("/role/powers", a.roleController.GetRolePowerList)
+	r.GET("/powers", a.roleController.GetPowerList)
 
 	// plugin
 	r.GET("/plugins", a.pluginController.GetPluginList)
@@ -378,4 +459,31 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	// badge
 	r.GET("/badges", a.adminBadgeController.GetBadgeList)
 	r.PUT("/badge/status", a.adminBadgeController.UpdateBadgeStatus)
+
+	// webhook
+	r.GET("/webhooks", a.webhookController.GetWebhookList)
+	r.POST("/webhooks", a.webhookController.AddWebhook)
+	r.PUT("/webhooks", a.webhookController.UpdateWebhook)
+	r.DELETE("/webhooks", a.webhookController.DeleteWebhook)
+	r.GET("/webhooks/events", a.webhookController.GetEventTypeList)
+	r.GET("/webhooks/deliveries/page", a.webhookController.GetDeliveryPage)
+	r.POST("/webhooks/deliveries/redeliver", a.webhookController.Redeliver)
+
+	// api key
+	r.GET("/api-keys/page", a.adminAPIKeyController.GetAPIKeyPage)
+	r.POST("/api-keys", a.adminAPIKeyController.AddAPIKey)
+	r.DELETE("/api-keys", a.adminAPIKeyController.RevokeAPIKey)
+
+	// cron job
+	r.GET("/cron-jobs", a.cronJobController.GetCronJobList)
+	r.POST("/cron-jobs/trigger", a.cronJobController.TriggerCronJob)
+	r.PUT("/cron-jobs/status", a.cronJobController.UpdateCronJobStatus)
+
+	// audit log
+	r.GET("/audit-logs/page", a.auditLogController.GetAuditLogPage)
+	r.GET("/audit-logs/export", a.auditLogController.ExportAuditLog)
+
+	// search
+	r.GET("/search/reindex", a.searchReindexController.GetSearchReindexProgress)
+	r.POST("/search/reindex", a.searchReindexController.StartSearchReindex)
 }
@@ -62,6 +62,13 @@ func (a *TemplateRouter) RegisterTemplateRouter(r *gin.RouterGroup, baseURLPath
 
 	seoNoAuth.GET("/opensearch.xml", a.templateController.OpenSearch)
 
+	// the feeds check private mode by themselves, feed readers could not login
+	seoNoAuth.GET("/feeds/:format/questions", a.templateController.FeedQuestions)
+	seoNoAuth.GET("/feeds/:format/questions/:id", a.templateController.FeedQuestionAnswers)
+	seoNoAuth.GET("/feeds/:format/tags/:tag", a.templateController.FeedTag)
+	seoNoAuth.GET("/feeds/:format/users/:username", a.templateController.FeedUser)
+	seoNoAuth.GET("/feeds/:format/search", a.templateController.FeedSearch)
+
 	seo := r.Group(baseURLPath)
 	seo.Use(a.authUserMiddleware.CheckPrivateMode())
 	seo.GET("/", a.templateController.Index)
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+import "github.com/apache/incubator-answer/internal/entity"
+
+const (
+	APIKeyScopeRead          = "read"
+	APIKeyScopeWriteQuestion = "write:question"
+	APIKeyScopeWriteAnswer   = "write:answer"
+	APIKeyScopeAdmin         = "admin"
+
+	// APIKeyTokenPrefix all api key tokens start with this prefix, so that they can be distinguished from access tokens
+	APIKeyTokenPrefix = "ans_"
+)
+
+// APIKeyStatusMap api key status display name
+var APIKeyStatusMap = map[int]string{
+	entity.APIKeyStatusAvailable: "available",
+	entity.APIKeyStatusRevoked:   "revoked",
+}
+
+// APIKeyScopeList all scopes
+var APIKeyScopeList = []string{
+	APIKeyScopeRead,
+	APIKeyScopeWriteQuestion,
+	APIKeyScopeWriteAnswer,
+	APIKeyScopeAdmin,
+}
+
+// APIKeyInfo api key info, the token itself is never returned except on creation
+type APIKeyInfo struct {
+	// api key id
+	ID string `json:"id"`
+	// owner user id
+	UserID string `json:"user_id"`
+	// api key name
+	Name string `json:"name"`
+	// the beginning of the token, used to recognize the token
+	TokenPrefix string `json:"token_prefix"`
+	// granted scopes
+	Scopes []string `json:"scopes"`
+	// status: available or revoked
+	Status string `json:"status"`
+	// created time
+	CreatedAt int64 `json:"created_at"`
+	// expired time, 0 means never expire
+	ExpiredAt int64 `json:"expired_at"`
+	// last used time, 0 means never used
+	LastUsedAt int64 `json:"last_used_at"`
+}
+
+// AddAPIKeyReq add api key request
+type AddAPIKeyReq struct {
+	// api key name
+	Name string `validate:"required,notblank,lte=100" json:"name"`
+	// granted scopes
+	Scopes []string `validate:"required,gt=0,dive,oneof=read write:question write:answer admin" json:"scopes"`
+	// expired time in unix seconds, 0 means never expire
+	ExpiredAt int64 `validate:"omitempty,min=0" json:"expired_at"`
+	// the owner of the api key, only admin can set it
+	UserID string `json:"-"`
+	// the user who creates the api key
+	CreatorUserID string `json:"-"`
+}
+
+// AdminAddAPIKeyReq admin add api key request
+type AdminAddAPIKeyReq struct {
+	AddAPIKeyReq
+	// the owner of the api key
+	OwnerUserID string `validate:"required" json:"user_id"`
+}
+
+// AddAPIKeyResp add api key response
+type AddAPIKeyResp struct {
+	*APIKeyInfo
+	// the token, it will be only returned once
+	Token string `json:"token"`
+}
+
+// RevokeAPIKeyReq revoke api key request
+type RevokeAPIKeyReq struct {
+	// api key id
+	ID string `validate:"required" json:"id"`
+	// current user id, empty means revoked by admin
+	UserID string `json:"-"`
+}
+
+// GetAPIKeyPageReq get api key page request
+type GetAPIKeyPageReq struct {
+	// page
+	Page int `validate:"omitempty,min=1" form:"page"`
+	// page size
+	PageSize int `validate:"omitempty,min=1" form:"page_size"`
+	// filter by owner user id
+	UserID string `validate:"omitempty" form:"user_id"`
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+import "encoding/json"
+
+// audit log actions
+const (
+	AuditActionUpdateUserStatus    = "user.status.update"
+	AuditActionUpdateUserRole      = "user.role.update"
+	AuditActionUpdateUserPassword  = "user.password.update"
+	AuditActionResetUserTwoFactor  = "user.two_factor.reset"
+	AuditActionUpdatePluginStatus  = "plugin.status.update"
+	AuditActionUpdatePluginConfig  = "plugin.config.update"
+	AuditActionUpdateSiteInfo      = "siteinfo.update"
+	AuditActionUpdateQuestion      = "question.status.update"
+	AuditActionUpdateAnswer        = "answer.status.update"
+	AuditActionReviewReport        = "report.review"
+	AuditActionReviewRevision      = "review.update"
+	AuditActionMergeTag            = "tag.merge"
+	AuditActionUpdateTagParent     = "tag.parent.update"
+	AuditActionMergeQuestion       = "question.merge"
+	AuditActionRevertQuestionMerge = "question.merge.revert"
+)
+
+// audit log object types
+const (
+	AuditObjectUser     = "user"
+	AuditObjectPlugin   = "plugin"
+	AuditObjectSiteInfo = "site_info"
+	AuditObjectQuestion = "question"
+	AuditObjectAnswer   = "answer"
+	AuditObjectReport   = "report"
+	AuditObjectReview   = "review"
+	AuditObjectTag      = "tag"
+)
+
+// AddAuditLogReq add audit log request, only the changed fields of before and after are recorded
+type AddAuditLogReq struct {
+	UserID     string
+	IP         string
+	Action     string
+	ObjectType string
+	ObjectID   string
+	Before     any
+	After      any
+}
+
+// UserAuditState the state of user that is changed by admin
+type UserAuditState struct {
+	Status     int `json:"status"`
+	MailStatus int `json:"mail_status"`
+	RoleID     int `json:"role_id"`
+}
+
+// GetAuditLogPageReq get audit log page request
+type GetAuditLogPageReq struct {
+	// page
+	Page int `validate:"omitempty,min=1" form:"page"`
+	// page size
+	PageSize int `validate:"omitempty,min=1" form:"page_size"`
+	// the user who did the operation
+	UserID string `validate:"omitempty" form:"user_id"`
+	// action, such as user.status.update
+	Action string `validate:"omitempty,lte=64" form:"action"`
+	// object type, such as user, question
+	ObjectType string `validate:"omitempty,lte=32" form:"object_type"`
+	// object id
+	ObjectID string `validate:"omitempty,lte=64" form:"object_id"`
+	// start time, unix timestamp in seconds
+	StartTime int64 `validate:"omitempty,min=0" form:"start_time"`
+	// end time, unix timestamp in seconds
+	EndTime int64 `validate:"omitempty,min=0" form:"end_time"`
+}
+
+// AuditLogInfo audit log info
+type AuditLogInfo struct {
+	ID         string          `json:"id"`
+	CreatedAt  int64           `json:"created_at"`
+	UserID     string          `json:"user_id"`
+	UserInfo   *UserBasicInfo  `json:"user_info"`
+	Action     string          `json:"action"`
+	ObjectType string          `json:"object_type"`
+	ObjectID   string          `json:"object_id"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	IP         string          `json:"ip"`
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+import "github.com/apache/incubator-answer/internal/entity"
+
+// CronJobStatusMap cron job last execution status display name
+var CronJobStatusMap = map[int]string{
+	entity.CronJobStatusSuccess: "success",
+	entity.CronJobStatusFailed:  "failed",
+}
+
+// CronJobInfo cron job info
+type CronJobInfo struct {
+	// job name
+	Name string `json:"name"`
+	// cron expression
+	Spec string `json:"spec"`
+	// job description
+	Description string `json:"description"`
+	// paused jobs are not scheduled on any instance, but can be triggered manually
+	Paused bool `json:"paused"`
+	// the job is running on some instance
+	Running bool `json:"running"`
+	// last run time, 0 means never run
+	LastRunAt int64 `json:"last_run_at"`
+	// last run duration in milliseconds
+	LastDuration int64 `json:"last_duration"`
+	// last run status: success or failed, empty means never run
+	LastStatus string `json:"last_status"`
+	// last run error
+	LastError string `json:"last_error"`
+	// next scheduled time
+	NextRunAt int64 `json:"next_run_at"`
+}
+
+// TriggerCronJobReq trigger cron job request
+type TriggerCronJobReq struct {
+	// job name
+	Name string `validate:"required" json:"name"`
+}
+
+// UpdateCronJobStatusReq update cron job status request
+type UpdateCronJobStatusReq struct {
+	// job name
+	Name string `validate:"required" json:"name"`
+	// pause or resume the job
+	Paused bool `json:"paused"`
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+const (
+	DraftObjectTypeQuestion     = "question"
+	DraftObjectTypeAnswer       = "answer"
+	DraftObjectTypeQuestionEdit = "question_edit"
+	DraftObjectTypeAnswerEdit   = "answer_edit"
+
+	// DraftMaxCountPerUser the oldest drafts are removed when the user has more drafts
+	DraftMaxCountPerUser = 50
+	// DraftRetentionDays the drafts not updated in these days are removed
+	DraftRetentionDays = 30
+)
+
+// SaveDraftReq save draft request
+type SaveDraftReq struct {
+	ObjectType string `validate:"required,oneof=question answer question_edit answer_edit" json:"object_type"`
+	// ObjectID empty for new question, question id for new answer, post id for edit
+	ObjectID string     `validate:"omitempty" json:"object_id"`
+	Title    string     `validate:"omitempty,lte=150" json:"title"`
+	Content  string     `validate:"omitempty,lte=65535" json:"content"`
+	Tags     []*TagItem `validate:"omitempty,dive" json:"tags"`
+	UserID   string     `json:"-"`
+}
+
+// GetDraftReq get draft request
+type GetDraftReq struct {
+	ObjectType string `validate:"required,oneof=question answer question_edit answer_edit" form:"object_type"`
+	ObjectID   string `validate:"omitempty" form:"object_id"`
+	UserID     string `json:"-"`
+}
+
+// RemoveDraftReq remove draft request
+type RemoveDraftReq struct {
+	ObjectType string `validate:"required,oneof=question answer question_edit answer_edit" json:"object_type"`
+	ObjectID   string `validate:"omitempty" json:"object_id"`
+	UserID     string `json:"-"`
+}
+
+// GetDraftPageReq get draft page request
+type GetDraftPageReq struct {
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+	UserID   string `json:"-"`
+}
+
+// DraftInfo draft info
+type DraftInfo struct {
+	ID         string     `json:"id"`
+	ObjectType string     `json:"object_type"`
+	ObjectID   string     `json:"object_id"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Tags       []*TagItem `json:"tags"`
+	CreatedAt  int64      `json:"created_at"`
+	UpdatedAt  int64      `json:"updated_at"`
+}
@@ -22,6 +22,7 @@ package schema
 import (
 	"encoding/json"
 	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/entity"
 )
 
 const (
@@ -142,3 +143,48 @@ type NewQuestionTemplateData struct {
 	Tags           string
 	UnsubscribeUrl string
 }
+
+type DigestTemplateRawData struct {
+	Frequency       string
+	Questions       []*entity.Question
+	Answers         []*entity.Answer
+	QuestionTitles  map[string]string
+	UnreadCount     int64
+	UnsubscribeCode string
+}
+
+type DigestTemplateData struct {
+	SiteName       string
+	Frequency      string
+	Questions      []*DigestTemplateItem
+	Answers        []*DigestTemplateItem
+	UnreadCount    int64
+	InboxUrl       string
+	UnsubscribeUrl string
+}
+
+type DigestTemplateItem struct {
+	Title string
+	Url   string
+}
+
+type SavedSearchAlertTemplateRawData struct {
+	Name           string
+	Query          string
+	IncludeSubTags bool
+	Results        []*SearchResult
+}
+
+type SavedSearchAlertTemplateData struct {
+	SiteName  string
+	Name      string
+	Query     string
+	SearchUrl string
+	Questions []*SavedSearchAlertTemplateItem
+	Answers   []*SavedSearchAlertTemplateItem
+}
+
+type SavedSearchAlertTemplateItem struct {
+	Title string
+	Url   string
+}
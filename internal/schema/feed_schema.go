@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package schema
+
+import "time"
+
+const (
+	FeedFormatAtom = "atom"
+	FeedFormatRSS  = "rss"
+	// FeedMaxSize the max number of entries in a feed
+	FeedMaxSize = 30
+)
+
+// FeedTokenQueryKey the query key of the feed token, required when the site is private
+const FeedTokenQueryKey = "token"
+
+// FeedQuestionListReq feed of question list request
+type FeedQuestionListReq struct {
+	OrderCond string `validate:"omitempty,oneof=newest active" form:"order"`
+}
+
+// FeedSearchReq feed of search request
+type FeedSearchReq struct {
+	Query string `validate:"required,gte=1,lte=60" form:"q"`
+}
+
+// FeedInfo the feed channel
+type FeedInfo struct {
+	SiteName    string
+	Title       string
+	Description string
+	// Link the html page of this feed
+	Link string
+	// SelfLink the url of this feed
+	SelfLink  string
+	UpdatedAt time.Time
+	Items     []*FeedItem
+}
+
+// FeedItem the entry of feed
+type FeedItem struct {
+	ID        string
+	Title     string
+	Link      string
+	Author    string
+	Content   string
+	Tags      []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GetFeedTokenResp get feed token response
+type GetFeedTokenResp struct {
+	// Token append it to the feed url as query `token` to read the feeds of private site
+	Token string `json:"token"`
+}
+
+// FeedTitleTplData the template data of feed title translation
+type FeedTitleTplData struct {
+	TagName       string
+	DisplayName   string
+	QuestionTitle string
+	Query         string
+}
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+const (
+	PushEventTypeNotification = "notification"
+	PushEventTypeRedDot       = "red_dot"
+	PushEventTypeNewAnswer    = "new_answer"
+	PushEventTypeReview       = "review"
+)
+
+// PushEvent the event pushed to the online users.
+// The event is pushed to the user if UserID is set, or to the users viewing the question if QuestionID is set,
+// otherwise it is pushed to all online users.
+type PushEvent struct {
+	Type       string            `json:"type"`
+	UserID     string            `json:"user_id,omitempty"`
+	QuestionID string            `json:"question_id,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+	// Seq the sequence of the event, it is set when the event is received
+	Seq int64 `json:"-"`
+}
+
+// GetNotificationStreamReq get notification stream request
+type GetNotificationStreamReq struct {
+	// QuestionID the question being viewed, the new answers of it are pushed
+	QuestionID string `validate:"omitempty" form:"question_id"`
+	UserID     string `json:"-"`
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+import (
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+)
+
+const (
+	// BountyDuration the bounty is open for a fixed period, then it is auto awarded or expired
+	BountyDuration = 7 * 24 * time.Hour
+)
+
+var QuestionBountyStatusMap = map[int]string{
+	entity.QuestionBountyStatusActive:      "active",
+	entity.QuestionBountyStatusAwarded:     "awarded",
+	entity.QuestionBountyStatusAutoAwarded: "auto_awarded",
+	entity.QuestionBountyStatusExpired:     "expired",
+}
+
+// OfferBountyReq offer bounty request
+type OfferBountyReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	// Amount the reputation escrowed on the question
+	Amount int    `validate:"required,min=50,max=500" json:"amount"`
+	UserID string `json:"-"`
+}
+
+// AwardBountyReq award bounty request
+type AwardBountyReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	AnswerID   string `validate:"required" json:"answer_id"`
+	UserID     string `json:"-"`
+}
+
+// GetQuestionBountyReq get question bounty request
+type GetQuestionBountyReq struct {
+	QuestionID string `validate:"required" form:"question_id"`
+}
+
+// QuestionBountyInfo question bounty info
+type QuestionBountyInfo struct {
+	ID         string         `json:"id"`
+	QuestionID string         `json:"question_id"`
+	Amount     int            `json:"amount"`
+	Status     string         `json:"status"`
+	AnswerID   string         `json:"answer_id"`
+	CreatedAt  int64          `json:"created_at"`
+	ExpiredAt  int64          `json:"expired_at"`
+	AwardedAt  int64          `json:"awarded_at"`
+	UserInfo   *UserBasicInfo `json:"user_info"`
+}
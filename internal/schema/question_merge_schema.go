@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// MergeQuestionReq merge the duplicate question into the canonical question request
+type MergeQuestionReq struct {
+	// QuestionID the duplicate question which will be closed
+	QuestionID string `validate:"required" json:"question_id"`
+	// TargetQuestionID the canonical question which receives the answers
+	TargetQuestionID string `validate:"required" json:"target_question_id"`
+	// MoveComments whether to move the comments of the duplicate question too
+	MoveComments bool   `json:"move_comments"`
+	UserID       string `json:"-"`
+}
+
+// MergeQuestionResp merge question response
+type MergeQuestionResp struct {
+	QuestionID       string `json:"question_id"`
+	TargetQuestionID string `json:"target_question_id"`
+	AnswerCount      int    `json:"answer_count"`
+	CommentCount     int    `json:"comment_count"`
+}
+
+// RevertQuestionMergeReq revert question merge request
+type RevertQuestionMergeReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	UserID     string `json:"-"`
+}
@@ -347,6 +347,7 @@ const (
 	QuestionOrderCondScore      = "score"
 	QuestionOrderCondUnanswered = "unanswered"
 	QuestionOrderCondRecommend  = "recommend"
+	QuestionOrderCondBounty     = "bounty"
 
 	// HotInDays limit max days of the hottest question
 	HotInDays = 90
@@ -356,10 +357,12 @@ const (
 type QuestionPageReq struct {
 	Page      int    `validate:"omitempty,min=1" form:"page"`
 	PageSize  int    `validate:"omitempty,min=1" form:"page_size"`
-	OrderCond string `validate:"omitempty,oneof=newest active hot score unanswered recommend frequent" form:"order"`
+	OrderCond string `validate:"omitempty,oneof=newest active hot score unanswered recommend frequent bounty" form:"order"`
 	Tag       string `validate:"omitempty,gt=0,lte=100" form:"tag"`
 	Username  string `validate:"omitempty,gt=0,lte=100" form:"username"`
 	InDays    int    `validate:"omitempty,min=1" form:"in_days"`
+	// include the questions of sub tags when query by tag
+	IncludeSubTags bool `validate:"omitempty" form:"include_sub_tags"`
 
 	LoginUserID      string `json:"-"`
 	UserIDBeSearched string `json:"-"`
@@ -391,6 +394,8 @@ type QuestionPageResp struct {
 	AnswerCount     int `json:"answer_count"`
 	CollectionCount int `json:"collection_count"`
 	FollowCount     int `json:"follow_count"`
+	// BountyAmount the amount of the active bounty, 0 if the question has no active bounty
+	BountyAmount int `json:"bounty_amount"`
 
 	// answer information
 	AcceptedAnswerID   string    `json:"accepted_answer_id"`
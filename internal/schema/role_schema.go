@@ -24,4 +24,44 @@ type GetRoleResp struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// built-in roles can not be renamed or deleted
+	BuiltIn bool `json:"built_in"`
+}
+
+// GetPowerResp get power response
+type GetPowerResp struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	PowerType   string `json:"power_type"`
+	Description string `json:"description"`
+}
+
+// GetRolePowerReq get role power request
+type GetRolePowerReq struct {
+	RoleID int `validate:"required" form:"role_id"`
+}
+
+// AddRoleReq add role request
+type AddRoleReq struct {
+	Name        string   `validate:"required,notblank,lte=50" json:"name"`
+	Description string   `validate:"omitempty,lte=200" json:"description"`
+	PowerTypes  []string `validate:"omitempty" json:"power_types"`
+}
+
+// AddRoleResp add role response
+type AddRoleResp struct {
+	ID int `json:"id"`
+}
+
+// UpdateRoleReq update role request, the name and description of built-in roles are ignored
+type UpdateRoleReq struct {
+	RoleID      int      `validate:"required" json:"role_id"`
+	Name        string   `validate:"required,notblank,lte=50" json:"name"`
+	Description string   `validate:"omitempty,lte=200" json:"description"`
+	PowerTypes  []string `validate:"omitempty" json:"power_types"`
+}
+
+// DeleteRoleReq delete role request
+type DeleteRoleReq struct {
+	RoleID int `validate:"required" json:"role_id"`
 }
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+const (
+	SavedSearchAlertChannelInbox  = "inbox"
+	SavedSearchAlertChannelEmail  = "email"
+	SavedSearchAlertChannelPlugin = "plugin"
+
+	// SavedSearchMaxCountPerUser the max count of saved searches of one user
+	SavedSearchMaxCountPerUser = 20
+	// SavedSearchAlertItemLimit the max count of new contents in one alert
+	SavedSearchAlertItemLimit = 10
+)
+
+// AddSavedSearchReq add saved search request
+type AddSavedSearchReq struct {
+	// saved search name
+	Name string `validate:"required,notblank,lte=100" json:"name"`
+	// search query, such as "[tag] isaccepted:no"
+	Query string `validate:"required,notblank,lte=60" json:"query"`
+	// the [tag] in query also matches the sub tags
+	IncludeSubTags bool `json:"include_sub_tags"`
+	// alert the new contents matched by the query
+	Alert bool `json:"alert"`
+	// the channels to deliver the alert, required if alert is enabled
+	AlertChannels []string `validate:"omitempty,unique,dive,oneof=inbox email plugin" json:"alert_channels"`
+	UserID        string   `json:"-"`
+}
+
+// UpdateSavedSearchReq update saved search request
+type UpdateSavedSearchReq struct {
+	// saved search id
+	ID string `validate:"required" json:"id"`
+	// saved search name
+	Name string `validate:"required,notblank,lte=100" json:"name"`
+	// search query, such as "[tag] isaccepted:no"
+	Query string `validate:"required,notblank,lte=60" json:"query"`
+	// the [tag] in query also matches the sub tags
+	IncludeSubTags bool `json:"include_sub_tags"`
+	// alert the new contents matched by the query
+	Alert bool `json:"alert"`
+	// the channels to deliver the alert, required if alert is enabled
+	AlertChannels []string `validate:"omitempty,unique,dive,oneof=inbox email plugin" json:"alert_channels"`
+	UserID        string   `json:"-"`
+}
+
+// RemoveSavedSearchReq remove saved search request
+type RemoveSavedSearchReq struct {
+	// saved search id
+	ID     string `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
+
+// SavedSearchInfo saved search info
+type SavedSearchInfo struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Query          string   `json:"query"`
+	IncludeSubTags bool     `json:"include_sub_tags"`
+	Alert          bool     `json:"alert"`
+	AlertChannels  []string `json:"alert_channels"`
+	// the last time the new contents are evaluated, 0 if never
+	LastAlertAt int64 `json:"last_alert_at"`
+	CreatedAt   int64 `json:"created_at"`
+	UpdatedAt   int64 `json:"updated_at"`
+}
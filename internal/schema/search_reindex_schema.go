@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+const (
+	// SearchReindexDefaultBatchSize the default count of contents sent to the search plugin in a batch
+	SearchReindexDefaultBatchSize = 100
+	// SearchReindexDriftSampleSize the max count of object ids of the drifted contents in the report
+	SearchReindexDriftSampleSize = 20
+)
+
+// SearchReindexReq search reindex request
+type SearchReindexReq struct {
+	// only compare the index with database, nothing is sent to the search plugin
+	DryRun bool `json:"dry_run"`
+	// start from the beginning instead of the checkpoint of last unfinished reindexing
+	Restart bool `json:"restart"`
+	// the count of contents in a batch, default is 100
+	BatchSize int `validate:"omitempty,min=1,max=1000" json:"batch_size"`
+}
+
+// SearchReindexResp the progress of reindexing
+type SearchReindexResp struct {
+	// the slug name of the search plugin
+	PluginSlugName string `json:"plugin_slug_name"`
+	// running, finished or failed, empty means never run
+	Status string `json:"status"`
+	DryRun bool   `json:"dry_run"`
+	// the object type being reindexed: question or answer
+	ObjectType string `json:"object_type"`
+	// the checkpoint, the contents after it are not reindexed yet
+	LastObjectID string `json:"last_object_id"`
+	// the total count of questions and answers
+	Total     int64 `json:"total"`
+	Processed int64 `json:"processed"`
+	// the count of contents sent by UpdateContent
+	Updated int64 `json:"updated"`
+	// the count of contents removed by DeleteContent
+	Deleted int64 `json:"deleted"`
+	Failed  int64 `json:"failed"`
+	// the drift between index and database, only for dry-run
+	Drift *SearchIndexDrift `json:"drift,omitempty"`
+	Error string            `json:"error"`
+	// start time
+	StartedAt int64 `json:"started_at"`
+	// last update time
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// SearchIndexDrift the drift between the index of search plugin and database
+type SearchIndexDrift struct {
+	// whether the search plugin supports reading the index, the drift is unknown if not
+	Supported bool `json:"supported"`
+	// the contents should be indexed but not in the index
+	Missing int64 `json:"missing"`
+	// the contents in the index are different from database
+	Stale int64 `json:"stale"`
+	// the deleted contents are still in the index
+	Orphaned int64 `json:"orphaned"`
+	// the object ids of some drifted contents
+	Samples []string `json:"samples"`
+}
@@ -35,7 +35,11 @@ type SearchDTO struct {
 	Order       string `validate:"required,oneof=newest active score relevance" form:"order,default=relevance" enums:"newest,active,score,relevance"`
 	CaptchaID   string `form:"captcha_id"`
 	CaptchaCode string `form:"captcha_code"`
-	UserID      string `json:"-"`
+	// the [tag] in query also matches the sub tags
+	IncludeSubTags bool `form:"include_sub_tags"`
+	// return the facets of all matched contents
+	Facets bool   `form:"facets"`
+	UserID string `json:"-"`
 }
 
 func (s *SearchDTO) Check() (errField []*validator.FormErrorField, err error) {
@@ -181,6 +185,44 @@ type SearchResp struct {
 	Total int64 `json:"count"`
 	// search response
 	SearchResults []*SearchResult `json:"list"`
+	// search facets, only returned when requested
+	Facets *SearchFacets `json:"facets,omitempty"`
+}
+
+// SearchFacets the counts of all matched contents grouped by facets
+type SearchFacets struct {
+	// top tags
+	Tags []*SearchTagFacet `json:"tags"`
+	// question and answer
+	Types []*SearchFacet `json:"types"`
+	// accepted answer and not accepted question
+	Accepted []*SearchFacet `json:"accepted"`
+	// past day, week, month and year
+	Dates []*SearchFacet `json:"dates"`
+	// top authors
+	Authors []*SearchAuthorFacet `json:"authors"`
+}
+
+type SearchFacet struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+	// the query to filter the search by this facet, empty if not supported
+	Query string `json:"query"`
+}
+
+type SearchTagFacet struct {
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
+	Count       int64  `json:"count"`
+	Query       string `json:"query"`
+}
+
+type SearchAuthorFacet struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Avatar      string `json:"avatar"`
+	Count       int64  `json:"count"`
+	Query       string `json:"query"`
 }
 
 type SearchDescResp struct {
@@ -81,7 +81,9 @@ type SiteWriteReq struct {
 	MaxImageMegapixel              int             `validate:"omitempty,gt=0" json:"max_image_megapixel"`
 	AuthorizedImageExtensions      []string        `validate:"omitempty" json:"authorized_image_extensions"`
 	AuthorizedAttachmentExtensions []string        `validate:"omitempty" json:"authorized_attachment_extensions"`
-	UserID                         string          `json:"-"`
+	// ImporterBypassRankCheck allows the importer plugins to import objects without checking the rank of the users
+	ImporterBypassRankCheck bool   `validate:"omitempty" json:"importer_bypass_rank_check"`
+	UserID                  string `json:"-"`
 }
 
 func (s *SiteWriteResp) GetMaxImageSize() int64 {
@@ -159,6 +161,8 @@ type SiteLoginReq struct {
 	AllowPasswordLogin      bool     `json:"allow_password_login"`
 	LoginRequired           bool     `json:"login_required"`
 	AllowEmailDomains       []string `json:"allow_email_domains"`
+	// RequireStaffTwoFactor admins and moderators must use two-factor authentication to log in with password
+	RequireStaffTwoFactor bool `json:"require_staff_two_factor"`
 }
 
 // SiteCustomCssHTMLReq site custom css html
@@ -385,6 +389,7 @@ var (
 		constant.RankTagAuditKey:                  {1, 2500, 5000},
 		constant.RankTagEditWithoutReviewKey:      {1, 10000, 20000},
 		constant.RankTagSynonymKey:                {1, 10000, 20000},
+		constant.RankQuestionBountyKey:            {1, 75, 75},
 	}
 )
 
@@ -87,6 +87,15 @@ type GetTagResp struct {
 	MainTagSlugName string `json:"main_tag_slug_name"`
 	Recommend       bool   `json:"recommend"`
 	Reserved        bool   `json:"reserved"`
+	// the parent tags from the root to the direct parent
+	ParentTags []*TagPathItem `json:"parent_tags"`
+}
+
+// TagPathItem tag item in the path of tag hierarchy
+type TagPathItem struct {
+	TagID       string `json:"tag_id"`
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
 }
 
 func (tr *GetTagResp) GetExcerpt() {
@@ -284,6 +293,49 @@ func (req *UpdateTagSynonymReq) Format() {
 	}
 }
 
+// UpdateTagParentReq update the parent of tag request
+type UpdateTagParentReq struct {
+	// tag id
+	TagID string `validate:"required" json:"tag_id"`
+	// parent tag id, empty means the tag is a root tag
+	ParentTagID string `validate:"omitempty" json:"parent_tag_id"`
+	// user id
+	UserID string `json:"-"`
+}
+
+// GetTagTreeReq get tag tree request
+type GetTagTreeReq struct {
+	// parent tag id, empty means get the root tags
+	TagID string `validate:"omitempty" form:"tag_id"`
+	// page
+	Page int `validate:"omitempty,min=1" form:"page"`
+	// page size
+	PageSize int `validate:"omitempty,min=1,max=100" form:"page_size"`
+}
+
+// GetTagTreeResp the sub tag in tag tree
+type GetTagTreeResp struct {
+	TagID         string `json:"tag_id"`
+	SlugName      string `json:"slug_name"`
+	DisplayName   string `json:"display_name"`
+	FollowCount   int    `json:"follow_count"`
+	QuestionCount int    `json:"question_count"`
+	// the amount of direct sub tags
+	SubTagCount int `json:"sub_tag_count"`
+}
+
+// MergeTagReq merge tag request
+type MergeTagReq struct {
+	// the tag whose questions and followers are moved
+	SourceTagID string `validate:"required" json:"source_tag_id"`
+	// the tag that source tag is merged into
+	TargetTagID string `validate:"required" json:"target_tag_id"`
+	// if true, the source tag is kept as a synonym of target tag, otherwise it is deleted
+	KeepAsSynonym bool `json:"keep_as_synonym"`
+	// user id
+	UserID string `json:"-"`
+}
+
 // GetFollowingTagsResp get following tags response
 type GetFollowingTagsResp struct {
 	// tag id
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// GetTwoFactorStatusReq get two factor status request
+type GetTwoFactorStatusReq struct {
+	UserID string `json:"-"`
+}
+
+// GetTwoFactorStatusResp get two factor status response
+type GetTwoFactorStatusResp struct {
+	Enabled                bool `json:"enabled"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollReq set up two factor request
+type TwoFactorEnrollReq struct {
+	UserID string `json:"-"`
+}
+
+// TwoFactorEnrollResp set up two factor response, the key uri is used to generate the QR code
+type TwoFactorEnrollResp struct {
+	Secret string `json:"secret"`
+	KeyURI string `json:"key_uri"`
+}
+
+// TwoFactorEnableReq enable two factor request
+type TwoFactorEnableReq struct {
+	Code   string `validate:"required,len=6" json:"code"`
+	UserID string `json:"-"`
+}
+
+// TwoFactorCodeReq the request that must be confirmed with the code of authenticator or a recovery code
+type TwoFactorCodeReq struct {
+	Code   string `validate:"required,notblank,lte=32" json:"code"`
+	UserID string `json:"-"`
+}
+
+// TwoFactorRecoveryCodesResp recovery codes response, the codes are only shown once
+type TwoFactorRecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// UserTwoFactorLoginReq the second step of login
+type UserTwoFactorLoginReq struct {
+	Token string `validate:"required,notblank" json:"token"`
+	Code  string `validate:"required,notblank,lte=32" json:"code"`
+}
+
+// TwoFactorChallengeEnrollReq set up two factor for the pending login
+type TwoFactorChallengeEnrollReq struct {
+	Token string `validate:"required,notblank" json:"token"`
+}
+
+// ResetUserTwoFactorReq admin reset the two factor of user
+type ResetUserTwoFactorReq struct {
+	UserID string `validate:"required" json:"user_id"`
+}
+
+// TwoFactorChallenge the pending login waiting for the second step
+type TwoFactorChallenge struct {
+	UserID     string `json:"user_id"`
+	ExternalID string `json:"external_id"`
+	// Enroll the user must set up two-factor authentication with the pending secret
+	Enroll bool `json:"enroll"`
+}
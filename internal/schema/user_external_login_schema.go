@@ -23,6 +23,8 @@ package schema
 type UserExternalLoginResp struct {
 	BindingKey  string `json:"binding_key"`
 	AccessToken string `json:"access_token"`
+	// TwoFactorToken the login must be completed with two factor, no access token is issued until then
+	TwoFactorToken string `json:"two_factor_token"`
 	// ErrMsg error message, if not empty, means login failed and this message should be displayed.
 	ErrMsg   string `json:"-"`
 	ErrTitle string `json:"-"`
@@ -25,9 +25,16 @@ import (
 	"github.com/apache/incubator-answer/internal/entity"
 )
 
+const (
+	NotificationDigestDaily  = "daily"
+	NotificationDigestWeekly = "weekly"
+)
+
 type NotificationChannelConfig struct {
 	Key    constant.NotificationChannelKey `json:"key"`
 	Enable bool                            `json:"enable"`
+	// Frequency only used by digest, daily or weekly
+	Frequency string `validate:"omitempty,oneof=daily weekly" json:"frequency,omitempty"`
 }
 
 type NotificationChannels []*NotificationChannelConfig
@@ -56,6 +63,7 @@ type NotificationConfig struct {
 	Inbox                          NotificationChannelConfig `json:"inbox"`
 	AllNewQuestion                 NotificationChannelConfig `json:"all_new_question"`
 	AllNewQuestionForFollowingTags NotificationChannelConfig `json:"all_new_question_for_following_tags"`
+	Digest                         NotificationChannelConfig `json:"digest"`
 }
 
 func NewNotificationConfig(configs []*entity.UserNotificationConfig) NotificationConfig {
@@ -68,6 +76,8 @@ func NewNotificationConfig(configs []*entity.UserNotificationConfig) Notificatio
 			nc.AllNewQuestion = NewNotificationChannelConfigFormJson(item.Channels)
 		case string(constant.AllNewQuestionForFollowingTagsSource):
 			nc.AllNewQuestionForFollowingTags = NewNotificationChannelConfigFormJson(item.Channels)
+		case string(constant.DigestSource):
+			nc.Digest = NewNotificationChannelConfigFormJson(item.Channels)
 		}
 	}
 	return nc
@@ -86,6 +96,13 @@ func (n *NotificationConfig) Format() {
 		n.AllNewQuestionForFollowingTags.Key = constant.EmailChannel
 		n.AllNewQuestionForFollowingTags.Enable = false
 	}
+	if n.Digest.Key == "" {
+		n.Digest.Key = constant.EmailChannel
+		n.Digest.Enable = false
+	}
+	if n.Digest.Frequency == "" {
+		n.Digest.Frequency = NotificationDigestDaily
+	}
 }
 
 // UpdateUserNotificationConfigReq update user notification config request
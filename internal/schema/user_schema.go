@@ -96,6 +96,14 @@ type UserLoginResp struct {
 	HavePassword bool `json:"have_password"`
 	// visit token
 	VisitToken string `json:"visit_token"`
+	// the second step of login is required, no access token is issued until it is completed
+	TwoFactorRequired bool `json:"two_factor_required,omitempty"`
+	// two factor token used to complete the login
+	TwoFactorToken string `json:"two_factor_token,omitempty"`
+	// the user must set up two-factor authentication to complete the login
+	TwoFactorEnroll *TwoFactorEnrollResp `json:"two_factor_enroll,omitempty"`
+	// recovery codes generated when two-factor authentication is set up during login
+	TwoFactorRecoveryCodes []string `json:"two_factor_recovery_codes,omitempty"`
 }
 
 func (r *UserLoginResp) ConvertFromUserEntity(userInfo *entity.User) {
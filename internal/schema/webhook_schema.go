@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+import (
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/entity"
+)
+
+const (
+	WebhookStatusActive   = "active"
+	WebhookStatusInactive = "inactive"
+)
+
+var WebhookStatusMap = map[int]string{
+	entity.WebhookStatusActive:   WebhookStatusActive,
+	entity.WebhookStatusInactive: WebhookStatusInactive,
+}
+
+var WebhookStatusEMap = map[string]int{
+	WebhookStatusActive:   entity.WebhookStatusActive,
+	WebhookStatusInactive: entity.WebhookStatusInactive,
+}
+
+var WebhookDeliveryStatusMap = map[int]string{
+	entity.WebhookDeliveryStatusPending:  "pending",
+	entity.WebhookDeliveryStatusSuccess:  "success",
+	entity.WebhookDeliveryStatusRetrying: "retrying",
+	entity.WebhookDeliveryStatusFailed:   "failed",
+}
+
+// WebhookInfo webhook info
+type WebhookInfo struct {
+	// webhook id
+	ID string `json:"id"`
+	// webhook name
+	Name string `json:"name"`
+	// the url that receives the payload
+	URL string `json:"url"`
+	// whether the secret is set
+	HasSecret bool `json:"has_secret"`
+	// subscribed event types
+	Events []string `json:"events"`
+	// webhook status
+	Status string `json:"status"`
+	// created time
+	CreatedAt int64 `json:"created_at"`
+}
+
+// AddWebhookReq add webhook request
+type AddWebhookReq struct {
+	// webhook name
+	Name string `validate:"omitempty,lte=100" json:"name"`
+	// the url that receives the payload
+	URL string `validate:"required,url,lte=1024" json:"url"`
+	// secret used to sign the payload
+	Secret string `validate:"omitempty,lte=255" json:"secret"`
+	// subscribed event types
+	Events []string `validate:"required,gt=0,dive,required" json:"events"`
+}
+
+// AddWebhookResp add webhook response
+type AddWebhookResp struct {
+	// webhook id
+	ID string `json:"id"`
+	// secret used to sign the payload, it will be only returned once
+	Secret string `json:"secret"`
+}
+
+// UpdateWebhookReq update webhook request
+type UpdateWebhookReq struct {
+	// webhook id
+	ID string `validate:"required" json:"id"`
+	// webhook name
+	Name string `validate:"omitempty,lte=100" json:"name"`
+	// the url that receives the payload
+	URL string `validate:"required,url,lte=1024" json:"url"`
+	// if set, the secret will be replaced
+	Secret string `validate:"omitempty,lte=255" json:"secret"`
+	// subscribed event types
+	Events []string `validate:"required,gt=0,dive,required" json:"events"`
+	// webhook status
+	Status string `validate:"required,oneof=active inactive" json:"status"`
+}
+
+// DeleteWebhookReq delete webhook request
+type DeleteWebhookReq struct {
+	// webhook id
+	ID string `validate:"required" json:"id"`
+}
+
+// GetWebhookDeliveryPageReq get webhook delivery page request
+type GetWebhookDeliveryPageReq struct {
+	// page
+	Page int `validate:"omitempty,min=1" form:"page"`
+	// page size
+	PageSize int `validate:"omitempty,min=1" form:"page_size"`
+	// webhook id
+	WebhookID string `validate:"required" form:"webhook_id"`
+}
+
+// WebhookDeliveryInfo webhook delivery info
+type WebhookDeliveryInfo struct {
+	// delivery id
+	ID string `json:"id"`
+	// webhook id
+	WebhookID string `json:"webhook_id"`
+	// event type
+	EventType string `json:"event_type"`
+	// request payload
+	Payload string `json:"payload"`
+	// delivery status
+	Status string `json:"status"`
+	// attempts count
+	Attempts int `json:"attempts"`
+	// the http status code of the last response
+	ResponseCode int `json:"response_code"`
+	// the body of the last response
+	ResponseBody string `json:"response_body"`
+	// the last error
+	LastError string `json:"last_error"`
+	// created time
+	CreatedAt int64 `json:"created_at"`
+	// next retry time
+	NextRetryAt int64 `json:"next_retry_at"`
+	// delivered time
+	DeliveredAt int64 `json:"delivered_at"`
+}
+
+// RedeliverWebhookReq redeliver webhook request
+type RedeliverWebhookReq struct {
+	// delivery id
+	DeliveryID string `validate:"required" json:"delivery_id"`
+}
+
+// WebhookPayload the payload sent to the webhook url
+type WebhookPayload struct {
+	Event     constant.EventType  `json:"event"`
+	Timestamp int64               `json:"timestamp"`
+	Data      *WebhookPayloadData `json:"data"`
+}
+
+// WebhookPayloadData the event data of the webhook payload
+type WebhookPayloadData struct {
+	UserID          string            `json:"user_id,omitempty"`
+	TriggerObjectID string            `json:"trigger_object_id,omitempty"`
+	QuestionID      string            `json:"question_id,omitempty"`
+	QuestionUserID  string            `json:"question_user_id,omitempty"`
+	AnswerID        string            `json:"answer_id,omitempty"`
+	AnswerUserID    string            `json:"answer_user_id,omitempty"`
+	CommentID       string            `json:"comment_id,omitempty"`
+	CommentUserID   string            `json:"comment_user_id,omitempty"`
+	ExtraInfo       map[string]string `json:"extra_info,omitempty"`
+}
+
+// WebhookDeliveryMsg webhook delivery queue message
+type WebhookDeliveryMsg struct {
+	DeliveryID string `json:"delivery_id"`
+}
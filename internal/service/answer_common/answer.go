@@ -51,6 +51,7 @@ type AnswerRepo interface {
 	GetAnswerCount(ctx context.Context) (count int64, err error)
 	RemoveAllUserAnswer(ctx context.Context, userID string) (err error)
 	SumVotesByQuestionID(ctx context.Context, questionID string) (float64, error)
+	UpdateSearch(ctx context.Context, answerID string) (err error)
 }
 
 // AnswerCommon user service
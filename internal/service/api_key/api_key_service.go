@@ -0,0 +1,271 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api_key
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/permission"
+	"github.com/apache/incubator-answer/internal/service/role"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// maxAPIKeysPerUser the max number of available api keys for one user
+	maxAPIKeysPerUser = 20
+	// lastUsedUpdateInterval avoid updating the last used time for every request
+	lastUsedUpdateInterval = time.Minute
+	tokenPrefixLength      = 12
+)
+
+// APIKeyRepo api key repository
+type APIKeyRepo interface {
+	AddAPIKey(ctx context.Context, apiKey *entity.APIKey) (err error)
+	GetAPIKey(ctx context.Context, id string) (apiKey *entity.APIKey, exist bool, err error)
+	GetAPIKeyByTokenHash(ctx context.Context, tokenHash string) (apiKey *entity.APIKey, exist bool, err error)
+	GetUserAPIKeyList(ctx context.Context, userID string) (apiKeys []*entity.APIKey, err error)
+	GetAPIKeyPage(ctx context.Context, page, pageSize int, userID string) (apiKeys []*entity.APIKey, total int64, err error)
+	CountUserAvailableAPIKeys(ctx context.Context, userID string) (count int64, err error)
+	UpdateAPIKeyStatus(ctx context.Context, id string, status int) (err error)
+	UpdateLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) (err error)
+}
+
+// APIKeyService api key service
+type APIKeyService struct {
+	apiKeyRepo          APIKeyRepo
+	userRepo            usercommon.UserRepo
+	userRoleService     *role.UserRoleRelService
+	rolePowerRelService *role.RolePowerRelService
+}
+
+// NewAPIKeyService new api key service
+func NewAPIKeyService(
+	apiKeyRepo APIKeyRepo,
+	userRepo usercommon.UserRepo,
+	userRoleService *role.UserRoleRelService,
+	rolePowerRelService *role.RolePowerRelService,
+) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:          apiKeyRepo,
+		userRepo:            userRepo,
+		userRoleService:     userRoleService,
+		rolePowerRelService: rolePowerRelService,
+	}
+}
+
+// IsAPIKeyToken check the token is api key token or not
+func IsAPIKeyToken(token string) bool {
+	return strings.HasPrefix(token, schema.APIKeyTokenPrefix)
+}
+
+// AddAPIKey add api key for user, the admin scope can only be granted to admin user
+func (as *APIKeyService) AddAPIKey(ctx context.Context, req *schema.AddAPIKeyReq) (
+	resp *schema.AddAPIKeyResp, err error) {
+	userInfo, exist, err := as.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	for _, scope := range req.Scopes {
+		if scope != schema.APIKeyScopeAdmin {
+			continue
+		}
+		isAdmin, err := as.rolePowerRelService.UserHasPower(ctx, req.UserID, permission.AdminAccess)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin {
+			return nil, errors.BadRequest(reason.APIKeyScopeNotAllowed)
+		}
+	}
+	if req.ExpiredAt > 0 && req.ExpiredAt <= time.Now().Unix() {
+		return nil, errors.BadRequest(reason.APIKeyExpiredTimeInvalid)
+	}
+
+	count, err := as.apiKeyRepo.CountUserAvailableAPIKeys(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAPIKeysPerUser {
+		return nil, errors.BadRequest(reason.APIKeyLimitExceeded)
+	}
+
+	token := generateToken()
+	apiKey := &entity.APIKey{
+		UserID:        req.UserID,
+		CreatorUserID: req.CreatorUserID,
+		Name:          req.Name,
+		TokenHash:     hashToken(token),
+		TokenPrefix:   token[:tokenPrefixLength],
+		Scopes:        strings.Join(req.Scopes, ","),
+		Status:        entity.APIKeyStatusAvailable,
+	}
+	if req.ExpiredAt > 0 {
+		apiKey.ExpiredAt = time.Unix(req.ExpiredAt, 0)
+	}
+	if err = as.apiKeyRepo.AddAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+	return &schema.AddAPIKeyResp{APIKeyInfo: formatAPIKeyInfo(apiKey), Token: token}, nil
+}
+
+// GetUserAPIKeyList get all api keys of the user
+func (as *APIKeyService) GetUserAPIKeyList(ctx context.Context, userID string) (resp []*schema.APIKeyInfo, err error) {
+	apiKeys, err := as.apiKeyRepo.GetUserAPIKeyList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.APIKeyInfo, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		resp = append(resp, formatAPIKeyInfo(apiKey))
+	}
+	return resp, nil
+}
+
+// GetAPIKeyPage get api key page for admin
+func (as *APIKeyService) GetAPIKeyPage(ctx context.Context, req *schema.GetAPIKeyPageReq) (
+	resp []*schema.APIKeyInfo, total int64, err error) {
+	apiKeys, total, err := as.apiKeyRepo.GetAPIKeyPage(ctx, req.Page, req.PageSize, req.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp = make([]*schema.APIKeyInfo, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		resp = append(resp, formatAPIKeyInfo(apiKey))
+	}
+	return resp, total, nil
+}
+
+// RevokeAPIKey revoke api key, the user can only revoke their own api key
+func (as *APIKeyService) RevokeAPIKey(ctx context.Context, req *schema.RevokeAPIKeyReq) (err error) {
+	apiKey, exist, err := as.apiKeyRepo.GetAPIKey(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist || (len(req.UserID) > 0 && apiKey.UserID != req.UserID) {
+		return errors.BadRequest(reason.APIKeyNotFound)
+	}
+	return as.apiKeyRepo.UpdateAPIKeyStatus(ctx, apiKey.ID, entity.APIKeyStatusRevoked)
+}
+
+// GetUserCacheInfo get user info by api key token.
+// It returns nil if the token is invalid, revoked or expired.
+func (as *APIKeyService) GetUserCacheInfo(ctx context.Context, token string) (
+	userInfo *entity.UserCacheInfo, apiKey *entity.APIKey, err error) {
+	apiKey, exist, err := as.apiKeyRepo.GetAPIKeyByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist || apiKey.Status != entity.APIKeyStatusAvailable || apiKey.IsExpired() {
+		return nil, nil, nil
+	}
+	user, exist, err := as.userRepo.GetByUserID(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist {
+		return nil, nil, nil
+	}
+	roleID, err := as.userRoleService.GetUserRole(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if time.Since(apiKey.LastUsedAt) > lastUsedUpdateInterval {
+		if err := as.apiKeyRepo.UpdateLastUsedAt(ctx, apiKey.ID, time.Now()); err != nil {
+			log.Error(err)
+		}
+	}
+	return &entity.UserCacheInfo{
+		UserID:      user.ID,
+		UserStatus:  user.Status,
+		EmailStatus: user.MailStatus,
+		RoleID:      roleID,
+	}, apiKey, nil
+}
+
+// RequiredScope returns the scope required by the request.
+// Only the question and answer apis can be written by api key, empty means the request is not allowed.
+// The api keys can only be managed with a login session, so that a leaked key can not list or create keys.
+func RequiredScope(method, fullPath string) string {
+	if strings.HasPrefix(fullPath, "/answer/admin/api/api-key") {
+		return ""
+	}
+	if strings.HasPrefix(fullPath, "/answer/admin/api/") {
+		return schema.APIKeyScopeAdmin
+	}
+	path := strings.TrimPrefix(fullPath, "/answer/api/v1")
+	if path == "/user/api-key" || path == "/user/api-keys" {
+		return ""
+	}
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return schema.APIKeyScopeRead
+	}
+	switch {
+	case path == "/question" || strings.HasPrefix(path, "/question/"):
+		return schema.APIKeyScopeWriteQuestion
+	case path == "/answer" || strings.HasPrefix(path, "/answer/"):
+		return schema.APIKeyScopeWriteAnswer
+	}
+	return ""
+}
+
+func formatAPIKeyInfo(apiKey *entity.APIKey) *schema.APIKeyInfo {
+	info := &schema.APIKeyInfo{
+		ID:          apiKey.ID,
+		UserID:      apiKey.UserID,
+		Name:        apiKey.Name,
+		TokenPrefix: apiKey.TokenPrefix,
+		Scopes:      apiKey.GetScopes(),
+		Status:      schema.APIKeyStatusMap[apiKey.Status],
+		CreatedAt:   apiKey.CreatedAt.Unix(),
+	}
+	if !apiKey.ExpiredAt.IsZero() {
+		info.ExpiredAt = apiKey.ExpiredAt.Unix()
+	}
+	if !apiKey.LastUsedAt.IsZero() {
+		info.LastUsedAt = apiKey.LastUsedAt.Unix()
+	}
+	return info
+}
+
+func generateToken() string {
+	bytes := make([]byte, 20)
+	_, _ = rand.Read(bytes)
+	return schema.APIKeyTokenPrefix + hex.EncodeToString(bytes)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api_key
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/answer/api/v1/question/info", schema.APIKeyScopeRead},
+		{"POST", "/answer/api/v1/question", schema.APIKeyScopeWriteQuestion},
+		{"PUT", "/answer/api/v1/answer", schema.APIKeyScopeWriteAnswer},
+		{"POST", "/answer/api/v1/user/info", ""},
+		{"GET", "/answer/admin/api/users/page", schema.APIKeyScopeAdmin},
+		// api keys can not be managed by api key
+		{"GET", "/answer/admin/api/api-keys/page", ""},
+		{"POST", "/answer/admin/api/api-keys", ""},
+		{"DELETE", "/answer/admin/api/api-keys", ""},
+		{"GET", "/answer/api/v1/user/api-keys", ""},
+		{"POST", "/answer/api/v1/user/api-key", ""},
+		{"DELETE", "/answer/api/v1/user/api-key", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RequiredScope(tt.method, tt.path), tt.method+" "+tt.path)
+	}
+}
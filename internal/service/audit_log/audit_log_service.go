@@ -0,0 +1,225 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package audit_log
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/log"
+)
+
+// sensitiveFieldKeywords the value of field whose name contains these keywords is never recorded
+var sensitiveFieldKeywords = []string{"password", "secret", "token"}
+
+const maskedValue = "******"
+
+// AuditLogRepo audit log repository, the log is append-only so there is no update or delete
+type AuditLogRepo interface {
+	AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) (err error)
+	GetAuditLogPage(ctx context.Context, req *schema.GetAuditLogPageReq) (
+		auditLogs []*entity.AuditLog, total int64, err error)
+	ForEachAuditLog(ctx context.Context, req *schema.GetAuditLogPageReq, fn func(auditLog *entity.AuditLog) error) (err error)
+}
+
+// AuditLogService audit log service
+type AuditLogService struct {
+	auditLogRepo AuditLogRepo
+	userCommon   *usercommon.UserCommon
+}
+
+// NewAuditLogService new audit log service
+func NewAuditLogService(
+	auditLogRepo AuditLogRepo,
+	userCommon *usercommon.UserCommon,
+) *AuditLogService {
+	return &AuditLogService{
+		auditLogRepo: auditLogRepo,
+		userCommon:   userCommon,
+	}
+}
+
+// AddAuditLog record the operation. The operation is already done, so the error is only logged.
+func (as *AuditLogService) AddAuditLog(ctx context.Context, req *schema.AddAuditLogReq) {
+	before, after := diffAuditValue(req.Before, req.After)
+	auditLog := &entity.AuditLog{
+		UserID:     req.UserID,
+		Action:     req.Action,
+		ObjectType: req.ObjectType,
+		ObjectID:   req.ObjectID,
+		Before:     before,
+		After:      after,
+		IP:         req.IP,
+	}
+	if err := as.auditLogRepo.AddAuditLog(ctx, auditLog); err != nil {
+		log.Errorf("add audit log %s of %s %s failed: %v", req.Action, req.ObjectType, req.ObjectID, err)
+	}
+}
+
+// GetAuditLogPage get audit log page
+func (as *AuditLogService) GetAuditLogPage(ctx context.Context, req *schema.GetAuditLogPageReq) (
+	pageModel *pager.PageModel, err error) {
+	auditLogs, total, err := as.auditLogRepo.GetAuditLogPage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		userIDs = append(userIDs, auditLog.UserID)
+	}
+	userInfoMapping, err := as.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*schema.AuditLogInfo, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		resp = append(resp, &schema.AuditLogInfo{
+			ID:         auditLog.ID,
+			CreatedAt:  auditLog.CreatedAt.Unix(),
+			UserID:     auditLog.UserID,
+			UserInfo:   userInfoMapping[auditLog.UserID],
+			Action:     auditLog.Action,
+			ObjectType: auditLog.ObjectType,
+			ObjectID:   auditLog.ObjectID,
+			Before:     json.RawMessage(auditLog.Before),
+			After:      json.RawMessage(auditLog.After),
+			IP:         auditLog.IP,
+		})
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// ExportAuditLog write all audit logs that match the filter as csv
+func (as *AuditLogService) ExportAuditLog(ctx context.Context, req *schema.GetAuditLogPageReq, w io.Writer) (err error) {
+	writer := csv.NewWriter(w)
+	err = writer.Write([]string{
+		"id", "created_at", "user_id", "username", "action", "object_type", "object_id", "before", "after", "ip"})
+	if err != nil {
+		return err
+	}
+
+	usernameMapping := make(map[string]string)
+	err = as.auditLogRepo.ForEachAuditLog(ctx, req, func(auditLog *entity.AuditLog) error {
+		username, ok := usernameMapping[auditLog.UserID]
+		if !ok {
+			userInfoMapping, err := as.userCommon.BatchUserBasicInfoByID(ctx, []string{auditLog.UserID})
+			if err != nil {
+				return err
+			}
+			if userInfo := userInfoMapping[auditLog.UserID]; userInfo != nil {
+				username = userInfo.Username
+			}
+			usernameMapping[auditLog.UserID] = username
+		}
+		return writer.Write([]string{
+			auditLog.ID,
+			auditLog.CreatedAt.UTC().Format(time.RFC3339),
+			auditLog.UserID,
+			escapeCSVField(username),
+			auditLog.Action,
+			auditLog.ObjectType,
+			escapeCSVField(auditLog.ObjectID),
+			escapeCSVField(auditLog.Before),
+			escapeCSVField(auditLog.After),
+			auditLog.IP,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// diffAuditValue returns the json of the changed fields only. If there is no before value,
+// all fields of after are recorded. The value of sensitive fields is masked.
+func diffAuditValue(before, after any) (beforeJSON, afterJSON string) {
+	beforeFields, afterFields := toAuditFields(before), toAuditFields(after)
+	if beforeFields != nil {
+		for key, beforeValue := range beforeFields {
+			if afterValue, ok := afterFields[key]; ok && reflect.DeepEqual(beforeValue, afterValue) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+	return marshalAuditFields(beforeFields), marshalAuditFields(afterFields)
+}
+
+// toAuditFields convert the value to a field map, the value which is not an object is recorded as "value"
+func toAuditFields(value any) map[string]any {
+	if value == nil {
+		return nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		log.Errorf("marshal audit value failed: %v", err)
+		return nil
+	}
+	fields := make(map[string]any)
+	if err = json.Unmarshal(content, &fields); err != nil {
+		var v any
+		_ = json.Unmarshal(content, &v)
+		fields = map[string]any{"value": v}
+	}
+	return fields
+}
+
+func marshalAuditFields(fields map[string]any) string {
+	if len(fields) == 0 {
+		return "{}"
+	}
+	for key, value := range fields {
+		if isSensitiveField(key) && value != nil && value != "" {
+			fields[key] = maskedValue
+		}
+	}
+	content, _ := json.Marshal(fields)
+	return string(content)
+}
+
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, keyword := range sensitiveFieldKeywords {
+		if strings.Contains(key, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeCSVField prevent the field from being treated as a formula by spreadsheet software
+func escapeCSVField(field string) string {
+	if len(field) > 0 && strings.ContainsRune("=+-@\t\r", rune(field[0])) {
+		return "'" + field
+	}
+	return field
+}
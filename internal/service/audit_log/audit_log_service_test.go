@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package audit_log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffAuditValue(t *testing.T) {
+	before, after := diffAuditValue(
+		map[string]any{"name": "answer", "status": 1, "smtp_password": "old"},
+		map[string]any{"name": "answer", "status": 2, "smtp_password": "new"},
+	)
+	assert.JSONEq(t, `{"status":1,"smtp_password":"******"}`, before)
+	assert.JSONEq(t, `{"status":2,"smtp_password":"******"}`, after)
+
+	before, after = diffAuditValue(nil, struct {
+		UserID   string `json:"user_id"`
+		Password string `json:"password"`
+	}{UserID: "1", Password: "12345678"})
+	assert.Equal(t, "{}", before)
+	assert.JSONEq(t, `{"user_id":"1","password":"******"}`, after)
+
+	before, after = diffAuditValue(map[string]any{"status": 1}, map[string]any{"status": 1})
+	assert.Equal(t, "{}", before)
+	assert.Equal(t, "{}", after)
+}
+
+func Test_escapeCSVField(t *testing.T) {
+	assert.Equal(t, "'=cmd()", escapeCSVField("=cmd()"))
+	assert.Equal(t, `{"a":1}`, escapeCSVField(`{"a":1}`))
+}
@@ -0,0 +1,293 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package bounty
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/cron"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/activity_common"
+	"github.com/apache/incubator-answer/internal/service/activity_type"
+	answercommon "github.com/apache/incubator-answer/internal/service/answer_common"
+	"github.com/apache/incubator-answer/internal/service/notice_queue"
+	questioncommon "github.com/apache/incubator-answer/internal/service/question_common"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/apache/incubator-answer/pkg/uid"
+	"github.com/apache/incubator-answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const expireBatchSize = 100
+
+// BountyRepo bounty repository
+type BountyRepo interface {
+	AddBounty(ctx context.Context, bounty *entity.QuestionBounty, activityType int) (added bool, err error)
+	AwardBounty(ctx context.Context, bounty *entity.QuestionBounty, answer *entity.Answer, activityType int) (
+		awarded bool, err error)
+	ExpireBounty(ctx context.Context, bountyID string) (expired bool, err error)
+	GetActiveBounty(ctx context.Context, questionID string) (bounty *entity.QuestionBounty, exist bool, err error)
+	GetLatestBounty(ctx context.Context, questionID string) (bounty *entity.QuestionBounty, exist bool, err error)
+	GetActiveBountyList(ctx context.Context, questionIDs []string) (bounties []*entity.QuestionBounty, err error)
+	GetExpiredBountyList(ctx context.Context, deadline time.Time, limit int) (bounties []*entity.QuestionBounty, err error)
+	GetTopAnswer(ctx context.Context, questionID, excludeUserID string) (answer *entity.Answer, exist bool, err error)
+}
+
+// BountyService bounty service
+type BountyService struct {
+	bountyRepo               BountyRepo
+	questionRepo             questioncommon.QuestionRepo
+	answerRepo               answercommon.AnswerRepo
+	activityRepo             activity_common.ActivityRepo
+	userCommon               *usercommon.UserCommon
+	notificationQueueService notice_queue.NotificationQueueService
+}
+
+// NewBountyService new bounty service
+func NewBountyService(
+	bountyRepo BountyRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	activityRepo activity_common.ActivityRepo,
+	userCommon *usercommon.UserCommon,
+	notificationQueueService notice_queue.NotificationQueueService,
+	cronManager *cron.ScheduledTaskManager,
+) *BountyService {
+	bs := &BountyService{
+		bountyRepo:               bountyRepo,
+		questionRepo:             questionRepo,
+		answerRepo:               answerRepo,
+		activityRepo:             activityRepo,
+		userCommon:               userCommon,
+		notificationQueueService: notificationQueueService,
+	}
+	if err := cronManager.Register(&cron.Job{
+		Name:        "expire_bounty",
+		Spec:        "*/10 * * * *",
+		Description: "award or expire the bounties which are due",
+		Run:         bs.ExpireBountyCron,
+	}); err != nil {
+		log.Error(err)
+	}
+	return bs
+}
+
+// OfferBounty escrow the reputation of user on the question for a fixed period
+func (bs *BountyService) OfferBounty(ctx context.Context, req *schema.OfferBountyReq) (err error) {
+	// the reputation is not managed by answer when the rank agent is enabled
+	if plugin.RankAgentEnabled() {
+		return errors.BadRequest(reason.BountyDisabled)
+	}
+	questionID := uid.DeShortID(req.QuestionID)
+	question, exist, err := bs.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	if question.Status != entity.QuestionStatusAvailable {
+		return errors.BadRequest(reason.BountyQuestionNotAvailable)
+	}
+	_, exist, err = bs.bountyRepo.GetActiveBounty(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return errors.BadRequest(reason.BountyAlreadyExist)
+	}
+
+	activityType, err := bs.activityRepo.GetActivityTypeByConfigKey(ctx, activity_type.QuestionBountyOffered)
+	if err != nil {
+		return err
+	}
+	added, err := bs.bountyRepo.AddBounty(ctx, &entity.QuestionBounty{
+		QuestionID: questionID,
+		UserID:     req.UserID,
+		Amount:     req.Amount,
+		ExpiredAt:  time.Now().Add(schema.BountyDuration),
+		Status:     entity.QuestionBountyStatusActive,
+	}, activityType)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return errors.BadRequest(reason.BountyRankNotEnough)
+	}
+	return nil
+}
+
+// AwardBounty award the active bounty of question to the chosen answer
+func (bs *BountyService) AwardBounty(ctx context.Context, req *schema.AwardBountyReq) (err error) {
+	questionID, answerID := uid.DeShortID(req.QuestionID), uid.DeShortID(req.AnswerID)
+	bounty, exist, err := bs.bountyRepo.GetActiveBounty(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.BountyNotFound)
+	}
+	if bounty.UserID != req.UserID {
+		return errors.Forbidden(reason.BountyOnlyOffererCanAward)
+	}
+	answer, exist, err := bs.answerRepo.GetAnswer(ctx, answerID)
+	if err != nil {
+		return err
+	}
+	if !exist || answer.Status != entity.AnswerStatusAvailable || uid.DeShortID(answer.QuestionID) != questionID {
+		return errors.BadRequest(reason.AnswerNotFound)
+	}
+	if answer.UserID == bounty.UserID {
+		return errors.BadRequest(reason.BountyCannotAwardOwnAnswer)
+	}
+
+	bounty.Status = entity.QuestionBountyStatusAwarded
+	awarded, err := bs.awardBounty(ctx, bounty, answer)
+	if err != nil {
+		return err
+	}
+	if !awarded {
+		return errors.BadRequest(reason.BountyNotFound)
+	}
+	return nil
+}
+
+// GetQuestionBounty get the latest bounty of question, nil if the question never has a bounty
+func (bs *BountyService) GetQuestionBounty(ctx context.Context, req *schema.GetQuestionBountyReq) (
+	resp *schema.QuestionBountyInfo, err error) {
+	bounty, exist, err := bs.bountyRepo.GetLatestBounty(ctx, uid.DeShortID(req.QuestionID))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, nil
+	}
+	resp = &schema.QuestionBountyInfo{
+		ID:         bounty.ID,
+		QuestionID: uid.EnShortID(bounty.QuestionID),
+		Amount:     bounty.Amount,
+		Status:     schema.QuestionBountyStatusMap[bounty.Status],
+		CreatedAt:  bounty.CreatedAt.Unix(),
+		ExpiredAt:  bounty.ExpiredAt.Unix(),
+	}
+	if bounty.AnswerID != "0" {
+		resp.AnswerID = uid.EnShortID(bounty.AnswerID)
+		resp.AwardedAt = bounty.AwardedAt.Unix()
+	}
+	userInfo, exist, err := bs.userCommon.GetUserBasicInfoByID(ctx, bounty.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		resp.UserInfo = userInfo
+	}
+	return resp, nil
+}
+
+// GetActiveBountyAmountMapping get the amount of active bounties, the key is question id
+func (bs *BountyService) GetActiveBountyAmountMapping(ctx context.Context, questionIDs []string) (
+	mapping map[string]int) {
+	mapping = make(map[string]int, len(questionIDs))
+	ids := make([]string, 0, len(questionIDs))
+	for _, questionID := range questionIDs {
+		ids = append(ids, uid.DeShortID(questionID))
+	}
+	bounties, err := bs.bountyRepo.GetActiveBountyList(ctx, ids)
+	if err != nil {
+		log.Error(err)
+		return mapping
+	}
+	for _, bounty := range bounties {
+		mapping[bounty.QuestionID] = bounty.Amount
+	}
+	return mapping
+}
+
+// ExpireBountyCron award the expired bounties to the top answer, if the question has no answer with positive votes,
+// the bounty expires and the escrowed reputation is not refunded.
+func (bs *BountyService) ExpireBountyCron(ctx context.Context) (err error) {
+	for {
+		bounties, err := bs.bountyRepo.GetExpiredBountyList(ctx, time.Now(), expireBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, bounty := range bounties {
+			if err = bs.expireBounty(ctx, bounty); err != nil {
+				return err
+			}
+		}
+		if len(bounties) < expireBatchSize {
+			return nil
+		}
+	}
+}
+
+func (bs *BountyService) expireBounty(ctx context.Context, bounty *entity.QuestionBounty) (err error) {
+	answer, exist, err := bs.bountyRepo.GetTopAnswer(ctx, bounty.QuestionID, bounty.UserID)
+	if err != nil {
+		return err
+	}
+	if exist {
+		bounty.Status = entity.QuestionBountyStatusAutoAwarded
+		_, err = bs.awardBounty(ctx, bounty, answer)
+		return err
+	}
+
+	expired, err := bs.bountyRepo.ExpireBounty(ctx, bounty.ID)
+	if err != nil || !expired {
+		return err
+	}
+	bs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+		TriggerUserID:      bounty.UserID,
+		ReceiverUserID:     bounty.UserID,
+		Type:               schema.NotificationTypeInbox,
+		ObjectID:           bounty.QuestionID,
+		ObjectType:         constant.QuestionObjectType,
+		NotificationAction: constant.NotificationBountyExpired,
+	})
+	return nil
+}
+
+func (bs *BountyService) awardBounty(ctx context.Context, bounty *entity.QuestionBounty, answer *entity.Answer) (
+	awarded bool, err error) {
+	activityType, err := bs.activityRepo.GetActivityTypeByConfigKey(ctx, activity_type.AnswerBountyAwarded)
+	if err != nil {
+		return false, err
+	}
+	answer.ID = uid.DeShortID(answer.ID)
+	awarded, err = bs.bountyRepo.AwardBounty(ctx, bounty, answer, activityType)
+	if err != nil || !awarded {
+		return awarded, err
+	}
+	bs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+		TriggerUserID:      bounty.UserID,
+		ReceiverUserID:     answer.UserID,
+		Type:               schema.NotificationTypeInbox,
+		ObjectID:           answer.ID,
+		ObjectType:         constant.AnswerObjectType,
+		NotificationAction: constant.NotificationBountyAwarded,
+	})
+	return true, nil
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/apache/incubator-answer/internal/service/activity_queue"
 	answercommon "github.com/apache/incubator-answer/internal/service/answer_common"
 	collectioncommon "github.com/apache/incubator-answer/internal/service/collection_common"
+	"github.com/apache/incubator-answer/internal/service/draft"
 	"github.com/apache/incubator-answer/internal/service/export"
 	"github.com/apache/incubator-answer/internal/service/notice_queue"
 	"github.com/apache/incubator-answer/internal/service/permission"
@@ -70,6 +71,8 @@ type AnswerService struct {
 	activityQueueService             activity_queue.ActivityQueueService
 	reviewService                    *review.ReviewService
 	eventQueueService                event_queue.EventQueueService
+	rolePowerRelService              *role.RolePowerRelService
+	draftService                     *draft.DraftService
 }
 
 func NewAnswerService(
@@ -90,6 +93,8 @@ func NewAnswerService(
 	activityQueueService activity_queue.ActivityQueueService,
 	reviewService *review.ReviewService,
 	eventQueueService event_queue.EventQueueService,
+	rolePowerRelService *role.RolePowerRelService,
+	draftService *draft.DraftService,
 ) *AnswerService {
 	return &AnswerService{
 		answerRepo:                       answerRepo,
@@ -109,6 +114,8 @@ func NewAnswerService(
 		activityQueueService:             activityQueueService,
 		reviewService:                    reviewService,
 		eventQueueService:                eventQueueService,
+		rolePowerRelService:              rolePowerRelService,
+		draftService:                     draftService,
 	}
 }
 
@@ -125,11 +132,11 @@ func (as *AnswerService) RemoveAnswer(ctx context.Context, req *schema.RemoveAns
 	if answerInfo.Status == entity.AnswerStatusDeleted {
 		return nil
 	}
-	roleID, err := as.roleService.GetUserRole(ctx, req.UserID)
+	canDelete, err := as.rolePowerRelService.UserHasPower(ctx, req.UserID, permission.AnswerDelete)
 	if err != nil {
 		return err
 	}
-	if roleID != role.RoleAdminID && roleID != role.RoleModeratorID {
+	if !canDelete {
 		if answerInfo.UserID != req.UserID {
 			return errors.BadRequest(reason.AnswerCannotDeleted)
 		}
@@ -333,6 +340,7 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	})
 	as.eventQueueService.Send(ctx, schema.NewEvent(constant.EventAnswerCreate, req.UserID).TID(insertData.ID).
 		AID(insertData.ID, insertData.UserID))
+	as.draftService.RemoveDraftAfterPublish(ctx, req.UserID, schema.DraftObjectTypeAnswer, questionInfo.ID)
 	return insertData.ID, nil
 }
 
@@ -428,6 +436,7 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 		as.eventQueueService.Send(ctx, schema.NewEvent(constant.EventAnswerUpdate, req.UserID).TID(insertData.ID).
 			AID(insertData.ID, insertData.UserID))
 	}
+	as.draftService.RemoveDraftAfterPublish(ctx, req.UserID, schema.DraftObjectTypeAnswerEdit, insertData.ID)
 
 	return insertData.ID, nil
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/apache/incubator-answer/internal/service/event_queue"
 
 	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/cron"
 	"github.com/apache/incubator-answer/internal/base/handler"
 	"github.com/apache/incubator-answer/internal/base/pager"
 	"github.com/apache/incubator-answer/internal/base/reason"
@@ -39,8 +40,10 @@ import (
 	"github.com/apache/incubator-answer/internal/service/activity_common"
 	"github.com/apache/incubator-answer/internal/service/activity_queue"
 	answercommon "github.com/apache/incubator-answer/internal/service/answer_common"
+	"github.com/apache/incubator-answer/internal/service/bounty"
 	collectioncommon "github.com/apache/incubator-answer/internal/service/collection_common"
 	"github.com/apache/incubator-answer/internal/service/config"
+	"github.com/apache/incubator-answer/internal/service/draft"
 	"github.com/apache/incubator-answer/internal/service/export"
 	metacommon "github.com/apache/incubator-answer/internal/service/meta_common"
 	"github.com/apache/incubator-answer/internal/service/notice_queue"
@@ -92,6 +95,9 @@ type QuestionService struct {
 	configService                    *config.ConfigService
 	eventQueueService                event_queue.EventQueueService
 	reviewRepo                       review.ReviewRepo
+	rolePowerRelService              *role.RolePowerRelService
+	bountyService                    *bounty.BountyService
+	draftService                     *draft.DraftService
 }
 
 func NewQuestionService(
@@ -118,8 +124,12 @@ func NewQuestionService(
 	configService *config.ConfigService,
 	eventQueueService event_queue.EventQueueService,
 	reviewRepo review.ReviewRepo,
+	rolePowerRelService *role.RolePowerRelService,
+	bountyService *bounty.BountyService,
+	draftService *draft.DraftService,
+	cronManager *cron.ScheduledTaskManager,
 ) *QuestionService {
-	return &QuestionService{
+	qs := &QuestionService{
 		activityRepo:                     activityRepo,
 		questionRepo:                     questionRepo,
 		answerRepo:                       answerRepo,
@@ -143,7 +153,34 @@ func NewQuestionService(
 		configService:                    configService,
 		eventQueueService:                eventQueueService,
 		reviewRepo:                       reviewRepo,
+		rolePowerRelService:              rolePowerRelService,
+		bountyService:                    bountyService,
+		draftService:                     draftService,
+	}
+	if err := cronManager.Register(&cron.Job{
+		Name:        "sitemap",
+		Spec:        "0 */1 * * *",
+		Description: "generate the sitemap",
+		RunOnStart:  true,
+		Run: func(ctx context.Context) error {
+			qs.SitemapCron(ctx)
+			return nil
+		},
+	}); err != nil {
+		log.Error(err)
+	}
+	if err := cronManager.Register(&cron.Job{
+		Name:        "refresh_hottest",
+		Spec:        "0 */1 * * *",
+		Description: "refresh the hot score of questions",
+		Run: func(ctx context.Context) error {
+			qs.RefreshHottestCron(ctx)
+			return nil
+		},
+	}); err != nil {
+		log.Error(err)
 	}
+	return qs
 }
 
 func (qs *QuestionService) CloseQuestion(ctx context.Context, req *schema.CloseQuestionReq) error {
@@ -417,6 +454,7 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	}
 	qs.eventQueueService.Send(ctx, schema.NewEvent(constant.EventQuestionCreate, req.UserID).TID(question.ID).
 		QID(question.ID, question.UserID))
+	qs.draftService.RemoveDraftAfterPublish(ctx, req.UserID, schema.DraftObjectTypeQuestion, "")
 
 	questionInfo, err = qs.GetQuestion(ctx, question.ID, question.UserID, req.QuestionPermission)
 	return
@@ -1019,6 +1057,7 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		qs.eventQueueService.Send(ctx, schema.NewEvent(constant.EventQuestionUpdate, req.UserID).TID(question.ID).
 			QID(question.ID, question.UserID))
 	}
+	qs.draftService.RemoveDraftAfterPublish(ctx, req.UserID, schema.DraftObjectTypeQuestionEdit, question.ID)
 
 	questionInfo, err = qs.GetQuestion(ctx, question.ID, question.UserID, req.QuestionPermission)
 	return
@@ -1378,11 +1417,10 @@ func (qs *QuestionService) GetQuestionPage(ctx context.Context, req *schema.Ques
 	if req.LoginUserID != "" && req.UserIDBeSearched != "" {
 		showHidden = req.LoginUserID == req.UserIDBeSearched
 		if !showHidden {
-			userRole, err := qs.userRoleRelService.GetUserRole(ctx, req.LoginUserID)
+			showHidden, err = qs.rolePowerRelService.UserHasPower(ctx, req.LoginUserID, permission.QuestionShow)
 			if err != nil {
 				return nil, 0, err
 			}
-			showHidden = userRole == role.RoleAdminID || userRole == role.RoleModeratorID
 		}
 	}
 	// query by tag condition
@@ -1398,6 +1436,13 @@ func (qs *QuestionService) GetQuestionPage(ctx context.Context, req *schema.Ques
 				return nil, 0, err
 			}
 			tagIDs = append(synTagIds, tagInfo.ID)
+			if req.IncludeSubTags {
+				subTagIDs, err := qs.tagCommon.GetDescendantTagIDs(ctx, []string{tagInfo.ID})
+				if err != nil {
+					return nil, 0, err
+				}
+				tagIDs = append(tagIDs, subTagIDs...)
+			}
 		}
 	}
 
@@ -1426,6 +1471,14 @@ func (qs *QuestionService) GetQuestionPage(ctx context.Context, req *schema.Ques
 	if err != nil {
 		return nil, 0, err
 	}
+	questionIDs := make([]string, 0, len(questions))
+	for _, question := range questions {
+		questionIDs = append(questionIDs, question.ID)
+	}
+	bountyMapping := qs.bountyService.GetActiveBountyAmountMapping(ctx, questionIDs)
+	for _, question := range questions {
+		question.BountyAmount = bountyMapping[uid.DeShortID(question.ID)]
+	}
 	return questions, total, nil
 }
 
@@ -1440,6 +1493,12 @@ func (qs *QuestionService) GetRecommendQuestionPage(ctx context.Context, req *sc
 	for _, tag := range followingTagsResp {
 		tagIDs = append(tagIDs, tag.TagID)
 	}
+	// following a tag also means following its sub tags
+	subTagIDs, err := qs.tagCommon.GetDescendantTagIDs(ctx, tagIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	tagIDs = append(tagIDs, subTagIDs...)
 
 	activityType, err := qs.activityRepo.GetActivityTypeByObjectType(ctx, constant.QuestionObjectType, "follow")
 	if err != nil {
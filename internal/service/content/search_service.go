@@ -22,24 +22,34 @@ package content
 import (
 	"context"
 
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/search_common"
 	"github.com/apache/incubator-answer/internal/service/search_parser"
+	tagcommon "github.com/apache/incubator-answer/internal/service/tag_common"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
 	"github.com/apache/incubator-answer/plugin"
 )
 
 type SearchService struct {
-	searchParser *search_parser.SearchParser
-	searchRepo   search_common.SearchRepo
+	searchParser     *search_parser.SearchParser
+	searchRepo       search_common.SearchRepo
+	tagCommonService *tagcommon.TagCommonService
+	userCommon       *usercommon.UserCommon
 }
 
 func NewSearchService(
 	searchParser *search_parser.SearchParser,
 	searchRepo search_common.SearchRepo,
+	tagCommonService *tagcommon.TagCommonService,
+	userCommon *usercommon.UserCommon,
 ) *SearchService {
 	return &SearchService{
-		searchParser: searchParser,
-		searchRepo:   searchRepo,
+		searchParser:     searchParser,
+		searchRepo:       searchRepo,
+		tagCommonService: tagCommonService,
+		userCommon:       userCommon,
 	}
 }
 
@@ -78,7 +88,15 @@ func (ss *SearchService) Search(ctx context.Context, dto *schema.SearchDTO) (res
 			resp.SearchResults, resp.Total, err =
 				ss.searchRepo.SearchAnswers(ctx, cond.Words, cond.Tags, cond.Accepted, cond.QuestionID, dto.Page, dto.Size, dto.Order)
 		}
-		return
+		if err != nil || !dto.Facets {
+			return
+		}
+		facets, err := ss.searchRepo.SearchFacets(ctx, cond)
+		if err != nil {
+			return nil, err
+		}
+		resp.Facets, err = ss.convertFacets(ctx, facets)
+		return resp, err
 	}
 	return ss.searchByPlugin(ctx, finder, cond, dto)
 }
@@ -95,5 +113,91 @@ func (ss *SearchService) searchByPlugin(ctx context.Context, finder plugin.Searc
 	}
 
 	resp.SearchResults, err = ss.searchRepo.ParseSearchPluginResult(ctx, res, cond.Words)
+	if err != nil || !dto.Facets {
+		return resp, err
+	}
+
+	// the facets are only returned if the search plugin supports
+	provider, ok := finder.(plugin.SearchFacetsProvider)
+	if !ok {
+		return resp, nil
+	}
+	facets, err := provider.SearchFacets(ctx, cond.Convert2PluginSearchCond(dto.Page, dto.Size, dto.Order), cond.TargetType)
+	if err != nil {
+		return nil, err
+	}
+	resp.Facets, err = ss.convertFacets(ctx, facets)
 	return resp, err
 }
+
+// convertFacets convert the facets counted by repo or plugin to response, the tags and authors are filled with their info
+func (ss *SearchService) convertFacets(ctx context.Context, facets *plugin.SearchFacets) (resp *schema.SearchFacets, err error) {
+	resp = &schema.SearchFacets{
+		Tags:    make([]*schema.SearchTagFacet, 0),
+		Authors: make([]*schema.SearchAuthorFacet, 0),
+		Types: []*schema.SearchFacet{
+			{Value: constant.QuestionObjectType, Count: facets.Questions, Query: "is:question"},
+			{Value: constant.AnswerObjectType, Count: facets.Answers, Query: "is:answer"},
+		},
+		Accepted: []*schema.SearchFacet{
+			{Value: "accepted", Count: facets.AcceptedAnswers, Query: "isaccepted:yes"},
+			{Value: "not_accepted", Count: facets.NotAcceptedQuestions, Query: "hasaccepted:no"},
+		},
+		Dates: make([]*schema.SearchFacet, 0, len(facets.Dates)),
+	}
+	for _, date := range facets.Dates {
+		resp.Dates = append(resp.Dates, &schema.SearchFacet{Value: date.Value, Count: date.Count})
+	}
+
+	if len(facets.Tags) > 0 {
+		tagIDs := make([]string, 0, len(facets.Tags))
+		for _, t := range facets.Tags {
+			tagIDs = append(tagIDs, t.Value)
+		}
+		tagList, err := ss.tagCommonService.GetTagListByIDs(ctx, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+		tagMapping := make(map[string]*entity.Tag, len(tagList))
+		for _, tag := range tagList {
+			tagMapping[tag.ID] = tag
+		}
+		for _, t := range facets.Tags {
+			tag := tagMapping[t.Value]
+			if tag == nil {
+				continue
+			}
+			resp.Tags = append(resp.Tags, &schema.SearchTagFacet{
+				SlugName:    tag.SlugName,
+				DisplayName: tag.DisplayName,
+				Count:       t.Count,
+				Query:       "[" + tag.SlugName + "]",
+			})
+		}
+	}
+
+	if len(facets.Authors) > 0 {
+		userIDs := make([]string, 0, len(facets.Authors))
+		for _, a := range facets.Authors {
+			userIDs = append(userIDs, a.Value)
+		}
+		userInfoMapping, err := ss.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range facets.Authors {
+			userInfo := userInfoMapping[a.Value]
+			if userInfo == nil {
+				continue
+			}
+			resp.Authors = append(resp.Authors, &schema.SearchAuthorFacet{
+				Username:    userInfo.Username,
+				DisplayName: userInfo.DisplayName,
+				Avatar:      userInfo.Avatar,
+				Count:       a.Count,
+				Query:       "user:" + userInfo.Username,
+			})
+		}
+	}
+	return resp, nil
+}
@@ -41,8 +41,10 @@ import (
 	"github.com/apache/incubator-answer/internal/service/activity_common"
 	"github.com/apache/incubator-answer/internal/service/auth"
 	"github.com/apache/incubator-answer/internal/service/export"
+	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/role"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	"github.com/apache/incubator-answer/internal/service/two_factor"
 	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
 	"github.com/apache/incubator-answer/internal/service/user_external_login"
 	"github.com/apache/incubator-answer/pkg/checker"
@@ -67,6 +69,8 @@ type UserService struct {
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService
 	questionService               *questioncommon.QuestionCommon
 	eventQueueService             event_queue.EventQueueService
+	twoFactorService              *two_factor.TwoFactorService
+	rolePowerRelService           *role.RolePowerRelService
 }
 
 func NewUserService(userRepo usercommon.UserRepo,
@@ -82,6 +86,8 @@ func NewUserService(userRepo usercommon.UserRepo,
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService,
 	questionService *questioncommon.QuestionCommon,
 	eventQueueService event_queue.EventQueueService,
+	twoFactorService *two_factor.TwoFactorService,
+	rolePowerRelService *role.RolePowerRelService,
 ) *UserService {
 	return &UserService{
 		userCommonService:             userCommonService,
@@ -97,6 +103,8 @@ func NewUserService(userRepo usercommon.UserRepo,
 		userNotificationConfigService: userNotificationConfigService,
 		questionService:               questionService,
 		eventQueueService:             eventQueueService,
+		twoFactorService:              twoFactorService,
+		rolePowerRelService:           rolePowerRelService,
 	}
 }
 
@@ -175,15 +183,68 @@ func (us *UserService) EmailLogin(ctx context.Context, req *schema.UserEmailLogi
 		return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
 	}
 
-	err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID)
+	resp, err = us.twoFactorChallenge(ctx, userInfo.ID, externalID)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+	roleID, err := us.userRoleService.GetUserRole(ctx, userInfo.ID)
 	if err != nil {
-		log.Errorf("update last login data failed, err: %v", err)
+		log.Error(err)
 	}
+	return us.passwordLogin(ctx, userInfo, externalID, roleID)
+}
 
+// UserTwoFactorLogin complete the email login with the code of authenticator or a recovery code
+func (us *UserService) UserTwoFactorLogin(ctx context.Context, req *schema.UserTwoFactorLoginReq) (
+	resp *schema.UserLoginResp, err error) {
+	challenge, recoveryCodes, err := us.twoFactorService.VerifyChallenge(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
+	}
 	roleID, err := us.userRoleService.GetUserRole(ctx, userInfo.ID)
 	if err != nil {
 		log.Error(err)
 	}
+	resp, err = us.passwordLogin(ctx, userInfo, challenge.ExternalID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	resp.TwoFactorRecoveryCodes = recoveryCodes
+	return resp, nil
+}
+
+// twoFactorChallenge if the user enabled two factor, or the user is staff and the site requires it,
+// the login is paused and a token for the second step is returned
+func (us *UserService) twoFactorChallenge(ctx context.Context, userID, externalID string) (
+	resp *schema.UserLoginResp, err error) {
+	challenge, challengeToken, err := us.twoFactorService.LoginChallenge(ctx, userID, externalID)
+	if err != nil || len(challengeToken) == 0 {
+		return nil, err
+	}
+	resp = &schema.UserLoginResp{TwoFactorRequired: true, TwoFactorToken: challengeToken}
+	if challenge.Enroll {
+		resp.TwoFactorEnroll, err = us.twoFactorService.Enroll(ctx, &schema.TwoFactorEnrollReq{UserID: userID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// passwordLogin issue the access token after the user passed all verifications
+func (us *UserService) passwordLogin(ctx context.Context, userInfo *entity.User, externalID string, roleID int) (
+	resp *schema.UserLoginResp, err error) {
+	err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID)
+	if err != nil {
+		log.Errorf("update last login data failed, err: %v", err)
+	}
 
 	resp = &schema.UserLoginResp{}
 	resp.ConvertFromUserEntity(userInfo)
@@ -200,7 +261,11 @@ func (us *UserService) EmailLogin(ctx context.Context, req *schema.UserEmailLogi
 		return nil, err
 	}
 	resp.RoleID = userCacheInfo.RoleID
-	if resp.RoleID == role.RoleAdminID {
+	isAdmin, err := us.rolePowerRelService.RoleHasPower(ctx, resp.RoleID, permission.AdminAccess)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
 		err = us.authService.SetAdminUserCacheInfo(ctx, resp.AccessToken, userCacheInfo)
 		if err != nil {
 			return nil, err
@@ -480,7 +545,11 @@ func (us *UserService) UserRegisterByEmail(ctx context.Context, registerUserInfo
 		return nil, nil, err
 	}
 	resp.RoleID = userCacheInfo.RoleID
-	if resp.RoleID == role.RoleAdminID {
+	isAdmin, err := us.rolePowerRelService.RoleHasPower(ctx, resp.RoleID, permission.AdminAccess)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isAdmin {
 		err = us.authService.SetAdminUserCacheInfo(ctx, resp.AccessToken, &entity.UserCacheInfo{UserID: userInfo.ID})
 		if err != nil {
 			return nil, nil, err
@@ -546,7 +615,23 @@ func (us *UserService) UserVerifyEmail(ctx context.Context, req *schema.UserVeri
 		}
 	}
 
-	accessToken, userCacheInfo, err := us.userCommonService.CacheLoginUserInfo(
+	roleID, err := us.userRoleService.GetUserRole(ctx, userInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	// User verified email will update user email status. So user status cache should be updated.
+	err = us.authService.SetUserStatus(ctx, &entity.UserCacheInfo{
+		UserID: userInfo.ID, EmailStatus: userInfo.MailStatus, UserStatus: userInfo.Status, RoleID: roleID})
+	if err != nil {
+		return nil, err
+	}
+	// the email link must not skip the second step of the login
+	resp, err = us.twoFactorChallenge(ctx, userInfo.ID, "")
+	if err != nil || resp != nil {
+		return resp, err
+	}
+
+	accessToken, _, err := us.userCommonService.CacheLoginUserInfo(
 		ctx, userInfo.ID, userInfo.MailStatus, userInfo.Status, "")
 	if err != nil {
 		return nil, err
@@ -556,10 +641,6 @@ func (us *UserService) UserVerifyEmail(ctx context.Context, req *schema.UserVeri
 	resp.ConvertFromUserEntity(userInfo)
 	resp.Avatar = us.siteInfoService.FormatAvatar(ctx, userInfo.Avatar, userInfo.EMail, userInfo.Status).GetURL()
 	resp.AccessToken = accessToken
-	// User verified email will update user email status. So user status cache should be updated.
-	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
-		return nil, err
-	}
 	return resp, nil
 }
 
@@ -678,25 +759,35 @@ func (us *UserService) UserChangeEmailVerify(ctx context.Context, content string
 		log.Error(err)
 	}
 
-	resp = &schema.UserLoginResp{}
-	resp.ConvertFromUserEntity(userInfo)
-	resp.Avatar = us.siteInfoService.FormatAvatar(ctx, userInfo.Avatar, userInfo.EMail, userInfo.Status).GetURL()
 	userCacheInfo := &entity.UserCacheInfo{
 		UserID:      userInfo.ID,
 		EmailStatus: entity.EmailStatusAvailable,
 		UserStatus:  userInfo.Status,
 		RoleID:      roleID,
 	}
+	// User verified email will update user email status. So user status cache should be updated.
+	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
+		return nil, err
+	}
+	// the email link must not skip the second step of the login
+	resp, err = us.twoFactorChallenge(ctx, userInfo.ID, "")
+	if err != nil || resp != nil {
+		return resp, err
+	}
+
+	resp = &schema.UserLoginResp{}
+	resp.ConvertFromUserEntity(userInfo)
+	resp.Avatar = us.siteInfoService.FormatAvatar(ctx, userInfo.Avatar, userInfo.EMail, userInfo.Status).GetURL()
 	resp.AccessToken, resp.VisitToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
 	if err != nil {
 		return nil, err
 	}
-	// User verified email will update user email status. So user status cache should be updated.
-	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
+	resp.RoleID = userCacheInfo.RoleID
+	isAdmin, err := us.rolePowerRelService.RoleHasPower(ctx, resp.RoleID, permission.AdminAccess)
+	if err != nil {
 		return nil, err
 	}
-	resp.RoleID = userCacheInfo.RoleID
-	if resp.RoleID == role.RoleAdminID {
+	if isAdmin {
 		err = us.authService.SetAdminUserCacheInfo(ctx, resp.AccessToken, &entity.UserCacheInfo{UserID: userInfo.ID})
 		if err != nil {
 			return nil, err
@@ -848,7 +939,11 @@ func (us *UserService) getActivityUserVoteStat(ctx context.Context, startTime, e
 
 func (us *UserService) getStaff(ctx context.Context, userIDExist map[string]bool) (
 	userRoleRels []*entity.UserRoleRel, userIDs []string, err error) {
-	userRoleRels, err = us.userRoleService.GetUserByRoleID(ctx, []int{role.RoleAdminID, role.RoleModeratorID})
+	staffRoleIDs, err := us.rolePowerRelService.GetRoleIDListByPowers(ctx, permission.AdminAccess, permission.ContentModerate)
+	if err != nil {
+		return nil, nil, err
+	}
+	userRoleRels, err = us.userRoleService.GetUserByRoleID(ctx, staffRoleIDs)
 	if err != nil {
 		return nil, nil, err
 	}
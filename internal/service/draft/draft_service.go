@@ -0,0 +1,226 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/cron"
+	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/pager"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	answercommon "github.com/apache/incubator-answer/internal/service/answer_common"
+	questioncommon "github.com/apache/incubator-answer/internal/service/question_common"
+	"github.com/apache/incubator-answer/pkg/uid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// DraftRepo draft repository
+type DraftRepo interface {
+	SaveDraft(ctx context.Context, draft *entity.Draft) (err error)
+	GetDraft(ctx context.Context, userID, objectType, objectID string) (draft *entity.Draft, exist bool, err error)
+	GetDraftPage(ctx context.Context, userID string, page, pageSize int) (drafts []*entity.Draft, total int64, err error)
+	RemoveDraft(ctx context.Context, userID, objectType, objectID string) (err error)
+	RemoveUserOverflowDrafts(ctx context.Context, userID string, keep int) (err error)
+	RemoveExpiredDrafts(ctx context.Context, deadline time.Time) (affected int64, err error)
+}
+
+// DraftService draft service
+type DraftService struct {
+	draftRepo    DraftRepo
+	questionRepo questioncommon.QuestionRepo
+	answerRepo   answercommon.AnswerRepo
+}
+
+// NewDraftService new draft service
+func NewDraftService(
+	draftRepo DraftRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	cronManager *cron.ScheduledTaskManager,
+) *DraftService {
+	ds := &DraftService{
+		draftRepo:    draftRepo,
+		questionRepo: questionRepo,
+		answerRepo:   answerRepo,
+	}
+	if err := cronManager.Register(&cron.Job{
+		Name:        "clean_draft",
+		Spec:        "0 3 * * *",
+		Description: "remove the drafts which are not updated for a long time",
+		Run:         ds.CleanExpiredDraftCron,
+	}); err != nil {
+		log.Error(err)
+	}
+	return ds
+}
+
+// SaveDraft save the draft of user, only the latest drafts are kept
+func (ds *DraftService) SaveDraft(ctx context.Context, req *schema.SaveDraftReq) (resp *schema.DraftInfo, err error) {
+	objectID, err := ds.checkDraftObject(ctx, req.ObjectType, req.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	tags, _ := json.Marshal(req.Tags)
+	draft := &entity.Draft{
+		UserID:     req.UserID,
+		ObjectType: req.ObjectType,
+		ObjectID:   objectID,
+		Title:      req.Title,
+		Content:    req.Content,
+		Tags:       string(tags),
+	}
+	if err = ds.draftRepo.SaveDraft(ctx, draft); err != nil {
+		return nil, err
+	}
+	if err = ds.draftRepo.RemoveUserOverflowDrafts(ctx, req.UserID, schema.DraftMaxCountPerUser); err != nil {
+		log.Errorf("remove overflow drafts of user %s failed: %v", req.UserID, err)
+	}
+	return ds.formatDraftInfo(ctx, draft), nil
+}
+
+// GetDraft get the draft of user for the object
+func (ds *DraftService) GetDraft(ctx context.Context, req *schema.GetDraftReq) (resp *schema.DraftInfo, err error) {
+	objectID, err := ds.formatDraftObjectID(req.ObjectType, req.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	draft, exist, err := ds.draftRepo.GetDraft(ctx, req.UserID, req.ObjectType, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NotFound(reason.DraftNotFound)
+	}
+	return ds.formatDraftInfo(ctx, draft), nil
+}
+
+// GetDraftPage get the drafts of user
+func (ds *DraftService) GetDraftPage(ctx context.Context, req *schema.GetDraftPageReq) (
+	pageModel *pager.PageModel, err error) {
+	drafts, total, err := ds.draftRepo.GetDraftPage(ctx, req.UserID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*schema.DraftInfo, 0, len(drafts))
+	for _, draft := range drafts {
+		list = append(list, ds.formatDraftInfo(ctx, draft))
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// RemoveDraft discard the draft of user for the object
+func (ds *DraftService) RemoveDraft(ctx context.Context, req *schema.RemoveDraftReq) (err error) {
+	objectID, err := ds.formatDraftObjectID(req.ObjectType, req.ObjectID)
+	if err != nil {
+		return err
+	}
+	return ds.draftRepo.RemoveDraft(ctx, req.UserID, req.ObjectType, objectID)
+}
+
+// RemoveDraftAfterPublish remove the draft after the content is published.
+// The publishing should not fail because of the draft, so the error is only logged.
+func (ds *DraftService) RemoveDraftAfterPublish(ctx context.Context, userID, objectType, objectID string) {
+	objectID, err := ds.formatDraftObjectID(objectType, objectID)
+	if err != nil {
+		return
+	}
+	if err = ds.draftRepo.RemoveDraft(ctx, userID, objectType, objectID); err != nil {
+		log.Errorf("remove draft of user %s for %s %s failed: %v", userID, objectType, objectID, err)
+	}
+}
+
+// CleanExpiredDraftCron remove the drafts not updated in the retention days
+func (ds *DraftService) CleanExpiredDraftCron(ctx context.Context) (err error) {
+	deadline := time.Now().AddDate(0, 0, -schema.DraftRetentionDays)
+	affected, err := ds.draftRepo.RemoveExpiredDrafts(ctx, deadline)
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		log.Infof("cleaned %d expired drafts", affected)
+	}
+	return nil
+}
+
+// checkDraftObject check the object of draft exists and return the object id saved in draft
+func (ds *DraftService) checkDraftObject(ctx context.Context, objectType, objectID string) (string, error) {
+	objectID, err := ds.formatDraftObjectID(objectType, objectID)
+	if err != nil {
+		return "", err
+	}
+	var exist bool
+	switch objectType {
+	case schema.DraftObjectTypeQuestion:
+		return objectID, nil
+	case schema.DraftObjectTypeAnswer, schema.DraftObjectTypeQuestionEdit:
+		_, exist, err = ds.questionRepo.GetQuestion(ctx, objectID)
+	case schema.DraftObjectTypeAnswerEdit:
+		_, exist, err = ds.answerRepo.GetAnswer(ctx, objectID)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.BadRequest(reason.DraftObjectInvalid)
+	}
+	return objectID, nil
+}
+
+// formatDraftObjectID the new question draft has no object, the others must have one
+func (ds *DraftService) formatDraftObjectID(objectType, objectID string) (string, error) {
+	if objectType == schema.DraftObjectTypeQuestion {
+		if len(objectID) > 0 && objectID != "0" {
+			return "", errors.BadRequest(reason.DraftObjectInvalid)
+		}
+		return "0", nil
+	}
+	if len(objectID) == 0 {
+		return "", errors.BadRequest(reason.DraftObjectInvalid)
+	}
+	return uid.DeShortID(objectID), nil
+}
+
+func (ds *DraftService) formatDraftInfo(ctx context.Context, draft *entity.Draft) *schema.DraftInfo {
+	info := &schema.DraftInfo{
+		ID:         draft.ID,
+		ObjectType: draft.ObjectType,
+		ObjectID:   draft.ObjectID,
+		Title:      draft.Title,
+		Content:    draft.Content,
+		Tags:       make([]*schema.TagItem, 0),
+		CreatedAt:  draft.CreatedAt.Unix(),
+		UpdatedAt:  draft.UpdatedAt.Unix(),
+	}
+	if draft.ObjectType == schema.DraftObjectTypeQuestion {
+		info.ObjectID = ""
+	} else if handler.GetEnableShortID(ctx) {
+		info.ObjectID = uid.EnShortID(draft.ObjectID)
+	}
+	if len(draft.Tags) > 0 {
+		_ = json.Unmarshal([]byte(draft.Tags), &info.Tags)
+	}
+	return info
+}
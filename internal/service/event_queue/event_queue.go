@@ -22,8 +22,9 @@ package event_queue
 import (
 	"context"
 
+	"github.com/apache/incubator-answer/internal/base/queue"
 	"github.com/apache/incubator-answer/internal/schema"
-	"github.com/segmentfault/pacman/log"
+	"github.com/apache/incubator-answer/internal/service/service_config"
 )
 
 type EventQueueService interface {
@@ -31,39 +32,7 @@ type EventQueueService interface {
 	RegisterHandler(handler func(ctx context.Context, msg *schema.EventMsg) error)
 }
 
-type eventQueueService struct {
-	Queue   chan *schema.EventMsg
-	Handler func(ctx context.Context, msg *schema.EventMsg) error
-}
-
-func (ns *eventQueueService) Send(ctx context.Context, msg *schema.EventMsg) {
-	ns.Queue <- msg
-}
-
-func (ns *eventQueueService) RegisterHandler(
-	handler func(ctx context.Context, msg *schema.EventMsg) error) {
-	ns.Handler = handler
-}
-
-func (ns *eventQueueService) working() {
-	go func() {
-		for msg := range ns.Queue {
-			log.Debugf("received badge %+v", msg)
-			if ns.Handler == nil {
-				log.Warnf("no handler for badge")
-				continue
-			}
-			if err := ns.Handler(context.Background(), msg); err != nil {
-				log.Error(err)
-			}
-		}
-	}()
-}
-
 // NewEventQueueService create a new badge queue service
-func NewEventQueueService() EventQueueService {
-	ns := &eventQueueService{}
-	ns.Queue = make(chan *schema.EventMsg, 128)
-	ns.working()
-	return ns
+func NewEventQueueService(queueRepo queue.MessageRepo, serviceConf *service_config.ServiceConfig) EventQueueService {
+	return queue.New[*schema.EventMsg]("event", queueRepo, serviceConf.Queue)
 }
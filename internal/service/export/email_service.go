@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"github.com/apache/incubator-answer/pkg/display"
 	"mime"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -327,6 +328,91 @@ func (es *EmailService) NewQuestionTemplate(ctx context.Context, raw *schema.New
 	return title, body, nil
 }
 
+// DigestTemplate digest template
+func (es *EmailService) DigestTemplate(ctx context.Context, raw *schema.DigestTemplateRawData) (
+	title, body string, err error) {
+	siteInfo, err := es.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	seoInfo, err := es.siteInfoService.GetSiteSeo(ctx)
+	if err != nil {
+		return
+	}
+	templateData := &schema.DigestTemplateData{
+		SiteName:       siteInfo.Name,
+		Frequency:      raw.Frequency,
+		Questions:      make([]*schema.DigestTemplateItem, 0, len(raw.Questions)),
+		Answers:        make([]*schema.DigestTemplateItem, 0, len(raw.Answers)),
+		UnreadCount:    raw.UnreadCount,
+		InboxUrl:       fmt.Sprintf("%s/users/notifications/inbox", siteInfo.SiteUrl),
+		UnsubscribeUrl: fmt.Sprintf("%s/users/unsubscribe?code=%s", siteInfo.SiteUrl, raw.UnsubscribeCode),
+	}
+	for _, question := range raw.Questions {
+		templateData.Questions = append(templateData.Questions, &schema.DigestTemplateItem{
+			Title: question.Title,
+			Url:   display.QuestionURL(seoInfo.Permalink, siteInfo.SiteUrl, question.ID, question.Title),
+		})
+	}
+	for _, answer := range raw.Answers {
+		questionTitle := raw.QuestionTitles[answer.QuestionID]
+		templateData.Answers = append(templateData.Answers, &schema.DigestTemplateItem{
+			Title: questionTitle,
+			Url: display.AnswerURL(seoInfo.Permalink, siteInfo.SiteUrl,
+				answer.QuestionID, questionTitle, answer.ID),
+		})
+	}
+
+	lang := handler.GetLangByCtx(ctx)
+	title = translator.TrWithData(lang, constant.EmailTplKeyDigestTitle, templateData)
+	body = translator.TrWithData(lang, constant.EmailTplKeyDigestBody, templateData)
+	return title, body, nil
+}
+
+// SavedSearchAlertTemplate saved search alert template
+func (es *EmailService) SavedSearchAlertTemplate(ctx context.Context, raw *schema.SavedSearchAlertTemplateRawData) (
+	title, body string, err error) {
+	siteInfo, err := es.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	seoInfo, err := es.siteInfoService.GetSiteSeo(ctx)
+	if err != nil {
+		return
+	}
+	searchUrl := fmt.Sprintf("%s/search?q=%s", siteInfo.SiteUrl, url.QueryEscape(raw.Query))
+	if raw.IncludeSubTags {
+		searchUrl += "&include_sub_tags=true"
+	}
+	templateData := &schema.SavedSearchAlertTemplateData{
+		SiteName:  siteInfo.Name,
+		Name:      raw.Name,
+		Query:     raw.Query,
+		SearchUrl: searchUrl,
+		Questions: make([]*schema.SavedSearchAlertTemplateItem, 0),
+		Answers:   make([]*schema.SavedSearchAlertTemplateItem, 0),
+	}
+	for _, result := range raw.Results {
+		obj := result.Object
+		if result.ObjectType == constant.AnswerObjectType {
+			templateData.Answers = append(templateData.Answers, &schema.SavedSearchAlertTemplateItem{
+				Title: obj.Title,
+				Url:   display.AnswerURL(seoInfo.Permalink, siteInfo.SiteUrl, obj.QuestionID, obj.Title, obj.ID),
+			})
+			continue
+		}
+		templateData.Questions = append(templateData.Questions, &schema.SavedSearchAlertTemplateItem{
+			Title: obj.Title,
+			Url:   display.QuestionURL(seoInfo.Permalink, siteInfo.SiteUrl, obj.ID, obj.Title),
+		})
+	}
+
+	lang := handler.GetLangByCtx(ctx)
+	title = translator.TrWithData(lang, constant.EmailTplKeySavedSearchAlertTitle, templateData)
+	body = translator.TrWithData(lang, constant.EmailTplKeySavedSearchAlertBody, templateData)
+	return title, body, nil
+}
+
 func (es *EmailService) GetEmailConfig(ctx context.Context) (ec *EmailConfig, err error) {
 	emailConf, err := es.configService.GetStringValue(ctx, constant.EmailConfigKey)
 	if err != nil {
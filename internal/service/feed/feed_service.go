@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package feed
+
+import (
+	"context"
+
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/apache/incubator-answer/pkg/token"
+)
+
+// FeedRepo feed repository
+type FeedRepo interface {
+	GetFeedTokenByUserID(ctx context.Context, userID string) (feedToken *entity.FeedToken, exist bool, err error)
+	GetFeedTokenByToken(ctx context.Context, token string) (feedToken *entity.FeedToken, exist bool, err error)
+	SaveFeedToken(ctx context.Context, userID, token string) (err error)
+}
+
+// FeedService feed service
+type FeedService struct {
+	feedRepo FeedRepo
+	userRepo usercommon.UserRepo
+}
+
+// NewFeedService new feed service
+func NewFeedService(
+	feedRepo FeedRepo,
+	userRepo usercommon.UserRepo,
+) *FeedService {
+	return &FeedService{
+		feedRepo: feedRepo,
+		userRepo: userRepo,
+	}
+}
+
+// GetFeedToken get the feed token of user, generate one if user does not have
+func (fs *FeedService) GetFeedToken(ctx context.Context, userID string) (resp *schema.GetFeedTokenResp, err error) {
+	feedToken, exist, err := fs.feedRepo.GetFeedTokenByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return &schema.GetFeedTokenResp{Token: feedToken.Token}, nil
+	}
+	return fs.ResetFeedToken(ctx, userID)
+}
+
+// ResetFeedToken generate a new feed token for user, the feeds read by the old token will be unavailable
+func (fs *FeedService) ResetFeedToken(ctx context.Context, userID string) (resp *schema.GetFeedTokenResp, err error) {
+	newToken := token.GenerateToken()
+	if err = fs.feedRepo.SaveFeedToken(ctx, userID, newToken); err != nil {
+		return nil, err
+	}
+	return &schema.GetFeedTokenResp{Token: newToken}, nil
+}
+
+// CheckFeedToken check whether the feed token belongs to an available user
+func (fs *FeedService) CheckFeedToken(ctx context.Context, feedToken string) (valid bool, err error) {
+	if len(feedToken) == 0 {
+		return false, nil
+	}
+	tokenInfo, exist, err := fs.feedRepo.GetFeedTokenByToken(ctx, feedToken)
+	if err != nil || !exist {
+		return false, err
+	}
+	userInfo, exist, err := fs.userRepo.GetByUserID(ctx, tokenInfo.UserID)
+	if err != nil || !exist {
+		return false, err
+	}
+	return userInfo.Status == entity.UserStatusAvailable, nil
+}
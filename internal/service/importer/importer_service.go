@@ -21,83 +21,163 @@ package importer
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/apache/incubator-answer/internal/base/handler"
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/base/translator"
-	"github.com/apache/incubator-answer/internal/base/validator"
+	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/comment"
 	"github.com/apache/incubator-answer/internal/service/content"
 	"github.com/apache/incubator-answer/internal/service/permission"
+	questioncommon "github.com/apache/incubator-answer/internal/service/question_common"
 	"github.com/apache/incubator-answer/internal/service/rank"
+	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
 	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/apache/incubator-answer/pkg/converter"
+	"github.com/apache/incubator-answer/pkg/uid"
 	"github.com/apache/incubator-answer/plugin"
-	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
 )
 
+const (
+	sourceTypeQuestion = "question"
+	sourceTypeAnswer   = "answer"
+	sourceTypeComment  = "comment"
+	sourceTypeAccept   = "accept"
+	sourceTypeVote     = "vote"
+)
+
+// importClaimStaleTimeout the claim that is not completed in time is left by a crashed import, it can be claimed again
+const importClaimStaleTimeout = 10 * time.Minute
+
+// ImportRecordRepo import record repository
+type ImportRecordRepo interface {
+	GetImportRecord(ctx context.Context, source, sourceType, sourceID string) (
+		record *entity.ImportRecord, exist bool, err error)
+	ClaimImportRecord(ctx context.Context, record *entity.ImportRecord) (claimed bool, err error)
+	ReclaimImportRecord(ctx context.Context, staleRecordID string, record *entity.ImportRecord) (claimed bool, err error)
+	CompleteImportRecord(ctx context.Context, recordID, objectID string, createdAt, updatedAt time.Time) (err error)
+	RemoveImportRecord(ctx context.Context, recordID string) (err error)
+	UpdateObjectTime(ctx context.Context, objectID string, createdAt, updatedAt time.Time) (err error)
+	RefreshQuestionPostTime(ctx context.Context, questionID string) (err error)
+}
+
 // ImporterService importer service
 type ImporterService struct {
-	questionService *content.QuestionService
-	rankService     *rank.RankService
-	userCommon      *usercommon.UserCommon
+	questionService       *content.QuestionService
+	answerService         *content.AnswerService
+	commentService        *comment.CommentService
+	voteService           *content.VoteService
+	questionRepo          questioncommon.QuestionRepo
+	rankService           *rank.RankService
+	userCommon            *usercommon.UserCommon
+	importRecordRepo      ImportRecordRepo
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService
 }
 
-// NewRankService new rank service
+// NewImporterService new importer service
 func NewImporterService(
 	questionService *content.QuestionService,
+	answerService *content.AnswerService,
+	commentService *comment.CommentService,
+	voteService *content.VoteService,
+	questionRepo questioncommon.QuestionRepo,
 	rankService *rank.RankService,
-	userCommon *usercommon.UserCommon) *ImporterService {
+	userCommon *usercommon.UserCommon,
+	importRecordRepo ImportRecordRepo,
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+) *ImporterService {
 	return &ImporterService{
-		questionService: questionService,
-		rankService:     rankService,
-		userCommon:      userCommon,
+		questionService:       questionService,
+		answerService:         answerService,
+		commentService:        commentService,
+		voteService:           voteService,
+		questionRepo:          questionRepo,
+		rankService:           rankService,
+		userCommon:            userCommon,
+		importRecordRepo:      importRecordRepo,
+		siteInfoCommonService: siteInfoCommonService,
 	}
 }
 
+// ImporterFunc the functions provided to the importer plugin, the objects are recorded with the source of plugin
 type ImporterFunc struct {
 	importerService *ImporterService
+	source          string
 }
 
 func (ipfunc *ImporterFunc) AddQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) (err error) {
-	ipfunc.importerService.ImportQuestion(ctx, questionInfo)
-	return nil
+	_, err = ipfunc.importerService.ImportQuestion(ctx, ipfunc.source, questionInfo)
+	return err
 }
 
-func (ip *ImporterService) NewImporterFunc() plugin.ImporterFunc {
-	return &ImporterFunc{importerService: ip}
+func (ipfunc *ImporterFunc) ImportQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) (
+	result *plugin.ImporterResult, err error) {
+	return ipfunc.importerService.ImportQuestion(ctx, ipfunc.source, questionInfo)
 }
 
-func (ip *ImporterService) ImportQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) (err error) {
-	req := &schema.QuestionAdd{}
-	errFields := make([]*validator.FormErrorField, 0)
-	// To limit rate, remove the following code from comment: Part 1/2
-	// reject, rejectKey := ipc.rateLimitMiddleware.DuplicateRequestRejection(ctx, req)
-	// if reject {
-	// 	return
-	// }
-	userInfo, exist, err := ip.userCommon.GetByEmail(ctx, questionInfo.UserEmail)
-	if err != nil {
-		log.Errorf("error: %v", err)
-		return err
+func (ipfunc *ImporterFunc) AddAnswer(ctx context.Context, answerInfo plugin.AnswerImporterInfo) (
+	result *plugin.ImporterResult, err error) {
+	return ipfunc.importerService.ImportAnswer(ctx, ipfunc.source, answerInfo)
+}
+
+func (ipfunc *ImporterFunc) AddComment(ctx context.Context, commentInfo plugin.CommentImporterInfo) (
+	result *plugin.ImporterResult, err error) {
+	return ipfunc.importerService.ImportComment(ctx, ipfunc.source, commentInfo)
+}
+
+func (ipfunc *ImporterFunc) AcceptAnswer(ctx context.Context, acceptInfo plugin.AcceptAnswerImporterInfo) (err error) {
+	return ipfunc.importerService.ImportAcceptAnswer(ctx, ipfunc.source, acceptInfo)
+}
+
+func (ipfunc *ImporterFunc) AddVote(ctx context.Context, voteInfo plugin.VoteImporterInfo) (err error) {
+	return ipfunc.importerService.ImportVote(ctx, ipfunc.source, voteInfo)
+}
+
+// NewImporterFunc new importer functions for the importer plugin, it implements plugin.ImporterFuncV2 too
+func (ip *ImporterService) NewImporterFunc(slugName string) plugin.ImporterFunc {
+	return &ImporterFunc{importerService: ip, source: "plugin:" + slugName}
+}
+
+// ImportQuestion import question
+func (ip *ImporterService) ImportQuestion(ctx context.Context, source string, questionInfo plugin.QuestionImporterInfo) (
+	result *plugin.ImporterResult, err error) {
+	record, result, err := ip.claimImport(ctx, source, sourceTypeQuestion, questionInfo.SourceID)
+	if err != nil || result != nil {
+		return result, err
 	}
-	if !exist {
-		return fmt.Errorf("User not found")
+	questionID, err := ip.addQuestion(ctx, questionInfo)
+	if err != nil {
+		ip.abortImport(ctx, record)
+		return nil, err
+	}
+	if err = ip.completeImport(ctx, record, questionID, questionInfo.ImporterMeta); err != nil {
+		ip.abortImport(ctx, record)
+		return nil, err
+	}
+	log.Infof("importer %s added question %s", source, questionID)
+	return &plugin.ImporterResult{ID: questionID}, nil
+}
+
+func (ip *ImporterService) addQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) (
+	questionID string, err error) {
+	userID, err := ip.getUserIDByEmail(ctx, questionInfo.UserEmail)
+	if err != nil {
+		return "", err
+	}
+	bypass, err := ip.bypassRankCheck(ctx, questionInfo.BypassRankCheck)
+	if err != nil {
+		return "", err
 	}
 
-	// To limit rate, remove the following code from comment: Part 2/2
-	// defer func() {
-	// 	// If status is not 200 means that the bad request has been returned, so the record should be cleared
-	// 	if ctx.Writer.Status() != http.StatusOK {
-	// 		ipc.rateLimitMiddleware.DuplicateRequestClear(ctx, rejectKey)
-	// 	}
-	// }()
-	req.UserID = userInfo.ID
+	req := &schema.QuestionAdd{}
+	req.UserID = userID
 	req.Title = questionInfo.Title
 	req.Content = questionInfo.Content
-	req.HTML = "<p>" + questionInfo.Content + "</p>"
+	req.HTML = contentToHTML(questionInfo.Content, questionInfo.ContentFormat)
 	req.Tags = make([]*schema.TagItem, len(questionInfo.Tags))
 	for i, tag := range questionInfo.Tags {
 		req.Tags[i] = &schema.TagItem{
@@ -105,68 +185,350 @@ func (ip *ImporterService) ImportQuestion(ctx context.Context, questionInfo plug
 			DisplayName: tag,
 		}
 	}
-	canList, requireRanks, err := ip.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, []string{
-		permission.QuestionAdd,
-		permission.QuestionEdit,
-		permission.QuestionDelete,
-		permission.QuestionClose,
-		permission.QuestionReopen,
-		permission.TagUseReservedTag,
-		permission.TagAdd,
-		permission.LinkUrlLimit,
-	})
+	if bypass {
+		req.CanAdd, req.CanEdit, req.CanDelete, req.CanClose, req.CanReopen = true, true, true, true, true
+		req.CanUseReservedTag, req.CanAddTag = true, true
+	} else {
+		canList, requireRanks, err := ip.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, []string{
+			permission.QuestionAdd,
+			permission.QuestionEdit,
+			permission.QuestionDelete,
+			permission.QuestionClose,
+			permission.QuestionReopen,
+			permission.TagUseReservedTag,
+			permission.TagAdd,
+		})
+		if err != nil {
+			return "", err
+		}
+		req.CanAdd = canList[0]
+		req.CanEdit = canList[1]
+		req.CanDelete = canList[2]
+		req.CanClose = canList[3]
+		req.CanReopen = canList[4]
+		req.CanUseReservedTag = canList[5]
+		req.CanAddTag = canList[6]
+		if !req.CanAdd {
+			return "", noEnoughRankErr(ctx, requireRanks[0])
+		}
+		hasNewTag, err := ip.questionService.HasNewTag(ctx, req.Tags)
+		if err != nil {
+			return "", err
+		}
+		if !req.CanAddTag && hasNewTag {
+			return "", noEnoughRankErr(ctx, requireRanks[6])
+		}
+	}
+
+	if _, err = ip.questionService.CheckAddQuestion(ctx, req); err != nil {
+		return "", err
+	}
+	resp, err := ip.questionService.AddQuestion(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	questionResp, ok := resp.(*schema.QuestionInfoResp)
+	if !ok {
+		return "", errors.InternalServer(reason.UnknownError)
+	}
+	return uid.DeShortID(questionResp.ID), nil
+}
+
+// ImportAnswer import answer
+func (ip *ImporterService) ImportAnswer(ctx context.Context, source string, answerInfo plugin.AnswerImporterInfo) (
+	result *plugin.ImporterResult, err error) {
+	record, result, err := ip.claimImport(ctx, source, sourceTypeAnswer, answerInfo.SourceID)
+	if err != nil || result != nil {
+		return result, err
+	}
+	questionID := uid.DeShortID(answerInfo.QuestionID)
+	answerID, err := ip.addAnswer(ctx, questionID, answerInfo)
+	if err != nil {
+		ip.abortImport(ctx, record)
+		return nil, err
+	}
+	if err = ip.completeImport(ctx, record, answerID, answerInfo.ImporterMeta); err != nil {
+		ip.abortImport(ctx, record)
+		return nil, err
+	}
+	if !answerInfo.CreatedAt.IsZero() {
+		if err = ip.importRecordRepo.RefreshQuestionPostTime(ctx, questionID); err != nil {
+			return nil, err
+		}
+	}
+	return &plugin.ImporterResult{ID: answerID}, nil
+}
+
+func (ip *ImporterService) addAnswer(ctx context.Context, questionID string, answerInfo plugin.AnswerImporterInfo) (
+	answerID string, err error) {
+	userID, err := ip.getUserIDByEmail(ctx, answerInfo.UserEmail)
 	if err != nil {
-		log.Errorf("error: %v", err)
+		return "", err
+	}
+	if err = ip.checkPermission(ctx, userID, permission.AnswerAdd, answerInfo.BypassRankCheck); err != nil {
+		return "", err
+	}
+	req := &schema.AnswerAddReq{
+		QuestionID: questionID,
+		Content:    answerInfo.Content,
+		HTML:       contentToHTML(answerInfo.Content, answerInfo.ContentFormat),
+		UserID:     userID,
+	}
+	answerID, err = ip.answerService.Insert(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return uid.DeShortID(answerID), nil
+}
+
+// ImportComment import comment
+func (ip *ImporterService) ImportComment(ctx context.Context, source string, commentInfo plugin.CommentImporterInfo) (
+	result *plugin.ImporterResult, err error) {
+	record, result, err := ip.claimImport(ctx, source, sourceTypeComment, commentInfo.SourceID)
+	if err != nil || result != nil {
+		return result, err
+	}
+	commentID, err := ip.addComment(ctx, commentInfo)
+	if err != nil {
+		ip.abortImport(ctx, record)
+		return nil, err
+	}
+	if err = ip.completeImport(ctx, record, commentID, commentInfo.ImporterMeta); err != nil {
+		ip.abortImport(ctx, record)
+		return nil, err
+	}
+	return &plugin.ImporterResult{ID: commentID}, nil
+}
+
+func (ip *ImporterService) addComment(ctx context.Context, commentInfo plugin.CommentImporterInfo) (
+	commentID string, err error) {
+	userID, err := ip.getUserIDByEmail(ctx, commentInfo.UserEmail)
+	if err != nil {
+		return "", err
+	}
+	if err = ip.checkPermission(ctx, userID, permission.CommentAdd, commentInfo.BypassRankCheck); err != nil {
+		return "", err
+	}
+	req := &schema.AddCommentReq{
+		ObjectID:       uid.DeShortID(commentInfo.ObjectID),
+		OriginalText:   commentInfo.Content,
+		ParsedText:     contentToHTML(commentInfo.Content, commentInfo.ContentFormat),
+		ReplyCommentID: commentInfo.ReplyCommentID,
+		UserID:         userID,
+		CanAdd:         true,
+	}
+	resp, err := ip.commentService.AddComment(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.CommentID, nil
+}
+
+// ImportAcceptAnswer accept the answer on behalf of the question author,
+// nothing is changed if the answer has been accepted already.
+func (ip *ImporterService) ImportAcceptAnswer(ctx context.Context, source string,
+	acceptInfo plugin.AcceptAnswerImporterInfo) (err error) {
+	record, result, err := ip.claimImport(ctx, source, sourceTypeAccept, acceptInfo.SourceID)
+	if err != nil || result != nil {
+		return err
+	}
+	questionID := uid.DeShortID(acceptInfo.QuestionID)
+	if err = ip.acceptAnswer(ctx, questionID, uid.DeShortID(acceptInfo.AnswerID)); err != nil {
+		ip.abortImport(ctx, record)
 		return err
 	}
-	req.CanAdd = canList[0]
-	req.CanEdit = canList[1]
-	req.CanDelete = canList[2]
-	req.CanClose = canList[3]
-	req.CanReopen = canList[4]
-	req.CanUseReservedTag = canList[5]
-	req.CanAddTag = canList[6]
-	if !req.CanAdd {
-		log.Errorf("error: %v", err)
+	if err = ip.completeImport(ctx, record, questionID, plugin.ImporterMeta{}); err != nil {
+		ip.abortImport(ctx, record)
 		return err
 	}
-	hasNewTag, err := ip.questionService.HasNewTag(ctx.(*gin.Context), req.Tags)
+	return nil
+}
+
+func (ip *ImporterService) acceptAnswer(ctx context.Context, questionID, answerID string) (err error) {
+	questionInfo, exist, err := ip.questionRepo.GetQuestion(ctx, questionID)
 	if err != nil {
-		log.Errorf("error: %v", err)
 		return err
 	}
-	if !req.CanAddTag && hasNewTag {
-		lang := handler.GetLang(ctx.(*gin.Context))
-		msg := translator.TrWithData(lang, reason.NoEnoughRankToOperate, &schema.PermissionTrTplData{Rank: requireRanks[6]})
-		log.Errorf("error: %v", msg)
-		return errors.BadRequest(msg)
+	if !exist {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	if len(answerID) == 0 {
+		answerID = "0"
+	}
+	if questionInfo.AcceptedAnswerID == answerID {
+		return nil
 	}
+	return ip.answerService.AcceptAnswer(ctx, &schema.AcceptAnswerReq{
+		QuestionID: questionID,
+		AnswerID:   answerID,
+		UserID:     questionInfo.UserID,
+	})
+}
 
-	errList, err := ip.questionService.CheckAddQuestion(ctx, req)
+// ImportVote import vote
+func (ip *ImporterService) ImportVote(ctx context.Context, source string, voteInfo plugin.VoteImporterInfo) (err error) {
+	record, result, err := ip.claimImport(ctx, source, sourceTypeVote, voteInfo.SourceID)
+	if err != nil || result != nil {
+		return err
+	}
+	objectID := uid.DeShortID(voteInfo.ObjectID)
+	if err = ip.addVote(ctx, objectID, voteInfo); err != nil {
+		ip.abortImport(ctx, record)
+		return err
+	}
+	if err = ip.completeImport(ctx, record, objectID, plugin.ImporterMeta{}); err != nil {
+		ip.abortImport(ctx, record)
+		return err
+	}
+	return nil
+}
+
+func (ip *ImporterService) addVote(ctx context.Context, objectID string, voteInfo plugin.VoteImporterInfo) (err error) {
+	userID, err := ip.getUserIDByEmail(ctx, voteInfo.UserEmail)
 	if err != nil {
-		errlist, ok := errList.([]*validator.FormErrorField)
-		if ok {
-			errFields = append(errFields, errlist...)
+		return err
+	}
+	req := &schema.VoteReq{
+		ObjectID: objectID,
+		UserID:   userID,
+	}
+	bypass, err := ip.bypassRankCheck(ctx, voteInfo.BypassRankCheck)
+	if err != nil {
+		return err
+	}
+	if !bypass {
+		can, needRank, err := ip.rankService.CheckVotePermission(ctx, req.UserID, req.ObjectID, voteInfo.Up)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return noEnoughRankErr(ctx, needRank)
 		}
 	}
-	if len(errFields) > 0 {
-		return errors.BadRequest(reason.RequestFormatError)
+	if voteInfo.Up {
+		_, err = ip.voteService.VoteUp(ctx, req)
+	} else {
+		_, err = ip.voteService.VoteDown(ctx, req)
 	}
-	ginCtx := ctx.(*gin.Context)
-	req.UserAgent = ginCtx.GetHeader("User-Agent")
-	req.IP = ginCtx.ClientIP()
-	resp, err := ip.questionService.AddQuestion(ctx, req)
+	return err
+}
+
+// claimImport claim the source id before importing the object. The result is returned if the object with
+// the same source id has been imported, and the duplicate request error if it is still being imported.
+// The claim that is not completed for a while is left by a failed import, it can be claimed again.
+// The record is nil if the source id is empty, then the object is imported every time.
+func (ip *ImporterService) claimImport(ctx context.Context, source, sourceType, sourceID string) (
+	record *entity.ImportRecord, result *plugin.ImporterResult, err error) {
+	if len(sourceID) == 0 {
+		return nil, nil, nil
+	}
+	record = &entity.ImportRecord{
+		Source:     source,
+		SourceType: sourceType,
+		SourceID:   sourceID,
+	}
+	claimed, err := ip.importRecordRepo.ClaimImportRecord(ctx, record)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claimed {
+		return record, nil, nil
+	}
+	imported, exist, err := ip.importRecordRepo.GetImportRecord(ctx, source, sourceType, sourceID)
 	if err != nil {
-		errlist, ok := resp.([]*validator.FormErrorField)
-		if ok {
-			errFields = append(errFields, errlist...)
+		return nil, nil, err
+	}
+	if !exist {
+		return nil, nil, errors.BadRequest(reason.DuplicateRequestError)
+	}
+	if imported.ObjectID == "0" {
+		if time.Since(imported.CreatedAt) < importClaimStaleTimeout {
+			return nil, nil, errors.BadRequest(reason.DuplicateRequestError)
+		}
+		claimed, err = ip.importRecordRepo.ReclaimImportRecord(ctx, imported.ID, record)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !claimed {
+			return nil, nil, errors.BadRequest(reason.DuplicateRequestError)
 		}
+		log.Warnf("importer %s reclaimed the stale %s %s", source, sourceType, sourceID)
+		return record, nil, nil
+	}
+	return nil, &plugin.ImporterResult{ID: imported.ObjectID, Existed: true}, nil
+}
+
+// completeImport record the object created for the claimed source id and keep the original time of the object
+func (ip *ImporterService) completeImport(ctx context.Context, record *entity.ImportRecord, objectID string,
+	meta plugin.ImporterMeta) (err error) {
+	if record != nil {
+		return ip.importRecordRepo.CompleteImportRecord(ctx, record.ID, objectID, meta.CreatedAt, meta.UpdatedAt)
+	}
+	if meta.CreatedAt.IsZero() {
+		return nil
 	}
+	return ip.importRecordRepo.UpdateObjectTime(ctx, objectID, meta.CreatedAt, meta.UpdatedAt)
+}
+
+// abortImport release the claimed source id when the object fails to be imported, so it can be imported again
+func (ip *ImporterService) abortImport(ctx context.Context, record *entity.ImportRecord) {
+	if record == nil {
+		return
+	}
+	if err := ip.importRecordRepo.RemoveImportRecord(ctx, record.ID); err != nil {
+		log.Errorf("remove import record %s failed: %v", record.ID, err)
+	}
+}
 
-	if len(errFields) > 0 {
-		log.Errorf("error: RequestFormatError")
-		return errors.BadRequest(reason.RequestFormatError)
+// bypassRankCheck the importer bypasses the rank check only if the administrator allows it
+func (ip *ImporterService) bypassRankCheck(ctx context.Context, requested bool) (bypass bool, err error) {
+	if !requested {
+		return false, nil
+	}
+	siteWrite, err := ip.siteInfoCommonService.GetSiteWrite(ctx)
+	if err != nil {
+		return false, err
+	}
+	return siteWrite.ImporterBypassRankCheck, nil
+}
+
+func (ip *ImporterService) getUserIDByEmail(ctx context.Context, email string) (userID string, err error) {
+	userInfo, exist, err := ip.userCommon.GetByEmail(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.BadRequest(reason.UserNotFound)
+	}
+	return userInfo.ID, nil
+}
+
+func (ip *ImporterService) checkPermission(ctx context.Context, userID, action string, bypassRequested bool) (
+	err error) {
+	bypass, err := ip.bypassRankCheck(ctx, bypassRequested)
+	if err != nil || bypass {
+		return err
+	}
+	can, requireRanks, err := ip.rankService.CheckOperationPermissionsForRanks(ctx, userID, []string{action})
+	if err != nil {
+		return err
+	}
+	if !can[0] {
+		return noEnoughRankErr(ctx, requireRanks[0])
 	}
-	log.Info("Add Question Successfully")
 	return nil
 }
+
+func noEnoughRankErr(ctx context.Context, rank int) error {
+	lang := handler.GetLangByCtx(ctx)
+	msg := translator.TrWithData(lang, reason.NoEnoughRankToOperate, &schema.PermissionTrTplData{Rank: rank})
+	return errors.Forbidden(reason.NoEnoughRankToOperate).WithMsg(msg)
+}
+
+// contentToHTML convert the imported content to html, the markdown is used by default
+func contentToHTML(content, format string) string {
+	if format == plugin.ImporterContentFormatHTML {
+		return converter.SanitizeHTML(content)
+	}
+	return converter.Markdown2HTML(content)
+}
@@ -22,8 +22,9 @@ package notice_queue
 import (
 	"context"
 
+	"github.com/apache/incubator-answer/internal/base/queue"
 	"github.com/apache/incubator-answer/internal/schema"
-	"github.com/segmentfault/pacman/log"
+	"github.com/apache/incubator-answer/internal/service/service_config"
 )
 
 type ExternalNotificationQueueService interface {
@@ -31,39 +32,7 @@ type ExternalNotificationQueueService interface {
 	RegisterHandler(handler func(ctx context.Context, msg *schema.ExternalNotificationMsg) error)
 }
 
-type externalNotificationQueueService struct {
-	Queue   chan *schema.ExternalNotificationMsg
-	Handler func(ctx context.Context, msg *schema.ExternalNotificationMsg) error
-}
-
-func (ns *externalNotificationQueueService) Send(ctx context.Context, msg *schema.ExternalNotificationMsg) {
-	ns.Queue <- msg
-}
-
-func (ns *externalNotificationQueueService) RegisterHandler(
-	handler func(ctx context.Context, msg *schema.ExternalNotificationMsg) error) {
-	ns.Handler = handler
-}
-
-func (ns *externalNotificationQueueService) working() {
-	go func() {
-		for msg := range ns.Queue {
-			log.Debugf("received notification %+v", msg)
-			if ns.Handler == nil {
-				log.Warnf("no handler for notification")
-				continue
-			}
-			if err := ns.Handler(context.Background(), msg); err != nil {
-				log.Error(err)
-			}
-		}
-	}()
-}
-
 // NewNewQuestionNotificationQueueService create a new notification queue service
-func NewNewQuestionNotificationQueueService() ExternalNotificationQueueService {
-	ns := &externalNotificationQueueService{}
-	ns.Queue = make(chan *schema.ExternalNotificationMsg, 128)
-	ns.working()
-	return ns
+func NewNewQuestionNotificationQueueService(queueRepo queue.MessageRepo, serviceConf *service_config.ServiceConfig) ExternalNotificationQueueService {
+	return queue.New[*schema.ExternalNotificationMsg]("external_notification", queueRepo, serviceConf.Queue)
 }
@@ -22,8 +22,9 @@ package notice_queue
 import (
 	"context"
 
+	"github.com/apache/incubator-answer/internal/base/queue"
 	"github.com/apache/incubator-answer/internal/schema"
-	"github.com/segmentfault/pacman/log"
+	"github.com/apache/incubator-answer/internal/service/service_config"
 )
 
 type NotificationQueueService interface {
@@ -31,39 +32,7 @@ type NotificationQueueService interface {
 	RegisterHandler(handler func(ctx context.Context, msg *schema.NotificationMsg) error)
 }
 
-type notificationQueueService struct {
-	Queue   chan *schema.NotificationMsg
-	Handler func(ctx context.Context, msg *schema.NotificationMsg) error
-}
-
-func (ns *notificationQueueService) Send(ctx context.Context, msg *schema.NotificationMsg) {
-	ns.Queue <- msg
-}
-
-func (ns *notificationQueueService) RegisterHandler(
-	handler func(ctx context.Context, msg *schema.NotificationMsg) error) {
-	ns.Handler = handler
-}
-
-func (ns *notificationQueueService) working() {
-	go func() {
-		for msg := range ns.Queue {
-			log.Debugf("received notification %+v", msg)
-			if ns.Handler == nil {
-				log.Warnf("no handler for notification")
-				continue
-			}
-			if err := ns.Handler(context.Background(), msg); err != nil {
-				log.Error(err)
-			}
-		}
-	}()
-}
-
 // NewNotificationQueueService create a new notification queue service
-func NewNotificationQueueService() NotificationQueueService {
-	ns := &notificationQueueService{}
-	ns.Queue = make(chan *schema.NotificationMsg, 128)
-	ns.working()
-	return ns
+func NewNotificationQueueService(queueRepo queue.MessageRepo, serviceConf *service_config.ServiceConfig) NotificationQueueService {
+	return queue.New[*schema.NotificationMsg]("notification", queueRepo, serviceConf.Queue)
 }
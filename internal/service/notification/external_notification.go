@@ -29,6 +29,7 @@ import (
 	"github.com/apache/incubator-answer/internal/service/export"
 	"github.com/apache/incubator-answer/internal/service/notice_queue"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	"github.com/apache/incubator-answer/internal/service/tag_common"
 	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
 	"github.com/apache/incubator-answer/internal/service/user_external_login"
 	"github.com/apache/incubator-answer/internal/service/user_notification_config"
@@ -44,6 +45,7 @@ type ExternalNotificationService struct {
 	notificationQueueService   notice_queue.ExternalNotificationQueueService
 	userExternalLoginRepo      user_external_login.UserExternalLoginRepo
 	siteInfoService            siteinfo_common.SiteInfoCommonService
+	tagCommonService           *tag_common.TagCommonService
 }
 
 func NewExternalNotificationService(
@@ -55,6 +57,7 @@ func NewExternalNotificationService(
 	notificationQueueService notice_queue.ExternalNotificationQueueService,
 	userExternalLoginRepo user_external_login.UserExternalLoginRepo,
 	siteInfoService siteinfo_common.SiteInfoCommonService,
+	tagCommonService *tag_common.TagCommonService,
 ) *ExternalNotificationService {
 	n := &ExternalNotificationService{
 		data:                       data,
@@ -65,6 +68,7 @@ func NewExternalNotificationService(
 		notificationQueueService:   notificationQueueService,
 		userExternalLoginRepo:      userExternalLoginRepo,
 		siteInfoService:            siteInfoService,
+		tagCommonService:           tagCommonService,
 	}
 	notificationQueueService.RegisterHandler(n.Handler)
 	return n
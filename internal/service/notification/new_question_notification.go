@@ -79,7 +79,7 @@ func (ns *ExternalNotificationService) getNewQuestionSubscribers(ctx context.Con
 	// 1. get all this new question's tags followers
 	tagsFollowerIDs := make([]string, 0)
 	followerMapping := make(map[string]bool)
-	for _, tagID := range msg.NewQuestionTemplateRawData.TagIDs {
+	for _, tagID := range ns.getFollowedTagIDs(ctx, msg.NewQuestionTemplateRawData.TagIDs) {
 		userIDs, err := ns.followRepo.GetFollowUserIDs(ctx, tagID)
 		if err != nil {
 			log.Error(err)
@@ -138,6 +138,16 @@ func (ns *ExternalNotificationService) getNewQuestionSubscribers(ctx context.Con
 	return subscribers, nil
 }
 
+// getFollowedTagIDs the followers of parent tags are also notified, so the parent tags are included
+func (ns *ExternalNotificationService) getFollowedTagIDs(ctx context.Context, tagIDs []string) []string {
+	parentTagIDs, err := ns.tagCommonService.GetAncestorTagIDs(ctx, tagIDs)
+	if err != nil {
+		log.Error(err)
+		return tagIDs
+	}
+	return append(append(make([]string, 0, len(tagIDs)+len(parentTagIDs)), tagIDs...), parentTagIDs...)
+}
+
 func (ns *ExternalNotificationService) checkSendNewQuestionNotificationEmailLimit(ctx context.Context, userID string) bool {
 	key := constant.NewQuestionNotificationLimitCacheKeyPrefix + userID
 	old, exist, err := ns.data.Cache.GetInt64(ctx, key)
@@ -200,7 +210,7 @@ func (ns *ExternalNotificationService) syncNewQuestionNotificationToPlugin(ctx c
 	_ = plugin.CallNotification(func(fn plugin.Notification) error {
 		// 1. get all this new question's tags followers
 		subscribersMapping := make(map[string]plugin.NotificationType)
-		for _, tagID := range msg.NewQuestionTemplateRawData.TagIDs {
+		for _, tagID := range ns.getFollowedTagIDs(ctx, msg.NewQuestionTemplateRawData.TagIDs) {
 			userIDs, err := ns.followRepo.GetFollowUserIDs(ctx, tagID)
 			if err != nil {
 				log.Error(err)
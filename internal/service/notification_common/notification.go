@@ -31,6 +31,7 @@ import (
 
 	"github.com/apache/incubator-answer/internal/base/constant"
 	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/base/queue"
 	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
@@ -184,7 +185,7 @@ func (ns *NotificationCommon) AddNotification(ctx context.Context, msg *schema.N
 		return fmt.Errorf("get user basic info error: %w", err)
 	}
 	if !exist {
-		return fmt.Errorf("user not exist: %s", req.TriggerUserID)
+		return queue.Permanent(fmt.Errorf("user not exist: %s", req.TriggerUserID))
 	}
 	req.UserInfo = userBasicInfo
 	content, _ := json.Marshal(req)
@@ -350,6 +351,17 @@ func (ns *NotificationCommon) SendNotificationToAllFollower(ctx context.Context,
 	}
 }
 
+// SendNotificationToPlugin send the notification to the notification plugins only, it is not saved to inbox.
+// The inbox notification is synced to the plugins when it is added, so it should not be sent again.
+func (ns *NotificationCommon) SendNotificationToPlugin(ctx context.Context, msg *schema.NotificationMsg) {
+	objInfo, err := ns.objectInfoService.GetInfo(ctx, uid.DeShortID(msg.ObjectID))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	ns.syncNotificationToPlugin(ctx, objInfo, msg)
+}
+
 func (ns *NotificationCommon) syncNotificationToPlugin(ctx context.Context, objInfo *schema.SimpleObjectInfo,
 	msg *schema.NotificationMsg) {
 	siteInfo, err := ns.siteInfoService.GetSiteGeneral(ctx)
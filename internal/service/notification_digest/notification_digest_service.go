@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package notification_digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/cron"
+	"github.com/apache/incubator-answer/internal/base/translator"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/activity_common"
+	"github.com/apache/incubator-answer/internal/service/export"
+	notificationcommon "github.com/apache/incubator-answer/internal/service/notification_common"
+	questioncommon "github.com/apache/incubator-answer/internal/service/question_common"
+	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/apache/incubator-answer/internal/service/user_notification_config"
+	"github.com/apache/incubator-answer/pkg/token"
+	"github.com/segmentfault/pacman/i18n"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// digestItemLimit the max number of questions or answers in one digest
+	digestItemLimit = 10
+	// digestDueTolerance the digest job runs hourly, so the digest is sent a little earlier to avoid drifting
+	digestDueTolerance = 10 * time.Minute
+)
+
+// NotificationDigestRepo notification digest repository
+type NotificationDigestRepo interface {
+	GetLastDigest(ctx context.Context, userID string) (digest *entity.NotificationDigest, exist bool, err error)
+	SaveLastDigest(ctx context.Context, userID string, sentAt time.Time) (err error)
+	GetTagsNewQuestions(ctx context.Context, tagIDs []string, excludeUserID string, since time.Time, limit int) (
+		questions []*entity.Question, err error)
+	GetQuestionsNewAnswers(ctx context.Context, questionIDs []string, excludeUserID string, since time.Time,
+		limit int) (answers []*entity.Answer, err error)
+}
+
+// NotificationDigestService send the periodic digest of activity to the users who subscribe it
+type NotificationDigestService struct {
+	notificationDigestRepo     NotificationDigestRepo
+	userNotificationConfigRepo user_notification_config.UserNotificationConfigRepo
+	followRepo                 activity_common.FollowRepo
+	questionRepo               questioncommon.QuestionRepo
+	notificationRepo           notificationcommon.NotificationRepo
+	userRepo                   usercommon.UserRepo
+	emailService               *export.EmailService
+	siteInfoService            siteinfo_common.SiteInfoCommonService
+}
+
+// NewNotificationDigestService new notification digest service
+func NewNotificationDigestService(
+	notificationDigestRepo NotificationDigestRepo,
+	userNotificationConfigRepo user_notification_config.UserNotificationConfigRepo,
+	followRepo activity_common.FollowRepo,
+	questionRepo questioncommon.QuestionRepo,
+	notificationRepo notificationcommon.NotificationRepo,
+	userRepo usercommon.UserRepo,
+	emailService *export.EmailService,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+	cronManager *cron.ScheduledTaskManager,
+) *NotificationDigestService {
+	ns := &NotificationDigestService{
+		notificationDigestRepo:     notificationDigestRepo,
+		userNotificationConfigRepo: userNotificationConfigRepo,
+		followRepo:                 followRepo,
+		questionRepo:               questionRepo,
+		notificationRepo:           notificationRepo,
+		userRepo:                   userRepo,
+		emailService:               emailService,
+		siteInfoService:            siteInfoService,
+	}
+	if err := cronManager.Register(&cron.Job{
+		Name:        "send_digest",
+		Spec:        "0 */1 * * *",
+		Description: "send the daily or weekly digest emails which are due",
+		Run:         ns.SendDigestCron,
+	}); err != nil {
+		log.Error(err)
+	}
+	return ns
+}
+
+// SendDigestCron send the digest to the users whose digest is due
+func (ns *NotificationDigestService) SendDigestCron(ctx context.Context) (err error) {
+	configs, err := ns.userNotificationConfigRepo.GetBySource(ctx, constant.DigestSource)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, config := range configs {
+		channel := schema.NewNotificationChannelConfigFormJson(config.Channels)
+		if channel.Key != constant.EmailChannel || !channel.Enable {
+			continue
+		}
+		period := getDigestPeriod(channel.Frequency)
+		since := now.Add(-period)
+		lastDigest, exist, err := ns.notificationDigestRepo.GetLastDigest(ctx, config.UserID)
+		if err != nil {
+			return err
+		}
+		if exist {
+			if lastDigest.LastSentAt.Add(period).After(now.Add(digestDueTolerance)) {
+				continue
+			}
+			since = lastDigest.LastSentAt
+		}
+		if err = ns.sendDigest(ctx, config.UserID, channel.Frequency, since); err != nil {
+			log.Errorf("send digest to user %s failed: %v", config.UserID, err)
+			continue
+		}
+		if err = ns.notificationDigestRepo.SaveLastDigest(ctx, config.UserID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendDigest send the digest of the activity since the time, nothing is sent if there is no activity
+func (ns *NotificationDigestService) sendDigest(ctx context.Context, userID, frequency string, since time.Time) (
+	err error) {
+	userInfo, exist, err := ns.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exist || userInfo.Status != entity.UserStatusAvailable || userInfo.MailStatus != entity.EmailStatusAvailable {
+		return nil
+	}
+
+	rawData := &schema.DigestTemplateRawData{
+		Frequency:       frequency,
+		QuestionTitles:  make(map[string]string),
+		UnsubscribeCode: token.GenerateToken(),
+	}
+	tagIDs, err := ns.followRepo.GetFollowIDs(ctx, userID, entity.Tag{}.TableName())
+	if err != nil {
+		return err
+	}
+	rawData.Questions, err = ns.notificationDigestRepo.GetTagsNewQuestions(ctx, tagIDs, userID, since, digestItemLimit)
+	if err != nil {
+		return err
+	}
+	questionIDs, err := ns.followRepo.GetFollowIDs(ctx, userID, entity.Question{}.TableName())
+	if err != nil {
+		return err
+	}
+	rawData.Answers, err = ns.notificationDigestRepo.GetQuestionsNewAnswers(
+		ctx, questionIDs, userID, since, digestItemLimit)
+	if err != nil {
+		return err
+	}
+	if len(rawData.Answers) > 0 {
+		answeredQuestionIDs := make([]string, 0, len(rawData.Answers))
+		for _, answer := range rawData.Answers {
+			answeredQuestionIDs = append(answeredQuestionIDs, answer.QuestionID)
+		}
+		questions, err := ns.questionRepo.FindByID(ctx, answeredQuestionIDs)
+		if err != nil {
+			return err
+		}
+		for _, question := range questions {
+			rawData.QuestionTitles[question.ID] = question.Title
+		}
+	}
+	rawData.UnreadCount, err = ns.notificationRepo.CountNotificationByUser(ctx, &entity.Notification{
+		UserID: userID,
+		Type:   schema.NotificationTypeInbox,
+		IsRead: schema.NotificationNotRead,
+		Status: schema.NotificationStatusNormal,
+	})
+	if err != nil {
+		return err
+	}
+	if len(rawData.Questions) == 0 && len(rawData.Answers) == 0 && rawData.UnreadCount == 0 {
+		return nil
+	}
+
+	// If receiver has set language, use it to send email, otherwise use the site default language.
+	lang := userInfo.Language
+	if len(lang) == 0 || lang == translator.DefaultLangOption {
+		if interfaceInfo, _ := ns.siteInfoService.GetSiteInterface(ctx); interfaceInfo != nil {
+			lang = interfaceInfo.Language
+		}
+	}
+	ctx = context.WithValue(ctx, constant.AcceptLanguageFlag, i18n.Language(lang))
+	title, body, err := ns.emailService.DigestTemplate(ctx, rawData)
+	if err != nil {
+		return err
+	}
+
+	codeContent := &schema.EmailCodeContent{
+		SourceType:               schema.UnsubscribeSourceType,
+		Email:                    userInfo.EMail,
+		UserID:                   userID,
+		NotificationSources:      []constant.NotificationSource{constant.DigestSource},
+		SkipValidationLatestCode: true,
+	}
+	ns.emailService.SendAndSaveCodeWithTime(ctx, userInfo.ID, userInfo.EMail, title, body,
+		rawData.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
+	return nil
+}
+
+func getDigestPeriod(frequency string) time.Duration {
+	if frequency == schema.NotificationDigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
@@ -0,0 +1,277 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package notification_push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/event_queue"
+	"github.com/apache/incubator-answer/internal/service/notice_queue"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	pushPollInterval     = time.Second
+	subscriberBufferSize = 16
+	// pushMissingRetry the event may be published but not written to the cache yet,
+	// so it is skipped only if it is still missing after these polls
+	pushMissingRetry = 3
+)
+
+// Subscriber the online user waiting for the pushed events
+type Subscriber struct {
+	UserID     string
+	QuestionID string
+	Events     chan *schema.PushEvent
+}
+
+// NotificationPushService push the events to the online users.
+// The events are published to the cache with an increasing sequence. Every instance polls the sequence
+// and dispatches the new events to its own subscribers, so the events reach the users connected to any instance.
+type NotificationPushService struct {
+	data        *data.Data
+	subscribers map[*Subscriber]struct{}
+	// redDots the red dot of the latest event of each online user, it is shared by all the sessions of user
+	redDots   map[string]*redDot
+	lock      sync.RWMutex
+	seqLock   sync.Mutex
+	startOnce sync.Once
+}
+
+// redDot the red dot computed for the event
+type redDot struct {
+	seq   int64
+	once  sync.Once
+	value any
+	err   error
+}
+
+// NewNotificationPushService new notification push service
+func NewNotificationPushService(
+	data *data.Data,
+	notificationQueueService notice_queue.NotificationQueueService,
+	eventQueueService event_queue.EventQueueService,
+) *NotificationPushService {
+	ps := &NotificationPushService{
+		data:        data,
+		subscribers: make(map[*Subscriber]struct{}),
+		redDots:     make(map[string]*redDot),
+	}
+	notificationQueueService.RegisterHandler(ps.NotificationHandler)
+	eventQueueService.RegisterHandler(ps.EventHandler)
+	return ps
+}
+
+// Subscribe subscribe the events of user, the question id is optional
+func (ps *NotificationPushService) Subscribe(userID, questionID string) (sub *Subscriber) {
+	ps.startOnce.Do(func() {
+		go ps.receiving()
+	})
+	sub = &Subscriber{
+		UserID:     userID,
+		QuestionID: questionID,
+		Events:     make(chan *schema.PushEvent, subscriberBufferSize),
+	}
+	ps.lock.Lock()
+	ps.subscribers[sub] = struct{}{}
+	ps.lock.Unlock()
+	return sub
+}
+
+// Unsubscribe stop pushing events to the subscriber
+func (ps *NotificationPushService) Unsubscribe(sub *Subscriber) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+	delete(ps.subscribers, sub)
+	for other := range ps.subscribers {
+		if other.UserID == sub.UserID {
+			return
+		}
+	}
+	delete(ps.redDots, sub.UserID)
+}
+
+// GetRedDot get the red dot of user for the event, the red dot is computed by get only once
+// for all the sessions of user that receive the same event
+func (ps *NotificationPushService) GetRedDot(userID string, seq int64, get func() (any, error)) (any, error) {
+	ps.lock.Lock()
+	dot, ok := ps.redDots[userID]
+	if !ok || dot.seq != seq {
+		dot = &redDot{seq: seq}
+		ps.redDots[userID] = dot
+	}
+	ps.lock.Unlock()
+	dot.once.Do(func() {
+		dot.value, dot.err = get()
+	})
+	return dot.value, dot.err
+}
+
+// Publish publish the event to the subscribers of all instances
+func (ps *NotificationPushService) Publish(ctx context.Context, event *schema.PushEvent) (err error) {
+	content, _ := json.Marshal(event)
+	seq, err := ps.nextSeq(ctx)
+	if err != nil {
+		return fmt.Errorf("increase push event sequence failed: %w", err)
+	}
+	err = ps.data.Cache.SetString(ctx, constant.PushEventCacheKeyPrefix+strconv.FormatInt(seq, 10),
+		string(content), constant.PushEventCacheTime)
+	if err != nil {
+		return fmt.Errorf("save push event failed: %w", err)
+	}
+	return nil
+}
+
+// nextSeq increase the sequence of the events. The sequence is only started from 1 if it does not exist,
+// the error of increasing must not reset it, otherwise the receivers would skip or repeat the events.
+func (ps *NotificationPushService) nextSeq(ctx context.Context) (seq int64, err error) {
+	if counter, ok := ps.data.Cache.(data.CounterCache); ok {
+		return counter.IncreaseWithTTL(ctx, constant.PushEventSeqCacheKey, 1, 0)
+	}
+	// the memory cache can not increase the key which is not existed
+	ps.seqLock.Lock()
+	defer ps.seqLock.Unlock()
+	_, exist, err := ps.data.Cache.GetInt64(ctx, constant.PushEventSeqCacheKey)
+	if err != nil {
+		return 0, err
+	}
+	if exist {
+		return ps.data.Cache.Increase(ctx, constant.PushEventSeqCacheKey, 1)
+	}
+	return 1, ps.data.Cache.SetInt64(ctx, constant.PushEventSeqCacheKey, 1, 0)
+}
+
+// NotificationHandler push the new notification and the red dot to the receiver
+func (ps *NotificationPushService) NotificationHandler(ctx context.Context, msg *schema.NotificationMsg) error {
+	if len(msg.ReceiverUserID) == 0 {
+		return nil
+	}
+	return ps.Publish(ctx, &schema.PushEvent{
+		Type:   schema.PushEventTypeNotification,
+		UserID: msg.ReceiverUserID,
+		Data: map[string]string{
+			"notification_action": msg.NotificationAction,
+			"object_type":         msg.ObjectType,
+			"object_id":           msg.ObjectID,
+		},
+	})
+}
+
+// EventHandler push the new answer to the users viewing the question,
+// and notify the reviewers that the review queue may be changed.
+func (ps *NotificationPushService) EventHandler(ctx context.Context, msg *schema.EventMsg) error {
+	switch msg.EventType {
+	case constant.EventAnswerCreate:
+		err := ps.Publish(ctx, &schema.PushEvent{
+			Type:       schema.PushEventTypeNewAnswer,
+			QuestionID: msg.QuestionID,
+			Data: map[string]string{
+				"question_id": msg.QuestionID,
+				"answer_id":   msg.AnswerID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return ps.Publish(ctx, &schema.PushEvent{Type: schema.PushEventTypeReview})
+	case constant.EventQuestionCreate, constant.EventQuestionFlag, constant.EventAnswerFlag,
+		constant.EventCommentFlag:
+		return ps.Publish(ctx, &schema.PushEvent{Type: schema.PushEventTypeReview})
+	}
+	return nil
+}
+
+// PushRedDot notify all online sessions of user that the red dot is changed
+func (ps *NotificationPushService) PushRedDot(ctx context.Context, userID string) {
+	if err := ps.Publish(ctx, &schema.PushEvent{Type: schema.PushEventTypeRedDot, UserID: userID}); err != nil {
+		log.Error(err)
+	}
+}
+
+func (ps *NotificationPushService) receiving() {
+	ctx := context.Background()
+	lastSeq, _, err := ps.data.Cache.GetInt64(ctx, constant.PushEventSeqCacheKey)
+	if err != nil {
+		log.Errorf("get push event sequence failed: %v", err)
+	}
+	missing := 0
+	ticker := time.NewTicker(pushPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		seq, _, err := ps.data.Cache.GetInt64(ctx, constant.PushEventSeqCacheKey)
+		if err != nil {
+			log.Errorf("get push event sequence failed: %v", err)
+			continue
+		}
+		// the sequence is reset when the cache is flushed
+		if seq < lastSeq {
+			lastSeq = 0
+		}
+		for lastSeq < seq {
+			content, exist, err := ps.data.Cache.GetString(ctx,
+				constant.PushEventCacheKeyPrefix+strconv.FormatInt(lastSeq+1, 10))
+			if err != nil {
+				log.Errorf("get push event failed: %v", err)
+				break
+			}
+			if !exist && missing < pushMissingRetry {
+				missing++
+				break
+			}
+			if exist {
+				event := &schema.PushEvent{}
+				if err = json.Unmarshal([]byte(content), event); err != nil {
+					log.Errorf("unmarshal push event failed: %v", err)
+				} else {
+					event.Seq = lastSeq + 1
+					ps.dispatch(event)
+				}
+			}
+			missing = 0
+			lastSeq++
+		}
+	}
+}
+
+// dispatch send the event to the subscribers of this instance, the event is dropped if the subscriber is too slow
+func (ps *NotificationPushService) dispatch(event *schema.PushEvent) {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+	for sub := range ps.subscribers {
+		if len(event.UserID) > 0 && event.UserID != sub.UserID {
+			continue
+		}
+		if len(event.QuestionID) > 0 && event.QuestionID != sub.QuestionID {
+			continue
+		}
+		select {
+		case sub.Events <- event:
+		default:
+		}
+	}
+}
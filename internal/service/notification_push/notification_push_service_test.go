@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package notification_push
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/data"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/segmentfault/pacman/cache"
+	"github.com/segmentfault/pacman/contrib/cache/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPushService(cache cache.Cache) *NotificationPushService {
+	return &NotificationPushService{
+		data:        &data.Data{Cache: cache},
+		subscribers: make(map[*Subscriber]struct{}),
+		redDots:     make(map[string]*redDot),
+	}
+}
+
+// failIncreaseCache the cache that fails to increase the existing key
+type failIncreaseCache struct {
+	cache.Cache
+}
+
+func (c *failIncreaseCache) Increase(ctx context.Context, key string, value int64) (int64, error) {
+	return 0, errors.New("increase failed")
+}
+
+func receiveEvent(t *testing.T, sub *Subscriber) *schema.PushEvent {
+	select {
+	case event := <-sub.Events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+	return nil
+}
+
+func TestNotificationPushService_Dispatch(t *testing.T) {
+	ctx := context.TODO()
+	// the services of two instances share the same cache
+	cache := memory.NewCache()
+	publisher := newTestPushService(cache)
+	receiver := newTestPushService(cache)
+
+	userSub := receiver.Subscribe("1", "")
+	questionSub := receiver.Subscribe("2", "10010000000000001")
+	defer receiver.Unsubscribe(userSub)
+	defer receiver.Unsubscribe(questionSub)
+	// wait for the receiver to read the current sequence
+	time.Sleep(100 * time.Millisecond)
+
+	publisher.PushRedDot(ctx, "1")
+	require.NoError(t, publisher.Publish(ctx,
+		&schema.PushEvent{Type: schema.PushEventTypeNewAnswer, QuestionID: "10010000000000001"}))
+	require.NoError(t, publisher.Publish(ctx, &schema.PushEvent{Type: schema.PushEventTypeReview}))
+
+	event := receiveEvent(t, userSub)
+	require.NotNil(t, event)
+	assert.Equal(t, schema.PushEventTypeRedDot, event.Type)
+	event = receiveEvent(t, userSub)
+	require.NotNil(t, event)
+	assert.Equal(t, schema.PushEventTypeReview, event.Type)
+
+	event = receiveEvent(t, questionSub)
+	require.NotNil(t, event)
+	assert.Equal(t, schema.PushEventTypeNewAnswer, event.Type)
+	event = receiveEvent(t, questionSub)
+	require.NotNil(t, event)
+	assert.Equal(t, schema.PushEventTypeReview, event.Type)
+}
+
+func TestNotificationPushService_PublishKeepSequence(t *testing.T) {
+	ctx := context.TODO()
+	memoryCache := memory.NewCache()
+	ps := newTestPushService(memoryCache)
+	require.NoError(t, ps.Publish(ctx, &schema.PushEvent{Type: schema.PushEventTypeReview}))
+	require.NoError(t, ps.Publish(ctx, &schema.PushEvent{Type: schema.PushEventTypeReview}))
+
+	// the failure of increasing must not reset the sequence
+	ps.data.Cache = &failIncreaseCache{Cache: memoryCache}
+	assert.Error(t, ps.Publish(ctx, &schema.PushEvent{Type: schema.PushEventTypeReview}))
+	seq, _, err := memoryCache.GetInt64(ctx, constant.PushEventSeqCacheKey)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), seq)
+}
+
+func TestNotificationPushService_GetRedDot(t *testing.T) {
+	ps := newTestPushService(memory.NewCache())
+	sub1 := ps.Subscribe("1", "")
+	sub2 := ps.Subscribe("1", "")
+
+	calls := 0
+	get := func() (any, error) {
+		calls++
+		return calls, nil
+	}
+	// the sessions of the same user share the red dot of the same event
+	value, err := ps.GetRedDot("1", 1, get)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	value, err = ps.GetRedDot("1", 1, get)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 1, calls)
+
+	// the new event computes the red dot again
+	value, err = ps.GetRedDot("1", 2, get)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	// the red dot is removed after all the sessions of user are closed
+	ps.Unsubscribe(sub1)
+	assert.Contains(t, ps.redDots, "1")
+	ps.Unsubscribe(sub2)
+	assert.NotContains(t, ps.redDots, "1")
+}
@@ -62,6 +62,11 @@ const (
 	AnswerUnDelete              = "answer.undeleted"
 	QuestionUnDelete            = "question.undeleted"
 	TagUnDelete                 = "tag.undeleted"
+	QuestionBounty              = "question.bounty"
+	// ContentModerate moderate the content and the users, such as seeing the hidden content and handling reports
+	ContentModerate = "content.moderate"
+	// QuestionMerge merge the duplicate question into another question
+	QuestionMerge = "question.merge"
 )
 
 const (
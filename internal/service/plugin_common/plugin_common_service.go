@@ -104,7 +104,7 @@ func (ps *PluginCommonService) UpdatePluginConfig(ctx context.Context, req *sche
 		return nil
 	})
 	_ = plugin.CallImporter(func(importer plugin.Importer) error {
-		importer.RegisterImporterFunc(ctx, ps.importerService.NewImporterFunc())
+		importer.RegisterImporterFunc(ctx, ps.importerService.NewImporterFunc(importer.Info().SlugName))
 		return nil
 	})
 	return nil
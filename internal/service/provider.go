@@ -25,8 +25,11 @@ import (
 	"github.com/apache/incubator-answer/internal/service/activity_common"
 	"github.com/apache/incubator-answer/internal/service/activity_queue"
 	answercommon "github.com/apache/incubator-answer/internal/service/answer_common"
+	"github.com/apache/incubator-answer/internal/service/api_key"
+	"github.com/apache/incubator-answer/internal/service/audit_log"
 	"github.com/apache/incubator-answer/internal/service/auth"
 	"github.com/apache/incubator-answer/internal/service/badge"
+	"github.com/apache/incubator-answer/internal/service/bounty"
 	"github.com/apache/incubator-answer/internal/service/collection"
 	collectioncommon "github.com/apache/incubator-answer/internal/service/collection_common"
 	"github.com/apache/incubator-answer/internal/service/comment"
@@ -34,8 +37,10 @@ import (
 	"github.com/apache/incubator-answer/internal/service/config"
 	"github.com/apache/incubator-answer/internal/service/content"
 	"github.com/apache/incubator-answer/internal/service/dashboard"
+	"github.com/apache/incubator-answer/internal/service/draft"
 	"github.com/apache/incubator-answer/internal/service/event_queue"
 	"github.com/apache/incubator-answer/internal/service/export"
+	"github.com/apache/incubator-answer/internal/service/feed"
 	"github.com/apache/incubator-answer/internal/service/follow"
 	"github.com/apache/incubator-answer/internal/service/importer"
 	"github.com/apache/incubator-answer/internal/service/meta"
@@ -43,9 +48,12 @@ import (
 	"github.com/apache/incubator-answer/internal/service/notice_queue"
 	"github.com/apache/incubator-answer/internal/service/notification"
 	notficationcommon "github.com/apache/incubator-answer/internal/service/notification_common"
+	"github.com/apache/incubator-answer/internal/service/notification_digest"
+	"github.com/apache/incubator-answer/internal/service/notification_push"
 	"github.com/apache/incubator-answer/internal/service/object_info"
 	"github.com/apache/incubator-answer/internal/service/plugin_common"
 	questioncommon "github.com/apache/incubator-answer/internal/service/question_common"
+	"github.com/apache/incubator-answer/internal/service/question_merge"
 	"github.com/apache/incubator-answer/internal/service/rank"
 	"github.com/apache/incubator-answer/internal/service/reason"
 	"github.com/apache/incubator-answer/internal/service/report"
@@ -53,16 +61,20 @@ import (
 	"github.com/apache/incubator-answer/internal/service/review"
 	"github.com/apache/incubator-answer/internal/service/revision_common"
 	"github.com/apache/incubator-answer/internal/service/role"
+	"github.com/apache/incubator-answer/internal/service/saved_search"
 	"github.com/apache/incubator-answer/internal/service/search_parser"
+	"github.com/apache/incubator-answer/internal/service/search_reindex"
 	"github.com/apache/incubator-answer/internal/service/siteinfo"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
 	"github.com/apache/incubator-answer/internal/service/tag"
 	tagcommon "github.com/apache/incubator-answer/internal/service/tag_common"
+	"github.com/apache/incubator-answer/internal/service/two_factor"
 	"github.com/apache/incubator-answer/internal/service/uploader"
 	"github.com/apache/incubator-answer/internal/service/user_admin"
 	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
 	"github.com/apache/incubator-answer/internal/service/user_external_login"
 	"github.com/apache/incubator-answer/internal/service/user_notification_config"
+	"github.com/apache/incubator-answer/internal/service/webhook"
 	"github.com/google/wire"
 )
 
@@ -126,4 +138,16 @@ var ProviderSetService = wire.NewSet(
 	badge.NewBadgeAwardService,
 	badge.NewBadgeGroupService,
 	importer.NewImporterService,
+	webhook.NewWebhookService,
+	api_key.NewAPIKeyService,
+	two_factor.NewTwoFactorService,
+	audit_log.NewAuditLogService,
+	question_merge.NewQuestionMergeService,
+	bounty.NewBountyService,
+	draft.NewDraftService,
+	notification_digest.NewNotificationDigestService,
+	notification_push.NewNotificationPushService,
+	feed.NewFeedService,
+	search_reindex.NewSearchReindexService,
+	saved_search.NewSavedSearchService,
 )
@@ -0,0 +1,358 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package question_merge
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/activity"
+	"github.com/apache/incubator-answer/internal/service/activity_queue"
+	answercommon "github.com/apache/incubator-answer/internal/service/answer_common"
+	"github.com/apache/incubator-answer/internal/service/config"
+	metacommon "github.com/apache/incubator-answer/internal/service/meta_common"
+	"github.com/apache/incubator-answer/internal/service/notice_queue"
+	questioncommon "github.com/apache/incubator-answer/internal/service/question_common"
+	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	"github.com/apache/incubator-answer/pkg/converter"
+	"github.com/apache/incubator-answer/pkg/display"
+	"github.com/apache/incubator-answer/pkg/uid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// QuestionMergeRepo question merge repository
+type QuestionMergeRepo interface {
+	MergeQuestion(ctx context.Context, merge *entity.QuestionMerge, moveComments bool) (err error)
+	// RevertQuestionMerge it returns false if the merge has been reverted by others
+	RevertQuestionMerge(ctx context.Context, merge *entity.QuestionMerge) (ok bool, err error)
+	GetQuestionMerge(ctx context.Context, sourceQuestionID string) (merge *entity.QuestionMerge, exist bool, err error)
+}
+
+// QuestionMergeService merge the duplicate question into the canonical question
+type QuestionMergeService struct {
+	questionMergeRepo        QuestionMergeRepo
+	questionRepo             questioncommon.QuestionRepo
+	answerRepo               answercommon.AnswerRepo
+	questionCommon           *questioncommon.QuestionCommon
+	metaCommonService        *metacommon.MetaCommonService
+	configService            *config.ConfigService
+	siteInfoService          siteinfo_common.SiteInfoCommonService
+	activityQueueService     activity_queue.ActivityQueueService
+	notificationQueueService notice_queue.NotificationQueueService
+	answerActivityService    *activity.AnswerActivityService
+}
+
+// NewQuestionMergeService new question merge service
+func NewQuestionMergeService(
+	questionMergeRepo QuestionMergeRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	questionCommon *questioncommon.QuestionCommon,
+	metaCommonService *metacommon.MetaCommonService,
+	configService *config.ConfigService,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+	activityQueueService activity_queue.ActivityQueueService,
+	notificationQueueService notice_queue.NotificationQueueService,
+	answerActivityService *activity.AnswerActivityService,
+) *QuestionMergeService {
+	return &QuestionMergeService{
+		questionMergeRepo:        questionMergeRepo,
+		questionRepo:             questionRepo,
+		answerRepo:               answerRepo,
+		questionCommon:           questionCommon,
+		metaCommonService:        metaCommonService,
+		configService:            configService,
+		siteInfoService:          siteInfoService,
+		activityQueueService:     activityQueueService,
+		notificationQueueService: notificationQueueService,
+		answerActivityService:    answerActivityService,
+	}
+}
+
+// MergeQuestion move the answers of duplicate question to the canonical question and close the duplicate question
+func (qs *QuestionMergeService) MergeQuestion(ctx context.Context, req *schema.MergeQuestionReq) (
+	resp *schema.MergeQuestionResp, err error) {
+	sourceID, targetID := uid.DeShortID(req.QuestionID), uid.DeShortID(req.TargetQuestionID)
+	if sourceID == targetID {
+		return nil, errors.BadRequest(reason.QuestionCannotMergeIntoItself)
+	}
+	source, err := qs.getAvailableQuestion(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := qs.getAvailableQuestion(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	// the merged question can not be merged again, and can not be the target to avoid the merge chain
+	for _, questionID := range []string{sourceID, targetID} {
+		_, merged, err := qs.questionMergeRepo.GetQuestionMerge(ctx, questionID)
+		if err != nil {
+			return nil, err
+		}
+		if merged {
+			return nil, errors.BadRequest(reason.QuestionAlreadyMerged)
+		}
+	}
+
+	merge := &entity.QuestionMerge{
+		SourceQuestionID:       sourceID,
+		TargetQuestionID:       targetID,
+		UserID:                 req.UserID,
+		SourceStatus:           source.Status,
+		SourceAcceptedAnswerID: source.AcceptedAnswerID,
+		Status:                 entity.QuestionMergeStatusMerged,
+	}
+	if err = qs.questionMergeRepo.MergeQuestion(ctx, merge, req.MoveComments); err != nil {
+		// the source question may be merged by others at the same time
+		if _, merged, _ := qs.questionMergeRepo.GetQuestionMerge(ctx, sourceID); merged {
+			return nil, errors.BadRequest(reason.QuestionAlreadyMerged)
+		}
+		return nil, err
+	}
+	answerIDs, commentIDs := parseMergedIDs(merge)
+	qs.refreshQuestions(ctx, merge, answerIDs)
+	// the moved answers are not accepted by target question, so the accepted answer of source question is revoked
+	qs.updateAcceptedAnswerRank(ctx, req.UserID, source, merge.SourceAcceptedAnswerID, false)
+	qs.addCloseReason(ctx, source, target)
+
+	if source.Status != entity.QuestionStatusClosed {
+		qs.activityQueueService.Send(ctx, &schema.ActivityMsg{
+			UserID:           source.UserID,
+			TriggerUserID:    converter.StringToInt64(req.UserID),
+			ObjectID:         sourceID,
+			OriginalObjectID: sourceID,
+			ActivityTypeKey:  constant.ActQuestionClosed,
+		})
+	}
+	qs.notifyAuthors(ctx, req.UserID, source, answerIDs)
+
+	return &schema.MergeQuestionResp{
+		QuestionID:       uid.EnShortID(sourceID),
+		TargetQuestionID: uid.EnShortID(targetID),
+		AnswerCount:      len(answerIDs),
+		CommentCount:     len(commentIDs),
+	}, nil
+}
+
+// RevertQuestionMerge move the answers and comments back to the duplicate question and restore its status
+func (qs *QuestionMergeService) RevertQuestionMerge(ctx context.Context, req *schema.RevertQuestionMergeReq) (err error) {
+	sourceID := uid.DeShortID(req.QuestionID)
+	merge, exist, err := qs.questionMergeRepo.GetQuestionMerge(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.QuestionMergeNotFound)
+	}
+	// the answers have been moved further if the target question was merged after this merge
+	_, targetMerged, err := qs.questionMergeRepo.GetQuestionMerge(ctx, merge.TargetQuestionID)
+	if err != nil {
+		return err
+	}
+	if targetMerged {
+		return errors.BadRequest(reason.QuestionMergeCannotRevert)
+	}
+	source, exist, err := qs.questionRepo.GetQuestion(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+
+	reverted, err := qs.questionMergeRepo.RevertQuestionMerge(ctx, merge)
+	if err != nil {
+		return err
+	}
+	if !reverted {
+		return errors.BadRequest(reason.QuestionMergeNotFound)
+	}
+	qs.questionCommon.RemoveQuestionLinkForReopen(ctx, source)
+	answerIDs, _ := parseMergedIDs(merge)
+	qs.refreshQuestions(ctx, merge, answerIDs)
+	qs.updateAcceptedAnswerRank(ctx, req.UserID, source, merge.SourceAcceptedAnswerID, true)
+
+	if merge.SourceStatus != entity.QuestionStatusClosed {
+		qs.activityQueueService.Send(ctx, &schema.ActivityMsg{
+			UserID:           source.UserID,
+			TriggerUserID:    converter.StringToInt64(req.UserID),
+			ObjectID:         sourceID,
+			OriginalObjectID: sourceID,
+			ActivityTypeKey:  constant.ActQuestionReopened,
+		})
+	}
+	return nil
+}
+
+// GetMergedTargetQuestionID get the question id which the question is merged into
+func (qs *QuestionMergeService) GetMergedTargetQuestionID(ctx context.Context, questionID string) (
+	targetQuestionID string, merged bool) {
+	merge, merged, err := qs.questionMergeRepo.GetQuestionMerge(ctx, uid.DeShortID(questionID))
+	if err != nil {
+		log.Error(err)
+		return "", false
+	}
+	if !merged {
+		return "", false
+	}
+	return merge.TargetQuestionID, true
+}
+
+func (qs *QuestionMergeService) getAvailableQuestion(ctx context.Context, questionID string) (
+	question *entity.Question, err error) {
+	question, exist, err := qs.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || question.Status == entity.QuestionStatusDeleted {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+	return question, nil
+}
+
+// updateAcceptedAnswerRank revoke or restore the accept activity and the reputation of the accepted answer
+// of source question, the same as the accepted answer is changed by the question author
+func (qs *QuestionMergeService) updateAcceptedAnswerRank(ctx context.Context, operatorID string,
+	source *entity.Question, acceptedAnswerID string, accept bool) {
+	if len(acceptedAnswerID) == 0 || acceptedAnswerID == "0" {
+		return
+	}
+	answer, exist, err := qs.answerRepo.GetByID(ctx, acceptedAnswerID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !exist {
+		return
+	}
+	sourceID := uid.DeShortID(source.ID)
+	if accept {
+		err = qs.answerActivityService.AcceptAnswer(ctx, operatorID, acceptedAnswerID,
+			sourceID, source.UserID, answer.UserID, answer.UserID == source.UserID)
+	} else {
+		err = qs.answerActivityService.CancelAcceptAnswer(ctx, operatorID, acceptedAnswerID,
+			sourceID, source.UserID, answer.UserID)
+	}
+	if err != nil {
+		log.Errorf("update the accepted answer %s rank failed: %v", acceptedAnswerID, err)
+	}
+}
+
+// refreshQuestions refresh the answer count and search index after the answers are moved
+func (qs *QuestionMergeService) refreshQuestions(ctx context.Context, merge *entity.QuestionMerge, answerIDs []string) {
+	for _, questionID := range []string{merge.SourceQuestionID, merge.TargetQuestionID} {
+		if err := qs.questionCommon.UpdateAnswerCount(ctx, questionID); err != nil {
+			log.Errorf("update answer count of question %s failed: %v", questionID, err)
+		}
+		if err := qs.questionRepo.UpdateSearch(ctx, questionID); err != nil {
+			log.Errorf("update search of question %s failed: %v", questionID, err)
+		}
+	}
+	for _, answerID := range answerIDs {
+		if err := qs.answerRepo.UpdateSearch(ctx, answerID); err != nil {
+			log.Errorf("update search of answer %s failed: %v", answerID, err)
+		}
+	}
+}
+
+// addCloseReason close the duplicate question as a duplicate of the target question
+func (qs *QuestionMergeService) addCloseReason(ctx context.Context, source, target *entity.Question) {
+	closeType, err := qs.configService.GetIDByKey(ctx, constant.ReasonADuplicate)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	siteGeneral, err := qs.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	siteSeo, err := qs.siteInfoService.GetSiteSeo(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	closeMsg := display.QuestionURL(siteSeo.Permalink, siteGeneral.SiteUrl, target.ID, target.Title)
+	closeMeta, _ := json.Marshal(schema.CloseQuestionMeta{
+		CloseType: closeType,
+		CloseMsg:  closeMsg,
+	})
+	err = qs.metaCommonService.AddOrUpdateMetaByObjectIdAndKey(ctx, uid.DeShortID(source.ID), entity.QuestionCloseReasonKey,
+		func(meta *entity.Meta, exist bool) (*entity.Meta, error) {
+			meta.ObjectID = uid.DeShortID(source.ID)
+			meta.Key = entity.QuestionCloseReasonKey
+			meta.Value = string(closeMeta)
+			return meta, nil
+		})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	qs.questionCommon.AddQuestionLinkForCloseReason(ctx, source, closeMsg)
+}
+
+// notifyAuthors notify the authors of the duplicate question and the moved answers
+func (qs *QuestionMergeService) notifyAuthors(ctx context.Context, operatorID string,
+	source *entity.Question, answerIDs []string) {
+	if source.UserID != operatorID {
+		qs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+			TriggerUserID:      operatorID,
+			ReceiverUserID:     source.UserID,
+			Type:               schema.NotificationTypeInbox,
+			ObjectID:           source.ID,
+			ObjectType:         constant.QuestionObjectType,
+			NotificationAction: constant.NotificationYourQuestionWasMerged,
+		})
+	}
+	if len(answerIDs) == 0 {
+		return
+	}
+	answers, err := qs.answerRepo.GetByIDs(ctx, answerIDs...)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, answer := range answers {
+		if answer.Status == entity.AnswerStatusDeleted || answer.UserID == operatorID {
+			continue
+		}
+		qs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+			TriggerUserID:      operatorID,
+			ReceiverUserID:     answer.UserID,
+			Type:               schema.NotificationTypeInbox,
+			ObjectID:           answer.ID,
+			ObjectType:         constant.AnswerObjectType,
+			NotificationAction: constant.NotificationYourAnswerWasMoved,
+		})
+	}
+}
+
+func parseMergedIDs(merge *entity.QuestionMerge) (answerIDs, commentIDs []string) {
+	answerIDs, commentIDs = make([]string, 0), make([]string, 0)
+	_ = json.Unmarshal([]byte(merge.AnswerIDs), &answerIDs)
+	_ = json.Unmarshal([]byte(merge.CommentIDs), &commentIDs)
+	return answerIDs, commentIDs
+}
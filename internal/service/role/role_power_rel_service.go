@@ -26,6 +26,7 @@ import (
 // RolePowerRelRepo rolePowerRel repository
 type RolePowerRelRepo interface {
 	GetRolePowerTypeList(ctx context.Context, roleID int) (powers []string, err error)
+	GetRoleIDListByPowerTypes(ctx context.Context, powerTypes []string) (roleIDs []int, err error)
 }
 
 // RolePowerRelService user service
@@ -56,3 +57,36 @@ func (rs *RolePowerRelService) GetUserPowerList(ctx context.Context, userID stri
 	}
 	return rs.rolePowerRelRepo.GetRolePowerTypeList(ctx, roleID)
 }
+
+// UserHasPower check the user has the power by the role of user
+func (rs *RolePowerRelService) UserHasPower(ctx context.Context, userID, powerType string) (has bool, err error) {
+	powers, err := rs.GetUserPowerList(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, power := range powers {
+		if power == powerType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RoleHasPower check the role has the power
+func (rs *RolePowerRelService) RoleHasPower(ctx context.Context, roleID int, powerType string) (has bool, err error) {
+	powers, err := rs.rolePowerRelRepo.GetRolePowerTypeList(ctx, roleID)
+	if err != nil {
+		return false, err
+	}
+	for _, power := range powers {
+		if power == powerType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetRoleIDListByPowers get the roles that have any of the powers
+func (rs *RolePowerRelService) GetRoleIDListByPowers(ctx context.Context, powerTypes ...string) (roleIDs []int, err error) {
+	return rs.rolePowerRelRepo.GetRoleIDListByPowerTypes(ctx, powerTypes)
+}
@@ -21,18 +21,20 @@ package role
 
 import (
 	"context"
+	"strings"
 
 	"github.com/apache/incubator-answer/internal/base/handler"
+	"github.com/apache/incubator-answer/internal/base/reason"
 	"github.com/apache/incubator-answer/internal/base/translator"
 	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/jinzhu/copier"
+	"github.com/segmentfault/pacman/errors"
 )
 
 const (
-	// Since there is currently no need to edit roles to add roles and other operations,
-	// the current role information is translated directly.
-	// Later on, when the relevant ability is available, it can be adjusted by the user himself.
+	// The built-in roles can not be renamed or deleted, so their information is translated directly.
+	// The roles added by admin are shown as they are.
 
 	RoleUserID      = 1
 	RoleAdminID     = 2
@@ -55,16 +57,25 @@ const (
 type RoleRepo interface {
 	GetRoleAllList(ctx context.Context) (roles []*entity.Role, err error)
 	GetRoleAllMapping(ctx context.Context) (roleMapping map[int]*entity.Role, err error)
+	GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error)
+	GetRoleByName(ctx context.Context, name string) (role *entity.Role, exist bool, err error)
+	AddRole(ctx context.Context, role *entity.Role, powerTypes []string) (err error)
+	UpdateRole(ctx context.Context, role *entity.Role, powerTypes []string) (err error)
+	DeleteRole(ctx context.Context, roleID, defaultRoleID int) (err error)
 }
 
 // RoleService user service
 type RoleService struct {
-	roleRepo RoleRepo
+	roleRepo         RoleRepo
+	powerRepo        PowerRepo
+	rolePowerRelRepo RolePowerRelRepo
 }
 
-func NewRoleService(roleRepo RoleRepo) *RoleService {
+func NewRoleService(roleRepo RoleRepo, powerRepo PowerRepo, rolePowerRelRepo RolePowerRelRepo) *RoleService {
 	return &RoleService{
-		roleRepo: roleRepo,
+		roleRepo:         roleRepo,
+		powerRepo:        powerRepo,
+		rolePowerRelRepo: rolePowerRelRepo,
 	}
 }
 
@@ -88,7 +99,138 @@ func (rs *RoleService) GetRoleMapping(ctx context.Context) (roleMapping map[int]
 	return rs.roleRepo.GetRoleAllMapping(ctx)
 }
 
+// GetRole get role
+func (rs *RoleService) GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error) {
+	return rs.roleRepo.GetRole(ctx, roleID)
+}
+
+// GetPowerList get all powers that can be assigned to roles
+func (rs *RoleService) GetPowerList(ctx context.Context) (resp []*schema.GetPowerResp, err error) {
+	powers, err := rs.powerRepo.GetPowerList(ctx, &entity.Power{})
+	if err != nil {
+		return nil, err
+	}
+	resp = []*schema.GetPowerResp{}
+	_ = copier.Copy(&resp, powers)
+	return resp, nil
+}
+
+// GetRolePowerList get the power types of the role
+func (rs *RoleService) GetRolePowerList(ctx context.Context, req *schema.GetRolePowerReq) (
+	powerTypes []string, err error) {
+	_, exist, err := rs.roleRepo.GetRole(ctx, req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.RoleNotFound)
+	}
+	return rs.rolePowerRelRepo.GetRolePowerTypeList(ctx, req.RoleID)
+}
+
+// AddRole add custom role
+func (rs *RoleService) AddRole(ctx context.Context, req *schema.AddRoleReq) (resp *schema.AddRoleResp, err error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err = rs.checkRoleName(ctx, 0, req.Name); err != nil {
+		return nil, err
+	}
+	powerTypes, err := rs.checkPowerTypes(ctx, req.PowerTypes)
+	if err != nil {
+		return nil, err
+	}
+	role := &entity.Role{Name: req.Name, Description: req.Description}
+	if err = rs.roleRepo.AddRole(ctx, role, powerTypes); err != nil {
+		return nil, err
+	}
+	return &schema.AddRoleResp{ID: role.ID}, nil
+}
+
+// UpdateRole update role and its powers.
+// The built-in roles can not be renamed, and the powers of admin can not be changed.
+func (rs *RoleService) UpdateRole(ctx context.Context, req *schema.UpdateRoleReq) (err error) {
+	role, exist, err := rs.roleRepo.GetRole(ctx, req.RoleID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.RoleNotFound)
+	}
+	if role.ID == RoleAdminID {
+		return errors.BadRequest(reason.RoleCannotModifyBuiltIn)
+	}
+	if !role.BuiltIn {
+		role.Name = strings.TrimSpace(req.Name)
+		role.Description = req.Description
+		if err = rs.checkRoleName(ctx, role.ID, role.Name); err != nil {
+			return err
+		}
+	}
+	powerTypes, err := rs.checkPowerTypes(ctx, req.PowerTypes)
+	if err != nil {
+		return err
+	}
+	return rs.roleRepo.UpdateRole(ctx, role, powerTypes)
+}
+
+// DeleteRole delete custom role, the users of the role will become normal users
+func (rs *RoleService) DeleteRole(ctx context.Context, req *schema.DeleteRoleReq) (err error) {
+	role, exist, err := rs.roleRepo.GetRole(ctx, req.RoleID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.RoleNotFound)
+	}
+	if role.BuiltIn {
+		return errors.BadRequest(reason.RoleCannotModifyBuiltIn)
+	}
+	return rs.roleRepo.DeleteRole(ctx, role.ID, RoleUserID)
+}
+
+func (rs *RoleService) checkRoleName(ctx context.Context, roleID int, name string) (err error) {
+	role, exist, err := rs.roleRepo.GetRoleByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exist && role.ID != roleID {
+		return errors.BadRequest(reason.RoleNameDuplicate)
+	}
+	switch strings.ToLower(name) {
+	case strings.ToLower(roleUserName), strings.ToLower(roleAdminName), strings.ToLower(roleModeratorName):
+		return errors.BadRequest(reason.RoleNameDuplicate)
+	}
+	return nil
+}
+
+// checkPowerTypes check all power types exist and remove the duplicate ones
+func (rs *RoleService) checkPowerTypes(ctx context.Context, powerTypes []string) (result []string, err error) {
+	powers, err := rs.powerRepo.GetPowerList(ctx, &entity.Power{})
+	if err != nil {
+		return nil, err
+	}
+	powerMapping := make(map[string]bool, len(powers))
+	for _, power := range powers {
+		powerMapping[power.PowerType] = true
+	}
+	result = make([]string, 0, len(powerTypes))
+	seen := make(map[string]bool, len(powerTypes))
+	for _, powerType := range powerTypes {
+		if !powerMapping[powerType] {
+			return nil, errors.BadRequest(reason.PowerTypeInvalid)
+		}
+		if seen[powerType] {
+			continue
+		}
+		seen[powerType] = true
+		result = append(result, powerType)
+	}
+	return result, nil
+}
+
 func (rs *RoleService) translateRole(ctx context.Context, role *entity.Role) {
+	if !role.BuiltIn {
+		return
+	}
 	switch role.Name {
 	case roleUserName:
 		role.Name = translator.Tr(handler.GetLangByCtx(ctx), trRoleNameUser)
@@ -0,0 +1,361 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package saved_search
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/cron"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/base/translator"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/export"
+	"github.com/apache/incubator-answer/internal/service/notice_queue"
+	notificationcommon "github.com/apache/incubator-answer/internal/service/notification_common"
+	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/i18n"
+	"github.com/segmentfault/pacman/log"
+)
+
+// alertSearchSize the page size of searching the new contents to alert
+const alertSearchSize = 50
+
+// SavedSearchRepo saved search repository
+type SavedSearchRepo interface {
+	AddSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error)
+	UpdateSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error)
+	GetSavedSearch(ctx context.Context, id string) (savedSearch *entity.SavedSearch, exist bool, err error)
+	GetUserSavedSearchList(ctx context.Context, userID string) (savedSearches []*entity.SavedSearch, err error)
+	CountUserSavedSearch(ctx context.Context, userID string) (count int64, err error)
+	RemoveSavedSearch(ctx context.Context, id string) (err error)
+	GetAlertSavedSearchList(ctx context.Context) (savedSearches []*entity.SavedSearch, err error)
+	UpdateLastAlertAt(ctx context.Context, id string, lastAlertAt time.Time) (err error)
+}
+
+// ContentSearcher search the contents by the search query, it is the content search service
+type ContentSearcher interface {
+	Search(ctx context.Context, dto *schema.SearchDTO) (resp *schema.SearchResp, err error)
+}
+
+// SavedSearchService saved search service
+type SavedSearchService struct {
+	savedSearchRepo          SavedSearchRepo
+	searchService            ContentSearcher
+	userRepo                 usercommon.UserRepo
+	notificationQueueService notice_queue.NotificationQueueService
+	notificationCommon       *notificationcommon.NotificationCommon
+	emailService             *export.EmailService
+	siteInfoService          siteinfo_common.SiteInfoCommonService
+}
+
+// NewSavedSearchService new saved search service
+func NewSavedSearchService(
+	savedSearchRepo SavedSearchRepo,
+	searchService ContentSearcher,
+	userRepo usercommon.UserRepo,
+	notificationQueueService notice_queue.NotificationQueueService,
+	notificationCommon *notificationcommon.NotificationCommon,
+	emailService *export.EmailService,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+	cronManager *cron.ScheduledTaskManager,
+) *SavedSearchService {
+	ss := &SavedSearchService{
+		savedSearchRepo:          savedSearchRepo,
+		searchService:            searchService,
+		userRepo:                 userRepo,
+		notificationQueueService: notificationQueueService,
+		notificationCommon:       notificationCommon,
+		emailService:             emailService,
+		siteInfoService:          siteInfoService,
+	}
+	if err := cronManager.Register(&cron.Job{
+		Name:        "saved_search_alert",
+		Spec:        "*/15 * * * *",
+		Description: "alert the new contents matched by the saved searches",
+		Run:         ss.SendAlertCron,
+	}); err != nil {
+		log.Error(err)
+	}
+	return ss
+}
+
+// GetSavedSearchList get all saved searches of user
+func (ss *SavedSearchService) GetSavedSearchList(ctx context.Context, userID string) (
+	resp []*schema.SavedSearchInfo, err error) {
+	savedSearches, err := ss.savedSearchRepo.GetUserSavedSearchList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.SavedSearchInfo, 0, len(savedSearches))
+	for _, savedSearch := range savedSearches {
+		resp = append(resp, ss.formatSavedSearchInfo(savedSearch))
+	}
+	return resp, nil
+}
+
+// AddSavedSearch add saved search, only the contents created after it are alerted
+func (ss *SavedSearchService) AddSavedSearch(ctx context.Context, req *schema.AddSavedSearchReq) (
+	resp *schema.SavedSearchInfo, err error) {
+	if req.Alert && len(req.AlertChannels) == 0 {
+		return nil, errors.BadRequest(reason.SavedSearchAlertChannelRequired)
+	}
+	count, err := ss.savedSearchRepo.CountUserSavedSearch(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= schema.SavedSearchMaxCountPerUser {
+		return nil, errors.BadRequest(reason.SavedSearchTooMany)
+	}
+
+	savedSearch := &entity.SavedSearch{
+		UserID:         req.UserID,
+		Name:           req.Name,
+		Query:          req.Query,
+		IncludeSubTags: req.IncludeSubTags,
+		AlertEnabled:   req.Alert,
+	}
+	savedSearch.SetAlertChannels(req.AlertChannels)
+	if req.Alert {
+		savedSearch.LastAlertAt = time.Now()
+	}
+	if err = ss.savedSearchRepo.AddSavedSearch(ctx, savedSearch); err != nil {
+		return nil, err
+	}
+	return ss.formatSavedSearchInfo(savedSearch), nil
+}
+
+// UpdateSavedSearch update saved search. If the alert is enabled or the query is changed,
+// only the contents created after now are alerted.
+func (ss *SavedSearchService) UpdateSavedSearch(ctx context.Context, req *schema.UpdateSavedSearchReq) (
+	resp *schema.SavedSearchInfo, err error) {
+	if req.Alert && len(req.AlertChannels) == 0 {
+		return nil, errors.BadRequest(reason.SavedSearchAlertChannelRequired)
+	}
+	savedSearch, err := ss.getUserSavedSearch(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryChanged := savedSearch.Query != req.Query || savedSearch.IncludeSubTags != req.IncludeSubTags
+	if req.Alert && (!savedSearch.AlertEnabled || queryChanged) {
+		savedSearch.LastAlertAt = time.Now()
+	}
+	savedSearch.Name = req.Name
+	savedSearch.Query = req.Query
+	savedSearch.IncludeSubTags = req.IncludeSubTags
+	savedSearch.AlertEnabled = req.Alert
+	savedSearch.SetAlertChannels(req.AlertChannels)
+	if err = ss.savedSearchRepo.UpdateSavedSearch(ctx, savedSearch); err != nil {
+		return nil, err
+	}
+	return ss.formatSavedSearchInfo(savedSearch), nil
+}
+
+// RemoveSavedSearch remove saved search
+func (ss *SavedSearchService) RemoveSavedSearch(ctx context.Context, req *schema.RemoveSavedSearchReq) (err error) {
+	savedSearch, err := ss.getUserSavedSearch(ctx, req.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+	return ss.savedSearchRepo.RemoveSavedSearch(ctx, savedSearch.ID)
+}
+
+// SendAlertCron evaluate the contents created since the last run for the saved searches whose alert is enabled,
+// and deliver the matched contents to the users
+func (ss *SavedSearchService) SendAlertCron(ctx context.Context) (err error) {
+	savedSearches, err := ss.savedSearchRepo.GetAlertSavedSearchList(ctx)
+	if err != nil {
+		return err
+	}
+	for _, savedSearch := range savedSearches {
+		now := time.Now()
+		userInfo, results, err := ss.getAlertContents(ctx, savedSearch, now)
+		if err != nil {
+			// the contents will be evaluated again in the next run
+			log.Errorf("search alert contents of saved search %s failed: %v", savedSearch.ID, err)
+			continue
+		}
+		// the last alert time is updated before delivering, so the contents are never alerted twice
+		// even if some of the channels fail
+		if err = ss.savedSearchRepo.UpdateLastAlertAt(ctx, savedSearch.ID, now); err != nil {
+			// the contents are not delivered, they will be evaluated again in the next run
+			log.Errorf("update last alert time of saved search %s failed: %v", savedSearch.ID, err)
+			continue
+		}
+		if len(results) > 0 {
+			ss.deliverAlert(ctx, savedSearch, userInfo, results)
+		}
+	}
+	return nil
+}
+
+// getAlertContents get the contents created between the last alert and until to alert the user,
+// nothing is alerted if the user is not available
+func (ss *SavedSearchService) getAlertContents(ctx context.Context, savedSearch *entity.SavedSearch, until time.Time) (
+	userInfo *entity.User, results []*schema.SearchResult, err error) {
+	userInfo, exist, err := ss.userRepo.GetByUserID(ctx, savedSearch.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist || userInfo.Status != entity.UserStatusAvailable {
+		return nil, nil, nil
+	}
+	results, err = ss.searchNewContents(ctx, savedSearch, until)
+	if err != nil {
+		return nil, nil, err
+	}
+	return userInfo, results, nil
+}
+
+// deliverAlert deliver the contents by the alert channels, the failed channels are logged only
+func (ss *SavedSearchService) deliverAlert(ctx context.Context, savedSearch *entity.SavedSearch,
+	userInfo *entity.User, results []*schema.SearchResult) {
+	inbox, toPlugin, email := alertChannels(savedSearch, userInfo)
+	for _, result := range results {
+		msg := &schema.NotificationMsg{
+			TriggerUserID:       result.Object.UserInfo.ID,
+			ReceiverUserID:      savedSearch.UserID,
+			Type:                schema.NotificationTypeInbox,
+			ObjectID:            result.Object.ID,
+			ObjectType:          result.ObjectType,
+			NotificationAction:  constant.NotificationSavedSearchMatched,
+			NoNeedPushAllFollow: true,
+		}
+		if inbox {
+			ss.notificationQueueService.Send(ctx, msg)
+		} else if toPlugin {
+			ss.notificationCommon.SendNotificationToPlugin(ctx, msg)
+		}
+	}
+	if email {
+		if err := ss.sendAlertEmail(ctx, savedSearch, userInfo, results); err != nil {
+			log.Errorf("send alert email of saved search %s failed: %v", savedSearch.ID, err)
+		}
+	}
+}
+
+// alertChannels get the channels to deliver the alert. The inbox notification is synced to the notification
+// plugins too, so the plugins are notified directly only if the inbox is not selected. The email is sent only
+// if the email of user is available.
+func alertChannels(savedSearch *entity.SavedSearch, userInfo *entity.User) (inbox, toPlugin, email bool) {
+	inbox = savedSearch.HasAlertChannel(schema.SavedSearchAlertChannelInbox)
+	toPlugin = !inbox && savedSearch.HasAlertChannel(schema.SavedSearchAlertChannelPlugin)
+	email = savedSearch.HasAlertChannel(schema.SavedSearchAlertChannelEmail) &&
+		userInfo.MailStatus == entity.EmailStatusAvailable
+	return inbox, toPlugin, email
+}
+
+// searchNewContents search the contents matched by the saved search from the newest, page by page until the
+// contents created before the last alert. Only the contents created between the last alert and until by other
+// users are returned, at most SavedSearchAlertItemLimit.
+func (ss *SavedSearchService) searchNewContents(ctx context.Context, savedSearch *entity.SavedSearch,
+	until time.Time) (results []*schema.SearchResult, err error) {
+	results = make([]*schema.SearchResult, 0)
+	since := savedSearch.LastAlertAt.Unix()
+	for page := 1; ; page++ {
+		dto := &schema.SearchDTO{
+			Query:          savedSearch.Query,
+			Page:           page,
+			Size:           alertSearchSize,
+			Order:          "newest",
+			IncludeSubTags: savedSearch.IncludeSubTags,
+			UserID:         savedSearch.UserID,
+		}
+		_, _ = dto.Check()
+		resp, err := ss.searchService.Search(ctx, dto)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range resp.SearchResults {
+			createdAt := result.Object.CreatedAtParsed
+			if createdAt < since {
+				return results, nil
+			}
+			if createdAt >= until.Unix() || result.Object.UserInfo.ID == savedSearch.UserID {
+				continue
+			}
+			results = append(results, result)
+			if len(results) >= schema.SavedSearchAlertItemLimit {
+				return results, nil
+			}
+		}
+		if len(resp.SearchResults) < alertSearchSize || int64(page*alertSearchSize) >= resp.Total {
+			return results, nil
+		}
+	}
+}
+
+func (ss *SavedSearchService) sendAlertEmail(ctx context.Context, savedSearch *entity.SavedSearch,
+	userInfo *entity.User, results []*schema.SearchResult) (err error) {
+	// If receiver has set language, use it to send email, otherwise use the site default language.
+	lang := userInfo.Language
+	if len(lang) == 0 || lang == translator.DefaultLangOption {
+		if interfaceInfo, _ := ss.siteInfoService.GetSiteInterface(ctx); interfaceInfo != nil {
+			lang = interfaceInfo.Language
+		}
+	}
+	ctx = context.WithValue(ctx, constant.AcceptLanguageFlag, i18n.Language(lang))
+	title, body, err := ss.emailService.SavedSearchAlertTemplate(ctx, &schema.SavedSearchAlertTemplateRawData{
+		Name:           savedSearch.Name,
+		Query:          savedSearch.Query,
+		IncludeSubTags: savedSearch.IncludeSubTags,
+		Results:        results,
+	})
+	if err != nil {
+		return err
+	}
+	ss.emailService.Send(ctx, userInfo.EMail, title, body)
+	return nil
+}
+
+// getUserSavedSearch get the saved search of user, the saved search of other user is not found
+func (ss *SavedSearchService) getUserSavedSearch(ctx context.Context, id, userID string) (
+	savedSearch *entity.SavedSearch, err error) {
+	savedSearch, exist, err := ss.savedSearchRepo.GetSavedSearch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || savedSearch.UserID != userID {
+		return nil, errors.NotFound(reason.SavedSearchNotFound)
+	}
+	return savedSearch, nil
+}
+
+func (ss *SavedSearchService) formatSavedSearchInfo(savedSearch *entity.SavedSearch) *schema.SavedSearchInfo {
+	info := &schema.SavedSearchInfo{
+		ID:             savedSearch.ID,
+		Name:           savedSearch.Name,
+		Query:          savedSearch.Query,
+		IncludeSubTags: savedSearch.IncludeSubTags,
+		Alert:          savedSearch.AlertEnabled,
+		AlertChannels:  savedSearch.GetAlertChannels(),
+		CreatedAt:      savedSearch.CreatedAt.Unix(),
+		UpdatedAt:      savedSearch.UpdatedAt.Unix(),
+	}
+	if !savedSearch.LastAlertAt.IsZero() {
+		info.LastAlertAt = savedSearch.LastAlertAt.Unix()
+	}
+	return info
+}
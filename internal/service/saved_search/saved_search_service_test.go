@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package saved_search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/cron"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSavedSearchRepo struct {
+	SavedSearchRepo
+	savedSearches []*entity.SavedSearch
+	lastAlertAt   map[string]time.Time
+	updateErr     error
+}
+
+func (m *mockSavedSearchRepo) GetAlertSavedSearchList(_ context.Context) ([]*entity.SavedSearch, error) {
+	return m.savedSearches, nil
+}
+
+func (m *mockSavedSearchRepo) UpdateLastAlertAt(_ context.Context, id string, lastAlertAt time.Time) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	m.lastAlertAt[id] = lastAlertAt
+	return nil
+}
+
+type mockUserRepo struct {
+	usercommon.UserRepo
+	users map[string]*entity.User
+}
+
+func (m *mockUserRepo) GetByUserID(_ context.Context, userID string) (*entity.User, bool, error) {
+	user, ok := m.users[userID]
+	return user, ok, nil
+}
+
+// mockSearcher return the results from the newest page by page
+type mockSearcher struct {
+	results []*schema.SearchResult
+	pages   []int
+	err     error
+}
+
+func (m *mockSearcher) Search(_ context.Context, dto *schema.SearchDTO) (*schema.SearchResp, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.pages = append(m.pages, dto.Page)
+	start := min((dto.Page-1)*dto.Size, len(m.results))
+	end := min(start+dto.Size, len(m.results))
+	return &schema.SearchResp{Total: int64(len(m.results)), SearchResults: m.results[start:end]}, nil
+}
+
+type mockNotificationQueue struct {
+	msgs []*schema.NotificationMsg
+}
+
+func (m *mockNotificationQueue) Send(_ context.Context, msg *schema.NotificationMsg) {
+	m.msgs = append(m.msgs, msg)
+}
+
+func (m *mockNotificationQueue) RegisterHandler(func(ctx context.Context, msg *schema.NotificationMsg) error) {
+}
+
+func newSearchResult(id, userID string, createdAt time.Time) *schema.SearchResult {
+	return &schema.SearchResult{ObjectType: "question", Object: &schema.SearchObject{
+		ID: id, CreatedAtParsed: createdAt.Unix(), UserInfo: &schema.SearchObjectUser{ID: userID}}}
+}
+
+func TestSavedSearchService_SendAlertCron(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	lastAlertAt := now.Add(-time.Hour)
+	savedSearch := &entity.SavedSearch{ID: "1", UserID: "1", Query: "[go]", AlertEnabled: true,
+		AlertChannels: schema.SavedSearchAlertChannelInbox, LastAlertAt: lastAlertAt}
+
+	// the newest first: a content created after the run, a page of the user's own contents,
+	// two new contents of others on the next page and an old content evaluated before
+	results := []*schema.SearchResult{newSearchResult("future", "2", now.Add(time.Hour))}
+	for i := 0; i < alertSearchSize; i++ {
+		results = append(results, newSearchResult(fmt.Sprintf("self%d", i), "1", now.Add(-time.Minute)))
+	}
+	results = append(results,
+		newSearchResult("new1", "2", now.Add(-2*time.Minute)),
+		newSearchResult("new2", "3", lastAlertAt),
+		newSearchResult("old", "2", lastAlertAt.Add(-time.Second)),
+	)
+	for i := 0; i < alertSearchSize; i++ {
+		results = append(results, newSearchResult(fmt.Sprintf("older%d", i), "2", lastAlertAt.Add(-time.Hour)))
+	}
+
+	repo := &mockSavedSearchRepo{savedSearches: []*entity.SavedSearch{savedSearch}, lastAlertAt: map[string]time.Time{}}
+	searcher := &mockSearcher{results: results}
+	queue := &mockNotificationQueue{}
+	ss := NewSavedSearchService(repo, searcher, &mockUserRepo{users: map[string]*entity.User{
+		"1": {ID: "1", Status: entity.UserStatusAvailable, MailStatus: entity.EmailStatusToBeVerified},
+	}}, queue, nil, nil, nil, cron.NewScheduledTaskManager(nil))
+
+	require.NoError(t, ss.SendAlertCron(ctx))
+	// the search stops at the content created before the last alert
+	assert.Equal(t, []int{1, 2}, searcher.pages)
+	objectIDs := make([]string, 0)
+	for _, msg := range queue.msgs {
+		assert.Equal(t, "1", msg.ReceiverUserID)
+		objectIDs = append(objectIDs, msg.ObjectID)
+	}
+	assert.Equal(t, []string{"new1", "new2"}, objectIDs)
+	assert.False(t, repo.lastAlertAt["1"].Before(now))
+
+	// the contents are evaluated again in the next run if the search fails
+	repo.lastAlertAt = map[string]time.Time{}
+	queue.msgs = nil
+	searcher.err = fmt.Errorf("search failed")
+	require.NoError(t, ss.SendAlertCron(ctx))
+	assert.Empty(t, repo.lastAlertAt)
+	assert.Empty(t, queue.msgs)
+
+	// nothing is delivered and no error is returned if the last alert time fails to update
+	searcher.err = nil
+	repo.updateErr = fmt.Errorf("update failed")
+	require.NoError(t, ss.SendAlertCron(ctx))
+	assert.Empty(t, queue.msgs)
+}
+
+func TestSavedSearchService_SearchNewContentsLimit(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	results := make([]*schema.SearchResult, 0)
+	for i := 0; i < schema.SavedSearchAlertItemLimit*2; i++ {
+		results = append(results, newSearchResult(fmt.Sprintf("new%d", i), "2", now.Add(-time.Minute)))
+	}
+	ss := NewSavedSearchService(nil, &mockSearcher{results: results}, nil, nil, nil, nil, nil, cron.NewScheduledTaskManager(nil))
+	got, err := ss.searchNewContents(ctx, &entity.SavedSearch{UserID: "1", LastAlertAt: now.Add(-time.Hour)}, now)
+	require.NoError(t, err)
+	assert.Len(t, got, schema.SavedSearchAlertItemLimit)
+}
+
+func Test_alertChannels(t *testing.T) {
+	verified := &entity.User{MailStatus: entity.EmailStatusAvailable}
+	unverified := &entity.User{MailStatus: entity.EmailStatusToBeVerified}
+	tests := []struct {
+		name                   string
+		channels               []string
+		user                   *entity.User
+		inbox, toPlugin, email bool
+	}{
+		{"inbox is synced to plugins", []string{"inbox", "plugin"}, verified, true, false, false},
+		{"plugin only", []string{"plugin"}, verified, false, true, false},
+		{"email of verified user", []string{"inbox", "email"}, verified, true, false, true},
+		{"email of unverified user", []string{"email"}, unverified, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			savedSearch := &entity.SavedSearch{}
+			savedSearch.SetAlertChannels(tt.channels)
+			inbox, toPlugin, email := alertChannels(savedSearch, tt.user)
+			assert.Equal(t, tt.inbox, inbox)
+			assert.Equal(t, tt.toPlugin, toPlugin)
+			assert.Equal(t, tt.email, email)
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/apache/incubator-answer/internal/service/tag_common"
 	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
 	"github.com/apache/incubator-answer/pkg/converter"
+	"github.com/segmentfault/pacman/log"
 )
 
 type SearchParser struct {
@@ -54,7 +55,7 @@ func (sp *SearchParser) ParseStructure(ctx context.Context, dto *schema.SearchDT
 	)
 
 	// match tags
-	cond.Tags = sp.parseTags(ctx, &query)
+	cond.Tags = sp.parseTags(ctx, &query, dto.IncludeSubTags)
 
 	// match all
 	cond.UserID = sp.parseUserID(ctx, &query, dto.UserID)
@@ -105,7 +106,7 @@ func (sp *SearchParser) ParseStructure(ctx context.Context, dto *schema.SearchDT
 }
 
 // parseTags parse search tags, return tag ids array
-func (sp *SearchParser) parseTags(ctx context.Context, query *string) (tags [][]string) {
+func (sp *SearchParser) parseTags(ctx context.Context, query *string, includeSubTags bool) (tags [][]string) {
 	var (
 		// expire tag pattern
 		exprTag = `\[(.*?)\]`
@@ -136,6 +137,18 @@ func (sp *SearchParser) parseTags(ctx context.Context, query *string) (tags [][]
 		}
 		tagGroup = append(tagGroup, tag.ID)
 		tagGroup = append(tagGroup, synIDs...)
+		if includeSubTags {
+			mainTagID := tag.ID
+			if tag.MainTagID > 0 {
+				mainTagID = fmt.Sprintf("%d", tag.MainTagID)
+			}
+			// the tag itself is still searched if its sub tags can't be found
+			subTagIDs, err := sp.tagCommonService.GetDescendantTagIDs(ctx, []string{mainTagID})
+			if err != nil {
+				log.Errorf("get sub tags of %s failed: %v", mainTagID, err)
+			}
+			tagGroup = append(tagGroup, subTagIDs...)
+		}
 		tagGroup = converter.UniqueArray(tagGroup)
 		tags = append(tags, tagGroup)
 	}
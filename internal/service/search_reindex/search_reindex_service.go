@@ -0,0 +1,383 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_reindex
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// searchReindexStaleTimeout the running task that is not saved for a while can be claimed again,
+// the instance running it may be gone. The task is saved after each batch when it is running.
+const searchReindexStaleTimeout = 10 * time.Minute
+
+// SearchReindexRepo search reindex repository
+type SearchReindexRepo interface {
+	GetTask(ctx context.Context, pluginSlugName string) (task *entity.SearchReindexTask, exist bool, err error)
+	SaveTask(ctx context.Context, task *entity.SearchReindexTask) (err error)
+	ClaimTask(ctx context.Context, task, lastTask *entity.SearchReindexTask) (claimed bool, err error)
+	CountContents(ctx context.Context) (total int64, err error)
+	GetQuestionContents(ctx context.Context, lastID string, limit int) (contents []*plugin.SearchContent, err error)
+	GetAnswerContents(ctx context.Context, lastID string, limit int) (contents []*plugin.SearchContent, err error)
+}
+
+// SearchReindexService send all questions and answers to the search plugin again
+type SearchReindexService struct {
+	searchReindexRepo SearchReindexRepo
+	lock              sync.Mutex
+	// the progress of the reindexing started by this instance
+	progress *schema.SearchReindexResp
+}
+
+// NewSearchReindexService new search reindex service
+func NewSearchReindexService(searchReindexRepo SearchReindexRepo) *SearchReindexService {
+	return &SearchReindexService{
+		searchReindexRepo: searchReindexRepo,
+	}
+}
+
+// StartReindex start reindexing in background, use GetReindexProgress to get the progress
+func (ss *SearchReindexService) StartReindex(ctx context.Context, req *schema.SearchReindexReq) (
+	resp *schema.SearchReindexResp, err error) {
+	search, err := getSearchPlugin()
+	if err != nil {
+		return nil, err
+	}
+	task, err := ss.begin(ctx, search, req)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := ss.run(context.Background(), search, req, task, nil); err != nil {
+			log.Errorf("search reindex failed: %v", err)
+		}
+	}()
+	return ss.copyProgress(), nil
+}
+
+// Reindex rebuild the index and wait until it is done, the progress is reported after each batch
+func (ss *SearchReindexService) Reindex(ctx context.Context, req *schema.SearchReindexReq,
+	report func(progress *schema.SearchReindexResp)) (resp *schema.SearchReindexResp, err error) {
+	search, err := getSearchPlugin()
+	if err != nil {
+		return nil, err
+	}
+	task, err := ss.begin(ctx, search, req)
+	if err != nil {
+		return nil, err
+	}
+	err = ss.run(ctx, search, req, task, report)
+	return ss.copyProgress(), err
+}
+
+// GetReindexProgress get the progress of the running or last reindexing
+func (ss *SearchReindexService) GetReindexProgress(ctx context.Context) (resp *schema.SearchReindexResp, err error) {
+	search, err := getSearchPlugin()
+	if err != nil {
+		return nil, err
+	}
+	slugName := search.Info().SlugName
+	if resp = ss.copyProgress(); resp != nil && resp.PluginSlugName == slugName {
+		return resp, nil
+	}
+	task, exist, err := ss.searchReindexRepo.GetTask(ctx, slugName)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.SearchReindexResp{PluginSlugName: slugName}
+	if exist {
+		taskToProgress(task, resp)
+		resp.StartedAt = task.CreatedAt.Unix()
+	}
+	return resp, nil
+}
+
+// begin make sure only one reindexing is running, and continue from the checkpoint of last unfinished reindexing.
+// The task of the plugin is claimed in the database, so only one reindexing is running across all instances.
+func (ss *SearchReindexService) begin(ctx context.Context, search plugin.Search, req *schema.SearchReindexReq) (
+	task *entity.SearchReindexTask, err error) {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	if ss.progress != nil && ss.progress.Status == entity.SearchReindexStatusRunning {
+		return nil, errors.BadRequest(reason.SearchReindexRunning)
+	}
+	if req.BatchSize <= 0 {
+		req.BatchSize = schema.SearchReindexDefaultBatchSize
+	}
+
+	slugName := search.Info().SlugName
+	lastTask, exist, err := ss.searchReindexRepo.GetTask(ctx, slugName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		lastTask = nil
+	} else if lastTask.Status == entity.SearchReindexStatusRunning &&
+		time.Since(lastTask.UpdatedAt) < searchReindexStaleTimeout {
+		return nil, errors.BadRequest(reason.SearchReindexRunning)
+	}
+
+	task = &entity.SearchReindexTask{
+		PluginSlugName: slugName,
+		ObjectType:     constant.QuestionObjectType,
+		LastObjectID:   "0",
+	}
+	if lastTask != nil && !req.Restart && !req.DryRun && lastTask.Status != entity.SearchReindexStatusFinished {
+		log.Infof("search reindex continues from %s %s", lastTask.ObjectType, lastTask.LastObjectID)
+		continued := *lastTask
+		task = &continued
+	}
+	task.Status = entity.SearchReindexStatusRunning
+	task.Error = ""
+	if !req.DryRun {
+		claimed, err := ss.searchReindexRepo.ClaimTask(ctx, task, lastTask)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			return nil, errors.BadRequest(reason.SearchReindexRunning)
+		}
+	}
+
+	progress := &schema.SearchReindexResp{DryRun: req.DryRun, StartedAt: time.Now().Unix()}
+	taskToProgress(task, progress)
+	if req.DryRun {
+		progress.Drift = &schema.SearchIndexDrift{Samples: make([]string, 0)}
+		_, progress.Drift.Supported = search.(plugin.SearchIndexInspector)
+	}
+	progress.Total, err = ss.searchReindexRepo.CountContents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ss.progress = progress
+	return task, nil
+}
+
+// run send the contents to the search plugin batch by batch, the checkpoint is saved after each batch
+func (ss *SearchReindexService) run(ctx context.Context, search plugin.Search, req *schema.SearchReindexReq,
+	task *entity.SearchReindexTask, report func(progress *schema.SearchReindexResp)) (err error) {
+	defer func() {
+		task.Status = entity.SearchReindexStatusFinished
+		if err != nil {
+			task.Status, task.Error = entity.SearchReindexStatusFailed, err.Error()
+		}
+		if !req.DryRun {
+			if err := ss.searchReindexRepo.SaveTask(context.Background(), task); err != nil {
+				log.Errorf("save search reindex task failed: %v", err)
+			}
+		}
+		ss.updateProgress(task, report)
+	}()
+
+	getContents := map[string]func(ctx context.Context, lastID string, limit int) ([]*plugin.SearchContent, error){
+		constant.QuestionObjectType: ss.searchReindexRepo.GetQuestionContents,
+		constant.AnswerObjectType:   ss.searchReindexRepo.GetAnswerContents,
+	}
+	for _, objectType := range []string{constant.QuestionObjectType, constant.AnswerObjectType} {
+		// all questions are done if the checkpoint is in answers
+		if objectType == constant.QuestionObjectType && task.ObjectType == constant.AnswerObjectType {
+			continue
+		}
+		if task.ObjectType != objectType {
+			task.ObjectType, task.LastObjectID = objectType, "0"
+		}
+		for {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+			var contents []*plugin.SearchContent
+			contents, err = getContents[objectType](ctx, task.LastObjectID, req.BatchSize)
+			if err != nil {
+				return err
+			}
+			if len(contents) == 0 {
+				break
+			}
+			if req.DryRun {
+				err = ss.checkDrift(ctx, search, contents)
+			} else {
+				err = ss.sync(ctx, search, contents, task)
+			}
+			if err != nil {
+				return err
+			}
+			task.LastObjectID = contents[len(contents)-1].ObjectID
+			task.Processed += int64(len(contents))
+			if !req.DryRun {
+				if err = ss.searchReindexRepo.SaveTask(ctx, task); err != nil {
+					return err
+				}
+			}
+			ss.updateProgress(task, report)
+		}
+	}
+	return nil
+}
+
+// sync send the contents to the search plugin, the deleted contents are removed from the index.
+// The failed contents are skipped, but the reindexing stops if the whole batch failed.
+func (ss *SearchReindexService) sync(ctx context.Context, search plugin.Search, contents []*plugin.SearchContent,
+	task *entity.SearchReindexTask) (err error) {
+	var failed int
+	for _, content := range contents {
+		deleted := content.Status == plugin.SearchContentStatusDeleted
+		if deleted {
+			err = search.DeleteContent(ctx, content.ObjectID)
+		} else {
+			err = search.UpdateContent(ctx, content)
+		}
+		if err != nil {
+			log.Errorf("search reindex %s %s failed: %v", content.Type, content.ObjectID, err)
+			failed++
+			continue
+		}
+		if deleted {
+			task.Deleted++
+		} else {
+			task.Updated++
+		}
+	}
+	task.Failed += int64(failed)
+	if failed == len(contents) {
+		return fmt.Errorf("all %d contents after %s %s failed: %w", failed, task.ObjectType, task.LastObjectID, err)
+	}
+	return nil
+}
+
+// checkDrift compare the contents with the index of the search plugin
+func (ss *SearchReindexService) checkDrift(ctx context.Context, search plugin.Search,
+	contents []*plugin.SearchContent) (err error) {
+	inspector, ok := search.(plugin.SearchIndexInspector)
+	if !ok {
+		return nil
+	}
+	objectIDs := make([]string, 0, len(contents))
+	for _, content := range contents {
+		objectIDs = append(objectIDs, content.ObjectID)
+	}
+	indexedContents, err := inspector.GetContents(ctx, objectIDs)
+	if err != nil {
+		return err
+	}
+	indexedMapping := make(map[string]*plugin.SearchContent, len(indexedContents))
+	for _, indexed := range indexedContents {
+		indexedMapping[indexed.ObjectID] = indexed
+	}
+
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	drift := ss.progress.Drift
+	for _, content := range contents {
+		indexed, exist := indexedMapping[content.ObjectID]
+		deleted := content.Status == plugin.SearchContentStatusDeleted
+		switch {
+		case deleted && exist && indexed.Status != plugin.SearchContentStatusDeleted:
+			drift.Orphaned++
+		case !deleted && !exist:
+			drift.Missing++
+		case !deleted && !sameSearchContent(content, indexed):
+			drift.Stale++
+		default:
+			continue
+		}
+		if len(drift.Samples) < schema.SearchReindexDriftSampleSize {
+			drift.Samples = append(drift.Samples, content.ObjectID)
+		}
+	}
+	return nil
+}
+
+// updateProgress update the progress from the task and report it
+func (ss *SearchReindexService) updateProgress(task *entity.SearchReindexTask,
+	report func(progress *schema.SearchReindexResp)) {
+	ss.lock.Lock()
+	taskToProgress(task, ss.progress)
+	ss.progress.UpdatedAt = time.Now().Unix()
+	ss.lock.Unlock()
+	if report != nil {
+		report(ss.copyProgress())
+	}
+}
+
+func (ss *SearchReindexService) copyProgress() *schema.SearchReindexResp {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	if ss.progress == nil {
+		return nil
+	}
+	progress := *ss.progress
+	if ss.progress.Drift != nil {
+		drift := *ss.progress.Drift
+		drift.Samples = append([]string{}, ss.progress.Drift.Samples...)
+		progress.Drift = &drift
+	}
+	return &progress
+}
+
+// sameSearchContent compare the fields that are searched or filtered, the views are changed too often to compare
+func sameSearchContent(a, b *plugin.SearchContent) bool {
+	if a.Title != b.Title || a.Content != b.Content || a.Status != b.Status || a.Answers != b.Answers ||
+		a.Score != b.Score || a.HasAccepted != b.HasAccepted || a.Active != b.Active ||
+		a.QuestionID != b.QuestionID || a.UserID != b.UserID {
+		return false
+	}
+	aTags, bTags := append([]string{}, a.Tags...), append([]string{}, b.Tags...)
+	sort.Strings(aTags)
+	sort.Strings(bTags)
+	return strings.Join(aTags, ",") == strings.Join(bTags, ",")
+}
+
+func taskToProgress(task *entity.SearchReindexTask, progress *schema.SearchReindexResp) {
+	progress.PluginSlugName = task.PluginSlugName
+	progress.Status = task.Status
+	progress.ObjectType = task.ObjectType
+	progress.LastObjectID = task.LastObjectID
+	progress.Processed = task.Processed
+	progress.Updated = task.Updated
+	progress.Deleted = task.Deleted
+	progress.Failed = task.Failed
+	progress.Error = task.Error
+	if !task.UpdatedAt.IsZero() {
+		progress.UpdatedAt = task.UpdatedAt.Unix()
+	}
+}
+
+func getSearchPlugin() (search plugin.Search, err error) {
+	_ = plugin.CallSearch(func(fn plugin.Search) error {
+		search = fn
+		return nil
+	})
+	if search == nil {
+		return nil, errors.BadRequest(reason.SearchPluginNotEnabled)
+	}
+	return search, nil
+}
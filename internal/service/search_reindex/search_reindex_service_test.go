@@ -0,0 +1,244 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_reindex
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSearchReindexRepo struct {
+	task     *entity.SearchReindexTask
+	contents map[string][]*plugin.SearchContent
+}
+
+func (m *mockSearchReindexRepo) GetTask(_ context.Context, _ string) (*entity.SearchReindexTask, bool, error) {
+	if m.task == nil {
+		return nil, false, nil
+	}
+	task := *m.task
+	return &task, true, nil
+}
+
+func (m *mockSearchReindexRepo) SaveTask(_ context.Context, task *entity.SearchReindexTask) error {
+	saved := *task
+	saved.UpdatedAt = time.Now()
+	m.task = &saved
+	return nil
+}
+
+func (m *mockSearchReindexRepo) ClaimTask(_ context.Context, task, lastTask *entity.SearchReindexTask) (bool, error) {
+	if (lastTask == nil) != (m.task == nil) {
+		return false, nil
+	}
+	if lastTask != nil && (m.task.Status != lastTask.Status || m.task.LastObjectID != lastTask.LastObjectID ||
+		m.task.Processed != lastTask.Processed) {
+		return false, nil
+	}
+	saved := *task
+	saved.UpdatedAt = time.Now()
+	m.task = &saved
+	return true, nil
+}
+
+func (m *mockSearchReindexRepo) CountContents(_ context.Context) (int64, error) {
+	return int64(len(m.contents[constant.QuestionObjectType]) + len(m.contents[constant.AnswerObjectType])), nil
+}
+
+func (m *mockSearchReindexRepo) getContents(objectType, lastID string, limit int) []*plugin.SearchContent {
+	contents := make([]*plugin.SearchContent, 0)
+	for _, content := range m.contents[objectType] {
+		if content.ObjectID > lastID && len(contents) < limit {
+			contents = append(contents, content)
+		}
+	}
+	return contents
+}
+
+func (m *mockSearchReindexRepo) GetQuestionContents(_ context.Context, lastID string, limit int) (
+	[]*plugin.SearchContent, error) {
+	return m.getContents(constant.QuestionObjectType, lastID, limit), nil
+}
+
+func (m *mockSearchReindexRepo) GetAnswerContents(_ context.Context, lastID string, limit int) (
+	[]*plugin.SearchContent, error) {
+	return m.getContents(constant.AnswerObjectType, lastID, limit), nil
+}
+
+type mockSearch struct {
+	plugin.Search
+	index    map[string]*plugin.SearchContent
+	brokenID string
+}
+
+func (m *mockSearch) Info() plugin.Info {
+	return plugin.Info{SlugName: "mock_reindex_search"}
+}
+
+func (m *mockSearch) UpdateContent(_ context.Context, content *plugin.SearchContent) error {
+	if content.ObjectID == m.brokenID {
+		return fmt.Errorf("index %s failed", content.ObjectID)
+	}
+	m.index[content.ObjectID] = content
+	return nil
+}
+
+func (m *mockSearch) DeleteContent(_ context.Context, objectID string) error {
+	delete(m.index, objectID)
+	return nil
+}
+
+func (m *mockSearch) GetContents(_ context.Context, objectIDs []string) ([]*plugin.SearchContent, error) {
+	contents := make([]*plugin.SearchContent, 0)
+	for _, objectID := range objectIDs {
+		if content, ok := m.index[objectID]; ok {
+			contents = append(contents, content)
+		}
+	}
+	return contents, nil
+}
+
+var (
+	mockSearchOnce sync.Once
+	mockSearchInst = &mockSearch{}
+)
+
+// getMockSearch register the mock search plugin once, only one search plugin can be registered
+func getMockSearch() *mockSearch {
+	mockSearchOnce.Do(func() {
+		plugin.Register(mockSearchInst)
+		plugin.StatusManager.Enable(mockSearchInst.Info().SlugName, true)
+	})
+	mockSearchInst.index, mockSearchInst.brokenID = make(map[string]*plugin.SearchContent), ""
+	return mockSearchInst
+}
+
+func TestSearchReindexService_Reindex(t *testing.T) {
+	ctx := context.TODO()
+	search := getMockSearch()
+	search.brokenID = "10010000000000003"
+
+	repo := &mockSearchReindexRepo{contents: map[string][]*plugin.SearchContent{
+		constant.QuestionObjectType: {
+			{ObjectID: "10010000000000001", Title: "q1", Status: plugin.SearchContentStatusAvailable},
+			{ObjectID: "10010000000000002", Title: "q2", Status: plugin.SearchContentStatusAvailable},
+			{ObjectID: "10010000000000003", Title: "q3", Status: plugin.SearchContentStatusAvailable},
+		},
+		constant.AnswerObjectType: {
+			{ObjectID: "10020000000000001", QuestionID: "10010000000000001", Status: plugin.SearchContentStatusAvailable},
+			{ObjectID: "10020000000000002", QuestionID: "10010000000000002", Status: plugin.SearchContentStatusDeleted},
+		},
+	}}
+	// the index has a stale question and a deleted answer
+	search.index["10010000000000001"] = &plugin.SearchContent{ObjectID: "10010000000000001", Title: "old",
+		Status: plugin.SearchContentStatusAvailable}
+	search.index["10020000000000002"] = &plugin.SearchContent{ObjectID: "10020000000000002",
+		Status: plugin.SearchContentStatusAvailable}
+	ss := NewSearchReindexService(repo)
+
+	// dry-run reports the drift without changing the index
+	resp, err := ss.Reindex(ctx, &schema.SearchReindexReq{DryRun: true}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, resp.Drift)
+	assert.True(t, resp.Drift.Supported)
+	assert.Equal(t, int64(3), resp.Drift.Missing)
+	assert.Equal(t, int64(1), resp.Drift.Stale)
+	assert.Equal(t, int64(1), resp.Drift.Orphaned)
+	assert.Equal(t, "old", search.index["10010000000000001"].Title)
+	assert.Nil(t, repo.task)
+
+	// the reindexing stops at the batch that all contents failed, and the checkpoint is saved
+	resp, err = ss.Reindex(ctx, &schema.SearchReindexReq{BatchSize: 1}, nil)
+	require.Error(t, err)
+	assert.Equal(t, entity.SearchReindexStatusFailed, resp.Status)
+	assert.Equal(t, int64(2), resp.Processed)
+	assert.Equal(t, "10010000000000002", repo.task.LastObjectID)
+
+	// continue from the checkpoint
+	search.brokenID = ""
+	processed := make([]int64, 0)
+	resp, err = ss.Reindex(ctx, &schema.SearchReindexReq{BatchSize: 1}, func(progress *schema.SearchReindexResp) {
+		processed = append(processed, progress.Processed)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.SearchReindexStatusFinished, resp.Status)
+	assert.Equal(t, []int64{3, 4, 5, 5}, processed)
+	assert.Equal(t, int64(4), resp.Updated)
+	assert.Equal(t, int64(1), resp.Deleted)
+	assert.Equal(t, int64(1), resp.Failed)
+
+	indexed := make([]string, 0)
+	for objectID := range search.index {
+		indexed = append(indexed, objectID)
+	}
+	sort.Strings(indexed)
+	assert.Equal(t, []string{"10010000000000001", "10010000000000002", "10010000000000003", "10020000000000001"}, indexed)
+
+	// no drift after reindexing
+	resp, err = ss.Reindex(ctx, &schema.SearchReindexReq{DryRun: true}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), resp.Drift.Missing+resp.Drift.Stale+resp.Drift.Orphaned)
+}
+
+func TestSearchReindexService_ClaimTask(t *testing.T) {
+	ctx := context.TODO()
+	search := getMockSearch()
+
+	// the task is running in another instance
+	repo := &mockSearchReindexRepo{
+		task: &entity.SearchReindexTask{PluginSlugName: search.Info().SlugName, Status: entity.SearchReindexStatusRunning,
+			ObjectType: constant.QuestionObjectType, LastObjectID: "0", UpdatedAt: time.Now()},
+		contents: map[string][]*plugin.SearchContent{
+			constant.QuestionObjectType: {
+				{ObjectID: "10010000000000001", Title: "q1", Status: plugin.SearchContentStatusAvailable},
+			},
+		},
+	}
+	ss := NewSearchReindexService(repo)
+	_, err := ss.Reindex(ctx, &schema.SearchReindexReq{}, nil)
+	require.Error(t, err)
+	_, err = ss.Reindex(ctx, &schema.SearchReindexReq{DryRun: true}, nil)
+	require.Error(t, err)
+
+	// the running task that is not saved for a while is taken over
+	repo.task.UpdatedAt = time.Now().Add(-searchReindexStaleTimeout)
+	resp, err := ss.Reindex(ctx, &schema.SearchReindexReq{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, entity.SearchReindexStatusFinished, resp.Status)
+	assert.Equal(t, int64(1), resp.Processed)
+
+	// the task changed by another instance after it is read is not claimed
+	lastTask := *repo.task
+	repo.task.Processed++
+	claimed, err := repo.ClaimTask(ctx, &entity.SearchReindexTask{}, &lastTask)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+}
@@ -20,5 +20,43 @@
 package service_config
 
 type ServiceConfig struct {
-	UploadPath string `json:"upload_path" mapstructure:"upload_path" yaml:"upload_path"`
+	UploadPath string           `json:"upload_path" mapstructure:"upload_path" yaml:"upload_path"`
+	Queue      *QueueConfig     `json:"queue" mapstructure:"queue" yaml:"queue,omitempty"`
+	RateLimit  *RateLimitConfig `json:"rate_limit" mapstructure:"rate_limit" yaml:"rate_limit,omitempty"`
+}
+
+// QueueConfig persisted queue config, zero values fall back to defaults
+type QueueConfig struct {
+	// Workers the number of messages handled concurrently by each queue
+	Workers int `json:"workers" mapstructure:"workers" yaml:"workers,omitempty"`
+	// MaxAttempts the message will be moved to the dead letter state after the max attempts
+	MaxAttempts int `json:"max_attempts" mapstructure:"max_attempts" yaml:"max_attempts,omitempty"`
+	// PollInterval seconds between two polls of the queue table
+	PollInterval int `json:"poll_interval" mapstructure:"poll_interval" yaml:"poll_interval,omitempty"`
+}
+
+// RateLimitConfig request rate limit config, the default rules are used if no rule is configured
+type RateLimitConfig struct {
+	Disabled bool             `json:"disabled" mapstructure:"disabled" yaml:"disabled,omitempty"`
+	Rules    []*RateLimitRule `json:"rules" mapstructure:"rules" yaml:"rules,omitempty"`
+}
+
+// RateLimitRule limits the requests matched by the rule in a sliding window.
+// All matched rules are checked, so a global rule can be combined with the rules of some route groups.
+type RateLimitRule struct {
+	// Name the counters of the rule are stored by the name, so it must be unique
+	Name string `json:"name" mapstructure:"name" yaml:"name"`
+	// Paths request path prefixes, empty means all paths
+	Paths []string `json:"paths" mapstructure:"paths" yaml:"paths,omitempty"`
+	// Methods request methods, empty means all methods
+	Methods []string `json:"methods" mapstructure:"methods" yaml:"methods,omitempty"`
+	// Window seconds of the sliding window
+	Window int `json:"window" mapstructure:"window" yaml:"window"`
+	// Anonymous max requests in the window for anonymous visitors by ip, 0 means no limit
+	Anonymous int `json:"anonymous" mapstructure:"anonymous" yaml:"anonymous,omitempty"`
+	// User max requests in the window for normal users, 0 means no limit
+	User int `json:"user" mapstructure:"user" yaml:"user,omitempty"`
+	// Moderator max requests in the window for the users whose role can moderate content, 0 means no limit.
+	// The users whose role has admin access are never limited.
+	Moderator int `json:"moderator" mapstructure:"moderator" yaml:"moderator,omitempty"`
 }
@@ -22,10 +22,12 @@ package tag
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/apache/incubator-answer/internal/base/constant"
 	"github.com/apache/incubator-answer/internal/service/activity_queue"
+	questioncommon "github.com/apache/incubator-answer/internal/service/question_common"
 	"github.com/apache/incubator-answer/internal/service/revision_common"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
 	tagcommonser "github.com/apache/incubator-answer/internal/service/tag_common"
@@ -51,6 +53,8 @@ type TagService struct {
 	followCommon         activity_common.FollowRepo
 	siteInfoService      siteinfo_common.SiteInfoCommonService
 	activityQueueService activity_queue.ActivityQueueService
+	activityRepo         activity_common.ActivityRepo
+	questionRepo         questioncommon.QuestionRepo
 }
 
 // NewTagService new tag service
@@ -61,6 +65,8 @@ func NewTagService(
 	followCommon activity_common.FollowRepo,
 	siteInfoService siteinfo_common.SiteInfoCommonService,
 	activityQueueService activity_queue.ActivityQueueService,
+	activityRepo activity_common.ActivityRepo,
+	questionRepo questioncommon.QuestionRepo,
 ) *TagService {
 	return &TagService{
 		tagRepo:              tagRepo,
@@ -69,6 +75,8 @@ func NewTagService(
 		followCommon:         followCommon,
 		siteInfoService:      siteInfoService,
 		activityQueueService: activityQueueService,
+		activityRepo:         activityRepo,
+		questionRepo:         questionRepo,
 	}
 }
 
@@ -92,6 +100,14 @@ func (ts *TagService) RemoveTag(ctx context.Context, req *schema.RemoveTagReq) (
 		return errors.BadRequest(reason.TagIsUsedCannotDelete)
 	}
 
+	subTagList, err := ts.tagCommonService.GetSubTagList(ctx, []string{req.TagID})
+	if err != nil {
+		return err
+	}
+	if len(subTagList) > 0 {
+		return errors.BadRequest(reason.TagHasSubTagsCannotDelete)
+	}
+
 	// tagRelRepo
 	err = ts.tagRepo.RemoveTag(ctx, req.TagID)
 	if err != nil {
@@ -188,6 +204,19 @@ func (ts *TagService) GetTagInfo(ctx context.Context, req *schema.GetTagInfoReq)
 	resp.Status = entity.TagStatusDisplayMapping[tagInfo.Status]
 	resp.MemberActions = permission.GetTagPermission(ctx, tagInfo.Status, req.CanEdit, req.CanDelete, req.CanRecover)
 	resp.GetExcerpt()
+
+	parentTagList, err := ts.tagCommonService.GetAncestorTagList(ctx, tagInfo)
+	if err != nil {
+		return nil, err
+	}
+	resp.ParentTags = make([]*schema.TagPathItem, 0, len(parentTagList))
+	for _, parentTag := range parentTagList {
+		resp.ParentTags = append(resp.ParentTags, &schema.TagPathItem{
+			TagID:       parentTag.ID,
+			SlugName:    parentTag.SlugName,
+			DisplayName: parentTag.DisplayName,
+		})
+	}
 	return resp, nil
 }
 
@@ -399,6 +428,160 @@ func (ts *TagService) UpdateTagSynonym(ctx context.Context, req *schema.UpdateTa
 	return nil
 }
 
+// MergeTag merge source tag into target tag, the questions and followers of source tag are moved to target tag
+func (ts *TagService) MergeTag(ctx context.Context, req *schema.MergeTagReq) (err error) {
+	if req.SourceTagID == req.TargetTagID {
+		return errors.BadRequest(reason.TagCannotMergeIntoItself)
+	}
+	sourceTag, exist, err := ts.tagCommonService.GetTagByID(ctx, req.SourceTagID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.TagNotFound)
+	}
+	targetTag, exist, err := ts.tagCommonService.GetTagByID(ctx, req.TargetTagID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.TagNotFound)
+	}
+	if targetTag.MainTagID != 0 {
+		return errors.BadRequest(reason.TagCannotMergeIntoSynonym)
+	}
+	// the sub tags of source tag are moved to target tag, so target tag can't be one of them
+	descendantIDs, err := ts.tagCommonService.GetDescendantTagIDs(ctx, []string{sourceTag.ID})
+	if err != nil {
+		return err
+	}
+	for _, descendantID := range descendantIDs {
+		if descendantID == targetTag.ID {
+			return errors.BadRequest(reason.TagCannotMergeIntoSubTag)
+		}
+	}
+
+	followActivityType, err := ts.activityRepo.GetActivityTypeByObjectType(ctx, constant.TagObjectType, "follow")
+	if err != nil {
+		return err
+	}
+	questionIDs, err := ts.tagRepo.MergeTag(ctx, sourceTag, targetTag, followActivityType, req.KeepAsSynonym)
+	if err != nil {
+		return err
+	}
+	if err = ts.tagCommonService.RefreshTagQuestionCount(ctx, []string{targetTag.ID}); err != nil {
+		log.Errorf("refresh question count of tag %s failed: %v", targetTag.ID, err)
+	}
+
+	// record the merge as a revision of source tag
+	sourceTag, _, err = ts.tagRepo.MustGetTagByNameOrID(ctx, sourceTag.ID, "")
+	if err != nil {
+		return err
+	}
+	revisionDTO := &schema.AddRevisionDTO{
+		UserID:   req.UserID,
+		ObjectID: sourceTag.ID,
+		Title:    sourceTag.SlugName,
+		Log:      fmt.Sprintf("merged into %s", targetTag.SlugName),
+		Status:   entity.RevisionReviewPassStatus,
+	}
+	tagInfoJson, _ := json.Marshal(sourceTag)
+	revisionDTO.Content = string(tagInfoJson)
+	revisionID, err := ts.revisionService.AddRevision(ctx, revisionDTO, true)
+	if err != nil {
+		return err
+	}
+	ts.activityQueueService.Send(ctx, &schema.ActivityMsg{
+		UserID:           req.UserID,
+		ObjectID:         sourceTag.ID,
+		OriginalObjectID: sourceTag.ID,
+		ActivityTypeKey:  constant.ActTagEdited,
+		RevisionID:       revisionID,
+	})
+
+	for _, questionID := range questionIDs {
+		if err := ts.questionRepo.UpdateSearch(ctx, questionID); err != nil {
+			log.Errorf("update search of question %s failed: %v", questionID, err)
+		}
+	}
+	return nil
+}
+
+// UpdateTagParent update the parent of tag
+func (ts *TagService) UpdateTagParent(ctx context.Context, req *schema.UpdateTagParentReq) (err error) {
+	tagInfo, exist, err := ts.tagCommonService.GetTagByID(ctx, req.TagID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.TagNotFound)
+	}
+	if tagInfo.MainTagID != 0 {
+		return errors.BadRequest(reason.TagInvalidParent)
+	}
+	if len(req.ParentTagID) == 0 || req.ParentTagID == "0" {
+		return ts.tagRepo.UpdateTagParent(ctx, tagInfo.ID, 0)
+	}
+
+	parentTag, exist, err := ts.tagCommonService.GetTagByID(ctx, req.ParentTagID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.TagNotFound)
+	}
+	if parentTag.ID == tagInfo.ID || parentTag.MainTagID != 0 {
+		return errors.BadRequest(reason.TagInvalidParent)
+	}
+	// the parent can't be a sub tag of the tag, otherwise there will be a cycle
+	descendantIDs, err := ts.tagCommonService.GetDescendantTagIDs(ctx, []string{tagInfo.ID})
+	if err != nil {
+		return err
+	}
+	for _, descendantID := range descendantIDs {
+		if descendantID == parentTag.ID {
+			return errors.BadRequest(reason.TagInvalidParent)
+		}
+	}
+	return ts.tagRepo.UpdateTagParent(ctx, tagInfo.ID, converter.StringToInt64(parentTag.ID))
+}
+
+// GetTagTree get the direct sub tags of tag by page, or the root tags if tag id is empty.
+// The synonyms are not shown in the tree.
+func (ts *TagService) GetTagTree(ctx context.Context, req *schema.GetTagTreeReq) (
+	pageModel *pager.PageModel, err error) {
+	parentTagID := req.TagID
+	if len(parentTagID) == 0 {
+		parentTagID = "0"
+	}
+	tagList, total, err := ts.tagCommonService.GetSubTagPage(ctx, parentTagID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := make([]string, 0, len(tagList))
+	for _, tag := range tagList {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	subTagCount, err := ts.tagCommonService.CountSubTags(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*schema.GetTagTreeResp, 0, len(tagList))
+	for _, tag := range tagList {
+		resp = append(resp, &schema.GetTagTreeResp{
+			TagID:         tag.ID,
+			SlugName:      tag.SlugName,
+			DisplayName:   tag.DisplayName,
+			FollowCount:   tag.FollowCount,
+			QuestionCount: tag.QuestionCount,
+			SubTagCount:   subTagCount[tag.ID],
+		})
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
 // GetTagWithPage get tag list page
 func (ts *TagService) GetTagWithPage(ctx context.Context, req *schema.GetTagWithPageReq) (pageModel *pager.PageModel, err error) {
 	tag := &entity.Tag{}
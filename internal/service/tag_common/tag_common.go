@@ -62,6 +62,16 @@ type TagRepo interface {
 	GetTagSynonymCount(ctx context.Context, tagID string) (count int64, err error)
 	GetIDsByMainTagId(ctx context.Context, mainTagID string) (tagIDs []string, err error)
 	GetTagList(ctx context.Context, tag *entity.Tag) (tagList []*entity.Tag, err error)
+	GetTagListByParentIDs(ctx context.Context, parentTagIDs []string) (tagList []*entity.Tag, err error)
+	// GetSubTagPage get the sub tags of parent tag by page, the synonyms are not included
+	GetSubTagPage(ctx context.Context, parentTagID string, page, pageSize int) (tagList []*entity.Tag, total int64, err error)
+	// CountSubTags count the sub tags of each parent tag, the synonyms are not included
+	CountSubTags(ctx context.Context, parentTagIDs []string) (counts map[string]int, err error)
+	UpdateTagParent(ctx context.Context, tagID string, parentTagID int64) (err error)
+	// MergeTag move the questions and followers of source tag to target tag in a transaction,
+	// it returns the id of questions whose tags are changed
+	MergeTag(ctx context.Context, sourceTag, targetTag *entity.Tag, followActivityType int, keepAsSynonym bool) (
+		questionIDs []string, err error)
 }
 
 type TagRelRepo interface {
@@ -377,6 +387,101 @@ func (ts *TagCommonService) GetTagIDsByMainTagID(ctx context.Context, tagID stri
 	return
 }
 
+// GetSubTagList get the sub tags of parent tags
+func (ts *TagCommonService) GetSubTagList(ctx context.Context, parentTagIDs []string) (tagList []*entity.Tag, err error) {
+	return ts.tagRepo.GetTagListByParentIDs(ctx, parentTagIDs)
+}
+
+// GetSubTagPage get the sub tags of parent tag by page
+func (ts *TagCommonService) GetSubTagPage(ctx context.Context, parentTagID string, page, pageSize int) (
+	tagList []*entity.Tag, total int64, err error) {
+	return ts.tagRepo.GetSubTagPage(ctx, parentTagID, page, pageSize)
+}
+
+// CountSubTags count the sub tags of each parent tag
+func (ts *TagCommonService) CountSubTags(ctx context.Context, parentTagIDs []string) (counts map[string]int, err error) {
+	return ts.tagRepo.CountSubTags(ctx, parentTagIDs)
+}
+
+// GetDescendantTagIDs get the ids of all sub tags of tags, the tags themselves are not included
+func (ts *TagCommonService) GetDescendantTagIDs(ctx context.Context, tagIDs []string) (descendantIDs []string, err error) {
+	descendantIDs = make([]string, 0)
+	visited := make(map[string]bool, len(tagIDs))
+	for _, tagID := range tagIDs {
+		visited[tagID] = true
+	}
+	parentTagIDs := tagIDs
+	for len(parentTagIDs) > 0 {
+		subTagList, err := ts.tagRepo.GetTagListByParentIDs(ctx, parentTagIDs)
+		if err != nil {
+			return nil, err
+		}
+		parentTagIDs = make([]string, 0, len(subTagList))
+		for _, tag := range subTagList {
+			if visited[tag.ID] {
+				continue
+			}
+			visited[tag.ID] = true
+			descendantIDs = append(descendantIDs, tag.ID)
+			parentTagIDs = append(parentTagIDs, tag.ID)
+		}
+	}
+	return descendantIDs, nil
+}
+
+// GetAncestorTagIDs get the ids of all parent tags of tags, the tags themselves are not included
+func (ts *TagCommonService) GetAncestorTagIDs(ctx context.Context, tagIDs []string) (ancestorIDs []string, err error) {
+	ancestorIDs = make([]string, 0)
+	visited := make(map[string]bool, len(tagIDs))
+	for _, tagID := range tagIDs {
+		visited[tagID] = true
+	}
+	childTagIDs := tagIDs
+	for len(childTagIDs) > 0 {
+		tagList, err := ts.tagCommonRepo.GetTagListByIDs(ctx, childTagIDs)
+		if err != nil {
+			return nil, err
+		}
+		childTagIDs = make([]string, 0, len(tagList))
+		for _, tag := range tagList {
+			if tag.ParentTagID == 0 {
+				continue
+			}
+			parentTagID := converter.IntToString(tag.ParentTagID)
+			if visited[parentTagID] {
+				continue
+			}
+			visited[parentTagID] = true
+			ancestorIDs = append(ancestorIDs, parentTagID)
+			childTagIDs = append(childTagIDs, parentTagID)
+		}
+	}
+	return ancestorIDs, nil
+}
+
+// GetAncestorTagList get the parent tags of tag from the root to the direct parent
+func (ts *TagCommonService) GetAncestorTagList(ctx context.Context, tag *entity.Tag) (tagList []*entity.Tag, err error) {
+	tagList = make([]*entity.Tag, 0)
+	visited := map[string]bool{tag.ID: true}
+	for tag.ParentTagID > 0 {
+		parentTagID := converter.IntToString(tag.ParentTagID)
+		if visited[parentTagID] {
+			break
+		}
+		visited[parentTagID] = true
+		parentTag, exist, err := ts.tagCommonRepo.GetTagByID(ctx, parentTagID, false)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			break
+		}
+		tagList = append([]*entity.Tag{parentTag}, tagList...)
+		tag = parentTag
+	}
+	return tagList, nil
+}
+
 // GetTagBySlugName get object tag
 func (ts *TagCommonService) GetTagBySlugName(ctx context.Context, slugName string) (tag *entity.Tag, exist bool, err error) {
 	tag, exist, err = ts.tagCommonRepo.GetTagBySlugName(ctx, slugName)
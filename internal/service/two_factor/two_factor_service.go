@@ -0,0 +1,444 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package two_factor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/permission"
+	"github.com/apache/incubator-answer/internal/service/role"
+	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
+	"github.com/apache/incubator-answer/pkg/token"
+	"github.com/apache/incubator-answer/pkg/totp"
+	"github.com/segmentfault/pacman/errors"
+)
+
+const (
+	recoveryCodeCount = 10
+	// maxChallengeAttempts the login must restart after too many wrong codes
+	maxChallengeAttempts = 5
+	// userAttemptsKeyPrefix the attempts of managing the two factor of user are counted as a challenge of the user
+	userAttemptsKeyPrefix = "user:"
+)
+
+// TwoFactorRepo two factor repository
+type TwoFactorRepo interface {
+	GetUserTwoFactor(ctx context.Context, userID string) (tf *entity.UserTwoFactor, exist bool, err error)
+	SaveUserTwoFactor(ctx context.Context, tf *entity.UserTwoFactor) (err error)
+	UpdateUserTwoFactor(ctx context.Context, tf *entity.UserTwoFactor, cols ...string) (err error)
+	// UseTimeStep mark the time step as used, it returns false if the step or a later one was used
+	UseTimeStep(ctx context.Context, userID string, step int64) (ok bool, err error)
+	// UpdateRecoveryCodes replace the recovery codes only if they are not changed by others
+	UpdateRecoveryCodes(ctx context.Context, userID, oldCodes, newCodes string) (ok bool, err error)
+	RemoveUserTwoFactor(ctx context.Context, userID string) (err error)
+	SetChallenge(ctx context.Context, token string, challenge *schema.TwoFactorChallenge) (err error)
+	GetChallenge(ctx context.Context, token string) (challenge *schema.TwoFactorChallenge, exist bool, err error)
+	// IncreaseChallengeAttempts increase the attempts of the pending login atomically and return the new value
+	IncreaseChallengeAttempts(ctx context.Context, token string) (attempts int64, err error)
+	RemoveChallenge(ctx context.Context, token string) (err error)
+}
+
+// TwoFactorService TOTP two-factor authentication service
+type TwoFactorService struct {
+	twoFactorRepo       TwoFactorRepo
+	userRepo            usercommon.UserRepo
+	siteInfoService     siteinfo_common.SiteInfoCommonService
+	userRoleService     *role.UserRoleRelService
+	rolePowerRelService *role.RolePowerRelService
+}
+
+// NewTwoFactorService new two factor service
+func NewTwoFactorService(
+	twoFactorRepo TwoFactorRepo,
+	userRepo usercommon.UserRepo,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+	userRoleService *role.UserRoleRelService,
+	rolePowerRelService *role.RolePowerRelService,
+) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo:       twoFactorRepo,
+		userRepo:            userRepo,
+		siteInfoService:     siteInfoService,
+		userRoleService:     userRoleService,
+		rolePowerRelService: rolePowerRelService,
+	}
+}
+
+// GetTwoFactorStatus get the two factor status of user
+func (ts *TwoFactorService) GetTwoFactorStatus(ctx context.Context, req *schema.GetTwoFactorStatusReq) (
+	resp *schema.GetTwoFactorStatusResp, err error) {
+	resp = &schema.GetTwoFactorStatusResp{}
+	tf, exist, err := ts.twoFactorRepo.GetUserTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if exist && tf.IsEnabled() {
+		resp.Enabled = true
+		resp.RecoveryCodesRemaining = len(tf.GetRecoveryCodes())
+	}
+	return resp, nil
+}
+
+// IsEnabled check the user has enabled two factor or not
+func (ts *TwoFactorService) IsEnabled(ctx context.Context, userID string) (enabled bool, err error) {
+	tf, exist, err := ts.twoFactorRepo.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return exist && tf.IsEnabled(), nil
+}
+
+// Enroll generate a new pending secret, it takes effect after it is enabled with a valid code
+func (ts *TwoFactorService) Enroll(ctx context.Context, req *schema.TwoFactorEnrollReq) (
+	resp *schema.TwoFactorEnrollResp, err error) {
+	tf, exist, err := ts.twoFactorRepo.GetUserTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if exist && tf.IsEnabled() {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+	userInfo, exist, err := ts.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	tf = &entity.UserTwoFactor{
+		UserID: req.UserID,
+		Secret: secret,
+		Status: entity.UserTwoFactorStatusPending,
+	}
+	tf.SetRecoveryCodes(nil)
+	if err = ts.twoFactorRepo.SaveUserTwoFactor(ctx, tf); err != nil {
+		return nil, err
+	}
+
+	issuer := "Answer"
+	if general, err := ts.siteInfoService.GetSiteGeneral(ctx); err == nil && len(general.Name) > 0 {
+		issuer = general.Name
+	}
+	return &schema.TwoFactorEnrollResp{
+		Secret: secret,
+		KeyURI: totp.KeyURI(issuer, userInfo.EMail, secret),
+	}, nil
+}
+
+// Enable enable the pending two factor with the code of authenticator, the recovery codes are returned only once
+func (ts *TwoFactorService) Enable(ctx context.Context, req *schema.TwoFactorEnableReq) (
+	resp *schema.TwoFactorRecoveryCodesResp, err error) {
+	tf, err := ts.getPendingTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	codes, ok, err := ts.enable(ctx, tf, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	return &schema.TwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// Disable disable two factor, it must be confirmed with a valid code
+func (ts *TwoFactorService) Disable(ctx context.Context, req *schema.TwoFactorCodeReq) (err error) {
+	tf, err := ts.getEnabledTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if err = ts.verifyUserCode(ctx, tf, req.Code); err != nil {
+		return err
+	}
+	return ts.twoFactorRepo.RemoveUserTwoFactor(ctx, req.UserID)
+}
+
+// RegenerateRecoveryCodes replace all recovery codes, it must be confirmed with a valid code
+func (ts *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, req *schema.TwoFactorCodeReq) (
+	resp *schema.TwoFactorRecoveryCodesResp, err error) {
+	tf, err := ts.getEnabledTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err = ts.verifyUserCode(ctx, tf, req.Code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	tf.SetRecoveryCodes(hashes)
+	if err = ts.twoFactorRepo.UpdateUserTwoFactor(ctx, tf, "recovery_codes"); err != nil {
+		return nil, err
+	}
+	return &schema.TwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// ResetUserTwoFactor admin remove the two factor of user, for example the user lost the authenticator
+func (ts *TwoFactorService) ResetUserTwoFactor(ctx context.Context, req *schema.ResetUserTwoFactorReq) (err error) {
+	return ts.twoFactorRepo.RemoveUserTwoFactor(ctx, req.UserID)
+}
+
+// LoginChallenge if the user enabled two factor, or the user is staff and the site requires it,
+// the login must be paused and the token for the second step is returned. The token is empty if it is not required.
+// It is used by all the login methods, such as email login and external login.
+func (ts *TwoFactorService) LoginChallenge(ctx context.Context, userID, externalID string) (
+	challenge *schema.TwoFactorChallenge, challengeToken string, err error) {
+	enabled, err := ts.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !enabled {
+		siteLogin, err := ts.siteInfoService.GetSiteLogin(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		if !siteLogin.RequireStaffTwoFactor {
+			return nil, "", nil
+		}
+		roleID, err := ts.userRoleService.GetUserRole(ctx, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		isStaff, err := ts.rolePowerRelService.RoleHasPower(ctx, roleID, permission.ContentModerate)
+		if err != nil {
+			return nil, "", err
+		}
+		if !isStaff {
+			return nil, "", nil
+		}
+	}
+
+	challenge = &schema.TwoFactorChallenge{UserID: userID, ExternalID: externalID, Enroll: !enabled}
+	challengeToken, err = ts.CreateChallenge(ctx, challenge)
+	if err != nil {
+		return nil, "", err
+	}
+	return challenge, challengeToken, nil
+}
+
+// EnrollChallenge set up two factor for the pending login that requires it,
+// for example the login is redirected from an external login and the secret can't be returned directly.
+func (ts *TwoFactorService) EnrollChallenge(ctx context.Context, req *schema.TwoFactorChallengeEnrollReq) (
+	resp *schema.TwoFactorEnrollResp, err error) {
+	challenge, exist, err := ts.twoFactorRepo.GetChallenge(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || !challenge.Enroll {
+		return nil, errors.BadRequest(reason.TwoFactorTokenInvalid)
+	}
+	return ts.Enroll(ctx, &schema.TwoFactorEnrollReq{UserID: challenge.UserID})
+}
+
+// CreateChallenge save the pending login and return the token for the second step
+func (ts *TwoFactorService) CreateChallenge(ctx context.Context, challenge *schema.TwoFactorChallenge) (
+	challengeToken string, err error) {
+	challengeToken = token.GenerateToken()
+	if err = ts.twoFactorRepo.SetChallenge(ctx, challengeToken, challenge); err != nil {
+		return "", err
+	}
+	return challengeToken, nil
+}
+
+// VerifyChallenge verify the code of the pending login.
+// If the user is setting up two factor during login, the recovery codes are returned.
+func (ts *TwoFactorService) VerifyChallenge(ctx context.Context, req *schema.UserTwoFactorLoginReq) (
+	challenge *schema.TwoFactorChallenge, recoveryCodes []string, err error) {
+	challenge, exist, err := ts.twoFactorRepo.GetChallenge(ctx, req.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist {
+		return nil, nil, errors.BadRequest(reason.TwoFactorTokenInvalid)
+	}
+	// count the attempt before checking the code, so that concurrent requests can't check more codes than allowed
+	attempts, err := ts.twoFactorRepo.IncreaseChallengeAttempts(ctx, req.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempts > maxChallengeAttempts {
+		if err = ts.twoFactorRepo.RemoveChallenge(ctx, req.Token); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errors.BadRequest(reason.TwoFactorTokenInvalid)
+	}
+
+	var ok bool
+	if challenge.Enroll {
+		tf, err := ts.getPendingTwoFactor(ctx, challenge.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if recoveryCodes, ok, err = ts.enable(ctx, tf, req.Code); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		tf, err := ts.getEnabledTwoFactor(ctx, challenge.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok, err = ts.verifyCode(ctx, tf, req.Code); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !ok {
+		if attempts >= maxChallengeAttempts {
+			if err = ts.twoFactorRepo.RemoveChallenge(ctx, req.Token); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	if err = ts.twoFactorRepo.RemoveChallenge(ctx, req.Token); err != nil {
+		return nil, nil, err
+	}
+	return challenge, recoveryCodes, nil
+}
+
+func (ts *TwoFactorService) getPendingTwoFactor(ctx context.Context, userID string) (
+	tf *entity.UserTwoFactor, err error) {
+	tf, exist, err := ts.twoFactorRepo.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TwoFactorNotEnrolled)
+	}
+	if tf.IsEnabled() {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+	return tf, nil
+}
+
+// enable enable the pending two factor if the code is valid
+func (ts *TwoFactorService) enable(ctx context.Context, tf *entity.UserTwoFactor, code string) (
+	recoveryCodes []string, ok bool, err error) {
+	step, ok := totp.Validate(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, false, nil
+	}
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, false, err
+	}
+	tf.Status = entity.UserTwoFactorStatusEnabled
+	tf.LastUsedStep = step
+	tf.SetRecoveryCodes(hashes)
+	if err = ts.twoFactorRepo.UpdateUserTwoFactor(ctx, tf, "status", "last_used_step", "recovery_codes"); err != nil {
+		return nil, false, err
+	}
+	return recoveryCodes, true, nil
+}
+
+func (ts *TwoFactorService) getEnabledTwoFactor(ctx context.Context, userID string) (
+	tf *entity.UserTwoFactor, err error) {
+	tf, exist, err := ts.twoFactorRepo.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || !tf.IsEnabled() {
+		return nil, errors.BadRequest(reason.TwoFactorNotEnabled)
+	}
+	return tf, nil
+}
+
+// verifyUserCode verify the code to manage the two factor of user. The wrong codes are limited the same as
+// the pending login, the user must wait for a while after too many wrong codes.
+func (ts *TwoFactorService) verifyUserCode(ctx context.Context, tf *entity.UserTwoFactor, code string) (err error) {
+	attemptsKey := userAttemptsKeyPrefix + tf.UserID
+	attempts, err := ts.twoFactorRepo.IncreaseChallengeAttempts(ctx, attemptsKey)
+	if err != nil {
+		return err
+	}
+	if attempts > maxChallengeAttempts {
+		return errors.New(http.StatusTooManyRequests, reason.TooManyRequestsError)
+	}
+	ok, err := ts.verifyCode(ctx, tf, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	return ts.twoFactorRepo.RemoveChallenge(ctx, attemptsKey)
+}
+
+// verifyCode verify the code of authenticator or the recovery code, the recovery code can only be used once
+func (ts *TwoFactorService) verifyCode(ctx context.Context, tf *entity.UserTwoFactor, code string) (ok bool, err error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(tf.Secret, code, time.Now())
+		if !ok || step <= tf.LastUsedStep {
+			return false, nil
+		}
+		return ts.twoFactorRepo.UseTimeStep(ctx, tf.UserID, step)
+	}
+
+	codeHash := hashRecoveryCode(code)
+	hashes := tf.GetRecoveryCodes()
+	for i, h := range hashes {
+		if h != codeHash {
+			continue
+		}
+		remaining := append(append([]string{}, hashes[:i]...), hashes[i+1:]...)
+		newTF := &entity.UserTwoFactor{}
+		newTF.SetRecoveryCodes(remaining)
+		return ts.twoFactorRepo.UpdateRecoveryCodes(ctx, tf.UserID, tf.RecoveryCodes, newTF.RecoveryCodes)
+	}
+	return false, nil
+}
+
+// generateRecoveryCodes generate the recovery codes like "a1b2c-3d4e5" and their hashes
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err = rand.Read(b); err != nil {
+			return nil, nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+		s := hex.EncodeToString(b)
+		code := s[:5] + "-" + s[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -195,6 +195,27 @@ func (us *UserAdminService) UpdateUserRole(ctx context.Context, req *schema.Upda
 	return
 }
 
+// GetUserAuditState get the state of user which is recorded in the audit log before admin changes it
+func (us *UserAdminService) GetUserAuditState(ctx context.Context, userID string) (
+	state *schema.UserAuditState, err error) {
+	userInfo, exist, err := us.userRepo.GetUserInfo(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	roleID, err := us.userRoleRelService.GetUserRole(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.UserAuditState{
+		Status:     userInfo.Status,
+		MailStatus: userInfo.MailStatus,
+		RoleID:     roleID,
+	}, nil
+}
+
 // AddUser add user
 func (us *UserAdminService) AddUser(ctx context.Context, req *schema.AddUserReq) (err error) {
 	_, has, err := us.userRepo.GetUserInfoByEmail(ctx, req.Email)
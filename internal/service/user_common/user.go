@@ -30,6 +30,7 @@ import (
 	"github.com/apache/incubator-answer/internal/entity"
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/auth"
+	"github.com/apache/incubator-answer/internal/service/permission"
 	"github.com/apache/incubator-answer/internal/service/role"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
 	"github.com/apache/incubator-answer/pkg/checker"
@@ -66,6 +67,7 @@ type UserRepo interface {
 type UserCommon struct {
 	userRepo              UserRepo
 	userRoleService       *role.UserRoleRelService
+	rolePowerRelService   *role.RolePowerRelService
 	authService           *auth.AuthService
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService
 }
@@ -73,12 +75,14 @@ type UserCommon struct {
 func NewUserCommon(
 	userRepo UserRepo,
 	userRoleService *role.UserRoleRelService,
+	rolePowerRelService *role.RolePowerRelService,
 	authService *auth.AuthService,
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
 ) *UserCommon {
 	return &UserCommon{
 		userRepo:              userRepo,
 		userRoleService:       userRoleService,
+		rolePowerRelService:   rolePowerRelService,
 		authService:           authService,
 		siteInfoCommonService: siteInfoCommonService,
 	}
@@ -228,7 +232,11 @@ func (us *UserCommon) CacheLoginUserInfo(ctx context.Context, userID string, use
 	if err != nil {
 		return "", nil, err
 	}
-	if userCacheInfo.RoleID == role.RoleAdminID {
+	isAdmin, err := us.rolePowerRelService.RoleHasPower(ctx, userCacheInfo.RoleID, permission.AdminAccess)
+	if err != nil {
+		return "", nil, err
+	}
+	if isAdmin {
 		if err = us.authService.SetAdminUserCacheInfo(ctx, accessToken, userCacheInfo); err != nil {
 			return "", nil, err
 		}
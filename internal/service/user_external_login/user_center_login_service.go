@@ -32,6 +32,7 @@ import (
 	"github.com/apache/incubator-answer/internal/schema"
 	"github.com/apache/incubator-answer/internal/service/activity"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	"github.com/apache/incubator-answer/internal/service/two_factor"
 	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
 	"github.com/apache/incubator-answer/pkg/checker"
 	"github.com/apache/incubator-answer/pkg/converter"
@@ -47,6 +48,7 @@ type UserCenterLoginService struct {
 	userCommonService     *usercommon.UserCommon
 	userActivity          activity.UserActiveActivityRepo
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService
+	twoFactorService      *two_factor.TwoFactorService
 }
 
 // NewUserCenterLoginService new user external login service
@@ -56,6 +58,7 @@ func NewUserCenterLoginService(
 	userExternalLoginRepo UserExternalLoginRepo,
 	userActivity activity.UserActiveActivityRepo,
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+	twoFactorService *two_factor.TwoFactorService,
 ) *UserCenterLoginService {
 	return &UserCenterLoginService{
 		userRepo:              userRepo,
@@ -63,6 +66,7 @@ func NewUserCenterLoginService(
 		userExternalLoginRepo: userExternalLoginRepo,
 		userActivity:          userActivity,
 		siteInfoCommonService: siteInfoCommonService,
+		twoFactorService:      twoFactorService,
 	}
 }
 
@@ -113,9 +117,8 @@ func (us *UserCenterLoginService) ExternalLogin(
 			if err := us.userRepo.UpdateLastLoginDate(ctx, oldUserInfo.ID); err != nil {
 				log.Errorf("update user last login date failed: %v", err)
 			}
-			accessToken, _, err := us.userCommonService.CacheLoginUserInfo(
-				ctx, oldUserInfo.ID, oldUserInfo.MailStatus, oldUserInfo.Status, oldExternalLoginUserInfo.ExternalID)
-			return &schema.UserExternalLoginResp{AccessToken: accessToken}, err
+			return loginWithTwoFactor(ctx, us.userCommonService, us.twoFactorService,
+				oldUserInfo, oldUserInfo.MailStatus, oldExternalLoginUserInfo.ExternalID)
 		}
 	}
 
@@ -34,6 +34,7 @@ import (
 	"github.com/apache/incubator-answer/internal/service/activity"
 	"github.com/apache/incubator-answer/internal/service/export"
 	"github.com/apache/incubator-answer/internal/service/siteinfo_common"
+	"github.com/apache/incubator-answer/internal/service/two_factor"
 	usercommon "github.com/apache/incubator-answer/internal/service/user_common"
 	"github.com/apache/incubator-answer/internal/service/user_notification_config"
 	"github.com/apache/incubator-answer/pkg/checker"
@@ -65,6 +66,7 @@ type UserExternalLoginService struct {
 	siteInfoCommonService         siteinfo_common.SiteInfoCommonService
 	userActivity                  activity.UserActiveActivityRepo
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService
+	twoFactorService              *two_factor.TwoFactorService
 }
 
 // NewUserExternalLoginService new user external login service
@@ -76,6 +78,7 @@ func NewUserExternalLoginService(
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
 	userActivity activity.UserActiveActivityRepo,
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService,
+	twoFactorService *two_factor.TwoFactorService,
 ) *UserExternalLoginService {
 	return &UserExternalLoginService{
 		userRepo:                      userRepo,
@@ -85,6 +88,7 @@ func NewUserExternalLoginService(
 		siteInfoCommonService:         siteInfoCommonService,
 		userActivity:                  userActivity,
 		userNotificationConfigService: userNotificationConfigService,
+		twoFactorService:              twoFactorService,
 	}
 }
 
@@ -118,9 +122,8 @@ func (us *UserExternalLoginService) ExternalLogin(
 			if err != nil {
 				log.Error(err)
 			}
-			accessToken, _, err := us.userCommonService.CacheLoginUserInfo(
-				ctx, oldUserInfo.ID, newMailStatus, oldUserInfo.Status, oldExternalLoginUserInfo.ExternalID)
-			return &schema.UserExternalLoginResp{AccessToken: accessToken}, err
+			return loginWithTwoFactor(ctx, us.userCommonService, us.twoFactorService,
+				oldUserInfo, newMailStatus, oldExternalLoginUserInfo.ExternalID)
 		}
 	}
 
@@ -175,8 +178,24 @@ func (us *UserExternalLoginService) ExternalLogin(
 		log.Errorf("set default user notification config failed, err: %v", err)
 	}
 
-	accessToken, _, err := us.userCommonService.CacheLoginUserInfo(
-		ctx, oldUserInfo.ID, newMailStatus, oldUserInfo.Status, oldExternalLoginUserInfo.ExternalID)
+	return loginWithTwoFactor(ctx, us.userCommonService, us.twoFactorService,
+		oldUserInfo, newMailStatus, oldExternalLoginUserInfo.ExternalID)
+}
+
+// loginWithTwoFactor issue the access token, or return the token for the second step
+// if the user must complete the login with two factor, the same as email login.
+func loginWithTwoFactor(ctx context.Context, userCommonService *usercommon.UserCommon,
+	twoFactorService *two_factor.TwoFactorService, userInfo *entity.User, mailStatus int, externalID string) (
+	resp *schema.UserExternalLoginResp, err error) {
+	_, challengeToken, err := twoFactorService.LoginChallenge(ctx, userInfo.ID, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if len(challengeToken) > 0 {
+		return &schema.UserExternalLoginResp{TwoFactorToken: challengeToken}, nil
+	}
+	accessToken, _, err := userCommonService.CacheLoginUserInfo(
+		ctx, userInfo.ID, mailStatus, userInfo.Status, externalID)
 	return &schema.UserExternalLoginResp{AccessToken: accessToken}, err
 }
 
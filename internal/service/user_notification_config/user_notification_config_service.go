@@ -85,6 +85,11 @@ func (us *UserNotificationConfigService) UpdateUserNotificationConfig(
 	if err != nil {
 		return err
 	}
+	err = us.userNotificationConfigRepo.Save(ctx,
+		us.convertToEntity(ctx, req.UserID, constant.DigestSource, req.NotificationConfig.Digest))
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/entity"
+)
+
+const (
+	// maxResponseBodyLength only the beginning of the response body is saved in the delivery log
+	maxResponseBodyLength = 1024
+	deliveryTimeout       = 10 * time.Second
+
+	HeaderEvent     = "X-Answer-Event"
+	HeaderDelivery  = "X-Answer-Delivery"
+	HeaderSignature = "X-Answer-Signature-256"
+)
+
+type webhookClient struct {
+	httpClient *http.Client
+}
+
+type deliveryResult struct {
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func newWebhookClient() *webhookClient {
+	return &webhookClient{httpClient: &http.Client{Timeout: deliveryTimeout}}
+}
+
+// post sends the payload to the webhook url, only 2xx status code is treated as success
+func (wc *webhookClient) post(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) (
+	result *deliveryResult) {
+	result = &deliveryResult{}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Answer-Webhook")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	if len(webhook.Secret) > 0 {
+		req.Header.Set(HeaderSignature, "sha256="+Sign(webhook.Secret, []byte(delivery.Payload)))
+	}
+
+	resp, err := wc.httpClient.Do(req)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyLength))
+	result.StatusCode = resp.StatusCode
+	result.Body = string(body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.Err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return result
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,346 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-answer/internal/base/constant"
+	"github.com/apache/incubator-answer/internal/base/queue"
+	"github.com/apache/incubator-answer/internal/base/reason"
+	"github.com/apache/incubator-answer/internal/entity"
+	"github.com/apache/incubator-answer/internal/schema"
+	"github.com/apache/incubator-answer/internal/service/event_queue"
+	"github.com/apache/incubator-answer/internal/service/service_config"
+	myErrors "github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// WebhookRepo webhook repository
+type WebhookRepo interface {
+	AddWebhook(ctx context.Context, webhook *entity.Webhook) (err error)
+	UpdateWebhook(ctx context.Context, webhook *entity.Webhook) (err error)
+	DeleteWebhook(ctx context.Context, webhookID string) (err error)
+	GetWebhook(ctx context.Context, webhookID string) (webhook *entity.Webhook, exist bool, err error)
+	GetWebhookList(ctx context.Context) (webhooks []*entity.Webhook, err error)
+	GetActiveWebhookList(ctx context.Context) (webhooks []*entity.Webhook, err error)
+}
+
+// WebhookDeliveryRepo webhook delivery repository
+type WebhookDeliveryRepo interface {
+	AddDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error)
+	GetEventWebhookIDs(ctx context.Context, eventID string) (webhookIDs []string, err error)
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error)
+	GetDelivery(ctx context.Context, deliveryID string) (delivery *entity.WebhookDelivery, exist bool, err error)
+	GetDeliveryPage(ctx context.Context, page, pageSize int, webhookID string) (
+		deliveries []*entity.WebhookDelivery, total int64, err error)
+}
+
+// WebhookService webhook service
+type WebhookService struct {
+	webhookRepo         WebhookRepo
+	webhookDeliveryRepo WebhookDeliveryRepo
+	deliveryQueue       *queue.Queue[*schema.WebhookDeliveryMsg]
+	client              *webhookClient
+}
+
+// NewWebhookService new webhook service
+func NewWebhookService(
+	webhookRepo WebhookRepo,
+	webhookDeliveryRepo WebhookDeliveryRepo,
+	eventQueueService event_queue.EventQueueService,
+	queueRepo queue.MessageRepo,
+	serviceConf *service_config.ServiceConfig,
+) *WebhookService {
+	ws := &WebhookService{
+		webhookRepo:         webhookRepo,
+		webhookDeliveryRepo: webhookDeliveryRepo,
+		deliveryQueue:       queue.New[*schema.WebhookDeliveryMsg]("webhook_delivery", queueRepo, serviceConf.Queue),
+		client:              newWebhookClient(),
+	}
+	eventQueueService.RegisterHandler(ws.EventHandler)
+	ws.deliveryQueue.RegisterHandler(ws.DeliveryHandler)
+	return ws
+}
+
+// GetWebhookList get all webhooks
+func (ws *WebhookService) GetWebhookList(ctx context.Context) (resp []*schema.WebhookInfo, err error) {
+	webhooks, err := ws.webhookRepo.GetWebhookList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.WebhookInfo, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resp = append(resp, &schema.WebhookInfo{
+			ID:        webhook.ID,
+			Name:      webhook.Name,
+			URL:       webhook.URL,
+			HasSecret: len(webhook.Secret) > 0,
+			Events:    webhook.GetEvents(),
+			Status:    schema.WebhookStatusMap[webhook.Status],
+			CreatedAt: webhook.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// GetEventTypeList get all event types that can be subscribed
+func (ws *WebhookService) GetEventTypeList(ctx context.Context) (resp []constant.EventType, err error) {
+	return constant.EventTypeList, nil
+}
+
+// AddWebhook add webhook, a random secret will be generated if the secret is not set
+func (ws *WebhookService) AddWebhook(ctx context.Context, req *schema.AddWebhookReq) (
+	resp *schema.AddWebhookResp, err error) {
+	if err = checkEventTypes(req.Events); err != nil {
+		return nil, err
+	}
+	webhook := &entity.Webhook{
+		Name:   req.Name,
+		URL:    req.URL,
+		Secret: req.Secret,
+		Status: entity.WebhookStatusActive,
+	}
+	if len(webhook.Secret) == 0 {
+		webhook.Secret = generateSecret()
+	}
+	webhook.SetEvents(req.Events)
+	if err = ws.webhookRepo.AddWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return &schema.AddWebhookResp{ID: webhook.ID, Secret: webhook.Secret}, nil
+}
+
+// UpdateWebhook update webhook
+func (ws *WebhookService) UpdateWebhook(ctx context.Context, req *schema.UpdateWebhookReq) (err error) {
+	if err = checkEventTypes(req.Events); err != nil {
+		return err
+	}
+	webhook, exist, err := ws.webhookRepo.GetWebhook(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return myErrors.BadRequest(reason.WebhookNotFound)
+	}
+	webhook.Name = req.Name
+	webhook.URL = req.URL
+	webhook.Status = schema.WebhookStatusEMap[req.Status]
+	if len(req.Secret) > 0 {
+		webhook.Secret = req.Secret
+	}
+	webhook.SetEvents(req.Events)
+	return ws.webhookRepo.UpdateWebhook(ctx, webhook)
+}
+
+// DeleteWebhook delete webhook
+func (ws *WebhookService) DeleteWebhook(ctx context.Context, req *schema.DeleteWebhookReq) (err error) {
+	return ws.webhookRepo.DeleteWebhook(ctx, req.ID)
+}
+
+// GetDeliveryPage get the delivery log of the webhook
+func (ws *WebhookService) GetDeliveryPage(ctx context.Context, req *schema.GetWebhookDeliveryPageReq) (
+	resp []*schema.WebhookDeliveryInfo, total int64, err error) {
+	deliveries, total, err := ws.webhookDeliveryRepo.GetDeliveryPage(ctx, req.Page, req.PageSize, req.WebhookID)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp = make([]*schema.WebhookDeliveryInfo, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		info := &schema.WebhookDeliveryInfo{
+			ID:           delivery.ID,
+			WebhookID:    delivery.WebhookID,
+			EventType:    delivery.EventType,
+			Payload:      delivery.Payload,
+			Status:       schema.WebhookDeliveryStatusMap[delivery.Status],
+			Attempts:     delivery.Attempts,
+			ResponseCode: delivery.ResponseCode,
+			ResponseBody: delivery.ResponseBody,
+			LastError:    delivery.LastError,
+			CreatedAt:    delivery.CreatedAt.Unix(),
+		}
+		if !delivery.NextRetryAt.IsZero() {
+			info.NextRetryAt = delivery.NextRetryAt.Unix()
+		}
+		if !delivery.DeliveredAt.IsZero() {
+			info.DeliveredAt = delivery.DeliveredAt.Unix()
+		}
+		resp = append(resp, info)
+	}
+	return resp, total, nil
+}
+
+// Redeliver creates a new delivery with the same payload of the given delivery
+func (ws *WebhookService) Redeliver(ctx context.Context, req *schema.RedeliverWebhookReq) (err error) {
+	delivery, exist, err := ws.webhookDeliveryRepo.GetDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return myErrors.BadRequest(reason.WebhookDeliveryNotFound)
+	}
+	return ws.createDelivery(ctx, "0", delivery.WebhookID, delivery.EventType, delivery.Payload)
+}
+
+// EventHandler creates deliveries for all active webhooks that subscribe the event.
+// The delivery is created once for each webhook, so only the webhooks failed to create are retried with the event.
+func (ws *WebhookService) EventHandler(ctx context.Context, msg *schema.EventMsg) error {
+	webhooks, err := ws.webhookRepo.GetActiveWebhookList(ctx)
+	if err != nil {
+		return err
+	}
+	eventID := strconv.FormatInt(queue.MessageID(ctx), 10)
+	created := make([]string, 0)
+	if eventID != "0" {
+		created, err = ws.webhookDeliveryRepo.GetEventWebhookIDs(ctx, eventID)
+		if err != nil {
+			return err
+		}
+	}
+	var (
+		payload []byte
+		errs    []error
+	)
+	for _, webhook := range webhooks {
+		if !webhook.IsSubscribed(string(msg.EventType)) || slices.Contains(created, webhook.ID) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(buildPayload(msg))
+			if err != nil {
+				return err
+			}
+		}
+		if err = ws.createDelivery(ctx, eventID, webhook.ID, string(msg.EventType), string(payload)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeliveryHandler posts the payload to the webhook url.
+// The delivery follows the retry schedule of the queue: if failed, returns the error to let the queue retry it
+// with backoff, and the delivery is marked as failed at the last attempt of the queue (queue.max_attempts).
+func (ws *WebhookService) DeliveryHandler(ctx context.Context, msg *schema.WebhookDeliveryMsg) error {
+	delivery, exist, err := ws.webhookDeliveryRepo.GetDelivery(ctx, msg.DeliveryID)
+	if err != nil {
+		return err
+	}
+	if !exist || delivery.Status == entity.WebhookDeliveryStatusSuccess ||
+		delivery.Status == entity.WebhookDeliveryStatusFailed {
+		return nil
+	}
+	webhook, exist, err := ws.webhookRepo.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	delivery.Attempts++
+	if !exist || webhook.Status != entity.WebhookStatusActive {
+		delivery.Status = entity.WebhookDeliveryStatusFailed
+		delivery.LastError = "webhook is not found or inactive"
+		return ws.webhookDeliveryRepo.UpdateDelivery(ctx, delivery)
+	}
+
+	result := ws.client.post(ctx, webhook, delivery)
+	delivery.ResponseCode = result.StatusCode
+	delivery.ResponseBody = result.Body
+	delivery.LastError = ""
+	if result.Err == nil {
+		delivery.Status = entity.WebhookDeliveryStatusSuccess
+		delivery.DeliveredAt = time.Now()
+		delivery.NextRetryAt = time.Time{}
+		return ws.webhookDeliveryRepo.UpdateDelivery(ctx, delivery)
+	}
+
+	delivery.LastError = result.Err.Error()
+	if queue.IsLastAttempt(ctx) {
+		delivery.Status = entity.WebhookDeliveryStatusFailed
+		delivery.NextRetryAt = time.Time{}
+		return ws.webhookDeliveryRepo.UpdateDelivery(ctx, delivery)
+	}
+	delivery.Status = entity.WebhookDeliveryStatusRetrying
+	delivery.NextRetryAt = time.Now().Add(queue.RetryDelay(queue.Attempts(ctx)))
+	if err = ws.webhookDeliveryRepo.UpdateDelivery(ctx, delivery); err != nil {
+		log.Error(err)
+	}
+	return result.Err
+}
+
+func (ws *WebhookService) createDelivery(ctx context.Context, eventID, webhookID, eventType, payload string) (err error) {
+	delivery := &entity.WebhookDelivery{
+		EventID:   eventID,
+		WebhookID: webhookID,
+		EventType: eventType,
+		Payload:   payload,
+		Status:    entity.WebhookDeliveryStatusPending,
+	}
+	if err = ws.webhookDeliveryRepo.AddDelivery(ctx, delivery); err != nil {
+		return err
+	}
+	ws.deliveryQueue.Send(ctx, &schema.WebhookDeliveryMsg{DeliveryID: delivery.ID})
+	return nil
+}
+
+func buildPayload(msg *schema.EventMsg) *schema.WebhookPayload {
+	return &schema.WebhookPayload{
+		Event:     msg.EventType,
+		Timestamp: time.Now().Unix(),
+		Data: &schema.WebhookPayloadData{
+			UserID:          msg.UserID,
+			TriggerObjectID: msg.TriggerObjectID,
+			QuestionID:      msg.QuestionID,
+			QuestionUserID:  msg.QuestionUserID,
+			AnswerID:        msg.AnswerID,
+			AnswerUserID:    msg.AnswerUserID,
+			CommentID:       msg.CommentID,
+			CommentUserID:   msg.CommentUserID,
+			ExtraInfo:       msg.ExtraInfo,
+		},
+	}
+}
+
+func checkEventTypes(events []string) error {
+	for _, event := range events {
+		found := false
+		for _, eventType := range constant.EventTypeList {
+			if string(eventType) == event {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return myErrors.BadRequest(reason.WebhookEventTypeInvalid)
+		}
+	}
+	return nil
+}
+
+func generateSecret() string {
+	bytes := make([]byte, 20)
+	_, _ = rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
@@ -52,7 +52,11 @@ func Markdown2HTML(source string) string {
 		log.Error(err)
 		return source
 	}
-	html := buf.String()
+	return SanitizeHTML(buf.String())
+}
+
+// SanitizeHTML remove the dangerous html elements and attributes
+func SanitizeHTML(html string) string {
 	filter := bluemonday.UGCPolicy()
 	filter.AllowStyling()
 	filter.RequireNoFollowOnLinks(false)
@@ -61,8 +65,7 @@ func Markdown2HTML(source string) string {
 	filter.AllowElements("kbd")
 	filter.AllowAttrs("title").Matching(regexp.MustCompile(`^[\p{L}\p{N}\s\-_',\[\]!\./\\\(\)]*$|^@embed?$`)).Globally()
 	filter.AllowAttrs("start").OnElements("ol")
-	html = filter.Sanitize(html)
-	return html
+	return filter.Sanitize(html)
 }
 
 // Markdown2BasicHTML convert markdown to html ,Only basic syntax can be used
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package plugin
+
+import (
+	"context"
+)
+
+type CronJobInfo struct {
+	// Name the unique name of the job in the plugin
+	Name string
+	// Spec the cron expression, such as "0 */1 * * *"
+	Spec        string
+	Description string
+	Run         func(ctx context.Context) error
+}
+
+// CronJob plugins can register scheduled jobs, the jobs only run when the plugin is enabled.
+// The job name will be prefixed by the plugin slug name.
+type CronJob interface {
+	Base
+	CronJobs() []*CronJobInfo
+}
+
+var (
+	// CallCronJob is a function that calls all registered cron job plugins, including the disabled ones
+	CallCronJob,
+	registerCronJob = MakePlugin[CronJob](true)
+)
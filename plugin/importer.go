@@ -21,13 +21,85 @@ package plugin
 
 import (
 	"context"
+	"time"
 )
 
+const (
+	// ImporterContentFormatMarkdown the content is Markdown, it is the default format
+	ImporterContentFormatMarkdown = "markdown"
+	// ImporterContentFormatHTML the content is HTML, it will be sanitized before saving
+	ImporterContentFormatHTML = "html"
+)
+
+// ImporterMeta the options shared by all imported objects
+type ImporterMeta struct {
+	// SourceID is the id of the object in the import source. If it is set, the object with the same
+	// source id will be imported only once, and importing it again returns the object created before.
+	SourceID string `json:"source_id"`
+	// CreatedAt and UpdatedAt keep the original time of the object, the current time is used if they are zero.
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// BypassRankCheck imports the object without checking the rank of the user, as the administrator does.
+	// It only takes effect when the administrator allows importers to bypass the rank check in the write settings.
+	BypassRankCheck bool `json:"bypass_rank_check"`
+}
+
 type QuestionImporterInfo struct {
+	ImporterMeta
 	Title     string   `json:"title"`
 	Content   string   `json:"content"`
 	Tags      []string `json:"tags"`
 	UserEmail string   `json:"user_email"`
+	// ContentFormat markdown or html, default is markdown
+	ContentFormat string `json:"content_format"`
+}
+
+type AnswerImporterInfo struct {
+	ImporterMeta
+	// QuestionID is the id of the question returned by AddQuestion
+	QuestionID    string `json:"question_id"`
+	Content       string `json:"content"`
+	UserEmail     string `json:"user_email"`
+	ContentFormat string `json:"content_format"`
+}
+
+type CommentImporterInfo struct {
+	ImporterMeta
+	// ObjectID is the id of the question or answer to comment on
+	ObjectID string `json:"object_id"`
+	// ReplyCommentID is the id of the comment to reply to, optional
+	ReplyCommentID string `json:"reply_comment_id"`
+	Content        string `json:"content"`
+	UserEmail      string `json:"user_email"`
+	ContentFormat  string `json:"content_format"`
+}
+
+type AcceptAnswerImporterInfo struct {
+	// SourceID is the id of the acceptance in the import source, the acceptance with the same source id is applied once.
+	SourceID   string `json:"source_id"`
+	QuestionID string `json:"question_id"`
+	AnswerID   string `json:"answer_id"`
+}
+
+type VoteImporterInfo struct {
+	// SourceID is the id of the vote in the import source, the vote with the same source id is applied once.
+	SourceID string `json:"source_id"`
+	// ObjectID is the id of the question, answer or comment to vote on
+	ObjectID  string `json:"object_id"`
+	UserEmail string `json:"user_email"`
+	// Up is true for vote up, false for vote down
+	Up bool `json:"up"`
+	// BypassRankCheck votes without checking the rank of the user, as the administrator does.
+	// It only takes effect when the administrator allows importers to bypass the rank check in the write settings.
+	BypassRankCheck bool `json:"bypass_rank_check"`
+}
+
+// ImporterResult the result of importing an object
+type ImporterResult struct {
+	// ID is the id of the created object, use it to refer to the object in the following imports
+	ID string `json:"id"`
+	// Existed is true if the object with the same source id has been imported before
+	Existed bool `json:"existed"`
 }
 
 type Importer interface {
@@ -39,6 +111,19 @@ type ImporterFunc interface {
 	AddQuestion(ctx context.Context, questionInfo QuestionImporterInfo) (err error)
 }
 
+// ImporterFuncV2 the importer functions for answers, comments, votes and the results of the imported objects.
+// The ImporterFunc registered to the importer implements it, get it by type assertion.
+type ImporterFuncV2 interface {
+	ImporterFunc
+	// ImportQuestion adds the question like AddQuestion and returns the id of the question
+	ImportQuestion(ctx context.Context, questionInfo QuestionImporterInfo) (result *ImporterResult, err error)
+	AddAnswer(ctx context.Context, answerInfo AnswerImporterInfo) (result *ImporterResult, err error)
+	AddComment(ctx context.Context, commentInfo CommentImporterInfo) (result *ImporterResult, err error)
+	// AcceptAnswer accepts the answer on behalf of the question author
+	AcceptAnswer(ctx context.Context, acceptInfo AcceptAnswerImporterInfo) (err error)
+	AddVote(ctx context.Context, voteInfo VoteImporterInfo) (err error)
+}
+
 var (
 	// CallImporter is a function that calls all registered parsers
 	CallImporter,
@@ -114,6 +114,10 @@ func Register(p Base) {
 	if _, ok := p.(Importer); ok {
 		registerImporter(p.(Importer))
 	}
+
+	if _, ok := p.(CronJob); ok {
+		registerCronJob(p.(CronJob))
+	}
 }
 
 type Stack[T Base] struct {
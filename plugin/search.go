@@ -118,6 +118,52 @@ type SearchDesc struct {
 	Link string `json:"link"`
 }
 
+// SearchFacetsProvider is an optional interface of the search plugin to count the matched contents by facets.
+// If the search plugin implements it, the search API returns the facets when they are requested.
+type SearchFacetsProvider interface {
+	// SearchFacets count all contents matched by the condition, the page of condition is ignored.
+	// The objectType is the type of searched contents: "question", "answer" or empty for both.
+	SearchFacets(ctx context.Context, cond *SearchBasicCond, objectType string) (facets *SearchFacets, err error)
+}
+
+// SearchFacets the counts of the matched contents
+type SearchFacets struct {
+	Questions int64
+	Answers   int64
+	// the count of accepted answers
+	AcceptedAnswers int64
+	// the count of questions without accepted answer
+	NotAcceptedQuestions int64
+	// the top tags, the value is tag id, the answers are counted by the tags of their questions
+	Tags []SearchFacetCount
+	// the top authors, the value is user id
+	Authors []SearchFacetCount
+	// the count of contents created in the past day, week, month and year, the value is one of SearchFacetDate*
+	Dates []SearchFacetCount
+}
+
+type SearchFacetCount struct {
+	Value string
+	Count int64
+}
+
+const (
+	// SearchFacetTopSize the max count of the top tags and authors in facets
+	SearchFacetTopSize = 10
+
+	SearchFacetDatePastDay   = "past_day"
+	SearchFacetDatePastWeek  = "past_week"
+	SearchFacetDatePastMonth = "past_month"
+	SearchFacetDatePastYear  = "past_year"
+)
+
+// SearchIndexInspector is an optional interface of the search plugin to read the indexed contents.
+// If the search plugin implements it, the dry-run of reindexing reports the drift between the index and database.
+type SearchIndexInspector interface {
+	// GetContents returns the indexed contents of the object ids, the ids not in the index are omitted.
+	GetContents(ctx context.Context, objectIDs []string) (contents []*SearchContent, err error)
+}
+
 type SearchSyncer interface {
 	GetAnswersPage(ctx context.Context, page, pageSize int) (answerList []*SearchContent, err error)
 	GetQuestionsPage(ctx context.Context, page, pageSize int) (questionList []*SearchContent, err error)
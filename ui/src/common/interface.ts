@@ -445,6 +445,7 @@ export interface AdminSettingsWrite {
   max_image_megapixel?: number;
   authorized_image_extensions?: string[];
   authorized_attachment_extensions?: string[];
+  importer_bypass_rank_check?: boolean;
 }
 
 export interface AdminSettingsSeo {
@@ -77,6 +77,11 @@ const initFormData = {
     errorMsg: '',
     isInvalid: false,
   },
+  importer_bypass_rank_check: {
+    value: false,
+    errorMsg: '',
+    isInvalid: false,
+  },
 };
 
 const Index: FC = () => {
@@ -151,6 +156,7 @@ const Index: FC = () => {
               .split(',')
               ?.map((item) => item.trim().toLowerCase())
           : [],
+      importer_bypass_rank_check: formData.importer_bypass_rank_check.value,
     };
     postRequireAndReservedTag(reqParams)
       .then(() => {
@@ -189,6 +195,8 @@ const Index: FC = () => {
         res.authorized_image_extensions?.join(', ').toLowerCase();
       formData.authorized_attachment_extensions.value =
         res.authorized_attachment_extensions?.join(', ').toLowerCase();
+      formData.importer_bypass_rank_check.value =
+        res.importer_bypass_rank_check;
       setFormData({ ...formData });
     });
   };
@@ -402,6 +410,27 @@ const Index: FC = () => {
           </Form.Control.Feedback>
         </Form.Group>
 
+        <Form.Group className="mb-3" controlId="importer_bypass_rank_check">
+          <Form.Label>{t('importer_bypass_rank_check.title')}</Form.Label>
+          <Form.Switch
+            label={t('importer_bypass_rank_check.label')}
+            checked={formData.importer_bypass_rank_check.value}
+            onChange={(evt) => {
+              handleValueChange({
+                importer_bypass_rank_check: {
+                  value: evt.target.checked,
+                  errorMsg: '',
+                  isInvalid: false,
+                },
+              });
+            }}
+          />
+          <Form.Text>{t('importer_bypass_rank_check.text')}</Form.Text>
+          <Form.Control.Feedback type="invalid">
+            {formData.importer_bypass_rank_check.errorMsg}
+          </Form.Control.Feedback>
+        </Form.Group>
+
         <Form.Group className="mb-3">
           <Button type="submit">{t('save', { keyPrefix: 'btns' })}</Button>
         </Form.Group>